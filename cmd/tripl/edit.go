@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+)
+
+func editCommand() {
+	editFlags := flag.NewFlagSet("edit", flag.ExitOnError)
+
+	var additions, removals stringList
+	editFlags.Var(&additions, "add", "Triple to add, in Turtle syntax using the file's prefixes (repeatable)")
+	editFlags.Var(&removals, "remove", "Triple to remove, in Turtle syntax using the file's prefixes (repeatable)")
+	toStdout := editFlags.Bool("stdout", false, "Print the edited document instead of writing the file")
+
+	args := parseInterspersed(editFlags, os.Args[2:])
+
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: edit requires exactly one Turtle file")
+		editFlags.Usage()
+		os.Exit(1)
+	}
+	if len(additions) == 0 && len(removals) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one --add or --remove is required")
+		os.Exit(1)
+	}
+
+	path := args[0]
+	input, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+
+	doc, err := encoder.ParseTurtleDocument(string(input), encoder.DecodeOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	for _, text := range removals {
+		t, err := doc.ParseTriple(text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing --remove %q: %v\n", text, err)
+			os.Exit(1)
+		}
+		removed, err := doc.Remove(t)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing %q: %v\n", text, err)
+			os.Exit(1)
+		}
+		if removed == 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %q not found in %s\n", text, path)
+		}
+	}
+
+	for _, text := range additions {
+		t, err := doc.ParseTriple(text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing --add %q: %v\n", text, err)
+			os.Exit(1)
+		}
+		if _, err := doc.Add(t); err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding %q: %v\n", text, err)
+			os.Exit(1)
+		}
+	}
+
+	if *toStdout {
+		fmt.Print(doc.String())
+		return
+	}
+
+	if doc.String() == string(input) {
+		return
+	}
+
+	if err := replaceFile(path, doc.String()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+)
+
+func fmtCommand() {
+	fmtFlags := flag.NewFlagSet("fmt", flag.ExitOnError)
+
+	write := fmtFlags.Bool("w", false, "Write result to the source file instead of stdout")
+	list := fmtFlags.Bool("l", false, "List files whose formatting differs from tripl fmt's")
+	indent := fmtFlags.Int("indent", 4, "Continuation indent for predicates (0 aligns under the first predicate)")
+	width := fmtFlags.Int("width", 80, "Line width at which object lists are wrapped (0 disables wrapping)")
+	align := fmtFlags.Bool("align", false, "Align predicates within a subject block")
+	groupTypes := fmtFlags.Bool("group-types", false, "Place subjects sharing an rdf:type next to each other")
+	sortOutput := fmtFlags.Bool("sort", false, "Sort triples before formatting")
+
+	fmtFlags.Parse(os.Args[2:])
+
+	style := encoder.DefaultTurtleStyle()
+	style.AlignPredicates = *align
+	style.GroupByType = *groupTypes
+
+	opts := encoder.EncodeOptions{
+		Indent:    *indent,
+		LineWidth: *width,
+		Sort:      *sortOutput,
+		Turtle:    style,
+	}
+
+	if fmtFlags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "Error: cannot use -w with standard input")
+			os.Exit(1)
+		}
+		input, err := readInput("")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			os.Exit(1)
+		}
+		output, err := encoder.FormatTurtle(string(input), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting input: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(output)
+		return
+	}
+
+	files, err := collectTurtleFiles(fmtFlags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, path := range files {
+		if err := formatFile(path, opts, *write, *list); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func formatFile(path string, opts encoder.EncodeOptions, write, list bool) error {
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	output, err := encoder.FormatTurtle(string(input), opts)
+	if err != nil {
+		return err
+	}
+
+	changed := output != string(input)
+
+	if list && changed {
+		fmt.Println(path)
+	}
+
+	if write {
+		if !changed {
+			return nil
+		}
+		return replaceFile(path, output)
+	}
+
+	if !list {
+		fmt.Print(output)
+	}
+	return nil
+}
+
+func collectTurtleFiles(args []string) ([]string, error) {
+	var files []string
+
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), encoder.Turtle.Extension()) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/vocab"
+)
+
+func genVocabCommand() {
+	genFlags := flag.NewFlagSet("gen-vocab", flag.ExitOnError)
+
+	pkg := genFlags.String("package", "", "Name of the generated Go package (required)")
+	namespace := genFlags.String("namespace", "", "Namespace IRI of the terms to generate (default: inferred from the ontology)")
+	namespaceFrom := genFlags.String("namespace-from", "", "Go constant to declare Namespace as, e.g. example.com/pkg.Namespace")
+	from := genFlags.String("from", "", "Input format: ntriples, turtle, jsonld (default: from the file extension)")
+	output := genFlags.String("output", "", "File path to write the Go source (default: stdout)")
+	force := genFlags.Bool("force", false, "Allow overwriting existing output file")
+
+	args := parseInterspersed(genFlags, os.Args[2:])
+
+	if len(args) != 1 || *pkg == "" {
+		fmt.Fprintln(os.Stderr, "Error: gen-vocab requires an ontology file and --package")
+		genFlags.Usage()
+		os.Exit(1)
+	}
+
+	path := args[0]
+	formatName := *from
+	if formatName == "" {
+		formatName = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	format, err := encoder.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v (use --from)\n", err)
+		os.Exit(1)
+	}
+
+	input, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+
+	triples, _, err := encoder.Decode(string(input), format, encoder.DecodeOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error decoding %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	source, err := vocab.Generate(triples, vocab.GenerateOptions{Package: *pkg, Namespace: *namespace, NamespaceFrom: *namespaceFrom})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating vocabulary: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeOutput(string(source), *output, *force); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/graphstore"
+)
+
+func gspCommand() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "Error: gsp requires an operation: get, put, post or delete")
+		os.Exit(1)
+	}
+	op := os.Args[2]
+
+	gspFlags := flag.NewFlagSet("gsp "+op, flag.ExitOnError)
+
+	endpoint := gspFlags.String("endpoint", "", "Graph Store endpoint URL (required)")
+	graph := gspFlags.String("graph", "", "Graph IRI (default: the default graph)")
+	from := gspFlags.String("from", "", "Input format for put and post (default: from the file extension, turtle for stdin)")
+	to := gspFlags.String("to", "turtle", "Output format for get: ntriples, turtle, jsonld")
+	outputPath := gspFlags.String("output", "", "File path to write output of get (default: stdout)")
+	force := gspFlags.Bool("force", false, "Allow overwriting existing output file")
+
+	args := parseInterspersed(gspFlags, os.Args[3:])
+
+	if *endpoint == "" {
+		fmt.Fprintln(os.Stderr, "Error: --endpoint is required")
+		gspFlags.Usage()
+		os.Exit(1)
+	}
+
+	client := &graphstore.Client{Endpoint: *endpoint}
+	ctx := context.Background()
+
+	switch op {
+	case "get":
+		format, err := encoder.ParseFormat(*to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		client.Format = format
+		triples, prefixes, err := client.Get(ctx, *graph)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting graph: %v\n", err)
+			os.Exit(1)
+		}
+		output, err := encoder.Encode(triples, format, encoder.EncodeOptions{Prefixes: prefixes, Compact: true})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding output: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeOutput(output, *outputPath, *force); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "put", "post":
+		if len(args) > 1 {
+			fmt.Fprintf(os.Stderr, "Error: gsp %s takes at most one file\n", op)
+			os.Exit(1)
+		}
+		path := ""
+		if len(args) == 1 {
+			path = args[0]
+		}
+		formatName := *from
+		if formatName == "" {
+			formatName = "turtle"
+			if path != "" {
+				formatName = strings.TrimPrefix(filepath.Ext(path), ".")
+			}
+		}
+		format, err := encoder.ParseFormat(formatName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (use --from)\n", err)
+			os.Exit(1)
+		}
+		input, err := readInput(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			os.Exit(1)
+		}
+		triples, prefixes, err := encoder.Decode(string(input), format, encoder.DecodeOptions{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error decoding input: %v\n", err)
+			os.Exit(1)
+		}
+
+		client.Format = format
+		if op == "put" {
+			err = client.Put(ctx, *graph, triples, prefixes)
+		} else {
+			err = client.Post(ctx, *graph, triples, prefixes)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error sending graph: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "delete":
+		if err := client.Delete(ctx, *graph); err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting graph: %v\n", err)
+			os.Exit(1)
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown gsp operation %q (use get, put, post or delete)\n", op)
+		os.Exit(1)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/sparql"
 	"github.com/DeDude/tripl/pkg/triple"
 )
 
@@ -25,6 +26,30 @@ func main() {
 		createCommand()
 	case "convert":
 		convertCommand()
+	case "fmt":
+		fmtCommand()
+	case "edit":
+		editCommand()
+	case "gen-vocab":
+		genVocabCommand()
+	case "query":
+		queryCommand()
+	case "update":
+		updateCommand()
+	case "serve":
+		serveCommand()
+	case "gsp":
+		gspCommand()
+	case "load":
+		loadCommand()
+	case "export":
+		exportCommand()
+	case "log":
+		logCommand()
+	case "checkout":
+		checkoutCommand()
+	case "diff":
+		diffCommand()
 	case "help":
 		printUsage()
 	default:
@@ -44,6 +69,7 @@ func createCommand() {
 	object := createFlags.String("object", "", "Object value")
 	objectType := createFlags.String("object-type", "literal", "Object type: literal. iri, blank")
 	language := createFlags.String("language", "", "Language tag for literal (optional)")
+	direction := createFlags.String("direction", "", "Base direction for a language-tagged literal: ltr or rtl (optional)")
 	datatype := createFlags.String("datatype", "", "Datatype IRI for literal (optional)")
 	prefixFlag := createFlags.String("prefix", "", "Prefix definitions (format: prefix=uri, prefix2=uri2)")
 	compact := createFlags.Bool("compact", false, "Use compact output format (for turtle and jsonld)")
@@ -70,10 +96,15 @@ func createCommand() {
 
 	switch *objectType {
 	case "literal":
+		if *language != "" && *datatype != "" {
+			fmt.Fprintln(os.Stderr, "Error: --language and --datatype cannot be used together")
+			os.Exit(1)
+		}
 		lit := triple.Literal{Value: *object}
 		if *language != "" {
 			lit.Language = *language
 		}
+		lit.Direction = *direction
 		if *datatype != "" {
 			lit.Datatype = resolver.Expand(*datatype)
 		}
@@ -87,35 +118,27 @@ func createCommand() {
 		os.Exit(1)
 	}
 
-	triples := []triple.Triple{t}
-
-	var output string
-	var err error
+	if err := t.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	switch *format {
-	case "ntriples":
-		output = encoder.EncodeNTriple(t)
-	case "turtle":
-		if *compact {
-			output = encoder.EncodeTurtleCompact(triples, prefixes)
-		} else {
-			output = encoder.EncodeTurtle(triples, prefixes)
-		}
-	case "jsonld":
-		if *compact {
-			output, err = encoder.EncodeJSONLDCompact(triples, prefixes)
-		} else {
-			output, err = encoder.EncodeJSONLD(triples)
-		}
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error encoding JSON-LD: %v\n", err)
-			os.Exit(1)
-		}
-	default:
+	outFormat, err := encoder.ParseFormat(*format)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Unknown format: %s\n", *format)
 		os.Exit(1)
 	}
 
+	output, err := encoder.Encode([]triple.Triple{t}, outFormat, encoder.EncodeOptions{
+		Prefixes: prefixes,
+		Compact:  *compact,
+		Strict:   true,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding output: %v\n", err)
+		os.Exit(1)
+	}
+
 	if err := writeOutput(output, *outputPath, *force); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
 		os.Exit(1)
@@ -129,6 +152,13 @@ func convertCommand() {
 	toFormat := convertFlags.String("to", "", "Output format: ntriples, turtle, jsonld")
 	compact := convertFlags.Bool("compact", false, "Use compact output format (turtle/jsonld)")
 	prefixFlag := convertFlags.String("prefix", "", "Prefix definitions for output (format: prefix=uri, prefix2=uri2)")
+	base := convertFlags.String("base", "", "Base IRI for resolving relative IRIs and shortening output")
+	sortOutput := convertFlags.Bool("sort", false, "Sort triples in the output")
+	strict := convertFlags.Bool("strict", false, "Reject invalid IRIs, language tags and undefined prefixes, and fail on triples that cannot be written instead of skipping them with a warning")
+	validateLiterals := convertFlags.Bool("validate-literals", false, "Reject literals whose value does not match their XSD datatype")
+	rdfDirection := convertFlags.String("rdf-direction", "", "JSON-LD @direction as RDF: i18n-datatype or compound-literal (default: directional language strings)")
+	reification := convertFlags.String("reification", "", "Rewrite reification: statements (rdf:Statement) or triple-terms (rdf:reifies)")
+	constructPath := convertFlags.String("construct", "", "File with a SPARQL CONSTRUCT or DESCRIBE query applied to the input")
 	batch := convertFlags.Bool("batch", false, "Convert all files in input directory matching the source format extension")
 	inputPath := convertFlags.String("input", "", "File path to read input from (default: stdin)")
 	outputPath := convertFlags.String("output", "", "File path to write output (default: stdout)")
@@ -142,14 +172,66 @@ func convertCommand() {
 		os.Exit(1)
 	}
 
-	userPrefixes := parsePrefixes(*prefixFlag)
+	from, err := encoder.ParseFormat(*fromFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	to, err := encoder.ParseFormat(*toFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	directionMode, err := encoder.ParseDirectionMode(*rdfDirection)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *reification != "" && *reification != "statements" && *reification != "triple-terms" {
+		fmt.Fprintf(os.Stderr, "Error: unknown reification %q (use statements or triple-terms)\n", *reification)
+		os.Exit(1)
+	}
+
+	var construct string
+	if *constructPath != "" {
+		query, err := os.ReadFile(*constructPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading query: %v\n", err)
+			os.Exit(1)
+		}
+		construct = string(query)
+	}
+
+	opts := convertOptions{
+		from: from,
+		to:   to,
+		decode: encoder.DecodeOptions{
+			Base:             *base,
+			Strict:           *strict,
+			ValidateLiterals: *validateLiterals,
+			RDFDirection:     directionMode,
+		},
+		reification: *reification,
+		construct:   construct,
+		encode: encoder.EncodeOptions{
+			Prefixes:     parsePrefixes(*prefixFlag),
+			Base:         *base,
+			Compact:      *compact,
+			Sort:         *sortOutput,
+			Strict:       *strict,
+			RDFDirection: directionMode,
+			Skipped:      warnSkipped,
+		},
+	}
 
 	if *batch {
 		if *inputPath == "" {
 			fmt.Fprintln(os.Stderr, "Error: --input directory is required in batch mode")
 			os.Exit(1)
 		}
-		if err := convertBatch(strings.ToLower(*fromFormat), strings.ToLower(*toFormat), *compact, userPrefixes, *inputPath, *outputPath, *force); err != nil {
+		if err := convertBatch(opts, *inputPath, *outputPath, *force); err != nil {
 			fmt.Fprintf(os.Stderr, "Error converting batch: %v\n", err)
 			os.Exit(1)
 		}
@@ -168,22 +250,9 @@ func convertCommand() {
 		os.Exit(1)
 	}
 
-	triples, detectedPrefixes, err := decodeTriples(strings.ToLower(*fromFormat), input)
+	output, err := convertData(input, opts)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error decoding input: %v\n", err)
-		os.Exit(1)
-	}
-
-	if detectedPrefixes == nil {
-		detectedPrefixes = map[string]string{}
-	}
-	for k, v := range userPrefixes {
-		detectedPrefixes[k] = v
-	}
-
-	output, err := encodeTriples(triples, strings.ToLower(*toFormat), *compact, detectedPrefixes)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error encoding output: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error converting input: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -193,57 +262,86 @@ func convertCommand() {
 	}
 }
 
-func decodeTriples(format, data string) ([]triple.Triple, map[string]string, error) {
-	switch format {
-	case "ntriples", "nt":
-		var triples []triple.Triple
-		lines := strings.Split(data, "\n")
-		for _, line := range lines {
-			trimmed := strings.TrimSpace(line)
-			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
-				continue
-			}
-			t, err := encoder.DecodeNTriple(trimmed)
-			if err != nil {
-				return nil, nil, fmt.Errorf("line %q: %w", trimmed, err)
-			}
-			triples = append(triples, t)
-		}
-		return triples, map[string]string{}, nil
-	case "turtle", "ttl":
-		triples, prefixes, err := encoder.DecodeTurtle(data)
-		return triples, prefixes, err
-	case "jsonld":
-		triples, err := encoder.DecodeJSONLD(data)
-		return triples, map[string]string{}, err
-	default:
-		return nil, nil, fmt.Errorf("unsupported input format: %s", format)
-	}
+type convertOptions struct {
+	from, to encoder.Format
+	decode   encoder.DecodeOptions
+	encode   encoder.EncodeOptions
+	// reification is "statements", "triple-terms" or empty to leave it as is.
+	reification string
+	// construct is a CONSTRUCT or DESCRIBE query whose result replaces the
+	// decoded triples.
+	construct string
 }
 
-func encodeTriples(triples []triple.Triple, format string, compact bool, prefixes map[string]string) (string, error) {
-	switch format {
-	case "ntriples", "nt":
-		var b strings.Builder
-		for i, t := range triples {
-			if i > 0 {
-				b.WriteString("\n")
-			}
-			b.WriteString(encoder.EncodeNTriple(t))
+func convertData(input string, opts convertOptions) (string, error) {
+	triples, detectedPrefixes, err := encoder.Decode(input, opts.from, opts.decode)
+	if err != nil {
+		return "", fmt.Errorf("decoding: %w", err)
+	}
+
+	if opts.construct != "" {
+		q, err := sparql.Parse(opts.construct, sparql.ParseOptions{Prefixes: detectedPrefixes, Base: opts.decode.Base})
+		if err != nil {
+			return "", fmt.Errorf("parsing query: %w", err)
 		}
-		return b.String(), nil
-	case "turtle", "ttl":
-		if compact {
-			return encoder.EncodeTurtleCompact(triples, prefixes), nil
+		if q.Form != sparql.Construct && q.Form != sparql.Describe {
+			return "", fmt.Errorf("--construct needs a CONSTRUCT or DESCRIBE query, not %s", q.Form)
 		}
-		return encoder.EncodeTurtle(triples, prefixes), nil
-	case "jsonld":
-		if compact {
-			return encoder.EncodeJSONLDCompact(triples, prefixes)
+		res, err := q.Eval(triple.NewGraph(triples))
+		if err != nil {
+			return "", fmt.Errorf("evaluating query: %w", err)
 		}
-		return encoder.EncodeJSONLD(triples)
-	default:
-		return "", fmt.Errorf("unsupported output format: %s", format)
+		triples, detectedPrefixes = res.Triples, q.Prefixes
+	}
+
+	switch opts.reification {
+	case "statements":
+		triples = triple.StarToStatements(triples)
+	case "triple-terms":
+		triples = triple.StatementsToStar(triples)
+	}
+
+	encodeOpts := opts.encode
+
+	prefixes := make(map[string]string, len(detectedPrefixes)+len(encodeOpts.Prefixes))
+	for k, v := range detectedPrefixes {
+		prefixes[k] = v
+	}
+	for k, v := range encodeOpts.Prefixes {
+		prefixes[k] = v
+	}
+	encodeOpts.Prefixes = prefixes
+
+	output, err := encoder.Encode(triples, opts.to, encodeOpts)
+	if err != nil {
+		return "", fmt.Errorf("encoding: %w", err)
+	}
+	return output, nil
+}
+
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// parseInterspersed parses flags that may appear before or after positional
+// arguments and returns the positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
 	}
 }
 
@@ -272,6 +370,11 @@ func readInput(path string) ([]byte, error) {
 	return os.ReadFile(path)
 }
 
+// warnSkipped reports a triple the encoder left out of the output.
+func warnSkipped(t triple.Triple, err error) {
+	fmt.Fprintf(os.Stderr, "Warning: skipped triple %s: %v\n", strings.TrimSpace(encoder.EncodeNTriple(t)), err)
+}
+
 func writeOutput(data string, path string, force bool) error {
 	if path == "" {
 		fmt.Print(data)
@@ -289,15 +392,42 @@ func writeOutput(data string, path string, force bool) error {
 	return os.WriteFile(path, []byte(data), 0644)
 }
 
-func convertBatch(fromFormat, toFormat string, compact bool, userPrefixes map[string]string, inputDir, outputDir string, force bool) error {
-	fromExt, err := formatExtension(fromFormat)
+// replaceFile writes data to a temporary file next to path and renames it
+// over path, so a failed write never leaves path half written. The file
+// keeps path's permissions.
+func replaceFile(path string, data string) error {
+	info, err := os.Stat(path)
 	if err != nil {
 		return err
 	}
-	toExt, err := formatExtension(toFormat)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func convertBatch(opts convertOptions, inputDir, outputDir string, force bool) error {
+	fromExt := opts.from.Extension()
+	toExt := opts.to.Extension()
 
 	if outputDir != "" {
 		if err := ensureDir(outputDir); err != nil {
@@ -327,21 +457,9 @@ func convertBatch(fromFormat, toFormat string, compact bool, userPrefixes map[st
 			return fmt.Errorf("%s is empty", inPath)
 		}
 
-		triples, detectedPrefixes, err := decodeTriples(fromFormat, input)
+		output, err := convertData(input, opts)
 		if err != nil {
-			return fmt.Errorf("decoding %s: %w", inPath, err)
-		}
-
-		if detectedPrefixes == nil {
-			detectedPrefixes = map[string]string{}
-		}
-		for k, v := range userPrefixes {
-			detectedPrefixes[k] = v
-		}
-
-		output, err := encodeTriples(triples, toFormat, compact, detectedPrefixes)
-		if err != nil {
-			return fmt.Errorf("encoding %s: %w", inPath, err)
+			return fmt.Errorf("%s: %w", inPath, err)
 		}
 
 		base := strings.TrimSuffix(filepath.Base(inPath), filepath.Ext(inPath))
@@ -377,18 +495,6 @@ func convertBatch(fromFormat, toFormat string, compact bool, userPrefixes map[st
 	return nil
 }
 
-func formatExtension(format string) (string, error) {
-	switch format {
-	case "ntriples", "nt":
-		return ".nt", nil
-	case "turtle", "ttl":
-		return ".ttl", nil
-	case "jsonld":
-		return ".jsonld", nil
-	}
-	return "", fmt.Errorf("unsupported format: %s", format)
-}
-
 func ensureDir(path string) error {
 	info, err := os.Stat(path)
 	if os.IsNotExist(err) {
@@ -460,10 +566,35 @@ func printUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  tripl create [flags]")
 	fmt.Println("  tripl convert [flags] < input")
+	fmt.Println("  tripl fmt [-w] [-l] [flags] [files...]")
+	fmt.Println("  tripl edit file.ttl [--add triple] [--remove triple]")
+	fmt.Println("  tripl gen-vocab ontology.ttl --package name [flags]")
+	fmt.Println("  tripl query --query q.rq [flags] [files...]")
+	fmt.Println("  tripl query --query q.rq --endpoint URL [flags]")
+	fmt.Println("  tripl update --update u.ru data.ttl [-w]")
+	fmt.Println("  tripl serve [--port 8080] [--tpf | --ld] [flags] [files...]")
+	fmt.Println("  tripl gsp get|put|post|delete --endpoint URL [--graph IRI] [file]")
+	fmt.Println("  tripl load db/ [--from format] [--message text] [files...]")
+	fmt.Println("  tripl export db/ [--to format] [--output file]")
+	fmt.Println("  tripl log db/ [-n count]")
+	fmt.Println("  tripl checkout db/ --at version [--to format] [--output file]")
+	fmt.Println("  tripl diff db/ --from version [--to version]")
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  create    Create a triple and output in specified format")
 	fmt.Println("  convert   Convert triples between formats (reads from stdin)")
+	fmt.Println("  fmt       Reformat Turtle files in a canonical style")
+	fmt.Println("  edit      Add or remove triples in a Turtle file, keeping comments and layout")
+	fmt.Println("  gen-vocab Generate a Go package of IRIs from an RDFS/OWL vocabulary")
+	fmt.Println("  query     Run a SPARQL query over one or more data files")
+	fmt.Println("  update    Apply a SPARQL Update request to a data file")
+	fmt.Println("  serve     Serve data files over the SPARQL 1.1 Protocol and Graph Store Protocol")
+	fmt.Println("  gsp       Read, replace, add to or delete a graph in a Graph Store Protocol endpoint")
+	fmt.Println("  load      Add data files to an on-disk store, creating it if needed")
+	fmt.Println("  export    Write the contents of a store in an RDF format")
+	fmt.Println("  log       List the versions of a store, newest first")
+	fmt.Println("  checkout  Write a store as it was at a past version")
+	fmt.Println("  diff      Show the triples added and removed between two versions of a store")
 	fmt.Println("  help      Show this help message")
 	fmt.Println()
 	fmt.Println("Create flags:")
@@ -473,6 +604,7 @@ func printUsage() {
 	fmt.Println("  --object string        Object value (required)")
 	fmt.Println("  --object-type string   Object type: literal, iri, blank (default: literal)")
 	fmt.Println("  --language string      Language tag for literal")
+	fmt.Println("  --direction string     Base direction for a language-tagged literal: ltr or rtl")
 	fmt.Println("  --datatype string      Datatype IRI for literal")
 	fmt.Println("  --prefix string        Prefix definitions (format: ex=http://example.org/)")
 	fmt.Println("  --compact              Use compact output format (turtle: semicolons/commas, jsonld: @context)")
@@ -484,11 +616,105 @@ func printUsage() {
 	fmt.Println("  --to string            Output format: ntriples, turtle, jsonld (required)")
 	fmt.Println("  --prefix string        Prefix definitions for output (format: ex=http://example.org/)")
 	fmt.Println("  --compact              Use compact output format (turtle/jsonld)")
+	fmt.Println("  --base string          Base IRI for resolving relative IRIs and shortening output")
+	fmt.Println("  --sort                 Sort triples in the output")
+	fmt.Println("  --strict               Reject invalid IRIs, language tags and undefined prefixes, and fail on")
+	fmt.Println("                         triples that cannot be written instead of skipping them with a warning")
+	fmt.Println("  --validate-literals    Reject literals whose value does not match their XSD datatype")
+	fmt.Println("  --rdf-direction string JSON-LD @direction as RDF: i18n-datatype or compound-literal")
+	fmt.Println("  --reification string   Rewrite reification: statements (rdf:Statement) or triple-terms (rdf:reifies)")
+	fmt.Println("  --construct string     File with a SPARQL CONSTRUCT or DESCRIBE query applied between decode and encode")
 	fmt.Println("  --batch                Convert all files in an input directory (requires --input dir)")
 	fmt.Println("  --input string         File path to read input (default: stdin) or directory in batch mode")
 	fmt.Println("  --output string        File path to write output (default: stdout) or directory in batch mode")
 	fmt.Println("  --force                Allow overwriting existing output file")
 	fmt.Println()
+	fmt.Println("Fmt flags:")
+	fmt.Println("  -w                     Write result to the source file instead of stdout")
+	fmt.Println("  -l                     List files whose formatting differs")
+	fmt.Println("  --indent int           Continuation indent for predicates, 0 aligns under the first predicate (default: 4)")
+	fmt.Println("  --width int            Line width at which object lists are wrapped, 0 disables (default: 80)")
+	fmt.Println("  --align                Align predicates within a subject block")
+	fmt.Println("  --group-types          Place subjects sharing an rdf:type next to each other")
+	fmt.Println("  --sort                 Sort triples before formatting")
+	fmt.Println("  Directories are walked for .ttl files; with no files, formats stdin to stdout.")
+	fmt.Println()
+	fmt.Println("Edit flags:")
+	fmt.Println("  --add string           Triple to add, e.g. 'ex:note1 ex:title \"Hi\"' (repeatable)")
+	fmt.Println("  --remove string        Triple to remove, same syntax as --add (repeatable)")
+	fmt.Println("  --stdout               Print the edited document instead of writing the file")
+	fmt.Println()
+	fmt.Println("Gen-vocab flags:")
+	fmt.Println("  --package string       Name of the generated Go package (required)")
+	fmt.Println("  --namespace string     Namespace IRI of the terms to generate (default: inferred)")
+	fmt.Println("  --namespace-from string")
+	fmt.Println("                         Go constant to declare Namespace as, e.g. example.com/pkg.Namespace")
+	fmt.Println("  --from string          Input format (default: from the file extension)")
+	fmt.Println("  --output string        File path to write the Go source (default: stdout)")
+	fmt.Println("  --force                Allow overwriting existing output file")
+	fmt.Println()
+	fmt.Println("Query flags:")
+	fmt.Println("  --query string         File containing the SPARQL query (required)")
+	fmt.Println("  --results string       Results format: table, json, xml, csv, tsv (default: table)")
+	fmt.Println("  --to string            Output format for CONSTRUCT and DESCRIBE: ntriples, turtle, jsonld (default: turtle)")
+	fmt.Println("  --from string          Input format (default: from the file extension, turtle for stdin)")
+	fmt.Println("  --endpoint string      Send the query to this SPARQL endpoint URL instead of reading files")
+	fmt.Println("  --db string            Query this store directory instead of reading files")
+	fmt.Println("  --at string            With --db, query this version of the store: an ID such as v3, or a date or time")
+	fmt.Println("  --output string        File path to write output (default: stdout)")
+	fmt.Println("  --force                Allow overwriting existing output file")
+	fmt.Println()
+	fmt.Println("Update flags:")
+	fmt.Println("  --update string        File containing the SPARQL Update request (required)")
+	fmt.Println("  -w                     Write result to the data file instead of stdout")
+	fmt.Println("  --from string          Data format (default: from the file extension)")
+	fmt.Println("  --reformat             Re-encode a Turtle file when the update cannot be applied in place,")
+	fmt.Println("                         losing its comments and layout")
+	fmt.Println("  LOAD reads local files; relative IRIs are resolved against the data file's directory.")
+	fmt.Println()
+	fmt.Println("Serve flags:")
+	fmt.Println("  --port int             Port to listen on (default: 8080)")
+	fmt.Println("  --host string          Host or address to listen on (default: localhost)")
+	fmt.Println("  --from string          Input format (default: from the file extension)")
+	fmt.Println("  --read-only            Reject SPARQL updates and Graph Store writes")
+	fmt.Println("  --cors string          Origin of the web pages, or * for any, that may use /sparql and /rdf-graphs;")
+	fmt.Println("                         updates and writes from pages on other origins are rejected")
+	fmt.Println("  --tpf                  Serve Triple Pattern Fragments at / instead of the SPARQL endpoints")
+	fmt.Println("  --page-size int        Triples per fragment page with --tpf (default: 100)")
+	fmt.Println("  --ld                   Serve resources as Linked Data at / instead of the SPARQL endpoints")
+	fmt.Println("  --base string          IRI the server root stands for with --ld (default: the server URL)")
+	fmt.Println("  --inbound              Include triples pointing at a resource in its description with --ld")
+	fmt.Println("  --db string            Serve this store directory instead of reading files; updates are saved to it")
+	fmt.Println("  Endpoints are /sparql and /rdf-graphs. When serving files, updates change the data in memory")
+	fmt.Println("  and never the files; with --db, each update is committed to the store as a new version.")
+	fmt.Println()
+	fmt.Println("Gsp flags:")
+	fmt.Println("  --endpoint string      Graph Store endpoint URL (required)")
+	fmt.Println("  --graph string         Graph IRI (default: the default graph)")
+	fmt.Println("  --from string          Input format for put and post (default: from the file extension, turtle for stdin)")
+	fmt.Println("  --to string            Output format for get: ntriples, turtle, jsonld (default: turtle)")
+	fmt.Println("  --output string        File path to write output of get (default: stdout)")
+	fmt.Println("  --force                Allow overwriting existing output file")
+	fmt.Println()
+	fmt.Println("Load flags:")
+	fmt.Println("  --from string          Input format (default: from the file extension, turtle for stdin)")
+	fmt.Println("  --message string       Message recorded with the new version (default: the files loaded)")
+	fmt.Println()
+	fmt.Println("Export and checkout flags:")
+	fmt.Println("  --at string            Version to write: an ID such as v3, or a date or time (required for checkout)")
+	fmt.Println("  --to string            Output format: ntriples, turtle, jsonld (default: turtle)")
+	fmt.Println("  --output string        File path to write output (default: stdout)")
+	fmt.Println("  --force                Allow overwriting existing output file")
+	fmt.Println("  A date or time stands for the last version committed by then; a date means the end of that day.")
+	fmt.Println()
+	fmt.Println("Log flags:")
+	fmt.Println("  -n int                 Show only the latest n versions (default: all)")
+	fmt.Println()
+	fmt.Println("Diff flags:")
+	fmt.Println("  --from string          Version to compare from (required)")
+	fmt.Println("  --to string            Version to compare to (default: the latest)")
+	fmt.Println("  Removed triples are printed as N-Triples lines starting with -, added ones with +.")
+	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  tripl create --subject http://example.org/note1 --predicate http://example.org/title --object \"My Note\"")
 	fmt.Println("  tripl create --prefix ex=http://example.org/ --subject ex:note1 --predicate ex:title --object \"My Note\" --format turtle")
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/sparql"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func queryCommand() {
+	queryFlags := flag.NewFlagSet("query", flag.ExitOnError)
+
+	queryPath := queryFlags.String("query", "", "File containing the SPARQL query (required)")
+	resultsFormat := queryFlags.String("results", "table", "Results format: table, json, xml, csv, tsv")
+	to := queryFlags.String("to", "turtle", "Output format for CONSTRUCT and DESCRIBE: ntriples, turtle, jsonld")
+	from := queryFlags.String("from", "", "Input format: ntriples, turtle, jsonld (default: from the file extension, turtle for stdin)")
+	outputPath := queryFlags.String("output", "", "File path to write output (default: stdout)")
+	force := queryFlags.Bool("force", false, "Allow overwriting existing output file")
+	endpoint := queryFlags.String("endpoint", "", "Send the query to this SPARQL endpoint URL instead of reading files")
+	db := queryFlags.String("db", "", "Query this store directory instead of reading files")
+	at := queryFlags.String("at", "", "With --db, query this version of the store: an ID such as v3, or a date or time")
+
+	files := parseInterspersed(queryFlags, os.Args[2:])
+
+	if *queryPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: --query is required")
+		queryFlags.Usage()
+		os.Exit(1)
+	}
+
+	var format sparql.ResultsFormat
+	if *resultsFormat != "table" {
+		var err error
+		if format, err = sparql.ParseResultsFormat(*resultsFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	queryText, err := os.ReadFile(*queryPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading query: %v\n", err)
+		os.Exit(1)
+	}
+
+	var res *sparql.Results
+	var prefixes map[string]string
+	switch {
+	case *endpoint != "" && *db != "":
+		fmt.Fprintln(os.Stderr, "Error: --endpoint and --db cannot be combined")
+		os.Exit(1)
+	case (*endpoint != "" || *db != "") && len(files) > 0:
+		fmt.Fprintln(os.Stderr, "Error: --endpoint and --db cannot be combined with data files")
+		os.Exit(1)
+	case *at != "" && *db == "":
+		fmt.Fprintln(os.Stderr, "Error: --at needs --db")
+		os.Exit(1)
+	case *endpoint != "":
+		res, prefixes, err = queryEndpoint(*endpoint, string(queryText))
+	case *db != "":
+		res, prefixes, err = queryStore(*db, *at, string(queryText))
+	default:
+		res, prefixes, err = queryFiles(files, *from, string(queryText))
+	}
+	if err != nil {
+		// The error starts with what failed: "loading data: ...".
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		os.Exit(1)
+	}
+
+	var output string
+	switch {
+	case res.Form == sparql.Construct || res.Form == sparql.Describe:
+		outFormat, err := encoder.ParseFormat(*to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		output, err = encoder.Encode(res.Triples, outFormat, encoder.EncodeOptions{Prefixes: prefixes, Compact: true, Skipped: warnSkipped})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding output: %v\n", err)
+			os.Exit(1)
+		}
+	case format == "":
+		output = formatTable(res, prefixes)
+	default:
+		if output, err = sparql.EncodeResults(res, format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding results: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := writeOutput(output, *outputPath, *force); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// queryFiles evaluates a query over data files.
+func queryFiles(files []string, from, query string) (*sparql.Results, map[string]string, error) {
+	g, prefixes, err := loadGraph(files, from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading data: %w", err)
+	}
+	return evalQuery(g, prefixes, query)
+}
+
+// queryStore evaluates a query over a store directory, at a past version if
+// at names one.
+func queryStore(dir, at, query string) (*sparql.Results, map[string]string, error) {
+	st, err := openStore(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening store: %w", err)
+	}
+	defer st.Close()
+	snap, err := snapshotAt(st, at)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening version: %w", err)
+	}
+	return evalQuery(snap, st.Prefixes(), query)
+}
+
+// evalQuery evaluates a query with the data's prefixes available to it and
+// returns the results and the prefixes of the data and the query.
+func evalQuery(g triple.Matcher, prefixes map[string]string, query string) (*sparql.Results, map[string]string, error) {
+	q, err := sparql.Parse(query, sparql.ParseOptions{Prefixes: prefixes})
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing query: %w", err)
+	}
+
+	res, err := q.Eval(g)
+	if err != nil {
+		return nil, nil, fmt.Errorf("evaluating query: %w", err)
+	}
+
+	for k, v := range q.Prefixes {
+		prefixes[k] = v
+	}
+	return res, prefixes, nil
+}
+
+// queryEndpoint sends a query to a remote endpoint. The endpoint may support
+// more than package sparql, so the query is only parsed locally to borrow
+// its prefixes for output, and only if that works.
+func queryEndpoint(endpoint, query string) (*sparql.Results, map[string]string, error) {
+	client := &sparql.Client{Endpoint: endpoint}
+	res, err := client.Query(context.Background(), query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying %s: %w", endpoint, err)
+	}
+
+	prefixes := make(map[string]string)
+	if q, err := sparql.Parse(query, sparql.ParseOptions{}); err == nil {
+		for k, v := range q.Prefixes {
+			prefixes[k] = v
+		}
+	}
+	for k, v := range res.Prefixes {
+		prefixes[k] = v
+	}
+	return res, prefixes, nil
+}
+
+// loadGraph decodes the files into one graph, reading stdin when there are
+// none. The format of each file comes from its extension unless from is set.
+// Blank node labels are made distinct per file so that _:b0 in two files
+// stays two nodes.
+func loadGraph(paths []string, from string) (*triple.Graph, map[string]string, error) {
+	g := triple.NewGraph(nil)
+	prefixes := make(map[string]string)
+
+	load := func(input string, format encoder.Format, label string) error {
+		triples, detected, err := encoder.Decode(input, format, encoder.DecodeOptions{})
+		if err != nil {
+			return err
+		}
+		for _, t := range triples {
+			if label != "" {
+				t = relabelBlankNodes(t, label)
+			}
+			g.Add(t)
+		}
+		for k, v := range detected {
+			if _, ok := prefixes[k]; !ok {
+				prefixes[k] = v
+			}
+		}
+		return nil
+	}
+
+	if len(paths) == 0 {
+		name := from
+		if name == "" {
+			name = "turtle"
+		}
+		format, err := encoder.ParseFormat(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		input, err := readInput("")
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := load(string(input), format, ""); err != nil {
+			return nil, nil, fmt.Errorf("decoding stdin: %w", err)
+		}
+		return g, prefixes, nil
+	}
+
+	for i, path := range paths {
+		name := from
+		if name == "" {
+			name = strings.TrimPrefix(filepath.Ext(path), ".")
+		}
+		format, err := encoder.ParseFormat(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w (use --from)", path, err)
+		}
+		input, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		label := ""
+		if len(paths) > 1 {
+			label = fmt.Sprintf("f%d_", i+1)
+		}
+		if err := load(string(input), format, label); err != nil {
+			return nil, nil, fmt.Errorf("decoding %s: %w", path, err)
+		}
+	}
+	return g, prefixes, nil
+}
+
+func relabelBlankNodes(t triple.Triple, prefix string) triple.Triple {
+	relabel := func(n triple.Node) triple.Node {
+		switch node := n.(type) {
+		case triple.BlankNode:
+			return triple.BlankNode{Value: prefix + node.Value}
+		case triple.TripleTerm:
+			return triple.TripleTerm{Triple: relabelBlankNodes(node.Triple, prefix)}
+		}
+		return n
+	}
+	return triple.Triple{Subject: relabel(t.Subject), Predicate: t.Predicate, Object: relabel(t.Object)}
+}
+
+// formatTable renders results as an aligned text table with terms in
+// Turtle syntax. An ASK result is printed as true or false.
+func formatTable(res *sparql.Results, prefixes map[string]string) string {
+	if res.Form == sparql.Ask {
+		return fmt.Sprintln(res.Boolean)
+	}
+
+	rows := make([][]string, 0, len(res.Bindings)+1)
+	header := make([]string, len(res.Vars))
+	for i, v := range res.Vars {
+		header[i] = "?" + v
+	}
+	rows = append(rows, header)
+	for _, b := range res.Bindings {
+		row := make([]string, len(res.Vars))
+		for i, v := range res.Vars {
+			if n, ok := b[v]; ok {
+				row[i] = encoder.FormatTurtleTerm(n, prefixes)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(res.Vars))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(row []string) {
+		for i, cell := range row {
+			if i > 0 {
+				b.WriteString(" | ")
+			}
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	for i, w := range widths {
+		if i > 0 {
+			b.WriteString("-+-")
+		}
+		b.WriteString(strings.Repeat("-", w))
+	}
+	b.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	if len(res.Bindings) == 1 {
+		b.WriteString("(1 row)\n")
+	} else {
+		fmt.Fprintf(&b, "(%d rows)\n", len(res.Bindings))
+	}
+	return b.String()
+}
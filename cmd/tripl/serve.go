@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/graphstore"
+	"github.com/DeDude/tripl/pkg/linkeddata"
+	"github.com/DeDude/tripl/pkg/sparql"
+	"github.com/DeDude/tripl/pkg/store"
+	"github.com/DeDude/tripl/pkg/tpf"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func serveCommand() {
+	serveFlags := flag.NewFlagSet("serve", flag.ExitOnError)
+
+	port := serveFlags.Int("port", 8080, "Port to listen on")
+	host := serveFlags.String("host", "localhost", "Host or address to listen on")
+	from := serveFlags.String("from", "", "Input format: ntriples, turtle, jsonld (default: from the file extension)")
+	readOnly := serveFlags.Bool("read-only", false, "Reject SPARQL updates and Graph Store writes")
+	cors := serveFlags.String("cors", "", "Origin of the web pages, or * for any, that may use the SPARQL and Graph Store endpoints")
+	tpfMode := serveFlags.Bool("tpf", false, "Serve Triple Pattern Fragments at / instead of the SPARQL endpoints")
+	pageSize := serveFlags.Int("page-size", tpf.DefaultPageSize, "Triples per fragment page with --tpf")
+	ldMode := serveFlags.Bool("ld", false, "Serve resources as Linked Data at / instead of the SPARQL endpoints")
+	base := serveFlags.String("base", "", "IRI the server root stands for with --ld (default: the server URL)")
+	inbound := serveFlags.Bool("inbound", false, "Include triples pointing at a resource in its description with --ld")
+	db := serveFlags.String("db", "", "Serve this store directory instead of reading files; updates are saved to it")
+
+	files := parseInterspersed(serveFlags, os.Args[2:])
+
+	if *tpfMode && *ldMode {
+		fmt.Fprintln(os.Stderr, "Error: --tpf and --ld cannot be combined")
+		os.Exit(1)
+	}
+
+	// Without files or a store the graph starts empty, to be filled by
+	// updates.
+	var g servedGraph = triple.NewGraph(nil)
+	prefixes := map[string]string{}
+	var st *storeGraph
+	switch {
+	case *db != "" && len(files) > 0:
+		fmt.Fprintln(os.Stderr, "Error: --db cannot be combined with data files")
+		os.Exit(1)
+	case *db != "":
+		opened, err := openStore(*db)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		st = &storeGraph{Store: opened}
+		g, prefixes = st, opened.Prefixes()
+	case len(files) > 0:
+		graph, filePrefixes, err := loadGraph(files, *from)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading data: %v\n", err)
+			os.Exit(1)
+		}
+		g, prefixes = graph, filePrefixes
+	}
+
+	addr := net.JoinHostPort(*host, strconv.Itoa(*port))
+	mux := http.NewServeMux()
+	lock := new(sync.RWMutex)
+	var endpoints string
+	switch {
+	case *ldMode:
+		// Like fragments, Linked Data documents are only read.
+		mux.Handle("/", linkeddata.NewHandler(g, linkeddata.HandlerOptions{Base: *base, Inbound: *inbound, Prefixes: prefixes, Lock: lock}))
+		endpoints = fmt.Sprintf("as Linked Data at http://%s/", addr)
+		if *base != "" {
+			endpoints += " for " + *base
+		}
+	case *tpfMode:
+		// Fragments are read-only, so nothing else needs the lock.
+		mux.Handle("/", tpf.NewHandler(g, tpf.HandlerOptions{PageSize: *pageSize, Prefixes: prefixes, Lock: lock}))
+		endpoints = fmt.Sprintf("as Triple Pattern Fragments at http://%s/", addr)
+	default:
+		// Both endpoints serve the same default graph, so they share its lock.
+		sparqlOpts := sparql.HandlerOptions{Prefixes: prefixes, ReadOnly: *readOnly, AllowOrigin: *cors, Lock: lock}
+		gspOpts := graphstore.HandlerOptions{Prefixes: prefixes, ReadOnly: *readOnly, AllowOrigin: *cors, Lock: lock}
+		if st != nil {
+			// Each update request is one transaction. The store only
+			// holds the default graph.
+			sparqlOpts.Commit, sparqlOpts.Rollback = st.commit, st.rollback
+			gspOpts.Commit = st.commit
+			gspOpts.DefaultOnly = true
+		}
+		mux.Handle("/sparql", sparql.NewHandler(g, sparqlOpts))
+		mux.Handle("/rdf-graphs", graphstore.NewHandler(g, gspOpts))
+		endpoints = fmt.Sprintf("at http://%s/sparql and http://%s/rdf-graphs", addr, addr)
+	}
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving %d triples %s\n", g.Len(), endpoints)
+	err := srv.ListenAndServe()
+	if st != nil {
+		if cerr := st.Close(); cerr != nil {
+			fmt.Fprintf(os.Stderr, "Error closing store: %v\n", cerr)
+		}
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// servedGraph is what the handlers need from the data: *triple.Graph or
+// *store.Store.
+type servedGraph interface {
+	sparql.Updatable
+	tpf.Source
+	Triples() []triple.Triple
+	Len() int
+}
+
+// storeGraph serves a store, collecting the changes of each request in a
+// transaction that the handlers commit or roll back when the request is
+// done. Handlers only write with their lock held, and readers cannot hold it
+// then, so only the writing request sees the open transaction.
+type storeGraph struct {
+	*store.Store
+	tx *store.Tx
+}
+
+func (g *storeGraph) Add(t triple.Triple) bool {
+	if g.tx == nil {
+		g.tx = g.Begin()
+	}
+	return g.tx.Add(t)
+}
+
+func (g *storeGraph) Remove(t triple.Triple) bool {
+	if g.tx == nil {
+		g.tx = g.Begin()
+	}
+	return g.tx.Remove(t)
+}
+
+func (g *storeGraph) reader() interface {
+	tpf.Source
+	triple.Matcher
+	Triples() []triple.Triple
+	Len() int
+} {
+	if g.tx != nil {
+		return g.tx
+	}
+	return g.Snapshot()
+}
+
+func (g *storeGraph) Match(s, p, o triple.Node) []triple.Triple { return g.reader().Match(s, p, o) }
+func (g *storeGraph) Count(s, p, o triple.Node) int             { return g.reader().Count(s, p, o) }
+func (g *storeGraph) Triples() []triple.Triple                  { return g.reader().Triples() }
+func (g *storeGraph) Len() int                                  { return g.reader().Len() }
+
+func (g *storeGraph) MatchPage(s, p, o triple.Node, offset, limit int) []triple.Triple {
+	return g.reader().MatchPage(s, p, o, offset, limit)
+}
+
+func (g *storeGraph) commit() error {
+	if g.tx == nil {
+		return nil
+	}
+	g.tx.Message = "update via tripl serve"
+	err := g.tx.Commit()
+	g.tx = nil
+	return err
+}
+
+func (g *storeGraph) rollback() {
+	if g.tx != nil {
+		g.tx.Rollback()
+		g.tx = nil
+	}
+}
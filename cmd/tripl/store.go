@@ -0,0 +1,292 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/store"
+)
+
+func loadCommand() {
+	loadFlags := flag.NewFlagSet("load", flag.ExitOnError)
+
+	from := loadFlags.String("from", "", "Input format: ntriples, turtle, jsonld (default: from the file extension, turtle for stdin)")
+	message := loadFlags.String("message", "", "Message recorded with the new version (default: the files loaded)")
+
+	args := parseInterspersed(loadFlags, os.Args[2:])
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: a store directory is required")
+		loadFlags.Usage()
+		os.Exit(1)
+	}
+	dir, files := args[0], args[1:]
+
+	g, prefixes, err := loadGraph(files, *from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading data: %v\n", err)
+		os.Exit(1)
+	}
+
+	st, err := store.Open(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Blank nodes from earlier loads must stay distinct from these.
+	label := ""
+	if st.Len() > 0 {
+		label = "l" + strconv.FormatInt(time.Now().UnixNano(), 36) + "_"
+	}
+	triples := g.Triples()
+	if label != "" {
+		for i, t := range triples {
+			triples[i] = relabelBlankNodes(t, label)
+		}
+	}
+	// One transaction, so a failed load leaves the store as it was.
+	tx := st.Begin()
+	tx.Message = *message
+	if tx.Message == "" {
+		tx.Message = "load " + strings.Join(files, " ")
+		if len(files) == 0 {
+			tx.Message = "load stdin"
+		}
+	}
+	added := tx.AddAll(triples)
+	if err := tx.Commit(); err != nil {
+		st.Close()
+		fmt.Fprintf(os.Stderr, "Error writing store: %v\n", err)
+		os.Exit(1)
+	}
+	if err := st.SetPrefixes(prefixes); err != nil {
+		st.Close()
+		fmt.Fprintf(os.Stderr, "Error writing store: %v\n", err)
+		os.Exit(1)
+	}
+	if err := st.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing store: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Loaded %d triples into %s (%d in total)\n", added, dir, st.Len())
+}
+
+func exportCommand() {
+	exportVersion("export", false)
+}
+
+func checkoutCommand() {
+	exportVersion("checkout", true)
+}
+
+// exportVersion writes a store, or with --at one of its versions, in an RDF
+// format. The checkout command requires --at.
+func exportVersion(name string, requireAt bool) {
+	exportFlags := flag.NewFlagSet(name, flag.ExitOnError)
+
+	atUsage := "Version to write: an ID such as v3, or a date or time for the version then (default: the latest)"
+	if requireAt {
+		atUsage = "Version to write: an ID such as v3, or a date or time for the version then (required)"
+	}
+	at := exportFlags.String("at", "", atUsage)
+	to := exportFlags.String("to", "turtle", "Output format: ntriples, turtle, jsonld")
+	outputPath := exportFlags.String("output", "", "File path to write output (default: stdout)")
+	force := exportFlags.Bool("force", false, "Allow overwriting existing output file")
+
+	args := parseInterspersed(exportFlags, os.Args[2:])
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: exactly one store directory is required")
+		exportFlags.Usage()
+		os.Exit(1)
+	}
+	if requireAt && *at == "" {
+		fmt.Fprintln(os.Stderr, "Error: --at is required")
+		exportFlags.Usage()
+		os.Exit(1)
+	}
+
+	format, err := encoder.ParseFormat(*to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	st, err := openStore(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+		os.Exit(1)
+	}
+	snap, err := snapshotAt(st, *at)
+	if err != nil {
+		st.Close()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	output, err := encoder.Encode(snap.Triples(), format, encoder.EncodeOptions{Prefixes: st.Prefixes(), Compact: true})
+	st.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding output: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeOutput(output, *outputPath, *force); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func logCommand() {
+	logFlags := flag.NewFlagSet("log", flag.ExitOnError)
+
+	limit := logFlags.Int("n", 0, "Show only the latest n versions (default: all)")
+
+	args := parseInterspersed(logFlags, os.Args[2:])
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: exactly one store directory is required")
+		logFlags.Usage()
+		os.Exit(1)
+	}
+
+	st, err := openStore(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+		os.Exit(1)
+	}
+	versions, err := st.Log()
+	st.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Newest first, as git log.
+	for i, n := len(versions)-1, 0; i >= 0 && (*limit <= 0 || n < *limit); i, n = i-1, n+1 {
+		v := versions[i]
+		fmt.Printf("v%-5d %s  %-15s %s\n", v.ID, v.Time.Local().Format("2006-01-02 15:04:05"), fmt.Sprintf("+%d -%d", v.Added, v.Removed), v.Message)
+	}
+}
+
+func diffCommand() {
+	diffFlags := flag.NewFlagSet("diff", flag.ExitOnError)
+
+	fromFlag := diffFlags.String("from", "", "Version to compare from: an ID such as v1, or a date or time (required)")
+	toFlag := diffFlags.String("to", "", "Version to compare to (default: the latest)")
+
+	args := parseInterspersed(diffFlags, os.Args[2:])
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: exactly one store directory is required")
+		diffFlags.Usage()
+		os.Exit(1)
+	}
+	if *fromFlag == "" {
+		fmt.Fprintln(os.Stderr, "Error: --from is required")
+		diffFlags.Usage()
+		os.Exit(1)
+	}
+
+	st, err := openStore(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+		os.Exit(1)
+	}
+	from, err := resolveVersion(st, *fromFlag)
+	if err != nil {
+		st.Close()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	to := st.Version()
+	if *toFlag != "" {
+		if to, err = resolveVersion(st, *toFlag); err != nil {
+			st.Close()
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	added, removed, err := st.Diff(from, to)
+	st.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, t := range removed {
+		fmt.Println("- " + encoder.EncodeNTriple(t))
+	}
+	for _, t := range added {
+		fmt.Println("+ " + encoder.EncodeNTriple(t))
+	}
+}
+
+// snapshotAt returns a view of the store at the version --at names, or the
+// latest version if at is empty.
+func snapshotAt(st *store.Store, at string) (*store.Snapshot, error) {
+	if at == "" {
+		return st.Snapshot(), nil
+	}
+	id, err := resolveVersion(st, at)
+	if err != nil {
+		return nil, err
+	}
+	return st.At(id)
+}
+
+// resolveVersion parses a version ID such as v3 or 3, or a date or time,
+// which stands for the last version committed by then: v0, the empty store,
+// if there was none.
+func resolveVersion(st *store.Store, s string) (uint64, error) {
+	if id, err := strconv.ParseUint(strings.TrimPrefix(s, "v"), 10, 64); err == nil {
+		if id > st.Version() {
+			return 0, fmt.Errorf("no version %s; the latest is v%d", s, st.Version())
+		}
+		return id, nil
+	}
+	var t time.Time
+	var err error
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err = time.ParseInLocation(layout, s, time.Local); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q: want an ID such as v3, or a date or time such as 2024-06-30", s)
+	}
+	if len(s) == len("2006-01-02") {
+		// A date means the end of that day.
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	versions, err := st.Log()
+	if err != nil {
+		return 0, err
+	}
+	var id uint64
+	for _, v := range versions {
+		if v.Time.After(t) {
+			break
+		}
+		id = v.ID
+	}
+	return id, nil
+}
+
+// openStore opens an existing store. Unlike store.Open, it does not create
+// a missing directory, so that a mistyped path is an error rather than an
+// empty store.
+func openStore(dir string) (*store.Store, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no store at %s (create one with tripl load)", dir)
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a store directory", dir)
+	}
+	return store.Open(dir)
+}
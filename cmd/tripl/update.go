@@ -0,0 +1,192 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/sparql"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func updateCommand() {
+	updateFlags := flag.NewFlagSet("update", flag.ExitOnError)
+
+	updatePath := updateFlags.String("update", "", "File containing the SPARQL Update request (required)")
+	write := updateFlags.Bool("w", false, "Write result to the data file instead of stdout")
+	from := updateFlags.String("from", "", "Data format: ntriples, turtle, jsonld (default: from the file extension)")
+	reformat := updateFlags.Bool("reformat", false, "Re-encode a Turtle file when the update cannot be applied in place, losing its comments and layout")
+
+	args := parseInterspersed(updateFlags, os.Args[2:])
+
+	if *updatePath == "" || len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: update requires --update and one data file")
+		updateFlags.Usage()
+		os.Exit(1)
+	}
+	path := args[0]
+
+	formatName := *from
+	if formatName == "" {
+		formatName = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	format, err := encoder.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v (use --from)\n", err)
+		os.Exit(1)
+	}
+
+	request, err := os.ReadFile(*updatePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading update: %v\n", err)
+		os.Exit(1)
+	}
+
+	input, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+	var doc *encoder.TurtleDocument
+	var triples []triple.Triple
+	var prefixes map[string]string
+	if format == encoder.Turtle {
+		doc, err = encoder.ParseTurtleDocument(string(input), encoder.DecodeOptions{})
+		if err == nil {
+			triples, prefixes = doc.Triples(), doc.Prefixes()
+		}
+	} else {
+		triples, prefixes, err = encoder.Decode(string(input), format, encoder.DecodeOptions{})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error decoding %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	g := triple.NewGraph(triples)
+	err = sparql.ExecUpdate(g, string(request), sparql.UpdateOptions{
+		ParseOptions: sparql.ParseOptions{Prefixes: prefixes},
+		Load:         fileLoader(filepath.Dir(path)),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error applying update: %v\n", err)
+		os.Exit(1)
+	}
+
+	output, edited := "", false
+	if doc != nil {
+		if err := editTurtle(doc, triples, g); err == nil {
+			output, edited = doc.String(), true
+		} else if !*reformat {
+			fmt.Fprintf(os.Stderr, "Error updating %s in place: %v (use --reformat to re-encode it, losing its comments and layout)\n", path, err)
+			os.Exit(1)
+		}
+	}
+	if !edited {
+		output, err = encoder.Encode(g.Triples(), format, encoder.EncodeOptions{Prefixes: prefixes, Compact: true})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding output: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if !*write {
+		fmt.Print(output)
+		return
+	}
+	if output == string(input) {
+		return
+	}
+	if err := replaceFile(path, output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// editTurtle adds the triples g gained over before to doc and removes the
+// ones it no longer has, keeping the rest of the document as written. It
+// fails if a change cannot be made in place, such as removing a triple
+// written inside [ ].
+func editTurtle(doc *encoder.TurtleDocument, before []triple.Triple, g *triple.Graph) error {
+	// Additions go first, so a changed value joins its old one before that
+	// is removed and the statement keeps its layout.
+	old := triple.NewGraph(before)
+	for _, t := range g.Triples() {
+		if old.Has(t) {
+			continue
+		}
+		if _, err := doc.Add(t); err != nil {
+			return fmt.Errorf("cannot add %s in place: %w", encoder.EncodeNTriple(t), err)
+		}
+	}
+	for _, t := range old.Triples() {
+		if g.Has(t) {
+			continue
+		}
+		removed, err := doc.Remove(t)
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return fmt.Errorf("cannot remove %s in place", encoder.EncodeNTriple(t))
+		}
+	}
+
+	after := triple.NewGraph(doc.Triples())
+	for _, t := range g.Triples() {
+		if !after.Has(t) {
+			return fmt.Errorf("cannot keep %s when editing in place", encoder.EncodeNTriple(t))
+		}
+	}
+	if after.Len() != g.Len() {
+		return fmt.Errorf("editing in place does not give the updated graph")
+	}
+	return nil
+}
+
+// fileLoader returns a LOAD handler for local files. Relative IRIs are
+// resolved against dir; file: IRIs name absolute paths. Blank nodes of each
+// loaded document get their own labels.
+func fileLoader(dir string) func(iri string) ([]triple.Triple, error) {
+	loads := 0
+	return func(iri string) ([]triple.Triple, error) {
+		u, err := url.Parse(iri)
+		if err != nil {
+			return nil, err
+		}
+
+		var path string
+		switch u.Scheme {
+		case "file":
+			path = u.Path
+		case "":
+			path = filepath.Join(dir, filepath.FromSlash(u.Path))
+		default:
+			return nil, fmt.Errorf("only local files can be loaded")
+		}
+
+		format, err := encoder.ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+		if err != nil {
+			return nil, err
+		}
+		input, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		triples, _, err := encoder.Decode(string(input), format, encoder.DecodeOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		loads++
+		label := fmt.Sprintf("load%d_", loads)
+		for i, t := range triples {
+			triples[i] = relabelBlankNodes(t, label)
+		}
+		return triples, nil
+	}
+}
@@ -0,0 +1,121 @@
+// Package httputil holds the HTTP helpers shared by tripl's handlers.
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Error writes a plain-text error response.
+func Error(w http.ResponseWriter, status int, format string, args ...any) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, format+"\n", args...)
+}
+
+// Origin returns the scheme and host the client used.
+func Origin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// RequestURL returns the URL the client used, without its query.
+func RequestURL(r *http.Request) string {
+	return Origin(r) + r.URL.EscapedPath()
+}
+
+// AllowOrigin lets pages from origin, or from any origin for "*", read the
+// response. An empty origin sends no CORS header, so only pages from the
+// server's own origin can.
+func AllowOrigin(w http.ResponseWriter, origin string) {
+	if origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+}
+
+// CrossOrigin reports whether r comes from a page on another origin than the
+// server's that allowed, as passed to AllowOrigin, does not admit. Browsers
+// send Origin with every POST, PUT and DELETE, even simple form posts that
+// CORS lets through without asking; other clients send none.
+func CrossOrigin(r *http.Request, allowed string) bool {
+	origin := r.Header.Get("Origin")
+	return origin != "" && origin != Origin(r) && allowed != "*" && origin != allowed
+}
+
+// ContentType adds a charset to textual media types.
+func ContentType(mediaType string) string {
+	if strings.HasPrefix(mediaType, "text/") {
+		return mediaType + "; charset=utf-8"
+	}
+	return mediaType
+}
+
+// Negotiate picks the offer the Accept header value prefers, breaking ties
+// by the order of offers. An empty header accepts the first offer. It
+// reports false when the header accepts none of them.
+func Negotiate(accept string, offers []string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	type mediaRange struct {
+		typ, subtype string
+		q            float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(fields[0])), "/")
+		if !ok {
+			continue
+		}
+		mr := mediaRange{typ: typ, subtype: subtype, q: 1}
+		for _, param := range fields[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					mr.q = q
+				}
+			}
+		}
+		ranges = append(ranges, mr)
+	}
+	// More specific ranges take precedence over wildcards, so that
+	// "*/*, text/csv;q=0" excludes CSV.
+	specificity := func(mr mediaRange) int {
+		switch {
+		case mr.typ == "*":
+			return 0
+		case mr.subtype == "*":
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return specificity(ranges[i]) > specificity(ranges[j])
+	})
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		typ, subtype, _ := strings.Cut(offer, "/")
+		for _, mr := range ranges {
+			if (mr.typ == "*" || mr.typ == typ) && (mr.subtype == "*" || mr.subtype == subtype) {
+				if mr.q > bestQ {
+					best, bestQ = offer, mr.q
+				}
+				break
+			}
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,23 @@
+package httputil
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"text/turtle", "application/n-triples", "application/ld+json"}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "text/turtle"},
+		{"application/ld+json", "application/ld+json"},
+		{"application/*", "application/n-triples"},
+		{"text/turtle;q=0.5, application/ld+json", "application/ld+json"},
+		{"*/*;q=0.1, application/n-triples;q=0.2", "application/n-triples"},
+		{"text/html", ""},
+	}
+	for _, tt := range tests {
+		if got, _ := Negotiate(tt.accept, offers); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,9 @@ package encoder
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/DeDude/tripl/pkg/triple"
 )
 
@@ -29,9 +32,9 @@ func formatNode(n triple.Node) string {
 	case triple.IRI:
 		return fmt.Sprintf("<%s>", node.Value)
 	case triple.Literal:
-		result := fmt.Sprintf(`"%s"`, node.Value)
+		result := fmt.Sprintf(`"%s"`, escapeString(node.Value))
 		if node.Language != "" {
-			result += "@" + node.Language
+			result += "@" + langDir(node)
 		}
 		if node.Datatype != "" {
 			result += "^^<" + node.Datatype + ">"
@@ -39,6 +42,8 @@ func formatNode(n triple.Node) string {
 		return result
 	case triple.BlankNode:
 		return "_:" + node.Value
+	case triple.TripleTerm:
+		return fmt.Sprintf("<<( %s %s %s )>>", formatNode(node.Triple.Subject), formatNode(node.Triple.Predicate), formatNode(node.Triple.Object))
 	default:
 		return ""
 	}
@@ -49,10 +54,119 @@ func nodeKey(n triple.Node) string {
 	case triple.IRI:
 		return "iri:" + node.Value
 	case triple.Literal:
-		return fmt.Sprintf("lit:%s:%s:%s", node.Value, node.Language, node.Datatype)
+		return fmt.Sprintf("lit:%s:%s:%s:%s", node.Value, node.Language, node.Direction, node.Datatype)
 	case triple.BlankNode:
 		return "blank:" + node.Value
+	case triple.TripleTerm:
+		return "triple:" + formatNode(node)
 	default:
 		return ""
 	}
 }
+
+// langDir returns the language tag of lit with its base direction, as in "ar--rtl".
+func langDir(lit triple.Literal) string {
+	if lit.Direction != "" {
+		return lit.Language + "--" + lit.Direction
+	}
+	return lit.Language
+}
+
+// splitLangDir splits an "ar--rtl" tag into language and direction.
+func splitLangDir(tag string) (string, string) {
+	language, direction, _ := strings.Cut(tag, "--")
+	return language, direction
+}
+
+// mapTerms applies fn to every term of t, descending into triple terms.
+func mapTerms(t triple.Triple, fn func(triple.Node) triple.Node) triple.Triple {
+	apply := func(n triple.Node) triple.Node {
+		if term, ok := n.(triple.TripleTerm); ok {
+			return triple.TripleTerm{Triple: mapTerms(term.Triple, fn)}
+		}
+		return fn(n)
+	}
+	return triple.Triple{
+		Subject:   apply(t.Subject),
+		Predicate: apply(t.Predicate),
+		Object:    apply(t.Object),
+	}
+}
+
+func escapeString(s string) string {
+	var b strings.Builder
+	for _, ch := range s {
+		switch ch {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(ch)
+		}
+	}
+	return b.String()
+}
+
+func unescapeString(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("dangling escape")
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'b':
+			b.WriteByte('\b')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case '"', '\'', '\\':
+			b.WriteByte(s[i])
+		case 'u', 'U':
+			size := 4
+			if s[i] == 'U' {
+				size = 8
+			}
+			if i+size >= len(s) {
+				return "", fmt.Errorf("short unicode escape")
+			}
+			code, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i-1:i+1+size])
+			}
+			b.WriteRune(rune(code))
+			i += size
+		default:
+			return "", fmt.Errorf("invalid escape \\%c", s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+func unescapeIRI(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	if strings.Contains(strings.NewReplacer(`\u`, "", `\U`, "").Replace(s), `\`) {
+		return "", fmt.Errorf("invalid escape in IRI")
+	}
+	return unescapeString(s)
+}
@@ -0,0 +1,125 @@
+package encoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+)
+
+func directionalTriple() triple.Triple {
+	return triple.Triple{
+		Subject:   triple.IRI{Value: "http://example.org/book1"},
+		Predicate: triple.IRI{Value: "http://example.org/title"},
+		Object:    triple.Literal{Value: "كتاب", Language: "ar", Direction: "rtl"},
+	}
+}
+
+func TestDirectionRoundTrip(t *testing.T) {
+	expected := directionalTriple()
+
+	for _, format := range []Format{NTriples, Turtle, JSONLD} {
+		for _, compact := range []bool{false, true} {
+			encoded, err := Encode([]triple.Triple{expected}, format, EncodeOptions{Compact: compact, Strict: true})
+			if err != nil {
+				t.Fatalf("%s: Encode() error = %v", format, err)
+			}
+
+			decoded, _, err := Decode(encoded, format, DecodeOptions{Strict: true})
+			if err != nil {
+				t.Fatalf("%s: Decode() error = %v\n%s", format, err, encoded)
+			}
+			if len(decoded) != 1 || decoded[0] != expected {
+				t.Errorf("%s compact=%v: round trip = %+v, want %+v", format, compact, decoded, expected)
+			}
+		}
+	}
+
+	nt, _ := Encode([]triple.Triple{expected}, NTriples, EncodeOptions{})
+	if !strings.Contains(nt, `"كتاب"@ar--rtl`) {
+		t.Errorf("N-Triples output %q does not use @ar--rtl", nt)
+	}
+}
+
+func TestDecodeJSONLDDirection(t *testing.T) {
+	input := `{"@id": "http://example.org/book1",
+  "http://example.org/title": {"@value": "كتاب", "@language": "AR", "@direction": "rtl"}}`
+
+	subject := triple.IRI{Value: "http://example.org/book1"}
+	title := triple.IRI{Value: "http://example.org/title"}
+
+	tests := []struct {
+		name     string
+		mode     DirectionMode
+		expected []triple.Triple
+	}{
+		{
+			name:     "native",
+			mode:     DirectionNative,
+			expected: []triple.Triple{{Subject: subject, Predicate: title, Object: triple.Literal{Value: "كتاب", Language: "AR", Direction: "rtl"}}},
+		},
+		{
+			name: "i18n datatype",
+			mode: DirectionI18nDatatype,
+			expected: []triple.Triple{
+				{Subject: subject, Predicate: title, Object: triple.Literal{Value: "كتاب", Datatype: "https://www.w3.org/ns/i18n#ar_rtl"}},
+			},
+		},
+		{
+			name: "compound literal",
+			mode: DirectionCompoundLiteral,
+			expected: []triple.Triple{
+				{Subject: triple.BlankNode{Value: "jld1"}, Predicate: rdf.Value, Object: triple.Literal{Value: "كتاب"}},
+				{Subject: triple.BlankNode{Value: "jld1"}, Predicate: rdf.Language, Object: triple.Literal{Value: "ar"}},
+				{Subject: triple.BlankNode{Value: "jld1"}, Predicate: rdf.Direction, Object: triple.Literal{Value: "rtl"}},
+				{Subject: subject, Predicate: title, Object: triple.BlankNode{Value: "jld1"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triples, _, err := Decode(input, JSONLD, DecodeOptions{RDFDirection: tt.mode, Strict: true})
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if len(triples) != len(tt.expected) {
+				t.Fatalf("Decode() got %d triples, want %d: %+v", len(triples), len(tt.expected), triples)
+			}
+			for i := range tt.expected {
+				if triples[i] != tt.expected[i] {
+					t.Errorf("triple[%d] = %+v, want %+v", i, triples[i], tt.expected[i])
+				}
+			}
+
+			encoded, err := Encode(triples, JSONLD, EncodeOptions{RDFDirection: tt.mode})
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if !strings.Contains(encoded, `"@direction": "rtl"`) || strings.Contains(encoded, "rdf-syntax-ns#value") {
+				t.Errorf("Encode() did not fold direction into @direction:\n%s", encoded)
+			}
+		})
+	}
+}
+
+func TestDecodeDirectionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format Format
+	}{
+		{"unknown direction", `<http://example.org/a> <http://example.org/b> "x"@ar--up .`, NTriples},
+		{"turtle unknown direction", `<http://example.org/a> <http://example.org/b> "x"@ar--down .`, Turtle},
+		{"explicit dirLangString", `<http://example.org/a> <http://example.org/b> "x"^^<http://www.w3.org/1999/02/22-rdf-syntax-ns#dirLangString> .`, NTriples},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Decode(tt.input, tt.format, DecodeOptions{Strict: true}); err == nil {
+				t.Error("Decode() expected error")
+			}
+		})
+	}
+}
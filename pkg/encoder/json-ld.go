@@ -2,31 +2,141 @@ package encoder
 
 import (
 	"encoding/json"
-	"github.com/DeDude/tripl/pkg/triple"
 	"strings"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
 )
 
 func EncodeJSONLD(triples []triple.Triple) (string, error) {
-	grouped := groupBySubject(triples)
+	return encodeJSONLD(triples, EncodeOptions{})
+}
 
-	var result []map[string]interface{}
+func EncodeJSONLDCompact(triples []triple.Triple, context map[string]string) (string, error) {
+	return encodeJSONLD(triples, EncodeOptions{Prefixes: context, Compact: true})
+}
 
-	for _, group := range grouped {
-		obj := make(map[string]interface{})
+func encodeJSONLD(triples []triple.Triple, opts EncodeOptions) (string, error) {
+	triples, err := prepareTriples(triples, opts)
+	if err != nil {
+		return "", err
+	}
 
-		subjectIRI, ok := group.subject.(triple.IRI)
+	switch opts.RDFDirection {
+	case DirectionI18nDatatype:
+		triples = foldI18nDatatypes(triples)
+	case DirectionCompoundLiteral:
+		triples = foldCompoundLiterals(triples)
+	}
 
-		if !ok {
+	lists, triples := collectLists(triples)
+
+	var result interface{}
+	if opts.Compact {
+		result = compactJSONLD(triples, lists, opts)
+	} else {
+		result = expandedJSONLD(triples, lists)
+	}
+
+	indent := opts.Indent
+	if indent <= 0 {
+		indent = defaultJSONLDIndent
+	}
+
+	bytes, err := json.MarshalIndent(result, "", strings.Repeat(" ", indent))
+	if err != nil {
+		return "", err
+	}
+
+	return string(bytes), nil
+}
+
+const defaultJSONLDIndent = 2
+
+const i18nNamespace = "https://www.w3.org/ns/i18n#"
+
+// foldI18nDatatypes turns literals typed with an i18n datatype such as
+// i18n:ar_rtl back into directional language strings.
+func foldI18nDatatypes(triples []triple.Triple) []triple.Triple {
+	result := make([]triple.Triple, len(triples))
+	for i, t := range triples {
+		result[i] = t
+		lit, ok := t.Object.(triple.Literal)
+		if !ok || !strings.HasPrefix(lit.Datatype, i18nNamespace) {
+			continue
+		}
+		language, direction, found := strings.Cut(strings.TrimPrefix(lit.Datatype, i18nNamespace), "_")
+		if !found || (direction != "ltr" && direction != "rtl") {
+			continue
+		}
+		result[i].Object = triple.Literal{Value: lit.Value, Language: language, Direction: direction}
+	}
+	return result
+}
+
+// foldCompoundLiterals replaces blank nodes that only carry rdf:value,
+// rdf:language and rdf:direction with the directional string they describe.
+func foldCompoundLiterals(triples []triple.Triple) []triple.Triple {
+	parts := make(map[triple.Node]map[triple.Node][]triple.Node)
+	for _, t := range triples {
+		if _, ok := t.Subject.(triple.BlankNode); !ok {
+			continue
+		}
+		if parts[t.Subject] == nil {
+			parts[t.Subject] = make(map[triple.Node][]triple.Node)
+		}
+		parts[t.Subject][t.Predicate] = append(parts[t.Subject][t.Predicate], t.Object)
+	}
+
+	literals := make(map[triple.Node]triple.Literal)
+	for node, props := range parts {
+		value, ok := singleString(props[rdf.Value])
+		direction, hasDirection := singleString(props[rdf.Direction])
+		language, hasLanguage := singleString(props[rdf.Language])
+		if !ok || !hasDirection || (!hasLanguage && props[rdf.Language] != nil) {
+			continue
+		}
+		if len(props) != 2+len(props[rdf.Language]) {
+			continue
+		}
+		literals[node] = triple.Literal{Value: value, Language: language, Direction: direction}
+	}
+
+	result := make([]triple.Triple, 0, len(triples))
+	for _, t := range triples {
+		if _, folded := literals[t.Subject]; folded {
 			continue
 		}
+		if lit, folded := literals[t.Object]; folded {
+			t.Object = lit
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+func singleString(nodes []triple.Node) (string, bool) {
+	if len(nodes) != 1 {
+		return "", false
+	}
+	lit, ok := nodes[0].(triple.Literal)
+	return lit.Value, ok && lit.Language == "" && lit.Datatype == ""
+}
 
-		obj["@id"] = subjectIRI.Value
+func expandedJSONLD(triples []triple.Triple, lists map[triple.Node][]triple.Node) []map[string]interface{} {
+	grouped := groupBySubject(triples)
+
+	var result []map[string]interface{}
+
+	for _, group := range grouped {
+		obj := make(map[string]interface{})
+		obj["@id"] = nodeToJSONLD(group.subject, nil)["@id"]
 
 		for predicate, objects := range group.properties {
 			var values []map[string]interface{}
 
 			for _, objNode := range objects {
-				value := nodeToJSONLD(objNode)
+				value := nodeToJSONLD(objNode, lists)
 				values = append(values, value)
 			}
 
@@ -36,12 +146,7 @@ func EncodeJSONLD(triples []triple.Triple) (string, error) {
 		result = append(result, obj)
 	}
 
-	bytes, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return "", err
-	}
-
-	return string(bytes), nil
+	return result
 }
 
 type subjectProperties struct {
@@ -82,9 +187,18 @@ func groupBySubject(triples []triple.Triple) []subjectProperties {
 	return result
 }
 
-func nodeToJSONLD(n triple.Node) map[string]interface{} {
+func nodeToJSONLD(n triple.Node, lists map[triple.Node][]triple.Node) map[string]interface{} {
 	result := make(map[string]interface{})
 
+	if items, ok := lists[n]; ok {
+		values := make([]map[string]interface{}, len(items))
+		for i, item := range items {
+			values[i] = nodeToJSONLD(item, lists)
+		}
+		result["@list"] = values
+		return result
+	}
+
 	switch node := n.(type) {
 	case triple.IRI:
 		result["@id"] = node.Value
@@ -93,37 +207,45 @@ func nodeToJSONLD(n triple.Node) map[string]interface{} {
 		if node.Language != "" {
 			result["@language"] = node.Language
 		}
+		if node.Direction != "" {
+			result["@direction"] = node.Direction
+		}
 		if node.Datatype != "" {
 			result["@type"] = node.Datatype
 		}
 	case triple.BlankNode:
 		result["@id"] = "_:" + node.Value
+	case triple.TripleTerm:
+		t := node.Triple
+		embedded := nodeToJSONLD(t.Subject, nil)
+		embedded[t.Predicate.(triple.IRI).Value] = []map[string]interface{}{nodeToJSONLD(t.Object, nil)}
+		result["@id"] = embedded
 	}
 
 	return result
 }
 
-func EncodeJSONLDCompact(triples []triple.Triple, context map[string]string) (string, error) {
+func compactJSONLD(triples []triple.Triple, lists map[triple.Node][]triple.Node, opts EncodeOptions) map[string]interface{} {
 	grouped := groupBySubject(triples)
+	context := opts.Prefixes
 
 	var graph []map[string]interface{}
 
 	for _, group := range grouped {
 		obj := make(map[string]interface{})
 
-		subjectIRI, ok := group.subject.(triple.IRI)
-
-		if !ok {
-			continue
+		switch subject := group.subject.(type) {
+		case triple.IRI:
+			obj["@id"] = relativizeIRI(opts.Base, shortenURI(subject.Value, context))
+		case triple.BlankNode:
+			obj["@id"] = "_:" + subject.Value
 		}
 
-		obj["@id"] = shortenURI(subjectIRI.Value, context)
-
 		for predicate, objects := range group.properties {
 			shortPred := shortenURI(predicate, context)
 
 			if len(objects) == 1 {
-				if lit, ok := objects[0].(triple.Literal); ok && lit.Language == "" && lit.Datatype == "" {
+				if lit, ok := objects[0].(triple.Literal); ok && lit.Language == "" && lit.Datatype == "" && lit.Direction == "" {
 					obj[shortPred] = lit.Value
 					continue
 				}
@@ -132,7 +254,7 @@ func EncodeJSONLDCompact(triples []triple.Triple, context map[string]string) (st
 			var values []interface{}
 
 			for _, objNode := range objects {
-				value := nodeToJSONLDCompact(objNode, context)
+				value := nodeToJSONLDCompact(objNode, lists, context, opts.Base)
 				values = append(values, value)
 			}
 
@@ -146,48 +268,60 @@ func EncodeJSONLDCompact(triples []triple.Triple, context map[string]string) (st
 		graph = append(graph, obj)
 	}
 
-	result := map[string]interface{}{
-		"@context": context,
-		"@graph":   graph,
+	jsonContext := make(map[string]interface{}, len(context)+1)
+	for prefix, namespace := range context {
+		jsonContext[prefix] = namespace
 	}
-
-	bytes, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return "", err
+	if opts.Base != "" {
+		jsonContext["@base"] = opts.Base
 	}
 
-	return string(bytes), nil
+	return map[string]interface{}{
+		"@context": jsonContext,
+		"@graph":   graph,
+	}
 }
 
 func shortenURI(uri string, context map[string]string) string {
-	for prefix, namespace := range context {
-		if strings.HasPrefix(uri, namespace) {
-			localPart := strings.TrimPrefix(uri, namespace)
-			return prefix + ":" + localPart
+	return NewPrefixResolver(context).Shorten(uri)
+}
+
+func nodeToJSONLDCompact(n triple.Node, lists map[triple.Node][]triple.Node, context map[string]string, base string) interface{} {
+	if items, ok := lists[n]; ok {
+		values := make([]interface{}, len(items))
+		for i, item := range items {
+			values[i] = nodeToJSONLDCompact(item, lists, context, base)
 		}
+		return map[string]interface{}{"@list": values}
 	}
-	return uri
-}
 
-func nodeToJSONLDCompact(n triple.Node, context map[string]string) interface{} {
 	switch node := n.(type) {
 	case triple.IRI:
 		shortened := shortenURI(node.Value, context)
-		return map[string]interface{}{"@id": shortened}
+		return map[string]interface{}{"@id": relativizeIRI(base, shortened)}
 	case triple.Literal:
-		if node.Language == "" && node.Datatype == "" {
+		if node.Language == "" && node.Datatype == "" && node.Direction == "" {
 			return node.Value
 		}
 		result := map[string]interface{}{"@value": node.Value}
 		if node.Language != "" {
 			result["@language"] = node.Language
 		}
+		if node.Direction != "" {
+			result["@direction"] = node.Direction
+		}
 		if node.Datatype != "" {
 			result["@type"] = node.Datatype
 		}
 		return result
 	case triple.BlankNode:
 		return map[string]interface{}{"@id": "_:" + node.Value}
+	case triple.TripleTerm:
+		t := node.Triple
+		embedded := nodeToJSONLDCompact(t.Subject, nil, context, base).(map[string]interface{})
+		predicate := shortenURI(t.Predicate.(triple.IRI).Value, context)
+		embedded[predicate] = nodeToJSONLDCompact(t.Object, nil, context, base)
+		return map[string]interface{}{"@id": embedded}
 	}
 	return nil
 }
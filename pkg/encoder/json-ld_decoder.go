@@ -2,80 +2,798 @@ package encoder
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+	"github.com/DeDude/tripl/pkg/xsd"
 )
 
+type jsonLDDecoder struct {
+	ctx *jsonLDContext
+	// initial is the context a null @context returns to.
+	initial   *jsonLDContext
+	prefixes  map[string]string
+	strict    bool
+	direction DirectionMode
+	// used holds the blank node labels in the document, which generated
+	// labels must not take.
+	used    map[string]bool
+	blanks  int
+	triples []triple.Triple
+}
+
+// jsonLDContext is the active context: what terms and relative IRIs mean at
+// one point in a document.
+type jsonLDContext struct {
+	base     string
+	vocab    string
+	language string
+	terms    map[string]jsonLDTerm
+}
+
+// jsonLDTerm is a term definition.
+type jsonLDTerm struct {
+	// id is the IRI or keyword the term stands for, or empty if the term
+	// is defined as null, in which case its properties are dropped.
+	id string
+	// typ coerces string values: "@id" and "@vocab" make them IRIs, and
+	// an IRI makes them literals of that datatype.
+	typ string
+	// container is "@list", "@language" or empty.
+	container string
+	// language, if not nil, replaces the default language for the
+	// term's string values; empty means none.
+	language *string
+	// prefix reports whether the term is a prefix for compact IRIs in
+	// output, as a simple definition ending in a delimiter is.
+	prefix bool
+}
+
+func (c *jsonLDContext) clone() *jsonLDContext {
+	clone := *c
+	clone.terms = make(map[string]jsonLDTerm, len(c.terms))
+	for k, v := range c.terms {
+		clone.terms[k] = v
+	}
+	return &clone
+}
+
 func DecodeJSONLD(input string) ([]triple.Triple, error) {
-	var data []map[string]interface{}
+	triples, _, err := decodeJSONLD(input, DecodeOptions{})
+	return triples, err
+}
 
-	if err := json.Unmarshal([]byte(input), &data); err != nil {
-		return nil, err
+// decodeJSONLD reads JSON-LD with inline contexts: prefixes and term
+// definitions with @id, @type coercion, @language, @prefix, and @list and
+// @language containers, plus @base, @vocab and @language. Features beyond
+// those, such as remote contexts and other containers, are an error rather
+// than a source of wrong triples. Properties that do not expand to an
+// absolute IRI are dropped, as JSON-LD requires, or rejected when strict.
+func decodeJSONLD(input string, opts DecodeOptions) ([]triple.Triple, map[string]string, error) {
+	decoder := json.NewDecoder(strings.NewReader(input))
+	decoder.UseNumber()
+
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, nil, err
+	}
+
+	dec := &jsonLDDecoder{
+		initial:   &jsonLDContext{base: opts.Base, terms: make(map[string]jsonLDTerm, len(opts.Prefixes))},
+		prefixes:  make(map[string]string, len(opts.Prefixes)),
+		strict:    opts.Strict,
+		direction: opts.RDFDirection,
+		used:      make(map[string]bool),
+	}
+	for k, v := range opts.Prefixes {
+		dec.initial.terms[k] = jsonLDTerm{id: v, prefix: true}
+		dec.prefixes[k] = v
 	}
+	dec.ctx = dec.initial.clone()
+	collectBlankLabels(data, dec.used)
 
-	var triples []triple.Triple
+	var nodes []interface{}
+
+	switch doc := data.(type) {
+	case []interface{}:
+		nodes = doc
+	case map[string]interface{}:
+		if ctx, ok := doc["@context"]; ok {
+			if err := dec.readContext(ctx); err != nil {
+				return nil, nil, err
+			}
+		}
+		doc = dec.unalias(withoutKey(doc, "@context"))
+		if graph, ok := doc["@graph"]; ok {
+			nodes = asList(graph)
+		} else {
+			nodes = []interface{}{doc}
+		}
+	default:
+		return nil, nil, fmt.Errorf("JSON-LD document must be an object or array")
+	}
 
-	for _, obj := range data {
-		subjectID, ok := obj["@id"].(string)
+	for _, n := range nodes {
+		obj, ok := n.(map[string]interface{})
 		if !ok {
+			if dec.strict {
+				return nil, nil, fmt.Errorf("expected node object, got %T", n)
+			}
+			continue
+		}
+		if _, err := dec.readNode(obj); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if dec.strict {
+		for _, t := range dec.triples {
+			if err := t.Validate(); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", strings.TrimSpace(EncodeNTriple(t)), err)
+			}
+		}
+	}
+
+	return dec.triples, dec.prefixes, nil
+}
+
+func asList(v interface{}) []interface{} {
+	if list, ok := v.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{v}
+}
+
+// collectBlankLabels adds the label of every blank node identifier in a
+// JSON-LD document to used.
+func collectBlankLabels(v interface{}, used map[string]bool) {
+	switch value := v.(type) {
+	case string:
+		if strings.HasPrefix(value, "_:") {
+			used[value[2:]] = true
+		}
+	case []interface{}:
+		for _, item := range value {
+			collectBlankLabels(item, used)
+		}
+	case map[string]interface{}:
+		for k, item := range value {
+			collectBlankLabels(k, used)
+			collectBlankLabels(item, used)
+		}
+	}
+}
+
+func (dec *jsonLDDecoder) readContext(ctx interface{}) error {
+	switch c := ctx.(type) {
+	case nil:
+		// A null context drops the definitions made so far.
+		dec.ctx = dec.initial.clone()
+	case string:
+		return fmt.Errorf("remote @context %q is not supported", c)
+	case []interface{}:
+		for _, item := range c {
+			if err := dec.readContext(item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		return dec.readContextObject(c)
+	default:
+		return fmt.Errorf("invalid @context value %T", ctx)
+	}
+	return nil
+}
+
+func (dec *jsonLDDecoder) readContextObject(c map[string]interface{}) error {
+	if v, ok := c["@base"]; ok {
+		switch base := v.(type) {
+		case nil:
+			dec.ctx.base = ""
+		case string:
+			dec.ctx.base = resolveIRI(dec.ctx.base, base)
+		default:
+			return fmt.Errorf("invalid @base value %T", v)
+		}
+	}
+	if v, ok := c["@vocab"]; ok {
+		switch vocab := v.(type) {
+		case nil:
+			dec.ctx.vocab = ""
+		case string:
+			dec.ctx.vocab = resolveIRI(dec.ctx.base, dec.expandIRI(vocab, true))
+		default:
+			return fmt.Errorf("invalid @vocab value %T", v)
+		}
+	}
+	if v, ok := c["@language"]; ok {
+		switch lang := v.(type) {
+		case nil:
+			dec.ctx.language = ""
+		case string:
+			dec.ctx.language = lang
+		default:
+			return fmt.Errorf("invalid @language value %T", v)
+		}
+	}
+
+	defined := make(map[string]bool)
+	for _, key := range sortedKeys(c) {
+		switch key {
+		case "@base", "@vocab", "@language", "@version", "@protected", "@propagate":
+			// @protected and @propagate only restrict later contexts.
 			continue
 		}
+		if strings.HasPrefix(key, "@") {
+			return fmt.Errorf("@context keyword %s is not supported", key)
+		}
+		if err := dec.defineTerm(c, key, defined); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defineTerm adds the definition of term in the local context c to the
+// active context, first defining a term it uses as a prefix. defined tracks
+// the terms of c already done, and in progress, to catch cycles.
+func (dec *jsonLDDecoder) defineTerm(c map[string]interface{}, term string, defined map[string]bool) error {
+	if done, ok := defined[term]; ok {
+		if !done {
+			return fmt.Errorf("@context term %q is defined in terms of itself", term)
+		}
+		return nil
+	}
+	defined[term] = false
+	defer func() { defined[term] = true }()
 
-		subject := triple.IRI{Value: subjectID}
+	var def jsonLDTerm
+	var id interface{} = term
+	switch v := c[term].(type) {
+	case nil:
+		dec.setTerm(term, def)
+		return nil
+	case string:
+		id = v
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			switch x := v[k]; k {
+			case "@id":
+				id = x
+			case "@type":
+				typ, ok := x.(string)
+				if !ok {
+					return fmt.Errorf("@context term %q: @type must be a string", term)
+				}
+				switch typ {
+				case "@id", "@vocab":
+					def.typ = typ
+				case "@json", "@none":
+					return fmt.Errorf("@context term %q: @type %s is not supported", term, typ)
+				default:
+					def.typ = dec.expandIRI(typ, true)
+				}
+			case "@container":
+				for _, item := range asList(x) {
+					switch item {
+					case "@set":
+					case "@list", "@language":
+						def.container = item.(string)
+					default:
+						return fmt.Errorf("@context term %q: @container %v is not supported", term, item)
+					}
+				}
+			case "@language":
+				lang, ok := x.(string)
+				if x != nil && !ok {
+					return fmt.Errorf("@context term %q: @language must be a string or null", term)
+				}
+				def.language = &lang
+			case "@prefix":
+				prefix, ok := x.(bool)
+				if !ok {
+					return fmt.Errorf("@context term %q: @prefix must be true or false", term)
+				}
+				def.prefix = prefix
+			default:
+				return fmt.Errorf("@context term %q: %s is not supported", term, k)
+			}
+		}
+	default:
+		return fmt.Errorf("@context term %q: invalid definition %T", term, v)
+	}
 
-		for key, value := range obj {
-			if key == "@id" {
+	if id == nil {
+		dec.setTerm(term, def)
+		return nil
+	}
+	iri, ok := id.(string)
+	if !ok {
+		return fmt.Errorf("@context term %q: @id must be a string", term)
+	}
+	if prefix, _, found := strings.Cut(iri, ":"); found && prefix != term {
+		if _, local := c[prefix]; local {
+			if err := dec.defineTerm(c, prefix, defined); err != nil {
+				return err
+			}
+		}
+	}
+	switch {
+	case strings.HasPrefix(iri, "@"):
+		// A keyword alias, such as "id": "@id".
+		def.id = iri
+	case iri != term || strings.Contains(iri, ":"):
+		def.id = dec.expandIRI(iri, true)
+	case dec.ctx.vocab != "":
+		def.id = dec.ctx.vocab + iri
+	default:
+		return fmt.Errorf("@context term %q has no IRI and there is no @vocab", term)
+	}
+	if !strings.HasPrefix(def.id, "@") && !isAbsoluteIRI(def.id) && !strings.HasPrefix(def.id, "_:") {
+		return fmt.Errorf("@context term %q: %q is not an absolute IRI", term, def.id)
+	}
+	if _, simple := c[term].(string); simple {
+		def.prefix = strings.ContainsAny(def.id[len(def.id)-1:], ":/?#[]@")
+	}
+	dec.setTerm(term, def)
+	return nil
+}
+
+func (dec *jsonLDDecoder) setTerm(term string, def jsonLDTerm) {
+	dec.ctx.terms[term] = def
+	if def.prefix && def.id != "" && !strings.HasPrefix(def.id, "@") && !strings.Contains(term, ":") {
+		dec.prefixes[term] = def.id
+	} else {
+		delete(dec.prefixes, term)
+	}
+}
+
+// unalias returns obj with keys that are aliases of keywords replaced by
+// the keywords.
+func (dec *jsonLDDecoder) unalias(obj map[string]interface{}) map[string]interface{} {
+	var result map[string]interface{}
+	for k, v := range obj {
+		def, ok := dec.ctx.terms[k]
+		if !ok || !strings.HasPrefix(def.id, "@") {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]interface{}, len(obj))
+			for k, v := range obj {
+				result[k] = v
+			}
+		}
+		delete(result, k)
+		result[def.id] = v
+	}
+	if result == nil {
+		return obj
+	}
+	return result
+}
+
+// expandIRI expands a compact IRI, and a term when vocab is set, as for
+// property names and types. Otherwise a relative IRI is resolved against
+// the base, as for @id values; with vocab it is appended to @vocab, and
+// left relative if there is none.
+func (dec *jsonLDDecoder) expandIRI(value string, vocab bool) string {
+	if strings.HasPrefix(value, "@") {
+		return value
+	}
+	if vocab {
+		if def, ok := dec.ctx.terms[value]; ok {
+			return def.id
+		}
+	}
+	if prefix, suffix, found := strings.Cut(value, ":"); found {
+		if prefix == "_" || strings.HasPrefix(suffix, "//") {
+			return value
+		}
+		if def, ok := dec.ctx.terms[prefix]; ok && def.id != "" && !strings.HasPrefix(def.id, "@") {
+			return def.id + suffix
+		}
+		if isAbsoluteIRI(value) {
+			return value
+		}
+	}
+	if vocab {
+		if dec.ctx.vocab != "" {
+			return dec.ctx.vocab + value
+		}
+		return value
+	}
+	return resolveIRI(dec.ctx.base, value)
+}
+
+func (dec *jsonLDDecoder) idToNode(id string) triple.Node {
+	if len(id) > 2 && id[:2] == "_:" {
+		return triple.BlankNode{Value: id[2:]}
+	}
+	return triple.IRI{Value: dec.expandIRI(id, false)}
+}
+
+// typeToNode reads a value of @type, which is vocabulary-relative and then
+// document-relative.
+func (dec *jsonLDDecoder) typeToNode(typeName string) triple.Node {
+	if len(typeName) > 2 && typeName[:2] == "_:" {
+		return triple.BlankNode{Value: typeName[2:]}
+	}
+	return triple.IRI{Value: resolveIRI(dec.ctx.base, dec.expandIRI(typeName, true))}
+}
+
+// freshBlankNode returns a blank node with a label not used in the document.
+func (dec *jsonLDDecoder) freshBlankNode() triple.BlankNode {
+	for {
+		dec.blanks++
+		label := fmt.Sprintf("jld%d", dec.blanks)
+		if !dec.used[label] {
+			return triple.BlankNode{Value: label}
+		}
+	}
+}
+
+func (dec *jsonLDDecoder) readNode(obj map[string]interface{}) (triple.Node, error) {
+	// An embedded context applies to the node and what it contains.
+	if ctx, ok := obj["@context"]; ok {
+		saved := dec.ctx
+		dec.ctx = saved.clone()
+		defer func() { dec.ctx = saved }()
+		if err := dec.readContext(ctx); err != nil {
+			return nil, err
+		}
+	}
+	obj = dec.unalias(obj)
+
+	var subject triple.Node
+	switch id := obj["@id"].(type) {
+	case string:
+		subject = dec.idToNode(id)
+	case map[string]interface{}:
+		// A JSON-LD-star embedded node as subject describes a reifier of the triple.
+		term, err := dec.readEmbedded(id)
+		if err != nil {
+			return nil, err
+		}
+		subject = dec.freshBlankNode()
+		dec.emit(subject, rdf.Reifies, term)
+	default:
+		if _, present := obj["@id"]; present && dec.strict {
+			return nil, fmt.Errorf("@id must be a string")
+		}
+		subject = dec.freshBlankNode()
+	}
+
+	if err := dec.readProperties(subject, obj); err != nil {
+		return nil, err
+	}
+	return subject, nil
+}
+
+func (dec *jsonLDDecoder) readProperties(subject triple.Node, obj map[string]interface{}) error {
+	for _, key := range sortedKeys(obj) {
+		value := obj[key]
+		switch key {
+		case "@id", "@context":
+			continue
+		case "@type":
+			for _, t := range asList(value) {
+				typeName, ok := t.(string)
+				if !ok {
+					if dec.strict {
+						return fmt.Errorf("@type must be a string")
+					}
+					continue
+				}
+				dec.emit(subject, rdf.Type, dec.typeToNode(typeName))
+			}
+			continue
+		}
+
+		if strings.HasPrefix(key, "@") {
+			if dec.strict {
+				return fmt.Errorf("unsupported keyword %s", key)
+			}
+			continue
+		}
+
+		def := dec.ctx.terms[key]
+		iri := dec.expandIRI(key, true)
+		if iri == "" {
+			// The context defines the term as null.
+			continue
+		}
+		if !isAbsoluteIRI(iri) {
+			if dec.strict {
+				return fmt.Errorf("property %q does not expand to an absolute IRI", key)
+			}
+			continue
+		}
+		predicate := triple.IRI{Value: iri}
+
+		values := asList(value)
+		switch def.container {
+		case "@language":
+			if langMap, ok := value.(map[string]interface{}); ok {
+				if err := dec.readLanguageMap(subject, predicate, langMap); err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
 				continue
 			}
+		case "@list":
+			if obj, ok := value.(map[string]interface{}); !ok || obj["@list"] == nil {
+				values = []interface{}{map[string]interface{}{"@list": value}}
+			}
+		}
 
-			predicate := triple.IRI{Value: key}
+		for _, v := range values {
+			var annotation interface{}
+			if valueObj, ok := v.(map[string]interface{}); ok {
+				if annotation, ok = valueObj["@annotation"]; ok {
+					v = withoutKey(valueObj, "@annotation")
+				}
+			}
 
-			values, ok := value.([]interface{})
-			if !ok {
+			object, err := dec.readValue(v, def)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			if object == nil {
 				continue
 			}
+			dec.emit(subject, predicate, object)
 
-			for _, v := range values {
-				objMap, ok := v.(map[string]interface{})
-				if !ok {
+			if annotation != nil {
+				term := triple.TripleTerm{Triple: triple.Triple{Subject: subject, Predicate: predicate, Object: object}}
+				if err := dec.readAnnotation(term, annotation); err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// readLanguageMap reads the value of a term with an @language container,
+// whose keys are language tags.
+func (dec *jsonLDDecoder) readLanguageMap(subject, predicate triple.Node, langMap map[string]interface{}) error {
+	for _, lang := range sortedKeys(langMap) {
+		for _, item := range asList(langMap[lang]) {
+			value, ok := item.(string)
+			if !ok {
+				if item == nil {
 					continue
 				}
+				return fmt.Errorf("language map values must be strings")
+			}
+			lit := triple.Literal{Value: value}
+			if lang != "@none" {
+				lit.Language = lang
+			}
+			dec.emit(subject, predicate, lit)
+		}
+	}
+	return nil
+}
+
+// readAnnotation gives each @annotation node object a fresh reifier of term.
+func (dec *jsonLDDecoder) readAnnotation(term triple.TripleTerm, annotation interface{}) error {
+	for _, a := range asList(annotation) {
+		obj, ok := a.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("@annotation must be a node object")
+		}
+		reifier := dec.freshBlankNode()
+		dec.emit(reifier, rdf.Reifies, term)
+		if err := dec.readProperties(reifier, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-				object := jsonLDToNode(objMap)
-				if object != nil {
-					triples = append(triples, triple.Triple{
-						Subject:   subject,
-						Predicate: predicate,
-						Object:    object,
-					})
+// readEmbedded reads a JSON-LD-star embedded node, which has an @id and
+// exactly one property value, as a triple term.
+func (dec *jsonLDDecoder) readEmbedded(obj map[string]interface{}) (triple.TripleTerm, error) {
+	obj = dec.unalias(obj)
+	id, ok := obj["@id"].(string)
+	if !ok {
+		return triple.TripleTerm{}, fmt.Errorf("embedded node must have a string @id")
+	}
+
+	var terms []triple.TripleTerm
+	for _, key := range sortedKeys(obj) {
+		if key == "@id" {
+			continue
+		}
+		predicate := rdf.Type
+		if key != "@type" {
+			predicate = triple.IRI{Value: dec.expandIRI(key, true)}
+		}
+		for _, v := range asList(obj[key]) {
+			var object triple.Node
+			if typeName, ok := v.(string); ok && key == "@type" {
+				object = dec.typeToNode(typeName)
+			} else {
+				var err error
+				if object, err = dec.readValue(v, dec.ctx.terms[key]); err != nil {
+					return triple.TripleTerm{}, err
 				}
 			}
+			terms = append(terms, triple.TripleTerm{Triple: triple.Triple{Subject: dec.idToNode(id), Predicate: predicate, Object: object}})
+		}
+	}
+
+	if len(terms) != 1 {
+		return triple.TripleTerm{}, fmt.Errorf("embedded node must have exactly one property value, got %d", len(terms))
+	}
+	return terms[0], nil
+}
+
+func withoutKey(obj map[string]interface{}, key string) map[string]interface{} {
+	result := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		if k != key {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (dec *jsonLDDecoder) emit(subject, predicate, object triple.Node) {
+	dec.triples = append(dec.triples, triple.Triple{
+		Subject:   subject,
+		Predicate: predicate,
+		Object:    object,
+	})
+}
+
+// readValue reads a property value, coercing it as the property's term
+// definition says.
+func (dec *jsonLDDecoder) readValue(v interface{}, def jsonLDTerm) (triple.Node, error) {
+	coerced := def.typ != "" && def.typ != "@id" && def.typ != "@vocab"
+	switch value := v.(type) {
+	case string:
+		switch {
+		case def.typ == "@id":
+			return dec.idToNode(value), nil
+		case def.typ == "@vocab":
+			return dec.typeToNode(value), nil
+		case coerced:
+			return triple.Literal{Value: value, Datatype: def.typ}, nil
+		}
+		lit := triple.Literal{Value: value, Language: dec.ctx.language}
+		if def.language != nil {
+			lit.Language = *def.language
+		}
+		return lit, nil
+	case bool:
+		if coerced {
+			return triple.Literal{Value: fmt.Sprint(value), Datatype: def.typ}, nil
+		}
+		return triple.Literal{Value: fmt.Sprint(value), Datatype: xsd.Boolean}, nil
+	case json.Number:
+		if coerced {
+			return triple.Literal{Value: value.String(), Datatype: def.typ}, nil
+		}
+		if strings.ContainsAny(value.String(), ".eE") {
+			return triple.Literal{Value: value.String(), Datatype: xsd.Double}, nil
 		}
+		return triple.Literal{Value: value.String(), Datatype: xsd.Integer}, nil
+	case map[string]interface{}:
+		value = dec.unalias(value)
+		if embedded, ok := value["@id"].(map[string]interface{}); ok && len(value) == 1 {
+			return dec.readEmbedded(embedded)
+		}
+		if list, ok := value["@list"]; ok {
+			return dec.readList(list, def)
+		}
+		node, err := dec.jsonLDToNode(value)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			return node, nil
+		}
+		return dec.readNode(value)
+	case nil:
+		return nil, nil
 	}
 
-	return triples, nil
+	if dec.strict {
+		return nil, fmt.Errorf("unsupported value %T", v)
+	}
+	return nil, nil
 }
 
-func jsonLDToNode(obj map[string]interface{}) triple.Node {
-	if id, ok := obj["@id"].(string); ok {
-		if len(id) > 2 && id[:2] == "_:" {
-			return triple.BlankNode{Value: id[2:]}
+// readList builds an rdf:List from the values of a @list object.
+func (dec *jsonLDDecoder) readList(list interface{}, def jsonLDTerm) (triple.Node, error) {
+	var items []triple.Node
+	for _, v := range asList(list) {
+		item, err := dec.readValue(v, def)
+		if err != nil {
+			return nil, err
+		}
+		if item != nil {
+			items = append(items, item)
 		}
-		return triple.IRI{Value: id}
 	}
 
-	if value, ok := obj["@value"].(string); ok {
-		lit := triple.Literal{Value: value}
+	head, triples := triple.NewList(items, func() triple.Node { return dec.freshBlankNode() })
+	dec.triples = append(dec.triples, triples...)
+	return head, nil
+}
+
+func (dec *jsonLDDecoder) jsonLDToNode(obj map[string]interface{}) (triple.Node, error) {
+	if id, ok := obj["@id"].(string); ok && len(obj) == 1 {
+		return dec.idToNode(id), nil
+	}
+
+	if raw, ok := obj["@value"]; ok {
+		var lit triple.Literal
+		switch value := raw.(type) {
+		case string:
+			lit.Value = value
+		case json.Number:
+			lit.Value = value.String()
+		case bool:
+			lit.Value = fmt.Sprint(value)
+		}
 
 		if lang, ok := obj["@language"].(string); ok {
 			lit.Language = lang
 		}
 
 		if dtype, ok := obj["@type"].(string); ok {
-			lit.Datatype = dtype
+			if lit.Language != "" {
+				return nil, fmt.Errorf("invalid value object %q: it has both @language and @type", lit.Value)
+			}
+			lit.Datatype = resolveIRI(dec.ctx.base, dec.expandIRI(dtype, true))
 		}
 
+		if direction, ok := obj["@direction"].(string); ok {
+			return dec.directionalLiteral(lit, direction), nil
+		}
+		return lit, nil
+	}
+
+	return nil, nil
+}
+
+// directionalLiteral represents a literal with a JSON-LD @direction
+// according to the decoder's direction mode.
+func (dec *jsonLDDecoder) directionalLiteral(lit triple.Literal, direction string) triple.Node {
+	switch dec.direction {
+	case DirectionI18nDatatype:
+		lit.Datatype = i18nNamespace + strings.ToLower(lit.Language) + "_" + direction
+		lit.Language = ""
 		return lit
+	case DirectionCompoundLiteral:
+		node := dec.freshBlankNode()
+		dec.emit(node, rdf.Value, triple.Literal{Value: lit.Value})
+		if lit.Language != "" {
+			dec.emit(node, rdf.Language, triple.Literal{Value: strings.ToLower(lit.Language)})
+		}
+		dec.emit(node, rdf.Direction, triple.Literal{Value: direction})
+		return node
 	}
 
-	return nil
+	// RDF 1.2 directional strings need a language; without one the
+	// direction is dropped, as JSON-LD does when rdfDirection is unset.
+	if lit.Language != "" {
+		lit.Direction = direction
+	}
+	return lit
 }
@@ -3,6 +3,7 @@ package encoder
 import (
 	"encoding/json"
 	"github.com/DeDude/tripl/pkg/triple"
+	"reflect"
 	"testing"
 )
 
@@ -328,3 +329,92 @@ func TestEncodeJSONLDCompact(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeJSONLDContext(t *testing.T) {
+	ex := func(s string) triple.IRI { return triple.IRI{Value: "http://example.org/" + s} }
+	input := `{
+  "@context": {
+    "@base": "http://example.org/base/",
+    "@vocab": "http://example.org/vocab#",
+    "ex": "http://example.org/",
+    "id": "@id",
+    "name": "ex:name",
+    "homepage": {"@id": "ex:homepage", "@type": "@id"},
+    "age": {"@id": "ex:age", "@type": "http://www.w3.org/2001/XMLSchema#integer"},
+    "label": {"@id": "ex:label", "@container": "@language"},
+    "steps": {"@id": "ex:steps", "@container": "@list"},
+    "ignored": null
+  },
+  "id": "ex:alice",
+  "name": "Alice",
+  "knows": {"@id": "_:jld1"},
+  "homepage": "alice/",
+  "age": "42",
+  "label": {"en": "Alice", "fr": "Alice (fr)"},
+  "steps": ["a", "b"],
+  "ignored": "x",
+  "ex:friend": {"name": "Anonymous"}
+}`
+	triples, prefixes, err := Decode(input, JSONLD, DecodeOptions{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	has := func(s, p, o triple.Node) bool {
+		for _, tr := range triples {
+			if tr.Subject == s && tr.Predicate == p && tr.Object == o {
+				return true
+			}
+		}
+		return false
+	}
+	alice := ex("alice")
+	for _, want := range []triple.Triple{
+		{Subject: alice, Predicate: ex("name"), Object: triple.Literal{Value: "Alice"}},
+		{Subject: alice, Predicate: triple.IRI{Value: "http://example.org/vocab#knows"}, Object: triple.BlankNode{Value: "jld1"}},
+		{Subject: alice, Predicate: ex("homepage"), Object: triple.IRI{Value: "http://example.org/base/alice/"}},
+		{Subject: alice, Predicate: ex("age"), Object: triple.Literal{Value: "42", Datatype: "http://www.w3.org/2001/XMLSchema#integer"}},
+		{Subject: alice, Predicate: ex("label"), Object: triple.Literal{Value: "Alice", Language: "en"}},
+		{Subject: alice, Predicate: ex("label"), Object: triple.Literal{Value: "Alice (fr)", Language: "fr"}},
+	} {
+		if !has(want.Subject, want.Predicate, want.Object) {
+			t.Errorf("missing %s", EncodeNTriple(want))
+		}
+	}
+	for _, tr := range triples {
+		if tr.Predicate == ex("friend") && tr.Object == (triple.BlankNode{Value: "jld1"}) {
+			t.Error("anonymous node took the label of _:jld1")
+		}
+		if tr.Object == (triple.Literal{Value: "x"}) {
+			t.Error("property of a null term was not dropped")
+		}
+	}
+	if steps := triple.NewGraph(triples).Match(alice, ex("steps"), nil); len(steps) != 1 {
+		t.Errorf("steps = %v, want one list", steps)
+	} else if items, _, err := triple.ReadList(triple.NewGraph(triples), steps[0].Object); err != nil || len(items) != 2 {
+		t.Errorf("steps list = %v, %v", items, err)
+	}
+	if want := map[string]string{"ex": "http://example.org/"}; !reflect.DeepEqual(prefixes, want) {
+		t.Errorf("prefixes = %v, want %v", prefixes, want)
+	}
+
+	for name, input := range map[string]string{
+		"remote context":        `{"@context": "https://schema.org/", "name": "x"}`,
+		"unsupported container": `{"@context": {"tags": {"@id": "http://example.org/tags", "@container": "@index"}}}`,
+		"term without IRI":      `{"@context": {"knows": {"@type": "@id"}}}`,
+		"cyclic terms":          `{"@context": {"a": "b:x", "b": "a:y"}}`,
+	} {
+		if _, _, err := Decode(input, JSONLD, DecodeOptions{}); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+
+	// Without @vocab a plain property name is not an IRI.
+	relative := `{"@id": "http://example.org/a", "knows": "b"}`
+	if triples, _, err := Decode(relative, JSONLD, DecodeOptions{}); err != nil || len(triples) != 0 {
+		t.Errorf("relative property: %v, %v", triples, err)
+	}
+	if _, _, err := Decode(relative, JSONLD, DecodeOptions{Strict: true}); err == nil {
+		t.Error("relative property: no error with Strict")
+	}
+}
@@ -0,0 +1,96 @@
+package encoder
+
+import (
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+)
+
+// collectLists finds the rdf:Lists that can be written inline, as a Turtle
+// collection or a JSON-LD @list: well-formed lists of blank node cells that
+// carry nothing but rdf:first and rdf:rest, whose head is the object of
+// exactly one triple and which appear in no triple term. It returns the items
+// of each list by head, and the triples left once the list cells are removed.
+func collectLists(triples []triple.Triple) (map[triple.Node][]triple.Node, []triple.Triple) {
+	uses := make(map[triple.Node]int)
+	parent := make(map[triple.Node]triple.Node)
+	properties := make(map[triple.Node]int)
+	quoted := make(map[triple.Node]bool)
+
+	var markQuoted func(t triple.Triple)
+	markQuoted = func(t triple.Triple) {
+		for _, n := range []triple.Node{t.Subject, t.Object} {
+			quoted[n] = true
+			if term, ok := n.(triple.TripleTerm); ok {
+				markQuoted(term.Triple)
+			}
+		}
+	}
+
+	for _, t := range triples {
+		uses[t.Object]++
+		parent[t.Object] = t.Subject
+		properties[t.Subject]++
+		if term, ok := t.Object.(triple.TripleTerm); ok {
+			markQuoted(term.Triple)
+		}
+	}
+
+	graph := triple.NewGraph(triples)
+	inline := func(cell triple.Node) bool {
+		_, blank := cell.(triple.BlankNode)
+		return blank && uses[cell] == 1 && properties[cell] == 2 && !quoted[cell]
+	}
+
+	lists := make(map[triple.Node][]triple.Node)
+	cellHeads := make(map[triple.Node]triple.Node)
+	for _, t := range triples {
+		head := t.Subject
+		if t.Predicate != rdf.First || !inline(head) || len(graph.Match(nil, rdf.Rest, head)) > 0 {
+			continue
+		}
+		items, cells, err := triple.ReadList(graph, head)
+		if err != nil {
+			continue
+		}
+		ok := true
+		for _, cell := range cells {
+			ok = ok && inline(cell)
+		}
+		if !ok {
+			continue
+		}
+		lists[head] = items
+		for _, cell := range cells {
+			cellHeads[cell] = head
+		}
+	}
+
+	// A list nested in itself, directly or through other lists, has no
+	// written form, so it stays as plain triples.
+	for head := range lists {
+		seen := map[triple.Node]bool{head: true}
+		for h := head; ; {
+			outer, nested := cellHeads[parent[h]]
+			if !nested {
+				break
+			}
+			if seen[outer] {
+				delete(lists, head)
+				break
+			}
+			seen[outer] = true
+			h = outer
+		}
+	}
+
+	remaining := make([]triple.Triple, 0, len(triples))
+	for _, t := range triples {
+		if head, ok := cellHeads[t.Subject]; ok {
+			if _, inlined := lists[head]; inlined {
+				continue
+			}
+		}
+		remaining = append(remaining, t)
+	}
+	return lists, remaining
+}
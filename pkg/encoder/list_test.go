@@ -0,0 +1,135 @@
+package encoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+)
+
+func TestEncodeTurtleCollections(t *testing.T) {
+	input := `@prefix ex: <http://example.org/> .
+ex:s ex:items ( 1 "two" ( ex:a ex:b ) ) .
+`
+	triples, _, err := Decode(input, Turtle, DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	out, err := Encode(triples, Turtle, EncodeOptions{
+		Compact:  true,
+		Prefixes: map[string]string{"ex": "http://example.org/"},
+		Turtle:   TurtleStyle{LiteralShorthand: true},
+	})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !strings.Contains(out, `ex:s ex:items ( 1 "two" ( ex:a ex:b ) ) .`) {
+		t.Errorf("Encode() did not write collections:\n%s", out)
+	}
+}
+
+func TestEncodeTurtleEmptyList(t *testing.T) {
+	input := `@prefix ex: <http://example.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+ex:s ex:empty () ;
+    ex:items ( 1 () ) .
+`
+	triples, _, err := Decode(input, Turtle, DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	out, err := Encode(triples, Turtle, EncodeOptions{
+		Compact:  true,
+		Prefixes: map[string]string{"ex": "http://example.org/", "rdf": rdf.Namespace},
+		Turtle:   TurtleStyle{LiteralShorthand: true},
+	})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !strings.Contains(out, "ex:empty ()") || !strings.Contains(out, "( 1 () )") || strings.Contains(out, "rdf:nil") {
+		t.Errorf("Encode() did not write empty lists as ():\n%s", out)
+	}
+}
+
+func TestEncodeListsNotInlined(t *testing.T) {
+	s := triple.IRI{Value: "http://example.org/s"}
+	p := triple.IRI{Value: "http://example.org/p"}
+	a, b := triple.BlankNode{Value: "a"}, triple.BlankNode{Value: "b"}
+	x := triple.Literal{Value: "x"}
+
+	tests := []struct {
+		name    string
+		triples []triple.Triple
+	}{
+		{"shared head", []triple.Triple{
+			{Subject: a, Predicate: rdf.First, Object: x}, {Subject: a, Predicate: rdf.Rest, Object: rdf.Nil},
+			{Subject: s, Predicate: p, Object: a}, {Subject: s, Predicate: triple.IRI{Value: "http://example.org/q"}, Object: a},
+		}},
+		{"extra property", []triple.Triple{
+			{Subject: a, Predicate: rdf.First, Object: x}, {Subject: a, Predicate: rdf.Rest, Object: rdf.Nil},
+			{Subject: a, Predicate: p, Object: x}, {Subject: s, Predicate: p, Object: a},
+		}},
+		{"cyclic", []triple.Triple{
+			{Subject: a, Predicate: rdf.First, Object: x}, {Subject: a, Predicate: rdf.Rest, Object: b},
+			{Subject: b, Predicate: rdf.First, Object: x}, {Subject: b, Predicate: rdf.Rest, Object: a},
+		}},
+		{"contains itself", []triple.Triple{
+			{Subject: a, Predicate: rdf.First, Object: a}, {Subject: a, Predicate: rdf.Rest, Object: rdf.Nil},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, format := range []Format{Turtle, JSONLD} {
+				out, err := Encode(tt.triples, format, EncodeOptions{Compact: true})
+				if err != nil {
+					t.Fatalf("Encode() error = %v", err)
+				}
+				if strings.Contains(out, "( ") || strings.Contains(out, "@list") {
+					t.Errorf("%s: Encode() inlined a list:\n%s", format, out)
+				}
+				decoded, _, err := Decode(out, format, DecodeOptions{})
+				if err != nil || len(decoded) != len(tt.triples) {
+					t.Errorf("%s: round trip got %d triples, %v", format, len(decoded), err)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONLDListRoundTrip(t *testing.T) {
+	input := `{"@id": "http://example.org/s",
+  "http://example.org/items": {"@list": [1, "two", {"@list": [{"@id": "http://example.org/a"}]}]}}`
+
+	triples, _, err := Decode(input, JSONLD, DecodeOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	g := triple.NewGraph(triples)
+	heads := g.Match(triple.IRI{Value: "http://example.org/s"}, nil, nil)
+	if len(heads) != 1 {
+		t.Fatalf("expected one items triple, got %v", heads)
+	}
+	items, _, err := triple.ReadList(g, heads[0].Object)
+	if err != nil || len(items) != 3 || items[1] != (triple.Literal{Value: "two"}) {
+		t.Fatalf("ReadList() = %v, %v", items, err)
+	}
+
+	for _, compact := range []bool{false, true} {
+		out, err := Encode(triples, JSONLD, EncodeOptions{Compact: compact})
+		if err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		if strings.Count(out, "@list") != 2 {
+			t.Errorf("compact=%v: Encode() did not write @list:\n%s", compact, out)
+		}
+		again, _, err := Decode(out, JSONLD, DecodeOptions{})
+		if err != nil || len(again) != len(triples) {
+			t.Errorf("compact=%v: round trip got %d triples, want %d (%v)", compact, len(again), len(triples), err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package encoder
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/DeDude/tripl/pkg/triple"
 )
 
@@ -11,3 +13,28 @@ func EncodeNTriple(t triple.Triple) string {
 	object := formatNode(t.Object)
 	return fmt.Sprintf("%s %s %s .", subject, predicate, object)
 }
+
+// EncodeTerm returns a single term in N-Triples syntax.
+func EncodeTerm(n triple.Node) string {
+	return formatNode(n)
+}
+
+func EncodeNTriples(triples []triple.Triple) string {
+	result, _ := encodeNTriples(triples, EncodeOptions{})
+	return result
+}
+
+func encodeNTriples(triples []triple.Triple, opts EncodeOptions) (string, error) {
+	triples, err := prepareTriples(triples, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	for _, t := range triples {
+		result.WriteString(EncodeNTriple(t))
+		result.WriteString("\n")
+	}
+
+	return result.String(), nil
+}
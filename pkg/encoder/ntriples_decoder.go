@@ -1,12 +1,55 @@
 package encoder
 
 import (
-	"github.com/DeDude/tripl/pkg/triple"
+	"fmt"
 	"strings"
+
+	"github.com/DeDude/tripl/pkg/triple"
 )
 
 func DecodeNTriple(line string) (triple.Triple, error) {
-	ctx := &parseContext{line: 1, column: 1, input: line}
+	return decodeNTripleLine(line, 1, DecodeOptions{})
+}
+
+// DecodeTerm parses a single term in N-Triples syntax, the inverse of
+// EncodeTerm.
+func DecodeTerm(s string) (triple.Node, error) {
+	ctx := &parseContext{line: 1, column: 1, input: s}
+	n, rest, err := parseNodeWithContext(s, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return nil, ctx.error("unexpected content after term")
+	}
+	return n, nil
+}
+
+func DecodeNTriples(input string) ([]triple.Triple, error) {
+	return decodeNTriples(input, DecodeOptions{})
+}
+
+func decodeNTriples(input string, opts DecodeOptions) ([]triple.Triple, error) {
+	var triples []triple.Triple
+
+	for i, line := range strings.Split(input, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		t, err := decodeNTripleLine(trimmed, i+1, opts)
+		if err != nil {
+			return nil, fmt.Errorf("line %q: %w", trimmed, err)
+		}
+		triples = append(triples, t)
+	}
+
+	return triples, nil
+}
+
+func decodeNTripleLine(line string, lineNum int, opts DecodeOptions) (triple.Triple, error) {
+	ctx := &parseContext{line: lineNum, column: 1, input: line}
 	line = strings.TrimSpace(line)
 
 	if line == "" || strings.HasPrefix(line, "#") {
@@ -30,21 +73,40 @@ func DecodeNTriple(line string) (triple.Triple, error) {
 		return triple.Triple{}, err
 	}
 
-	object, _, err := parseNodeWithContext(rest, ctx)
+	object, rest, err := parseNodeWithContext(rest, ctx)
 	if err != nil {
 		return triple.Triple{}, err
 	}
 
-	return triple.Triple{
-		Subject:   subject,
-		Predicate: predicate,
-		Object:    object,
-	}, nil
+	t := triple.Triple{Subject: subject, Predicate: predicate, Object: object}
+	if opts.Base != "" {
+		t = mapTerms(t, func(n triple.Node) triple.Node {
+			if iri, ok := n.(triple.IRI); ok {
+				return triple.IRI{Value: resolveIRI(opts.Base, iri.Value)}
+			}
+			return n
+		})
+	}
+
+	if opts.Strict {
+		if strings.TrimSpace(rest) != "" {
+			return triple.Triple{}, ctx.error("unexpected content after object")
+		}
+		if err := t.Validate(); err != nil {
+			return triple.Triple{}, ctx.error(err.Error())
+		}
+	}
+
+	return t, nil
 }
 
 func parseNodeWithContext(s string, ctx *parseContext) (triple.Node, string, error) {
 	s = strings.TrimSpace(s)
 
+	if strings.HasPrefix(s, "<<(") {
+		return parseTripleTermWithContext(s, ctx)
+	}
+
 	if strings.HasPrefix(s, "<") {
 		return parseIRIWithContext(s, ctx)
 	}
@@ -59,3 +121,25 @@ func parseNodeWithContext(s string, ctx *parseContext) (triple.Node, string, err
 
 	return nil, "", ctx.error("invalid node format")
 }
+
+func parseTripleTermWithContext(s string, ctx *parseContext) (triple.Node, string, error) {
+	ctx.advance(3)
+	rest := s[3:]
+
+	var nodes [3]triple.Node
+	for i := range nodes {
+		node, remaining, err := parseNodeWithContext(rest, ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		nodes[i], rest = node, strings.TrimSpace(remaining)
+	}
+
+	if !strings.HasPrefix(rest, ")>>") {
+		return nil, "", ctx.error("expected )>> to close triple term")
+	}
+	ctx.advance(3)
+
+	term := triple.TripleTerm{Triple: triple.Triple{Subject: nodes[0], Predicate: nodes[1], Object: nodes[2]}}
+	return term, strings.TrimSpace(rest[3:]), nil
+}
@@ -150,3 +150,30 @@ func nodesEqual(a, b triple.Node) bool {
 	}
 	return false
 }
+
+func TestDecodeTerm(t *testing.T) {
+	terms := []triple.Node{
+		triple.IRI{Value: "http://example.org/a"},
+		triple.BlankNode{Value: "b1"},
+		triple.Literal{Value: "tab\there \"quoted\""},
+		triple.Literal{Value: "chat", Language: "fr"},
+		triple.Literal{Value: "1", Datatype: "http://www.w3.org/2001/XMLSchema#integer"},
+		triple.TripleTerm{Triple: triple.Triple{
+			Subject:   triple.IRI{Value: "http://example.org/s"},
+			Predicate: triple.IRI{Value: "http://example.org/p"},
+			Object:    triple.Literal{Value: "o"},
+		}},
+	}
+	for _, want := range terms {
+		got, err := DecodeTerm(EncodeTerm(want))
+		if err != nil || got != want {
+			t.Errorf("DecodeTerm(%s) = %v, %v, want %v", EncodeTerm(want), got, err, want)
+		}
+	}
+
+	for _, bad := range []string{"", "plain", "<http://example.org/a> extra"} {
+		if _, err := DecodeTerm(bad); err == nil {
+			t.Errorf("DecodeTerm(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package encoder
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+type Format string
+
+const (
+	NTriples Format = "ntriples"
+	Turtle   Format = "turtle"
+	JSONLD   Format = "jsonld"
+)
+
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "ntriples", "nt":
+		return NTriples, nil
+	case "turtle", "ttl":
+		return Turtle, nil
+	case "jsonld", "json-ld":
+		return JSONLD, nil
+	}
+	return "", fmt.Errorf("unsupported format: %s", name)
+}
+
+func (f Format) Extension() string {
+	switch f {
+	case NTriples:
+		return ".nt"
+	case Turtle:
+		return ".ttl"
+	case JSONLD:
+		return ".jsonld"
+	}
+	return ""
+}
+
+// MediaType returns the IANA media type of the format.
+func (f Format) MediaType() string {
+	switch f {
+	case NTriples:
+		return "application/n-triples"
+	case Turtle:
+		return "text/turtle"
+	case JSONLD:
+		return "application/ld+json"
+	}
+	return ""
+}
+
+// FormatForMediaType returns the format registered for a media type such as
+// "text/turtle". Parameters like charset are ignored.
+func FormatForMediaType(mediaType string) (Format, error) {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "application/n-triples":
+		return NTriples, nil
+	case "text/turtle", "application/x-turtle":
+		return Turtle, nil
+	case "application/ld+json":
+		return JSONLD, nil
+	}
+	return "", fmt.Errorf("unsupported media type: %s", mediaType)
+}
+
+type BlankNodePolicy int
+
+const (
+	// BlankNodesPreserve keeps blank node labels exactly as given.
+	BlankNodesPreserve BlankNodePolicy = iota
+	// BlankNodesRelabel renames blank nodes to b0, b1, ... in order of first appearance.
+	BlankNodesRelabel
+)
+
+// DirectionMode selects how JSON-LD @direction is represented in RDF,
+// following the JSON-LD rdfDirection option.
+type DirectionMode string
+
+const (
+	// DirectionNative uses RDF 1.2 directional language strings ("..."@ar--rtl).
+	DirectionNative DirectionMode = ""
+	// DirectionI18nDatatype uses datatypes such as https://www.w3.org/ns/i18n#ar_rtl.
+	DirectionI18nDatatype DirectionMode = "i18n-datatype"
+	// DirectionCompoundLiteral uses a blank node with rdf:value, rdf:language
+	// and rdf:direction.
+	DirectionCompoundLiteral DirectionMode = "compound-literal"
+)
+
+func ParseDirectionMode(name string) (DirectionMode, error) {
+	switch mode := DirectionMode(name); mode {
+	case DirectionNative, DirectionI18nDatatype, DirectionCompoundLiteral:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unsupported direction mode: %s", name)
+}
+
+type EncodeOptions struct {
+	Prefixes   map[string]string
+	Base       string
+	Compact    bool
+	Sort       bool
+	Indent     int
+	LineWidth  int
+	BlankNodes BlankNodePolicy
+	Strict     bool
+	Turtle     TurtleStyle
+	// RDFDirection names how base directions appear in the triples; JSON-LD
+	// output writes them as @direction.
+	RDFDirection DirectionMode
+	// Skipped, if set, is called with each triple that cannot be written,
+	// such as one without a subject, and why. Without Strict such triples
+	// are left out of the output; with it they are an error.
+	Skipped func(t triple.Triple, err error)
+}
+
+type DecodeOptions struct {
+	Prefixes   map[string]string
+	Base       string
+	BlankNodes BlankNodePolicy
+	Strict     bool
+	// ValidateLiterals rejects literals whose lexical form does not match
+	// their XSD datatype.
+	ValidateLiterals bool
+	// RDFDirection controls how JSON-LD @direction values are decoded.
+	RDFDirection DirectionMode
+}
+
+func Encode(triples []triple.Triple, format Format, opts EncodeOptions) (string, error) {
+	switch format {
+	case NTriples:
+		return encodeNTriples(triples, opts)
+	case Turtle:
+		return encodeTurtle(triples, opts)
+	case JSONLD:
+		return encodeJSONLD(triples, opts)
+	}
+	return "", fmt.Errorf("unsupported output format: %s", format)
+}
+
+func Decode(input string, format Format, opts DecodeOptions) ([]triple.Triple, map[string]string, error) {
+	var triples []triple.Triple
+	var prefixes map[string]string
+	var err error
+
+	switch format {
+	case NTriples:
+		triples, err = decodeNTriples(input, opts)
+		prefixes = map[string]string{}
+	case Turtle:
+		triples, prefixes, err = decodeTurtle(input, opts)
+	case JSONLD:
+		triples, prefixes, err = decodeJSONLD(input, opts)
+	default:
+		return nil, nil, fmt.Errorf("unsupported input format: %s", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.ValidateLiterals {
+		if err := validateLiterals(triples); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return applyBlankNodePolicy(triples, opts.BlankNodes), prefixes, nil
+}
+
+func validateLiterals(triples []triple.Triple) error {
+	var errs []error
+	for _, t := range triples {
+		lit, ok := t.Object.(triple.Literal)
+		if !ok {
+			continue
+		}
+		if err := lit.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", strings.TrimSpace(EncodeNTriple(t)), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func prepareTriples(triples []triple.Triple, opts EncodeOptions) ([]triple.Triple, error) {
+	prepared := make([]triple.Triple, 0, len(triples))
+
+	for i, t := range triples {
+		if opts.Strict {
+			if err := t.Validate(); err != nil {
+				return nil, fmt.Errorf("triple %d: %w", i, err)
+			}
+		} else if err := checkTriple(t); err != nil {
+			if opts.Skipped != nil {
+				opts.Skipped(t, err)
+			}
+			continue
+		}
+		prepared = append(prepared, t)
+	}
+
+	prepared = applyBlankNodePolicy(prepared, opts.BlankNodes)
+
+	if opts.Sort {
+		sortTriples(prepared)
+	}
+
+	return prepared, nil
+}
+
+func checkTriple(t triple.Triple) error {
+	switch t.Subject.(type) {
+	case triple.IRI, triple.BlankNode:
+	default:
+		return fmt.Errorf("subject must be an IRI or blank node")
+	}
+
+	if _, ok := t.Predicate.(triple.IRI); !ok {
+		return fmt.Errorf("predicate must be an IRI")
+	}
+
+	if t.Object == nil {
+		return fmt.Errorf("object is missing")
+	}
+
+	switch term := t.Object.(type) {
+	case triple.TripleTerm:
+		return checkTriple(term.Triple)
+	case triple.Literal:
+		if term.Language != "" && term.Datatype != "" {
+			return fmt.Errorf("literal %q has both a language and a datatype", term.Value)
+		}
+	}
+	return nil
+}
+
+func sortTriples(triples []triple.Triple) {
+	sort.SliceStable(triples, func(i, j int) bool {
+		a, b := triples[i], triples[j]
+		if ka, kb := formatNode(a.Subject), formatNode(b.Subject); ka != kb {
+			return ka < kb
+		}
+		if ka, kb := formatNode(a.Predicate), formatNode(b.Predicate); ka != kb {
+			return ka < kb
+		}
+		return formatNode(a.Object) < formatNode(b.Object)
+	})
+}
+
+func applyBlankNodePolicy(triples []triple.Triple, policy BlankNodePolicy) []triple.Triple {
+	if policy != BlankNodesRelabel {
+		return triples
+	}
+
+	labels := make(map[string]string)
+	relabel := func(n triple.Node) triple.Node {
+		bn, ok := n.(triple.BlankNode)
+		if !ok {
+			return n
+		}
+		label, exists := labels[bn.Value]
+		if !exists {
+			label = fmt.Sprintf("b%d", len(labels))
+			labels[bn.Value] = label
+		}
+		return triple.BlankNode{Value: label}
+	}
+
+	result := make([]triple.Triple, len(triples))
+	for i, t := range triples {
+		result[i] = mapTerms(t, relabel)
+	}
+	return result
+}
+
+func sortedPrefixes(prefixes map[string]string) []string {
+	keys := make([]string, 0, len(prefixes))
+	for k := range prefixes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func resolveIRI(base, ref string) string {
+	if base == "" {
+		return ref
+	}
+
+	refURL, err := url.Parse(ref)
+	if err != nil || refURL.IsAbs() {
+		return ref
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+
+	return baseURL.ResolveReference(refURL).String()
+}
+
+func isAbsoluteIRI(iri string) bool {
+	colon := strings.Index(iri, ":")
+	if colon <= 0 {
+		return false
+	}
+	for i, ch := range iri[:colon] {
+		isAlpha := (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+		if i == 0 && !isAlpha {
+			return false
+		}
+		if !isAlpha && !(ch >= '0' && ch <= '9') && ch != '+' && ch != '-' && ch != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func relativizeIRI(base, iri string) string {
+	if base == "" || !strings.HasPrefix(iri, base) {
+		return iri
+	}
+	rel := strings.TrimPrefix(iri, base)
+	if rel == "" || strings.HasPrefix(rel, "//") || isAbsoluteIRI(rel) {
+		return iri
+	}
+	if strings.HasPrefix(rel, "#") || strings.HasSuffix(base, "/") || strings.HasSuffix(base, "#") {
+		return rel
+	}
+	return iri
+}
@@ -0,0 +1,453 @@
+package encoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Format
+		wantErr  bool
+	}{
+		{name: "ntriples", input: "ntriples", expected: NTriples},
+		{name: "nt alias", input: "nt", expected: NTriples},
+		{name: "turtle", input: "Turtle", expected: Turtle},
+		{name: "ttl alias", input: "ttl", expected: Turtle},
+		{name: "jsonld", input: "jsonld", expected: JSONLD},
+		{name: "unknown", input: "rdfxml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("ParseFormat() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatForMediaType(t *testing.T) {
+	for _, f := range []Format{NTriples, Turtle, JSONLD} {
+		got, err := FormatForMediaType(f.MediaType() + "; charset=utf-8")
+		if err != nil || got != f {
+			t.Errorf("FormatForMediaType(%q) = %q, %v, want %q", f.MediaType(), got, err, f)
+		}
+	}
+	if _, err := FormatForMediaType("application/rdf+xml"); err == nil {
+		t.Errorf("FormatForMediaType() accepted RDF/XML")
+	}
+}
+
+func TestEncodeOptionsRoundTrip(t *testing.T) {
+	originalTriples := []triple.Triple{
+		{
+			Subject:   triple.IRI{Value: "http://example.org/note1"},
+			Predicate: triple.IRI{Value: "http://example.org/title"},
+			Object:    triple.Literal{Value: "Test"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/note1"},
+			Predicate: triple.IRI{Value: "http://example.org/author"},
+			Object:    triple.BlankNode{Value: "author"},
+		},
+		{
+			Subject:   triple.BlankNode{Value: "author"},
+			Predicate: triple.IRI{Value: "http://example.org/name"},
+			Object:    triple.Literal{Value: "John", Language: "en"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/note1"},
+			Predicate: triple.IRI{Value: "http://example.org/count"},
+			Object:    triple.Literal{Value: "42", Datatype: "http://www.w3.org/2001/XMLSchema#integer"},
+		},
+	}
+
+	opts := EncodeOptions{
+		Prefixes: map[string]string{"ex": "http://example.org/"},
+		Compact:  true,
+	}
+
+	for _, format := range []Format{NTriples, Turtle, JSONLD} {
+		t.Run(string(format), func(t *testing.T) {
+			encoded, err := Encode(originalTriples, format, opts)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			decodedTriples, _, err := Decode(encoded, format, DecodeOptions{Strict: true})
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if len(decodedTriples) != len(originalTriples) {
+				t.Errorf("Round trip produced %d triples, want %d", len(decodedTriples), len(originalTriples))
+			}
+
+			for i, original := range originalTriples {
+				found := false
+				for _, decoded := range decodedTriples {
+					if triplesEqual(original, decoded) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Original triple %d not found in decoded triples: %+v", i, original)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeOptions(t *testing.T) {
+	triples := []triple.Triple{
+		{
+			Subject:   triple.IRI{Value: "http://example.org/note2"},
+			Predicate: triple.IRI{Value: "http://example.org/title"},
+			Object:    triple.Literal{Value: "Second"},
+		},
+		{
+			Subject:   triple.BlankNode{Value: "xyz"},
+			Predicate: triple.IRI{Value: "http://example.org/title"},
+			Object:    triple.Literal{Value: "Blank"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/note1"},
+			Predicate: triple.IRI{Value: "http://example.org/title"},
+			Object:    triple.Literal{Value: "First"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		format   Format
+		opts     EncodeOptions
+		expected string
+	}{
+		{
+			name:   "ntriples sorted",
+			format: NTriples,
+			opts:   EncodeOptions{Sort: true},
+			expected: `<http://example.org/note1> <http://example.org/title> "First" .
+<http://example.org/note2> <http://example.org/title> "Second" .
+_:xyz <http://example.org/title> "Blank" .
+`,
+		},
+		{
+			name:   "ntriples relabeled blank nodes",
+			format: NTriples,
+			opts:   EncodeOptions{BlankNodes: BlankNodesRelabel},
+			expected: `<http://example.org/note2> <http://example.org/title> "Second" .
+_:b0 <http://example.org/title> "Blank" .
+<http://example.org/note1> <http://example.org/title> "First" .
+`,
+		},
+		{
+			name:   "turtle with base",
+			format: Turtle,
+			opts:   EncodeOptions{Base: "http://example.org/", Sort: true},
+			expected: `@base <http://example.org/> .
+
+<note1> <title> "First" .
+<note2> <title> "Second" .
+_:xyz <title> "Blank" .
+`,
+		},
+		{
+			name:   "turtle compact with indent",
+			format: Turtle,
+			opts: EncodeOptions{
+				Prefixes: map[string]string{"ex": "http://example.org/"},
+				Compact:  true,
+				Sort:     true,
+				Indent:   4,
+			},
+			expected: `@prefix ex: <http://example.org/> .
+
+ex:note1 ex:title "First" .
+
+ex:note2 ex:title "Second" .
+
+_:xyz ex:title "Blank" .
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Encode(triples, tt.format, tt.opts)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Encode() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeStrict(t *testing.T) {
+	triples := []triple.Triple{
+		{
+			Subject:   triple.Literal{Value: "not a subject"},
+			Predicate: triple.IRI{Value: "http://example.org/title"},
+			Object:    triple.Literal{Value: "Test"},
+		},
+	}
+
+	for _, format := range []Format{NTriples, Turtle, JSONLD} {
+		t.Run(string(format), func(t *testing.T) {
+			if _, err := Encode(triples, format, EncodeOptions{Strict: true}); err == nil {
+				t.Error("Encode() expected error for literal subject in strict mode")
+			}
+
+			skipped := 0
+			result, err := Encode(triples, format, EncodeOptions{Skipped: func(triple.Triple, error) { skipped++ }})
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if strings.Contains(result, "not a subject") {
+				t.Errorf("Encode() kept malformed triple: %q", result)
+			}
+			if skipped != 1 {
+				t.Errorf("Skipped called %d times, want 1", skipped)
+			}
+		})
+	}
+}
+
+// TestLanguageAndDatatype checks that a literal with both a language tag and
+// a datatype is a syntax error in every decoder and mode, and that encoders
+// do not write one.
+func TestLanguageAndDatatype(t *testing.T) {
+	inputs := map[Format]string{
+		NTriples: `<http://example.org/a> <http://example.org/p> "o"@en^^<http://example.org/d> .`,
+		Turtle:   `<http://example.org/a> <http://example.org/p> "o"@en^^<http://example.org/d> .`,
+		JSONLD:   `{"@id": "http://example.org/a", "http://example.org/p": {"@value": "o", "@language": "en", "@type": "http://example.org/d"}}`,
+	}
+	for format, input := range inputs {
+		for _, strict := range []bool{false, true} {
+			if triples, _, err := Decode(input, format, DecodeOptions{Strict: strict}); err == nil {
+				t.Errorf("%s (strict %v): Decode() = %v, want an error", format, strict, triples)
+			}
+		}
+	}
+
+	// Spaces do not make the datatype go away.
+	if tr, err := DecodeNTriple(`<http://example.org/a> <http://example.org/p> "o"@en ^^<http://example.org/d> .`); err == nil {
+		t.Errorf("DecodeNTriple() = %v, want an error", tr)
+	}
+
+	triples := []triple.Triple{{
+		Subject:   triple.IRI{Value: "http://example.org/a"},
+		Predicate: triple.IRI{Value: "http://example.org/p"},
+		Object:    triple.Literal{Value: "o", Language: "en", Datatype: "http://example.org/d"},
+	}}
+	for _, format := range []Format{NTriples, Turtle, JSONLD} {
+		if _, err := Encode(triples, format, EncodeOptions{Strict: true}); err == nil {
+			t.Errorf("%s: Encode() in strict mode succeeded", format)
+		}
+		skipped := 0
+		result, err := Encode(triples, format, EncodeOptions{Skipped: func(triple.Triple, error) { skipped++ }})
+		if err != nil || skipped != 1 || strings.Contains(result, `"o"`) {
+			t.Errorf("%s: Encode() = %q, %v with %d skipped", format, result, err, skipped)
+		}
+	}
+}
+
+func TestDecodeOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		format   Format
+		opts     DecodeOptions
+		expected []triple.Triple
+		wantErr  bool
+	}{
+		{
+			name:   "turtle relative IRIs with base option",
+			input:  `<note1> <title> "Test" .`,
+			format: Turtle,
+			opts:   DecodeOptions{Base: "http://example.org/"},
+			expected: []triple.Triple{
+				{
+					Subject:   triple.IRI{Value: "http://example.org/note1"},
+					Predicate: triple.IRI{Value: "http://example.org/title"},
+					Object:    triple.Literal{Value: "Test"},
+				},
+			},
+		},
+		{
+			name: "turtle base directive",
+			input: `@base <http://example.org/> .
+<note1> <title> "Test" .`,
+			format: Turtle,
+			expected: []triple.Triple{
+				{
+					Subject:   triple.IRI{Value: "http://example.org/note1"},
+					Predicate: triple.IRI{Value: "http://example.org/title"},
+					Object:    triple.Literal{Value: "Test"},
+				},
+			},
+		},
+		{
+			name:   "turtle predeclared prefixes",
+			input:  `ex:note1 ex:title "Test" .`,
+			format: Turtle,
+			opts:   DecodeOptions{Prefixes: map[string]string{"ex": "http://example.org/"}, Strict: true},
+			expected: []triple.Triple{
+				{
+					Subject:   triple.IRI{Value: "http://example.org/note1"},
+					Predicate: triple.IRI{Value: "http://example.org/title"},
+					Object:    triple.Literal{Value: "Test"},
+				},
+			},
+		},
+		{
+			name:    "turtle strict undefined prefix",
+			input:   `ex:note1 ex:title "Test" .`,
+			format:  Turtle,
+			opts:    DecodeOptions{Strict: true},
+			wantErr: true,
+		},
+		{
+			name:    "ntriples strict relative IRI",
+			input:   `<note1> <http://example.org/title> "Test" .`,
+			format:  NTriples,
+			opts:    DecodeOptions{Strict: true},
+			wantErr: true,
+		},
+		{
+			name:   "ntriples relabeled blank nodes",
+			input:  "_:x <http://example.org/p> _:y .\n_:y <http://example.org/p> _:x .",
+			format: NTriples,
+			opts:   DecodeOptions{BlankNodes: BlankNodesRelabel},
+			expected: []triple.Triple{
+				{
+					Subject:   triple.BlankNode{Value: "b0"},
+					Predicate: triple.IRI{Value: "http://example.org/p"},
+					Object:    triple.BlankNode{Value: "b1"},
+				},
+				{
+					Subject:   triple.BlankNode{Value: "b1"},
+					Predicate: triple.IRI{Value: "http://example.org/p"},
+					Object:    triple.BlankNode{Value: "b0"},
+				},
+			},
+		},
+		{
+			name: "jsonld compact document",
+			input: `{
+  "@context": {"ex": "http://example.org/", "@base": "http://example.org/base/"},
+  "@graph": [
+    {"@id": "ex:note1", "@type": "ex:Note", "ex:title": "Test", "ex:next": {"@id": "note2"}}
+  ]
+}`,
+			format: JSONLD,
+			opts:   DecodeOptions{Strict: true},
+			expected: []triple.Triple{
+				{
+					Subject:   triple.IRI{Value: "http://example.org/note1"},
+					Predicate: triple.IRI{Value: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"},
+					Object:    triple.IRI{Value: "http://example.org/Note"},
+				},
+				{
+					Subject:   triple.IRI{Value: "http://example.org/note1"},
+					Predicate: triple.IRI{Value: "http://example.org/next"},
+					Object:    triple.IRI{Value: "http://example.org/base/note2"},
+				},
+				{
+					Subject:   triple.IRI{Value: "http://example.org/note1"},
+					Predicate: triple.IRI{Value: "http://example.org/title"},
+					Object:    triple.Literal{Value: "Test"},
+				},
+			},
+		},
+		{
+			name:    "jsonld strict unsupported value",
+			input:   `[{"@id": "http://example.org/note1", "http://example.org/title": [["nested"]]}]`,
+			format:  JSONLD,
+			opts:    DecodeOptions{Strict: true},
+			wantErr: true,
+		},
+		{
+			name:    "ntriples strict IRI with space",
+			input:   `<http://example.org/a b> <http://example.org/title> "Test" .`,
+			format:  NTriples,
+			opts:    DecodeOptions{Strict: true},
+			wantErr: true,
+		},
+		{
+			name:    "turtle strict malformed language tag",
+			input:   `<http://example.org/note1> <http://example.org/title> "Test"@en-a .`,
+			format:  Turtle,
+			opts:    DecodeOptions{Strict: true},
+			wantErr: true,
+		},
+		{
+			name:    "jsonld strict language and datatype",
+			input:   `[{"@id": "http://example.org/note1", "http://example.org/title": {"@value": "Test", "@language": "en", "@type": "http://example.org/T"}}]`,
+			format:  JSONLD,
+			opts:    DecodeOptions{Strict: true},
+			wantErr: true,
+		},
+		{
+			name: "turtle validated literals",
+			input: `@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+<http://example.org/note1> <http://example.org/count> 7 ;
+    <http://example.org/due> "2024-01-15"^^xsd:date .`,
+			format: Turtle,
+			opts:   DecodeOptions{ValidateLiterals: true},
+			expected: []triple.Triple{
+				{
+					Subject:   triple.IRI{Value: "http://example.org/note1"},
+					Predicate: triple.IRI{Value: "http://example.org/count"},
+					Object:    triple.Literal{Value: "7", Datatype: "http://www.w3.org/2001/XMLSchema#integer"},
+				},
+				{
+					Subject:   triple.IRI{Value: "http://example.org/note1"},
+					Predicate: triple.IRI{Value: "http://example.org/due"},
+					Object:    triple.Literal{Value: "2024-01-15", Datatype: "http://www.w3.org/2001/XMLSchema#date"},
+				},
+			},
+		},
+		{
+			name:    "ntriples invalid typed literal",
+			input:   `<http://example.org/note1> <http://example.org/count> "seven"^^<http://www.w3.org/2001/XMLSchema#integer> .`,
+			format:  NTriples,
+			opts:    DecodeOptions{ValidateLiterals: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triples, _, err := Decode(tt.input, tt.format, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(triples) != len(tt.expected) {
+				t.Fatalf("Decode() got %d triples, want %d: %+v", len(triples), len(tt.expected), triples)
+			}
+			for i, expected := range tt.expected {
+				if !triplesEqual(triples[i], expected) {
+					t.Errorf("Decode() triple[%d] = %+v, want %+v", i, triples[i], expected)
+				}
+			}
+		})
+	}
+}
@@ -6,81 +6,6 @@ import (
 	"github.com/DeDude/tripl/pkg/triple"
 )
 
-func parseIRI(s string) (triple.IRI, string, error) {
-	if !strings.HasPrefix(s, "<") {
-		return triple.IRI{}, s, (&parseContext{}).error("expected IRI to start with <")
-	}
-
-	end := strings.Index(s, ">")
-	if end == -1 {
-		return triple.IRI{}, "", (&parseContext{}).error("unclosed IRI")
-	}
-
-	value := s[1:end]
-	rest := strings.TrimSpace(s[end+1:])
-	return triple.IRI{Value: value}, rest, nil
-}
-
-func parseBlankNode(s string) (triple.BlankNode, string, error) {
-	if !strings.HasPrefix(s, "_:") {
-		return triple.BlankNode{}, s, (&parseContext{}).error("expected blank node to start with _:")
-	}
-
-	parts := strings.SplitN(s[2:], " ", 2)
-	value := parts[0]
-	rest := ""
-	if len(parts) > 1 {
-		rest = parts[1]
-	}
-
-	return triple.BlankNode{Value: value}, rest, nil
-}
-
-func parseLiteral(s string) (triple.Literal, string, error) {
-	if !strings.HasPrefix(s, `"`) {
-		return triple.Literal{}, s, (&parseContext{}).error("expected literal to start with \"")
-	}
-
-	end := 1
-	for end < len(s) {
-		if s[end] == '"' && (end == 1 || s[end-1] != '\\') {
-			break
-		}
-		end++
-	}
-
-	if end >= len(s) {
-		return triple.Literal{}, "", (&parseContext{}).error("unclosed literal")
-	}
-
-	value := s[1:end]
-	rest := strings.TrimSpace(s[end+1:])
-	lit := triple.Literal{Value: value}
-
-	if strings.HasPrefix(rest, "@") {
-		parts := strings.SplitN(rest[1:], " ", 2)
-		lit.Language = parts[0]
-
-		if len(parts) > 1 {
-			rest = parts[1]
-		} else {
-			rest = ""
-		}
-		return lit, rest, nil
-	}
-
-	if strings.HasPrefix(rest, "^^<") {
-		end := strings.Index(rest, ">")
-		if end == -1 {
-			return triple.Literal{}, "", (&parseContext{}).error("unclosed datatype")
-		}
-		lit.Datatype = rest[3:end]
-		rest = strings.TrimSpace(rest[end+1:])
-	}
-
-	return lit, rest, nil
-}
-
 func parseIRIWithContext(s string, ctx *parseContext) (triple.IRI, string, error) {
 	if !strings.HasPrefix(s, "<") {
 		return triple.IRI{}, s, ctx.error("expected IRI to start with <")
@@ -91,7 +16,10 @@ func parseIRIWithContext(s string, ctx *parseContext) (triple.IRI, string, error
 		return triple.IRI{}, "", ctx.error("unclosed IRI")
 	}
 
-	value := s[1:end]
+	value, err := unescapeIRI(s[1:end])
+	if err != nil {
+		return triple.IRI{}, "", ctx.error(err.Error())
+	}
 	rest := strings.TrimSpace(s[end+1:])
 	ctx.advance(end + 1)
 	return triple.IRI{Value: value}, rest, nil
@@ -119,9 +47,9 @@ func parseLiteralWithContext(s string, ctx *parseContext) (triple.Literal, strin
 	}
 
 	end := 1
-	for end < len(s) {
-		if s[end] == '"' && (end == 1 || s[end-1] != '\\') {
-			break
+	for end < len(s) && s[end] != '"' {
+		if s[end] == '\\' {
+			end++
 		}
 		end++
 	}
@@ -130,20 +58,25 @@ func parseLiteralWithContext(s string, ctx *parseContext) (triple.Literal, strin
 		return triple.Literal{}, "", ctx.error("unclosed literal")
 	}
 
-	value := s[1:end]
+	value, err := unescapeString(s[1:end])
+	if err != nil {
+		return triple.Literal{}, "", ctx.error(err.Error())
+	}
 	rest := strings.TrimSpace(s[end+1:])
 	lit := triple.Literal{Value: value}
 	ctx.advance(end + 1)
 
 	if strings.HasPrefix(rest, "@") {
-		parts := strings.SplitN(rest[1:], " ", 2)
-		lit.Language = parts[0]
-		ctx.advance(1 + len(parts[0]))
-
-		if len(parts) > 1 {
-			rest = parts[1]
-		} else {
-			rest = ""
+		end := 1
+		for end < len(rest) && isLangTagChar(rest[end]) {
+			end++
+		}
+		lit.Language, lit.Direction = splitLangDir(rest[1:end])
+		ctx.advance(end)
+
+		rest = strings.TrimSpace(rest[end:])
+		if strings.HasPrefix(rest, "^^") {
+			return triple.Literal{}, "", ctx.error("literal cannot have both a language tag and a datatype")
 		}
 		return lit, rest, nil
 	}
@@ -160,3 +93,7 @@ func parseLiteralWithContext(s string, ctx *parseContext) (triple.Literal, strin
 
 	return lit, rest, nil
 }
+
+func isLangTagChar(c byte) bool {
+	return c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -25,12 +25,19 @@ func (pr *PrefixResolver) Expand(uri string) string {
 }
 
 func (pr *PrefixResolver) Shorten(iri string) string {
+	best, bestURI := "", ""
 	for prefix, uri := range pr.prefixes {
-		if strings.HasPrefix(iri, uri) {
-			return prefix + ":" + strings.TrimPrefix(iri, uri)
+		if uri == "" || !strings.HasPrefix(iri, uri) {
+			continue
 		}
+		if len(uri) > len(bestURI) || (len(uri) == len(bestURI) && prefix < best) {
+			best, bestURI = prefix, uri
+		}
+	}
+	if bestURI == "" {
+		return iri
 	}
-	return iri
+	return best + ":" + strings.TrimPrefix(iri, bestURI)
 }
 
 func (pr *PrefixResolver) Get(prefix string) (string, bool) {
@@ -0,0 +1,144 @@
+package encoder
+
+import (
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+)
+
+const rdfStarSample = `@prefix ex: <http://example.org/> .
+
+<< ex:alice ex:knows ex:bob >> ex:source ex:survey .
+ex:alice ex:age 42 ~ ex:r1 {| ex:certainty ex:high |} .
+ex:stmt <http://www.w3.org/1999/02/22-rdf-syntax-ns#reifies> <<( ex:s ex:p "o"@en )>> .`
+
+func rdfStarTriples() []triple.Triple {
+	ex := func(local string) triple.IRI { return triple.IRI{Value: "http://example.org/" + local} }
+	reifies := rdf.Reifies
+	age := triple.Literal{Value: "42", Datatype: "http://www.w3.org/2001/XMLSchema#integer"}
+
+	return []triple.Triple{
+		{Subject: triple.BlankNode{Value: "genid1"}, Predicate: reifies, Object: triple.TripleTerm{Triple: triple.Triple{Subject: ex("alice"), Predicate: ex("knows"), Object: ex("bob")}}},
+		{Subject: triple.BlankNode{Value: "genid1"}, Predicate: ex("source"), Object: ex("survey")},
+		{Subject: ex("alice"), Predicate: ex("age"), Object: age},
+		{Subject: ex("r1"), Predicate: reifies, Object: triple.TripleTerm{Triple: triple.Triple{Subject: ex("alice"), Predicate: ex("age"), Object: age}}},
+		{Subject: ex("r1"), Predicate: ex("certainty"), Object: ex("high")},
+		{Subject: ex("stmt"), Predicate: reifies, Object: triple.TripleTerm{Triple: triple.Triple{Subject: ex("s"), Predicate: ex("p"), Object: triple.Literal{Value: "o", Language: "en"}}}},
+	}
+}
+
+func TestDecodeTurtleTripleTerms(t *testing.T) {
+	triples, _, err := Decode(rdfStarSample, Turtle, DecodeOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	expected := rdfStarTriples()
+	if len(triples) != len(expected) {
+		t.Fatalf("Decode() got %d triples, want %d: %+v", len(triples), len(expected), triples)
+	}
+	for i := range expected {
+		if triples[i] != expected[i] {
+			t.Errorf("triple[%d] = %+v, want %+v", i, triples[i], expected[i])
+		}
+	}
+}
+
+func TestDecodeTurtleTripleTermErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"literal subject in triple term", `<http://example.org/a> <http://example.org/b> <<( "x" <http://example.org/p> <http://example.org/o> )>> .`},
+		{"triple term as subject", `<<( <http://example.org/s> <http://example.org/p> <http://example.org/o> )>> <http://example.org/b> <http://example.org/c> .`},
+		{"unclosed reified triple", `<< <http://example.org/s> <http://example.org/p> <http://example.org/o> <http://example.org/b> <http://example.org/c> .`},
+		{"unclosed annotation", `<http://example.org/s> <http://example.org/p> <http://example.org/o> {| <http://example.org/b> <http://example.org/c> .`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Decode(tt.input, Turtle, DecodeOptions{}); err == nil {
+				t.Error("Decode() expected error")
+			}
+		})
+	}
+}
+
+func TestTripleTermRoundTrip(t *testing.T) {
+	triples := rdfStarTriples()
+	prefixes := map[string]string{"ex": "http://example.org/"}
+
+	for _, format := range []Format{NTriples, Turtle, JSONLD} {
+		for _, compact := range []bool{false, true} {
+			encoded, err := Encode(triples, format, EncodeOptions{Prefixes: prefixes, Compact: compact, Strict: true})
+			if err != nil {
+				t.Fatalf("%s: Encode() error = %v", format, err)
+			}
+
+			decoded, _, err := Decode(encoded, format, DecodeOptions{Strict: true, BlankNodes: BlankNodesRelabel})
+			if err != nil {
+				t.Fatalf("%s: Decode() error = %v\n%s", format, err, encoded)
+			}
+
+			expected := applyBlankNodePolicy(triples, BlankNodesRelabel)
+			sortTriples(decoded)
+			sortTriples(expected)
+			if len(decoded) != len(expected) {
+				t.Fatalf("%s compact=%v: got %d triples, want %d\n%s", format, compact, len(decoded), len(expected), encoded)
+			}
+			for i := range expected {
+				if decoded[i] != expected[i] {
+					t.Errorf("%s compact=%v: triple[%d] = %+v, want %+v", format, compact, i, decoded[i], expected[i])
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeTurtleReifiedSubject(t *testing.T) {
+	triples := rdfStarTriples()[:2]
+	expected := `@prefix ex: <http://example.org/> .
+
+<< ex:alice ex:knows ex:bob >> ex:source ex:survey .
+`
+
+	result, err := Encode(triples, Turtle, EncodeOptions{Prefixes: map[string]string{"ex": "http://example.org/"}, Compact: true})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if result != expected {
+		t.Errorf("Encode() = %q, want %q", result, expected)
+	}
+}
+
+func TestDecodeJSONLDAnnotation(t *testing.T) {
+	input := `{
+  "@context": {"ex": "http://example.org/"},
+  "@id": "ex:alice",
+  "ex:knows": {"@id": "ex:bob", "@annotation": {"ex:source": {"@id": "ex:survey"}}}
+}`
+
+	triples, _, err := Decode(input, JSONLD, DecodeOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	expected := rdfStarTriples()[:2]
+	expected = append([]triple.Triple{{
+		Subject:   triple.IRI{Value: "http://example.org/alice"},
+		Predicate: triple.IRI{Value: "http://example.org/knows"},
+		Object:    triple.IRI{Value: "http://example.org/bob"},
+	}}, expected...)
+	expected[1].Subject = triple.BlankNode{Value: "jld1"}
+	expected[2].Subject = triple.BlankNode{Value: "jld1"}
+
+	if len(triples) != len(expected) {
+		t.Fatalf("Decode() got %d triples, want %d: %+v", len(triples), len(expected), triples)
+	}
+	for i := range expected {
+		if triples[i] != expected[i] {
+			t.Errorf("triple[%d] = %+v, want %+v", i, triples[i], expected[i])
+		}
+	}
+}
@@ -2,116 +2,71 @@ package encoder
 
 import (
 	"fmt"
-	"github.com/DeDude/tripl/pkg/triple"
 	"strings"
+
+	"github.com/DeDude/tripl/pkg/triple"
 )
 
 func EncodeTurtle(triples []triple.Triple, prefixes map[string]string) string {
-	resolver := NewPrefixResolver(prefixes)
-	var result strings.Builder
+	result, _ := encodeTurtle(triples, EncodeOptions{Prefixes: prefixes})
+	return result
+}
 
-	for prefix, uri := range prefixes {
-		result.WriteString(fmt.Sprintf("@prefix %s: <%s> .\n", prefix, uri))
-	}
+func EncodeTurtleCompact(triples []triple.Triple, prefixes map[string]string) string {
+	result, _ := encodeTurtle(triples, EncodeOptions{Prefixes: prefixes, Compact: true})
+	return result
+}
 
-	if len(prefixes) > 0 {
-		result.WriteString("\n")
+func encodeTurtle(triples []triple.Triple, opts EncodeOptions) (string, error) {
+	triples, err := prepareTriples(triples, opts)
+	if err != nil {
+		return "", err
 	}
 
-	for _, t := range triples {
-		subject := formatTurtleNode(t.Subject, resolver)
-		predicate := formatTurtleNode(t.Predicate, resolver)
-		object := formatTurtleNode(t.Object, resolver)
-		result.WriteString(fmt.Sprintf("%s %s %s .\n", subject, predicate, object))
-	}
+	w := &turtleWriter{resolver: NewPrefixResolver(opts.Prefixes), opts: opts}
+	var result strings.Builder
 
-	return result.String()
-}
+	writeTurtleHeader(&result, opts)
 
-func formatTurtleNode(n triple.Node, resolver *PrefixResolver) string {
-	switch node := n.(type) {
-	case triple.IRI:
-		shortened := resolver.Shorten(node.Value)
-		if shortened != node.Value {
-			return shortened
+	if !opts.Compact {
+		for _, t := range triples {
+			subject := w.node(t.Subject)
+			predicate := w.node(t.Predicate)
+			object := w.node(t.Object)
+			result.WriteString(fmt.Sprintf("%s %s %s .\n", subject, predicate, object))
 		}
-		return fmt.Sprintf("<%s>", node.Value)
-	case triple.Literal:
-		result := fmt.Sprintf(`"%s"`, node.Value)
-		if node.Language != "" {
-			result += "@" + node.Language
-		}
-		if node.Datatype != "" {
-			datatypeShort := resolver.Shorten(node.Datatype)
-			if datatypeShort != node.Datatype {
-				result += "^^" + datatypeShort
-			} else {
-				result += "^^<" + node.Datatype + ">"
-			}
-		}
-		return result
-	case triple.BlankNode:
-		return "_:" + node.Value
-	default:
-		return ""
+		return result.String(), nil
 	}
-}
 
-func EncodeTurtleCompact(triples []triple.Triple, prefixes map[string]string) string {
-	resolver := NewPrefixResolver(prefixes)
-	var result strings.Builder
+	w.writeCompactBody(&result, triples)
+	return result.String(), nil
+}
 
-	for prefix, uri := range prefixes {
-		result.WriteString(fmt.Sprintf("@prefix %s: <%s> .\n", prefix, uri))
+func writeTurtleHeader(result *strings.Builder, opts EncodeOptions) {
+	if opts.Base != "" {
+		result.WriteString(fmt.Sprintf("@base <%s> .\n", opts.Base))
 	}
 
-	if len(prefixes) > 0 {
-		result.WriteString("\n")
+	for _, prefix := range sortedPrefixes(opts.Prefixes) {
+		result.WriteString(fmt.Sprintf("@prefix %s: <%s> .\n", prefix, opts.Prefixes[prefix]))
 	}
 
-	grouped := groupTriples(triples)
-
-	for i, subjectGroup := range grouped {
-		if i > 0 {
-			result.WriteString("\n")
-		}
-
-		subject := formatTurtleNode(subjectGroup.subject, resolver)
-		result.WriteString(subject)
-
-		for j, predGroup := range subjectGroup.predicates {
-			if j == 0 {
-				result.WriteString(" ")
-			} else {
-				result.WriteString(" ;\n         ")
-			}
-
-			predicate := predGroup.predicate
-
-			if iri, ok := predicate.(triple.IRI); ok && iri.Value == rdfType {
-				result.WriteString("a")
-			} else {
-				result.WriteString(formatTurtleNode(predicate, resolver))
-			}
-
-			for k, obj := range predGroup.objects {
-				if k == 0 {
-					result.WriteString(" ")
-				} else {
-					result.WriteString(", ")
-				}
-
-				result.WriteString(formatTurtleNode(obj, resolver))
-			}
-		}
-
-		result.WriteString(" .\n")
+	if opts.Base != "" || len(opts.Prefixes) > 0 {
+		result.WriteString("\n")
 	}
+}
 
-	return result.String()
+func formatTurtleNode(n triple.Node, resolver *PrefixResolver) string {
+	return (&turtleWriter{resolver: resolver}).node(n)
 }
 
-const rdfType = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+// FormatTurtleTerm returns a single term in Turtle syntax, shortening IRIs
+// with prefixes and writing numbers and booleans without quotes.
+func FormatTurtleTerm(n triple.Node, prefixes map[string]string) string {
+	w := &turtleWriter{resolver: NewPrefixResolver(prefixes)}
+	w.opts.Turtle.LiteralShorthand = true
+	return w.node(n)
+}
 
 type subjectGroup struct {
 	subject    triple.Node
@@ -0,0 +1,928 @@
+package encoder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+// TurtleDocument is a concrete syntax tree for a Turtle document. It keeps the
+// original source, so comments, blank lines and statement order survive edits.
+type TurtleDocument struct {
+	source     string
+	opts       DecodeOptions
+	statements []*TurtleStatement
+	prefixes   map[string]string
+	base       string
+}
+
+type TurtleStatement struct {
+	// Leading holds the whitespace and comments between the previous statement and this one.
+	Leading   string
+	Text      string
+	Directive bool
+	Triples   []triple.Triple
+
+	start      int
+	end        int
+	subject    cstTerm
+	predicates []cstPredicateObjects
+}
+
+type cstTerm struct {
+	start int
+	end   int
+	node  triple.Node
+}
+
+type cstPredicateObjects struct {
+	verb    cstTerm
+	objects []cstTerm
+	// separator is the offset of the ';' that follows the group, or -1.
+	separator int
+}
+
+func ParseTurtleDocument(input string, opts DecodeOptions) (*TurtleDocument, error) {
+	tokens, err := tokenizeTurtle(input)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := make(map[string]string, len(opts.Prefixes))
+	for k, v := range opts.Prefixes {
+		prefixes[k] = v
+	}
+
+	p := &turtleParser{
+		src:      input,
+		resolver: NewPrefixResolver(prefixes),
+		base:     opts.Base,
+		strict:   opts.Strict,
+		used:     make(map[string]bool),
+	}
+	for _, tok := range tokens {
+		if tok.kind == tokBlankNode {
+			p.used[tok.text[2:]] = true
+		}
+		if tok.kind != tokWhitespace && tok.kind != tokComment {
+			p.tokens = append(p.tokens, tok)
+		}
+	}
+
+	doc := &TurtleDocument{source: input, opts: opts}
+	prevEnd := 0
+
+	for p.peek().kind != tokEOF {
+		stmt, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Leading = input[prevEnd:stmt.start]
+		stmt.Text = input[stmt.start:stmt.end]
+		prevEnd = stmt.end
+		doc.statements = append(doc.statements, stmt)
+	}
+
+	doc.prefixes = p.resolver.All()
+	doc.base = p.base
+	return doc, nil
+}
+
+func (d *TurtleDocument) String() string {
+	return d.source
+}
+
+func (d *TurtleDocument) Statements() []*TurtleStatement {
+	return d.statements
+}
+
+func (d *TurtleDocument) Prefixes() map[string]string {
+	return d.prefixes
+}
+
+func (d *TurtleDocument) Triples() []triple.Triple {
+	var triples []triple.Triple
+	for _, stmt := range d.statements {
+		triples = append(triples, stmt.Triples...)
+	}
+	return triples
+}
+
+func (d *TurtleDocument) Contains(t triple.Triple) bool {
+	for _, stmt := range d.statements {
+		for _, existing := range stmt.Triples {
+			if existing == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseTriple parses a single Turtle triple using the document's prefixes and base.
+func (d *TurtleDocument) ParseTriple(text string) (triple.Triple, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasSuffix(text, ".") {
+		text += " ."
+	}
+
+	doc, err := ParseTurtleDocument(text, DecodeOptions{Prefixes: d.prefixes, Base: d.base, Strict: true})
+	if err != nil {
+		return triple.Triple{}, err
+	}
+
+	triples := doc.Triples()
+	if len(triples) != 1 {
+		return triple.Triple{}, fmt.Errorf("expected exactly one triple, got %d", len(triples))
+	}
+	return triples[0], nil
+}
+
+// Add inserts t next to an existing statement about the same subject, or
+// appends a new statement. It reports whether the document changed.
+func (d *TurtleDocument) Add(t triple.Triple) (bool, error) {
+	if err := t.Validate(); err != nil {
+		return false, err
+	}
+	if d.Contains(t) {
+		return false, nil
+	}
+
+	w := &turtleWriter{resolver: NewPrefixResolver(d.prefixes), opts: EncodeOptions{Base: d.base, Turtle: TurtleStyle{LiteralShorthand: true}}}
+	object := w.node(t.Object)
+
+	var edited string
+	if stmt := d.lastStatementAbout(t.Subject); stmt != nil {
+		edited = d.insertInto(stmt, w.predicate(t.Predicate), t.Predicate, object)
+	} else {
+		edited = d.appendStatement(fmt.Sprintf("%s %s %s .\n", w.node(t.Subject), w.predicate(t.Predicate), object))
+	}
+
+	if err := d.reparse(edited); err != nil {
+		return false, err
+	}
+	if !d.Contains(t) {
+		return false, fmt.Errorf("inserted text does not produce triple %s", EncodeNTriple(t))
+	}
+	return true, nil
+}
+
+// Remove deletes every explicit occurrence of t and reports how many were removed.
+func (d *TurtleDocument) Remove(t triple.Triple) (int, error) {
+	removed := 0
+	for {
+		edited, ok := d.removeOne(t)
+		if !ok {
+			return removed, nil
+		}
+		if err := d.reparse(edited); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+}
+
+func (d *TurtleDocument) reparse(source string) error {
+	doc, err := ParseTurtleDocument(source, d.opts)
+	if err != nil {
+		return fmt.Errorf("edited document no longer parses: %w", err)
+	}
+	*d = *doc
+	return nil
+}
+
+func (d *TurtleDocument) lastStatementAbout(subject triple.Node) *TurtleStatement {
+	for i := len(d.statements) - 1; i >= 0; i-- {
+		stmt := d.statements[i]
+		if !stmt.Directive && len(stmt.predicates) > 0 && stmt.subject.node == subject {
+			return stmt
+		}
+	}
+	return nil
+}
+
+func (d *TurtleDocument) insertInto(stmt *TurtleStatement, verb string, predicate triple.Node, object string) string {
+	for _, po := range stmt.predicates {
+		if po.verb.node == predicate {
+			at := po.objects[len(po.objects)-1].end
+			return d.source[:at] + ", " + object + d.source[at:]
+		}
+	}
+
+	last := stmt.predicates[len(stmt.predicates)-1]
+	at := last.objects[len(last.objects)-1].end
+
+	indent := strings.Repeat(" ", d.column(stmt.predicates[0].verb.start))
+	if len(stmt.predicates) > 1 {
+		start := stmt.predicates[1].verb.start
+		if lineStart := strings.LastIndexByte(d.source[:start], '\n') + 1; strings.TrimSpace(d.source[lineStart:start]) == "" {
+			indent = d.source[lineStart:start]
+		}
+	}
+
+	return d.source[:at] + " ;\n" + indent + verb + " " + object + d.source[at:]
+}
+
+func (d *TurtleDocument) column(offset int) int {
+	return offset - (strings.LastIndexByte(d.source[:offset], '\n') + 1)
+}
+
+func (d *TurtleDocument) appendStatement(text string) string {
+	source := d.source
+	switch {
+	case source == "":
+	case strings.HasSuffix(source, "\n\n"):
+	case strings.HasSuffix(source, "\n"):
+		source += "\n"
+	default:
+		source += "\n\n"
+	}
+	return source + text
+}
+
+func (d *TurtleDocument) removeOne(t triple.Triple) (string, bool) {
+	for _, stmt := range d.statements {
+		if stmt.Directive || stmt.subject.node != t.Subject {
+			continue
+		}
+
+		for i, po := range stmt.predicates {
+			if po.verb.node != t.Predicate {
+				continue
+			}
+
+			for j, obj := range po.objects {
+				if obj.node != t.Object {
+					continue
+				}
+
+				switch {
+				case len(po.objects) > 1 && j > 0:
+					return d.splice(po.objects[j-1].end, obj.end, ""), true
+				case len(po.objects) > 1:
+					return d.splice(obj.start, po.objects[1].start, ""), true
+				case len(stmt.predicates) > 1 && i == len(stmt.predicates)-1:
+					// Move the terminating '.' up to the previous group.
+					prev := stmt.predicates[i-1]
+					return d.splice(prev.objects[len(prev.objects)-1].end, stmt.end, " ."), true
+				case len(stmt.predicates) > 1:
+					return d.splice(po.verb.start, stmt.predicates[i+1].verb.start, ""), true
+				default:
+					return d.cutStatement(stmt), true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func (d *TurtleDocument) cut(start, end int) string {
+	return d.source[:start] + d.source[end:]
+}
+
+// splice replaces the tokens between start and end with text. Comments
+// among the removed tokens are kept, each still ending its line, and a
+// comment that followed the range on its line stays on a line of its own.
+func (d *TurtleDocument) splice(start, end int, text string) string {
+	before := d.source[:start] + text
+	kept := d.keptComments(start, end, before)
+	rest := d.source[end:]
+	if kept == "" {
+		return before + rest
+	}
+
+	if i := strings.LastIndexByte(kept, '\n'); i != -1 && strings.TrimSpace(kept[i:]) == "" {
+		line, _, _ := strings.Cut(rest, "\n")
+		switch line = strings.TrimLeft(line, " \t"); {
+		case line == "":
+			kept, rest = kept[:i], strings.TrimLeft(rest, " \t")
+		case strings.HasPrefix(line, "#"):
+			kept, rest = kept[:i+1], strings.TrimLeft(rest, " \t")
+		}
+	}
+	return before + kept + rest
+}
+
+// keptComments returns the comments between start and end with the line
+// breaks and indentation that follow them, or "" if there are none. before
+// is the text that will precede them.
+func (d *TurtleDocument) keptComments(start, end int, before string) string {
+	tokens, err := tokenizeTurtle(d.source[start:end])
+	if err != nil {
+		return ""
+	}
+
+	var kept strings.Builder
+	for i, tok := range tokens {
+		switch {
+		case tok.kind == tokComment:
+			if kept.Len() == 0 && before != "" && !strings.HasSuffix(before, " ") && !strings.HasSuffix(before, "\n") {
+				space := " "
+				if prev := tokens[max(i-1, 0)]; i > 0 && prev.kind == tokWhitespace && !strings.Contains(prev.text, "\n") {
+					space = prev.text
+				}
+				kept.WriteString(space)
+			}
+			kept.WriteString(tok.text)
+		case tok.kind == tokWhitespace && i > 0 && tokens[i-1].kind == tokComment:
+			kept.WriteString(tok.text[strings.LastIndexByte(tok.text, '\n'):])
+		}
+	}
+	return kept.String()
+}
+
+// cutStatement removes a statement. Comments inside it or after it on its
+// last line are kept on lines of their own.
+func (d *TurtleDocument) cutStatement(stmt *TurtleStatement) string {
+	start, end := stmt.start, stmt.end
+
+	lineStart := strings.LastIndexByte(d.source[:start], '\n') + 1
+	lineEnd := strings.IndexByte(d.source[end:], '\n')
+	if lineEnd == -1 {
+		lineEnd = len(d.source)
+	} else {
+		lineEnd += end + 1
+	}
+
+	indent := d.source[lineStart:start]
+	after := strings.TrimSpace(d.source[end:lineEnd])
+	if strings.TrimSpace(indent) != "" || after != "" && !strings.HasPrefix(after, "#") {
+		return d.splice(start, end, "")
+	}
+
+	var comments strings.Builder
+	if tokens, err := tokenizeTurtle(d.source[start:lineEnd]); err == nil {
+		for _, tok := range tokens {
+			if tok.kind == tokComment {
+				comments.WriteString(indent + tok.text + "\n")
+			}
+		}
+	}
+	if comments.Len() > 0 {
+		return d.source[:lineStart] + comments.String() + d.source[lineEnd:]
+	}
+
+	start, end = lineStart, lineEnd
+	if start == 0 || strings.HasSuffix(d.source[:start], "\n\n") {
+		if end < len(d.source) && d.source[end] == '\n' {
+			end++
+		} else if end == len(d.source) && start > 0 {
+			start--
+		}
+	}
+	return d.cut(start, end)
+}
+
+type turtleParser struct {
+	src      string
+	tokens   []turtleToken
+	pos      int
+	resolver *PrefixResolver
+	base     string
+	strict   bool
+	blanks   int
+	// used holds the blank node labels written in the source, which
+	// generated labels must not repeat.
+	used    map[string]bool
+	triples []triple.Triple
+}
+
+func (p *turtleParser) peek() turtleToken {
+	if p.pos >= len(p.tokens) {
+		return turtleToken{kind: tokEOF, start: len(p.src)}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *turtleParser) next() turtleToken {
+	tok := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *turtleParser) errorAt(tok turtleToken, msg string) error {
+	if tok.kind == tokEOF {
+		return fmt.Errorf("%s at end of input", msg)
+	}
+	return (&parseContext{line: tok.line, column: tok.column}).error(msg)
+}
+
+func (p *turtleParser) expect(text string) (turtleToken, error) {
+	tok := p.next()
+	if tok.kind != tokPunct || tok.text != text {
+		return tok, p.errorAt(tok, fmt.Sprintf("expected %q, found %q", text, tok.text))
+	}
+	return tok, nil
+}
+
+func (p *turtleParser) emit(subject, predicate, object triple.Node) {
+	p.triples = append(p.triples, triple.Triple{
+		Subject:   subject,
+		Predicate: predicate,
+		Object:    object,
+	})
+}
+
+func (p *turtleParser) freshBlankNode() triple.BlankNode {
+	for {
+		p.blanks++
+		label := fmt.Sprintf("genid%d", p.blanks)
+		if !p.used[label] {
+			return triple.BlankNode{Value: label}
+		}
+	}
+}
+
+func (p *turtleParser) parseStatement() (*TurtleStatement, error) {
+	first := p.peek()
+	stmt := &TurtleStatement{start: first.start}
+
+	if first.kind == tokKeyword && first.text != "a" && first.text != "true" && first.text != "false" {
+		stmt.Directive = true
+		end, err := p.parseDirective()
+		if err != nil {
+			return nil, err
+		}
+		stmt.end = end
+		return stmt, nil
+	}
+
+	p.triples = nil
+
+	var err error
+	if first.is(tokPunct, "[") || first.is(tokPunct, "<<") {
+		if first.is(tokPunct, "[") {
+			stmt.subject, err = p.parseBlankNodePropertyList()
+		} else {
+			stmt.subject, err = p.parseReifiedTriple()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek().is(tokPunct, ".") {
+			stmt.predicates, err = p.parsePredicateObjectList(stmt.subject.node)
+		}
+	} else {
+		stmt.subject, err = p.parseSubject()
+		if err != nil {
+			return nil, err
+		}
+		stmt.predicates, err = p.parsePredicateObjectList(stmt.subject.node)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dot, err := p.expect(".")
+	if err != nil {
+		return nil, err
+	}
+
+	if p.strict {
+		for _, t := range p.triples {
+			if err := t.Validate(); err != nil {
+				return nil, p.errorAt(first, err.Error())
+			}
+		}
+	}
+
+	stmt.end = dot.end()
+	stmt.Triples = p.triples
+	return stmt, nil
+}
+
+func (p *turtleParser) parseDirective() (int, error) {
+	keyword := p.next()
+	sparqlStyle := !strings.HasPrefix(keyword.text, "@")
+	isPrefix := strings.EqualFold(strings.TrimPrefix(keyword.text, "@"), "prefix")
+
+	prefix := ""
+	if isPrefix {
+		name := p.next()
+		if name.kind != tokPrefixedName || !strings.HasSuffix(name.text, ":") {
+			return 0, p.errorAt(name, "invalid prefix declaration")
+		}
+		prefix = strings.TrimSuffix(name.text, ":")
+	}
+
+	iriTok := p.next()
+	if iriTok.kind != tokIRI {
+		if isPrefix {
+			return 0, p.errorAt(iriTok, "prefix URI must be in angle brackets")
+		}
+		return 0, p.errorAt(iriTok, "base URI must be in angle brackets")
+	}
+	iri, err := p.iriValue(iriTok)
+	if err != nil {
+		return 0, err
+	}
+
+	if isPrefix {
+		p.resolver.Set(prefix, iri)
+	} else {
+		p.base = iri
+	}
+
+	if sparqlStyle {
+		return iriTok.end(), nil
+	}
+	dot, err := p.expect(".")
+	if err != nil {
+		return 0, err
+	}
+	return dot.end(), nil
+}
+
+func (p *turtleParser) iriValue(tok turtleToken) (string, error) {
+	value, err := unescapeIRI(tok.text[1 : len(tok.text)-1])
+	if err != nil {
+		return "", p.errorAt(tok, err.Error())
+	}
+	return resolveIRI(p.base, value), nil
+}
+
+func (p *turtleParser) prefixedNameValue(tok turtleToken) (string, error) {
+	prefix, local, _ := strings.Cut(tok.text, ":")
+	if strings.Contains(local, `\`) {
+		var b strings.Builder
+		for i := 0; i < len(local); i++ {
+			if local[i] == '\\' && i+1 < len(local) {
+				i++
+			}
+			b.WriteByte(local[i])
+		}
+		local = b.String()
+	}
+
+	namespace, ok := p.resolver.Get(prefix)
+	if !ok {
+		if p.strict {
+			return "", p.errorAt(tok, fmt.Sprintf("undefined prefix %q", prefix))
+		}
+		return tok.text, nil
+	}
+	return namespace + local, nil
+}
+
+func (p *turtleParser) parseIRI() (cstTerm, error) {
+	tok := p.next()
+	term := cstTerm{start: tok.start, end: tok.end()}
+
+	switch tok.kind {
+	case tokIRI:
+		value, err := p.iriValue(tok)
+		term.node = triple.IRI{Value: value}
+		return term, err
+	case tokPrefixedName:
+		value, err := p.prefixedNameValue(tok)
+		term.node = triple.IRI{Value: value}
+		return term, err
+	}
+	return term, p.errorAt(tok, fmt.Sprintf("expected IRI, found %q", tok.text))
+}
+
+func (p *turtleParser) parseSubject() (cstTerm, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokBlankNode:
+		p.next()
+		return cstTerm{start: tok.start, end: tok.end(), node: triple.BlankNode{Value: tok.text[2:]}}, nil
+	case tok.is(tokPunct, "("):
+		return p.parseCollection()
+	}
+	term, err := p.parseIRI()
+	if err != nil {
+		return term, fmt.Errorf("parsing subject: %w", err)
+	}
+	return term, nil
+}
+
+func (p *turtleParser) parseVerb() (cstTerm, error) {
+	tok := p.peek()
+	if tok.is(tokKeyword, "a") {
+		p.next()
+		return cstTerm{start: tok.start, end: tok.end(), node: rdf.Type}, nil
+	}
+	term, err := p.parseIRI()
+	if err != nil {
+		return term, fmt.Errorf("parsing predicate: %w", err)
+	}
+	return term, nil
+}
+
+func (p *turtleParser) parsePredicateObjectList(subject triple.Node) ([]cstPredicateObjects, error) {
+	var list []cstPredicateObjects
+
+	for {
+		verb, err := p.parseVerb()
+		if err != nil {
+			return nil, err
+		}
+
+		po := cstPredicateObjects{verb: verb, separator: -1}
+		for {
+			object, err := p.parseObject()
+			if err != nil {
+				return nil, fmt.Errorf("parsing object: %w", err)
+			}
+			p.emit(subject, verb.node, object.node)
+
+			end, err := p.parseAnnotation(subject, verb.node, object.node)
+			if err != nil {
+				return nil, err
+			}
+			if end != -1 {
+				object.end = end
+			}
+			po.objects = append(po.objects, object)
+
+			if !p.peek().is(tokPunct, ",") {
+				break
+			}
+			p.next()
+		}
+		if tok := p.peek(); tok.is(tokPunct, ";") {
+			po.separator = tok.start
+		}
+		list = append(list, po)
+
+		if po.separator == -1 {
+			return list, nil
+		}
+		for p.peek().is(tokPunct, ";") {
+			p.next()
+		}
+		if tok := p.peek(); tok.is(tokPunct, ".") || tok.is(tokPunct, "]") || tok.is(tokPunct, "|}") || tok.kind == tokEOF {
+			return list, nil
+		}
+	}
+}
+
+func (p *turtleParser) parseObject() (cstTerm, error) {
+	tok := p.peek()
+	term := cstTerm{start: tok.start, end: tok.end()}
+
+	switch tok.kind {
+	case tokIRI, tokPrefixedName:
+		return p.parseIRI()
+	case tokBlankNode:
+		p.next()
+		term.node = triple.BlankNode{Value: tok.text[2:]}
+		return term, nil
+	case tokString:
+		return p.parseLiteral()
+	case tokInteger:
+		p.next()
+		term.node = triple.Literal{Value: tok.text, Datatype: xsd.Integer}
+		return term, nil
+	case tokDecimal:
+		p.next()
+		term.node = triple.Literal{Value: tok.text, Datatype: xsd.Decimal}
+		return term, nil
+	case tokDouble:
+		p.next()
+		term.node = triple.Literal{Value: tok.text, Datatype: xsd.Double}
+		return term, nil
+	case tokKeyword:
+		if tok.text == "true" || tok.text == "false" {
+			p.next()
+			term.node = triple.Literal{Value: tok.text, Datatype: xsd.Boolean}
+			return term, nil
+		}
+	case tokPunct:
+		switch tok.text {
+		case "[":
+			return p.parseBlankNodePropertyList()
+		case "(":
+			return p.parseCollection()
+		case "<<(":
+			return p.parseTripleTerm()
+		case "<<":
+			return p.parseReifiedTriple()
+		}
+	}
+
+	return term, p.errorAt(tok, fmt.Sprintf("unexpected %q", tok.text))
+}
+
+// parseQuotedTerm parses the subject or object of a triple term or reified
+// triple, where only IRIs, blank nodes, literals and nested triples may appear.
+func (p *turtleParser) parseQuotedTerm(object, reified bool) (cstTerm, error) {
+	tok := p.peek()
+	switch {
+	case tok.is(tokPunct, "["):
+		p.next()
+		closing, err := p.expect("]")
+		if err != nil {
+			return cstTerm{}, err
+		}
+		return cstTerm{start: tok.start, end: closing.end(), node: p.freshBlankNode()}, nil
+	case tok.kind == tokIRI, tok.kind == tokPrefixedName, tok.kind == tokBlankNode:
+		return p.parseObject()
+	case tok.is(tokPunct, "<<") && reified:
+		return p.parseReifiedTriple()
+	case !object:
+	case tok.is(tokPunct, "<<("),
+		tok.kind == tokString, tok.kind == tokInteger, tok.kind == tokDecimal, tok.kind == tokDouble,
+		tok.is(tokKeyword, "true"), tok.is(tokKeyword, "false"):
+		return p.parseObject()
+	}
+	return cstTerm{}, p.errorAt(tok, fmt.Sprintf("unexpected %q in quoted triple", tok.text))
+}
+
+func (p *turtleParser) parseQuotedTriple(reified bool) (triple.Triple, error) {
+	subject, err := p.parseQuotedTerm(false, reified)
+	if err != nil {
+		return triple.Triple{}, err
+	}
+	verb, err := p.parseVerb()
+	if err != nil {
+		return triple.Triple{}, err
+	}
+	object, err := p.parseQuotedTerm(true, reified)
+	if err != nil {
+		return triple.Triple{}, err
+	}
+	return triple.Triple{Subject: subject.node, Predicate: verb.node, Object: object.node}, nil
+}
+
+func (p *turtleParser) parseTripleTerm() (cstTerm, error) {
+	open, err := p.expect("<<(")
+	if err != nil {
+		return cstTerm{}, err
+	}
+	t, err := p.parseQuotedTriple(false)
+	if err != nil {
+		return cstTerm{}, err
+	}
+	closing, err := p.expect(")>>")
+	if err != nil {
+		return cstTerm{}, err
+	}
+	return cstTerm{start: open.start, end: closing.end(), node: triple.TripleTerm{Triple: t}}, nil
+}
+
+// parseReifiedTriple parses << s p o ~ r >>, emitting r rdf:reifies <<( s p o )>>.
+// The term's node is the reifier.
+func (p *turtleParser) parseReifiedTriple() (cstTerm, error) {
+	open, err := p.expect("<<")
+	if err != nil {
+		return cstTerm{}, err
+	}
+	t, err := p.parseQuotedTriple(true)
+	if err != nil {
+		return cstTerm{}, err
+	}
+
+	var reifier triple.Node
+	if p.peek().is(tokPunct, "~") {
+		r, err := p.parseReifier()
+		if err != nil {
+			return cstTerm{}, err
+		}
+		reifier = r.node
+	} else {
+		reifier = p.freshBlankNode()
+	}
+
+	closing, err := p.expect(">>")
+	if err != nil {
+		return cstTerm{}, err
+	}
+	p.emit(reifier, rdf.Reifies, triple.TripleTerm{Triple: t})
+	return cstTerm{start: open.start, end: closing.end(), node: reifier}, nil
+}
+
+func (p *turtleParser) parseReifier() (cstTerm, error) {
+	tilde, err := p.expect("~")
+	if err != nil {
+		return cstTerm{}, err
+	}
+	switch tok := p.peek(); tok.kind {
+	case tokIRI, tokPrefixedName:
+		return p.parseIRI()
+	case tokBlankNode:
+		p.next()
+		return cstTerm{start: tok.start, end: tok.end(), node: triple.BlankNode{Value: tok.text[2:]}}, nil
+	}
+	return cstTerm{start: tilde.start, end: tilde.end(), node: p.freshBlankNode()}, nil
+}
+
+// parseAnnotation parses the reifiers and {| |} blocks that may follow an
+// object, and returns the end offset of the annotation or -1 if there is none.
+func (p *turtleParser) parseAnnotation(subject, predicate, object triple.Node) (int, error) {
+	term := triple.TripleTerm{Triple: triple.Triple{Subject: subject, Predicate: predicate, Object: object}}
+	end := -1
+
+	var pending triple.Node
+	for {
+		switch tok := p.peek(); {
+		case tok.is(tokPunct, "~"):
+			r, err := p.parseReifier()
+			if err != nil {
+				return -1, err
+			}
+			p.emit(r.node, rdf.Reifies, term)
+			pending, end = r.node, r.end
+		case tok.is(tokPunct, "{|"):
+			p.next()
+			reifier := pending
+			if reifier == nil {
+				reifier = p.freshBlankNode()
+				p.emit(reifier, rdf.Reifies, term)
+			}
+			pending = nil
+			if _, err := p.parsePredicateObjectList(reifier); err != nil {
+				return -1, err
+			}
+			closing, err := p.expect("|}")
+			if err != nil {
+				return -1, err
+			}
+			end = closing.end()
+		default:
+			return end, nil
+		}
+	}
+}
+
+func (p *turtleParser) parseLiteral() (cstTerm, error) {
+	tok := p.next()
+	value, err := unescapeTurtleString(tok.text)
+	if err != nil {
+		return cstTerm{}, p.errorAt(tok, err.Error())
+	}
+
+	lit := triple.Literal{Value: value}
+	term := cstTerm{start: tok.start, end: tok.end()}
+
+	switch next := p.peek(); next.kind {
+	case tokLangTag:
+		p.next()
+		lit.Language, lit.Direction = splitLangDir(next.text[1:])
+		term.end = next.end()
+	case tokDatatypeMarker:
+		p.next()
+		datatype, err := p.parseIRI()
+		if err != nil {
+			return term, err
+		}
+		lit.Datatype = datatype.node.(triple.IRI).Value
+		term.end = datatype.end
+	}
+
+	term.node = lit
+	return term, nil
+}
+
+func (p *turtleParser) parseBlankNodePropertyList() (cstTerm, error) {
+	open, err := p.expect("[")
+	if err != nil {
+		return cstTerm{}, err
+	}
+
+	node := p.freshBlankNode()
+	if !p.peek().is(tokPunct, "]") {
+		if _, err := p.parsePredicateObjectList(node); err != nil {
+			return cstTerm{}, err
+		}
+	}
+
+	closing, err := p.expect("]")
+	if err != nil {
+		return cstTerm{}, err
+	}
+	return cstTerm{start: open.start, end: closing.end(), node: node}, nil
+}
+
+func (p *turtleParser) parseCollection() (cstTerm, error) {
+	open, err := p.expect("(")
+	if err != nil {
+		return cstTerm{}, err
+	}
+
+	var items []triple.Node
+	for !p.peek().is(tokPunct, ")") {
+		if p.peek().kind == tokEOF {
+			return cstTerm{}, p.errorAt(p.peek(), "unclosed collection")
+		}
+		item, err := p.parseObject()
+		if err != nil {
+			return cstTerm{}, err
+		}
+		items = append(items, item.node)
+	}
+	closing := p.next()
+
+	head, triples := triple.NewList(items, func() triple.Node { return p.freshBlankNode() })
+	for _, t := range triples {
+		p.emit(t.Subject, t.Predicate, t.Object)
+	}
+
+	return cstTerm{start: open.start, end: closing.end(), node: head}, nil
+}
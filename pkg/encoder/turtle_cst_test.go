@@ -0,0 +1,407 @@
+package encoder
+
+import (
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+const cstSample = `# Notes ontology
+@prefix ex: <http://example.org/> .
+
+# The first note
+ex:note1 a ex:Note ;
+    ex:title "Test" ;   # inline comment
+    ex:tag "work", "important" .
+
+ex:note2 ex:title "Second" .
+# trailing comment
+`
+
+func TestParseTurtleDocument(t *testing.T) {
+	doc, err := ParseTurtleDocument(cstSample, DecodeOptions{})
+	if err != nil {
+		t.Fatalf("ParseTurtleDocument() error = %v", err)
+	}
+
+	if doc.String() != cstSample {
+		t.Errorf("String() = %q, want original input", doc.String())
+	}
+
+	statements := doc.Statements()
+	if len(statements) != 3 {
+		t.Fatalf("got %d statements, want 3", len(statements))
+	}
+
+	if !statements[0].Directive {
+		t.Error("statement 0 should be a directive")
+	}
+	if statements[0].Leading != "# Notes ontology\n" {
+		t.Errorf("statement 0 leading = %q", statements[0].Leading)
+	}
+	if statements[1].Leading != "\n\n# The first note\n" {
+		t.Errorf("statement 1 leading = %q", statements[1].Leading)
+	}
+	if len(statements[1].Triples) != 4 {
+		t.Errorf("statement 1 has %d triples, want 4", len(statements[1].Triples))
+	}
+
+	var rebuilt string
+	for _, stmt := range statements {
+		rebuilt += stmt.Leading + stmt.Text
+	}
+	if rebuilt+"\n# trailing comment\n" != cstSample {
+		t.Errorf("statements do not cover the source: %q", rebuilt)
+	}
+}
+
+func TestTurtleDocumentAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		triple   triple.Triple
+		expected string
+	}{
+		{
+			name: "object for existing predicate",
+			triple: triple.Triple{
+				Subject:   triple.IRI{Value: "http://example.org/note1"},
+				Predicate: triple.IRI{Value: "http://example.org/tag"},
+				Object:    triple.Literal{Value: "draft"},
+			},
+			expected: `# Notes ontology
+@prefix ex: <http://example.org/> .
+
+# The first note
+ex:note1 a ex:Note ;
+    ex:title "Test" ;   # inline comment
+    ex:tag "work", "important", "draft" .
+
+ex:note2 ex:title "Second" .
+# trailing comment
+`,
+		},
+		{
+			name: "new predicate for existing subject",
+			triple: triple.Triple{
+				Subject:   triple.IRI{Value: "http://example.org/note2"},
+				Predicate: triple.IRI{Value: "http://example.org/author"},
+				Object:    triple.IRI{Value: "http://example.org/alice"},
+			},
+			expected: `# Notes ontology
+@prefix ex: <http://example.org/> .
+
+# The first note
+ex:note1 a ex:Note ;
+    ex:title "Test" ;   # inline comment
+    ex:tag "work", "important" .
+
+ex:note2 ex:title "Second" ;
+         ex:author ex:alice .
+# trailing comment
+`,
+		},
+		{
+			name: "new subject",
+			triple: triple.Triple{
+				Subject:   triple.IRI{Value: "http://example.org/note3"},
+				Predicate: triple.IRI{Value: "http://example.org/title"},
+				Object:    triple.Literal{Value: "Say \"hi\""},
+			},
+			expected: cstSample + `
+ex:note3 ex:title "Say \"hi\"" .
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseTurtleDocument(cstSample, DecodeOptions{})
+			if err != nil {
+				t.Fatalf("ParseTurtleDocument() error = %v", err)
+			}
+
+			changed, err := doc.Add(tt.triple)
+			if err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if !changed {
+				t.Fatal("Add() reported no change")
+			}
+			if doc.String() != tt.expected {
+				t.Errorf("Add() result = %q, want %q", doc.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestTurtleDocumentRemove(t *testing.T) {
+	tests := []struct {
+		name     string
+		triple   triple.Triple
+		expected string
+		removed  int
+	}{
+		{
+			name: "one object of several",
+			triple: triple.Triple{
+				Subject:   triple.IRI{Value: "http://example.org/note1"},
+				Predicate: triple.IRI{Value: "http://example.org/tag"},
+				Object:    triple.Literal{Value: "work"},
+			},
+			removed: 1,
+			expected: `# Notes ontology
+@prefix ex: <http://example.org/> .
+
+# The first note
+ex:note1 a ex:Note ;
+    ex:title "Test" ;   # inline comment
+    ex:tag "important" .
+
+ex:note2 ex:title "Second" .
+# trailing comment
+`,
+		},
+		{
+			name: "first predicate of several",
+			triple: triple.Triple{
+				Subject:   triple.IRI{Value: "http://example.org/note1"},
+				Predicate: triple.IRI{Value: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"},
+				Object:    triple.IRI{Value: "http://example.org/Note"},
+			},
+			removed: 1,
+			expected: `# Notes ontology
+@prefix ex: <http://example.org/> .
+
+# The first note
+ex:note1 ex:title "Test" ;   # inline comment
+    ex:tag "work", "important" .
+
+ex:note2 ex:title "Second" .
+# trailing comment
+`,
+		},
+		{
+			name: "whole statement",
+			triple: triple.Triple{
+				Subject:   triple.IRI{Value: "http://example.org/note2"},
+				Predicate: triple.IRI{Value: "http://example.org/title"},
+				Object:    triple.Literal{Value: "Second"},
+			},
+			removed: 1,
+			expected: `# Notes ontology
+@prefix ex: <http://example.org/> .
+
+# The first note
+ex:note1 a ex:Note ;
+    ex:title "Test" ;   # inline comment
+    ex:tag "work", "important" .
+
+# trailing comment
+`,
+		},
+		{
+			name: "missing triple",
+			triple: triple.Triple{
+				Subject:   triple.IRI{Value: "http://example.org/note9"},
+				Predicate: triple.IRI{Value: "http://example.org/title"},
+				Object:    triple.Literal{Value: "Nope"},
+			},
+			removed:  0,
+			expected: cstSample,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseTurtleDocument(cstSample, DecodeOptions{})
+			if err != nil {
+				t.Fatalf("ParseTurtleDocument() error = %v", err)
+			}
+
+			removed, err := doc.Remove(tt.triple)
+			if err != nil {
+				t.Fatalf("Remove() error = %v", err)
+			}
+			if removed != tt.removed {
+				t.Errorf("Remove() removed %d, want %d", removed, tt.removed)
+			}
+			if doc.String() != tt.expected {
+				t.Errorf("Remove() result = %q, want %q", doc.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestTurtleDocumentRemoveKeepsComments(t *testing.T) {
+	input := `@prefix ex: <http://example.org/> .
+
+ex:a ex:p "x" ;  # keep me
+     ex:q 1 .
+`
+	expected := `@prefix ex: <http://example.org/> .
+
+ex:a ex:p "x" .  # keep me
+`
+
+	doc, err := ParseTurtleDocument(input, DecodeOptions{})
+	if err != nil {
+		t.Fatalf("ParseTurtleDocument() error = %v", err)
+	}
+
+	removed, err := doc.Remove(triple.Triple{
+		Subject:   triple.IRI{Value: "http://example.org/a"},
+		Predicate: triple.IRI{Value: "http://example.org/q"},
+		Object:    triple.Literal{Value: "1", Datatype: "http://www.w3.org/2001/XMLSchema#integer"},
+	})
+	if err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Remove() removed %d, want 1", removed)
+	}
+	if doc.String() != expected {
+		t.Errorf("Remove() result = %q, want %q", doc.String(), expected)
+	}
+}
+
+func TestTurtleDocumentRemoveCommentedStatement(t *testing.T) {
+	input := `@prefix ex: <http://example.org/> .
+
+ex:a ex:p 1 ; # keep
+  ex:q 2 . # end
+ex:b ex:p 3 .
+`
+	iri := func(s string) triple.IRI { return triple.IRI{Value: "http://example.org/" + s} }
+	integer := func(v string) triple.Literal {
+		return triple.Literal{Value: v, Datatype: "http://www.w3.org/2001/XMLSchema#integer"}
+	}
+	p := triple.Triple{Subject: iri("a"), Predicate: iri("p"), Object: integer("1")}
+	q := triple.Triple{Subject: iri("a"), Predicate: iri("q"), Object: integer("2")}
+
+	tests := []struct {
+		name     string
+		remove   []triple.Triple
+		expected string
+	}{
+		{
+			name:   "last predicate",
+			remove: []triple.Triple{q},
+			expected: `@prefix ex: <http://example.org/> .
+
+ex:a ex:p 1 . # keep
+# end
+ex:b ex:p 3 .
+`,
+		},
+		{
+			name:   "first predicate",
+			remove: []triple.Triple{p},
+			expected: `@prefix ex: <http://example.org/> .
+
+ex:a # keep
+  ex:q 2 . # end
+ex:b ex:p 3 .
+`,
+		},
+		{
+			name:   "whole statement",
+			remove: []triple.Triple{p, q},
+			expected: `@prefix ex: <http://example.org/> .
+
+# keep
+# end
+ex:b ex:p 3 .
+`,
+		},
+		{
+			name:   "whole statement, last predicate first",
+			remove: []triple.Triple{q, p},
+			expected: `@prefix ex: <http://example.org/> .
+
+# keep
+# end
+ex:b ex:p 3 .
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseTurtleDocument(input, DecodeOptions{})
+			if err != nil {
+				t.Fatalf("ParseTurtleDocument() error = %v", err)
+			}
+			for _, tr := range tt.remove {
+				if removed, err := doc.Remove(tr); err != nil || removed != 1 {
+					t.Fatalf("Remove(%v) = %d, %v", tr, removed, err)
+				}
+			}
+			if doc.String() != tt.expected {
+				t.Errorf("Remove() result = %q, want %q", doc.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestTurtleDocumentBlankNodeLabels(t *testing.T) {
+	doc, err := ParseTurtleDocument(`@prefix ex: <http://example.org/> .
+_:genid1 ex:p [ ex:q 1 ] .
+`, DecodeOptions{})
+	if err != nil {
+		t.Fatalf("ParseTurtleDocument() error = %v", err)
+	}
+
+	triples := doc.Triples()
+	if len(triples) != 2 {
+		t.Fatalf("Triples() = %v", triples)
+	}
+	if triples[0].Subject == triples[1].Subject {
+		t.Errorf("[ ] got the label of _:genid1: %v", triples)
+	}
+}
+
+func TestDecodeTurtleSyntax(t *testing.T) {
+	input := `PREFIX ex: <http://example.org/>
+ex:note1 ex:body """Line one
+line two""" ;
+    ex:quote "say \"hi\"!" ;
+    ex:author [ ex:name "Ann" ] ;
+    ex:tags ( "a" "b" ) .`
+
+	triples, _, err := DecodeTurtle(input)
+	if err != nil {
+		t.Fatalf("DecodeTurtle() error = %v", err)
+	}
+
+	want := []triple.Triple{
+		{
+			Subject:   triple.IRI{Value: "http://example.org/note1"},
+			Predicate: triple.IRI{Value: "http://example.org/body"},
+			Object:    triple.Literal{Value: "Line one\nline two"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/note1"},
+			Predicate: triple.IRI{Value: "http://example.org/quote"},
+			Object:    triple.Literal{Value: `say "hi"!`},
+		},
+		{
+			Subject:   triple.BlankNode{Value: "genid1"},
+			Predicate: triple.IRI{Value: "http://example.org/name"},
+			Object:    triple.Literal{Value: "Ann"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/note1"},
+			Predicate: triple.IRI{Value: "http://example.org/author"},
+			Object:    triple.BlankNode{Value: "genid1"},
+		},
+	}
+
+	if len(triples) != 9 {
+		t.Fatalf("DecodeTurtle() got %d triples, want 9: %+v", len(triples), triples)
+	}
+	for i, expected := range want {
+		if !triplesEqual(triples[i], expected) {
+			t.Errorf("DecodeTurtle() triple[%d] = %+v, want %+v", i, triples[i], expected)
+		}
+	}
+}
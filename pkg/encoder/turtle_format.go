@@ -0,0 +1,510 @@
+package encoder
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+type TurtleStyle struct {
+	// AlignPredicates pads predicates within a subject block so their objects line up.
+	AlignPredicates bool
+	// TypeFirst writes the rdf:type predicate (as "a") before all other predicates.
+	TypeFirst bool
+	// GroupByType places subjects sharing an rdf:type next to each other.
+	GroupByType bool
+	// LiteralShorthand writes xsd:integer, xsd:decimal, xsd:double and xsd:boolean
+	// literals as bare numbers and booleans when their lexical form allows it.
+	LiteralShorthand bool
+}
+
+func DefaultTurtleStyle() TurtleStyle {
+	return TurtleStyle{
+		TypeFirst:        true,
+		LiteralShorthand: true,
+	}
+}
+
+// FormatTurtle lays out a Turtle document in the given style. It works on the
+// document's TurtleDocument, so comments, directives and blank node labels
+// are kept. Statements holding comments, [ ] or << >> syntax, or names with
+// undeclared prefixes are laid out from their tokens rather than their
+// triples, so each comment stays after the token it follows. Sort and
+// GroupByType reorder the statements between directives, each with the
+// comments above it.
+func FormatTurtle(input string, opts EncodeOptions) (string, error) {
+	doc, err := ParseTurtleDocument(input, DecodeOptions{Base: opts.Base})
+	if err != nil {
+		return "", err
+	}
+
+	opts.Compact = true
+	opts.BlankNodes = BlankNodesPreserve
+	prefixes := make(map[string]string)
+	base := opts.Base
+
+	var units []formatUnit
+	prevEnd := 0
+	for i, stmt := range doc.statements {
+		same, lines := splitTrivia(stmt.Leading, i == 0)
+		if i > 0 {
+			units[i-1].comment = same
+		}
+		u := formatUnit{leading: lines, text: stmt.Text, directive: stmt.Directive}
+
+		if stmt.Directive {
+			parsed, err := ParseTurtleDocument(stmt.Text, DecodeOptions{Prefixes: prefixes, Base: base})
+			if err != nil {
+				return "", err
+			}
+			prefixes, base = parsed.prefixes, parsed.base
+		} else {
+			u.subject = formatNode(stmt.subject.node)
+			for _, t := range stmt.Triples {
+				if t.Subject == stmt.subject.node && t.Predicate == rdf.Type {
+					u.typeKey = nodeKey(t.Object)
+					break
+				}
+			}
+			if u.text, err = formatStatement(stmt, prefixes, base, opts); err != nil {
+				return "", err
+			}
+		}
+		units = append(units, u)
+		prevEnd = stmt.end
+	}
+
+	same, trailing := splitTrivia(input[prevEnd:], len(units) == 0)
+	if len(units) > 0 {
+		units[len(units)-1].comment = same
+	}
+	if len(trailing) > 0 && trailing[len(trailing)-1] == "" {
+		trailing = trailing[:len(trailing)-1]
+	}
+
+	for start := 0; start < len(units); {
+		end := start
+		for end < len(units) && !units[end].directive {
+			end++
+		}
+		orderUnits(units[start:end], opts.Turtle.GroupByType, opts.Sort)
+		start = end + 1
+	}
+
+	var result strings.Builder
+	for i, u := range units {
+		lines := u.leading
+		if i > 0 && (!u.directive || !units[i-1].directive || len(lines) > 0 && lines[0] == "") {
+			result.WriteString("\n")
+		}
+		for len(lines) > 0 && lines[0] == "" {
+			lines = lines[1:]
+		}
+		for _, line := range lines {
+			result.WriteString(line + "\n")
+		}
+		result.WriteString(u.text)
+		if u.comment != "" {
+			result.WriteString(" " + u.comment)
+		}
+		result.WriteString("\n")
+	}
+	if len(units) == 0 {
+		for len(trailing) > 0 && trailing[0] == "" {
+			trailing = trailing[1:]
+		}
+	}
+	for _, line := range trailing {
+		result.WriteString(line + "\n")
+	}
+	return result.String(), nil
+}
+
+// formatUnit is one statement of a document being formatted, with the
+// comment lines above it and the comment that ends its last line.
+type formatUnit struct {
+	// leading holds comment lines, with "" marking a blank line.
+	leading   []string
+	text      string
+	comment   string
+	directive bool
+	subject   string
+	typeKey   string
+}
+
+// splitTrivia splits the whitespace and comments between two statements
+// into the comment on the rest of the previous statement's line and the
+// lines below it, trimmed, with each run of blank lines as a single "". At
+// the start of a document there is no previous line.
+func splitTrivia(trivia string, first bool) (string, []string) {
+	parts := strings.Split(trivia, "\n")
+	same := ""
+	if !first {
+		same, parts = strings.TrimSpace(parts[0]), parts[1:]
+	}
+	// The last part is the start of the next statement's line, which only
+	// holds a comment at the end of a document without a final newline.
+	if n := len(parts); n > 0 && strings.TrimSpace(parts[n-1]) == "" {
+		parts = parts[:n-1]
+	}
+
+	var lines []string
+	for _, part := range parts {
+		line := strings.TrimSpace(part)
+		if line == "" && len(lines) > 0 && lines[len(lines)-1] == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return same, lines
+}
+
+func orderUnits(units []formatUnit, groupByType, sortUnits bool) {
+	if sortUnits {
+		sort.SliceStable(units, func(i, j int) bool { return units[i].subject < units[j].subject })
+	}
+	if !groupByType {
+		return
+	}
+
+	var typeOrder []string
+	byType := make(map[string][]formatUnit)
+	for _, u := range units {
+		if _, seen := byType[u.typeKey]; !seen {
+			typeOrder = append(typeOrder, u.typeKey)
+		}
+		byType[u.typeKey] = append(byType[u.typeKey], u)
+	}
+
+	ordered := make([]formatUnit, 0, len(units))
+	for _, key := range typeOrder {
+		if key != "" {
+			ordered = append(ordered, byType[key]...)
+		}
+	}
+	copy(units, append(ordered, byType[""]...))
+}
+
+// formatStatement writes the triples of stmt in the compact layout. A
+// statement the triples cannot give back is laid out from its tokens.
+func formatStatement(stmt *TurtleStatement, prefixes map[string]string, base string, opts EncodeOptions) (string, error) {
+	tokens, err := tokenizeTurtle(stmt.Text)
+	if err != nil {
+		return "", err
+	}
+	opts.Prefixes = prefixes
+	opts.Base = base
+
+	for _, tok := range tokens {
+		switch {
+		case tok.kind == tokComment:
+			return layoutStatement(stmt.Text, prefixes, base, opts)
+		case tok.kind == tokPunct && (tok.text == "[" || tok.text == "<<" || tok.text == "~" || tok.text == "{|"):
+			return layoutStatement(stmt.Text, prefixes, base, opts)
+		case tok.kind == tokPrefixedName:
+			prefix, _, _ := strings.Cut(tok.text, ":")
+			if _, ok := prefixes[prefix]; !ok {
+				return layoutStatement(stmt.Text, prefixes, base, opts)
+			}
+		}
+	}
+
+	triples, err := prepareTriples(stmt.Triples, opts)
+	if err != nil || len(triples) != len(stmt.Triples) {
+		return layoutStatement(stmt.Text, prefixes, base, opts)
+	}
+
+	w := &turtleWriter{resolver: NewPrefixResolver(prefixes), opts: opts}
+	var result strings.Builder
+	w.writeCompactBody(&result, triples)
+	return strings.TrimSuffix(result.String(), "\n"), nil
+}
+
+var (
+	turtleInteger = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	turtleDecimal = regexp.MustCompile(`^[+-]?[0-9]*\.[0-9]+$`)
+	turtleDouble  = regexp.MustCompile(`^[+-]?([0-9]+\.[0-9]*|\.[0-9]+|[0-9]+)[eE][+-]?[0-9]+$`)
+)
+
+func turtleShorthand(lit triple.Literal) (string, bool) {
+	if lit.Language != "" {
+		return "", false
+	}
+
+	switch lit.Datatype {
+	case xsd.Integer:
+		return lit.Value, turtleInteger.MatchString(lit.Value)
+	case xsd.Decimal:
+		return lit.Value, turtleDecimal.MatchString(lit.Value)
+	case xsd.Double:
+		return lit.Value, turtleDouble.MatchString(lit.Value)
+	case xsd.Boolean:
+		return lit.Value, lit.Value == "true" || lit.Value == "false"
+	}
+	return "", false
+}
+
+func parseTurtleShorthand(token string) (triple.Literal, bool) {
+	switch {
+	case token == "true" || token == "false":
+		return triple.Literal{Value: token, Datatype: xsd.Boolean}, true
+	case turtleInteger.MatchString(token):
+		return triple.Literal{Value: token, Datatype: xsd.Integer}, true
+	case turtleDecimal.MatchString(token):
+		return triple.Literal{Value: token, Datatype: xsd.Decimal}, true
+	case turtleDouble.MatchString(token):
+		return triple.Literal{Value: token, Datatype: xsd.Double}, true
+	}
+	return triple.Literal{}, false
+}
+
+func isTurtleLocalName(local string) bool {
+	for i, ch := range local {
+		switch {
+		case ch == '_' || unicode.IsLetter(ch) || unicode.IsDigit(ch):
+		case ch == '-' || ch == '.':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return !strings.HasSuffix(local, ".")
+}
+
+type turtleWriter struct {
+	resolver *PrefixResolver
+	opts     EncodeOptions
+	// referenced holds blank nodes used as objects; only unreferenced
+	// reifiers can be written as << s p o >>.
+	referenced map[triple.Node]bool
+	// lists holds the items of rdf:Lists written as ( ... ), by head.
+	lists map[triple.Node][]triple.Node
+}
+
+func (w *turtleWriter) iri(value string) string {
+	shortened := w.resolver.Shorten(value)
+	if shortened != value {
+		if _, local, _ := strings.Cut(shortened, ":"); isTurtleLocalName(local) {
+			return shortened
+		}
+	}
+	return fmt.Sprintf("<%s>", relativizeIRI(w.opts.Base, value))
+}
+
+func (w *turtleWriter) node(n triple.Node) string {
+	switch node := n.(type) {
+	case triple.IRI:
+		// The empty list, written like the other collections.
+		if w.opts.Compact && node == rdf.Nil {
+			return "()"
+		}
+		return w.iri(node.Value)
+	case triple.Literal:
+		if w.opts.Turtle.LiteralShorthand {
+			if short, ok := turtleShorthand(node); ok {
+				return short
+			}
+		}
+		result := fmt.Sprintf(`"%s"`, escapeString(node.Value))
+		if node.Language != "" {
+			result += "@" + langDir(node)
+		}
+		if node.Datatype != "" {
+			result += "^^" + w.iri(node.Datatype)
+		}
+		return result
+	case triple.BlankNode:
+		if items, ok := w.lists[node]; ok {
+			parts := make([]string, len(items))
+			for i, item := range items {
+				parts[i] = w.node(item)
+			}
+			return "( " + strings.Join(parts, " ") + " )"
+		}
+		return "_:" + node.Value
+	case triple.TripleTerm:
+		return fmt.Sprintf("<<( %s %s %s )>>", w.node(node.Triple.Subject), w.predicate(node.Triple.Predicate), w.node(node.Triple.Object))
+	default:
+		return ""
+	}
+}
+
+func (w *turtleWriter) predicate(n triple.Node) string {
+	if n == rdf.Type {
+		return "a"
+	}
+	if iri, ok := n.(triple.IRI); ok {
+		return w.iri(iri.Value)
+	}
+	return w.node(n)
+}
+
+func (w *turtleWriter) writeCompactBody(result *strings.Builder, triples []triple.Triple) {
+	w.lists, triples = collectLists(triples)
+	w.referenced = make(map[triple.Node]bool)
+	var mark func(n triple.Node)
+	mark = func(n triple.Node) {
+		switch node := n.(type) {
+		case triple.BlankNode:
+			w.referenced[node] = true
+		case triple.TripleTerm:
+			mark(node.Triple.Subject)
+			mark(node.Triple.Object)
+		}
+	}
+	for _, t := range triples {
+		mark(t.Object)
+	}
+
+	grouped := groupTriples(triples)
+	if w.opts.Turtle.GroupByType {
+		grouped = groupSubjectsByType(grouped)
+	}
+
+	for i, subjectGroup := range grouped {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+		w.writeSubjectBlock(result, subjectGroup)
+	}
+}
+
+func (w *turtleWriter) writeSubjectBlock(result *strings.Builder, sg subjectGroup) {
+	predicates := sg.predicates
+	if w.opts.Turtle.TypeFirst {
+		predicates = typeFirst(predicates)
+	}
+
+	subject := w.node(sg.subject)
+	if reified, rest, ok := w.reifiedSubject(sg.subject, predicates); ok {
+		subject, predicates = reified, rest
+	}
+	indent := w.opts.Indent
+	if indent <= 0 {
+		indent = len(subject) + 1
+	}
+
+	names := make([]string, len(predicates))
+	nameWidth := 0
+	for j, pg := range predicates {
+		names[j] = w.predicate(pg.predicate)
+		if len(names[j]) > nameWidth {
+			nameWidth = len(names[j])
+		}
+	}
+
+	result.WriteString(subject)
+
+	for j, pg := range predicates {
+		column := indent
+		if j == 0 {
+			result.WriteString(" ")
+			column = len(subject) + 1
+		} else {
+			result.WriteString(" ;\n")
+			result.WriteString(strings.Repeat(" ", indent))
+		}
+
+		name := names[j]
+		if w.opts.Turtle.AlignPredicates {
+			name += strings.Repeat(" ", nameWidth-len(name))
+		}
+		result.WriteString(name)
+		column += len(name) + 1
+
+		objects := make([]string, len(pg.objects))
+		lineLength := column + 2
+		for k, obj := range pg.objects {
+			objects[k] = w.node(obj)
+			lineLength += len(objects[k])
+			if k > 0 {
+				lineLength += 2
+			}
+		}
+
+		separator := ", "
+		if w.opts.LineWidth > 0 && lineLength > w.opts.LineWidth && len(objects) > 1 {
+			separator = ",\n" + strings.Repeat(" ", column)
+		}
+
+		result.WriteString(" ")
+		result.WriteString(strings.Join(objects, separator))
+	}
+
+	result.WriteString(" .\n")
+}
+
+// reifiedSubject writes an unreferenced blank node with a single rdf:reifies
+// triple term as << s p o >> and returns the remaining predicate groups.
+func (w *turtleWriter) reifiedSubject(subject triple.Node, predicates []predicateGroup) (string, []predicateGroup, bool) {
+	if _, ok := subject.(triple.BlankNode); !ok || w.referenced[subject] {
+		return "", nil, false
+	}
+
+	for i, pg := range predicates {
+		if pg.predicate != rdf.Reifies || len(pg.objects) != 1 {
+			continue
+		}
+		term, ok := pg.objects[0].(triple.TripleTerm)
+		if !ok {
+			return "", nil, false
+		}
+		t := term.Triple
+		reified := fmt.Sprintf("<< %s %s %s >>", w.node(t.Subject), w.predicate(t.Predicate), w.node(t.Object))
+
+		rest := make([]predicateGroup, 0, len(predicates)-1)
+		rest = append(rest, predicates[:i]...)
+		return reified, append(rest, predicates[i+1:]...), true
+	}
+	return "", nil, false
+}
+
+func typeFirst(predicates []predicateGroup) []predicateGroup {
+	ordered := make([]predicateGroup, 0, len(predicates))
+	for _, pg := range predicates {
+		if pg.predicate == rdf.Type {
+			ordered = append(ordered, pg)
+		}
+	}
+	for _, pg := range predicates {
+		if pg.predicate != rdf.Type {
+			ordered = append(ordered, pg)
+		}
+	}
+	return ordered
+}
+
+func groupSubjectsByType(groups []subjectGroup) []subjectGroup {
+	var typeOrder []string
+	byType := make(map[string][]subjectGroup)
+
+	for _, sg := range groups {
+		key := ""
+		for _, pg := range sg.predicates {
+			if pg.predicate == rdf.Type && len(pg.objects) > 0 {
+				key = nodeKey(pg.objects[0])
+				break
+			}
+		}
+		if _, seen := byType[key]; !seen {
+			typeOrder = append(typeOrder, key)
+		}
+		byType[key] = append(byType[key], sg)
+	}
+
+	result := make([]subjectGroup, 0, len(groups))
+	for _, key := range typeOrder {
+		if key != "" {
+			result = append(result, byType[key]...)
+		}
+	}
+	return append(result, byType[""]...)
+}
@@ -0,0 +1,407 @@
+package encoder
+
+import (
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func TestEncodeTurtleStyle(t *testing.T) {
+	triples := []triple.Triple{
+		{
+			Subject:   triple.IRI{Value: "http://example.org/longSubjectName"},
+			Predicate: triple.IRI{Value: "http://example.org/title"},
+			Object:    triple.Literal{Value: "Test"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/longSubjectName"},
+			Predicate: triple.IRI{Value: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"},
+			Object:    triple.IRI{Value: "http://example.org/Note"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/longSubjectName"},
+			Predicate: triple.IRI{Value: "http://example.org/count"},
+			Object:    triple.Literal{Value: "42", Datatype: "http://www.w3.org/2001/XMLSchema#integer"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/longSubjectName"},
+			Predicate: triple.IRI{Value: "http://example.org/done"},
+			Object:    triple.Literal{Value: "false", Datatype: "http://www.w3.org/2001/XMLSchema#boolean"},
+		},
+	}
+
+	prefixes := map[string]string{"ex": "http://example.org/"}
+
+	tests := []struct {
+		name     string
+		opts     EncodeOptions
+		expected string
+	}{
+		{
+			name: "continuation aligned under first predicate",
+			opts: EncodeOptions{Prefixes: prefixes, Compact: true},
+			expected: `@prefix ex: <http://example.org/> .
+
+ex:longSubjectName ex:title "Test" ;
+                   a ex:Note ;
+                   ex:count "42"^^<http://www.w3.org/2001/XMLSchema#integer> ;
+                   ex:done "false"^^<http://www.w3.org/2001/XMLSchema#boolean> .
+`,
+		},
+		{
+			name: "fixed indent, type first and shorthand",
+			opts: EncodeOptions{
+				Prefixes: prefixes,
+				Compact:  true,
+				Indent:   4,
+				Turtle:   TurtleStyle{TypeFirst: true, LiteralShorthand: true},
+			},
+			expected: `@prefix ex: <http://example.org/> .
+
+ex:longSubjectName a ex:Note ;
+    ex:title "Test" ;
+    ex:count 42 ;
+    ex:done false .
+`,
+		},
+		{
+			name: "aligned predicates",
+			opts: EncodeOptions{
+				Prefixes: prefixes,
+				Compact:  true,
+				Indent:   4,
+				Turtle:   TurtleStyle{AlignPredicates: true, LiteralShorthand: true},
+			},
+			expected: `@prefix ex: <http://example.org/> .
+
+ex:longSubjectName ex:title "Test" ;
+    a        ex:Note ;
+    ex:count 42 ;
+    ex:done  false .
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Encode(triples, Turtle, tt.opts)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Encode() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeTurtleLineWidth(t *testing.T) {
+	var triples []triple.Triple
+	for _, tag := range []string{"alpha", "beta", "gamma"} {
+		triples = append(triples, triple.Triple{
+			Subject:   triple.IRI{Value: "http://example.org/note1"},
+			Predicate: triple.IRI{Value: "http://example.org/tag"},
+			Object:    triple.Literal{Value: tag},
+		})
+	}
+
+	expected := `@prefix ex: <http://example.org/> .
+
+ex:note1 ex:tag "alpha",
+                "beta",
+                "gamma" .
+`
+
+	result, err := Encode(triples, Turtle, EncodeOptions{
+		Prefixes:  map[string]string{"ex": "http://example.org/"},
+		Compact:   true,
+		LineWidth: 30,
+	})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if result != expected {
+		t.Errorf("Encode() = %q, want %q", result, expected)
+	}
+}
+
+func TestEncodeTurtleGroupByType(t *testing.T) {
+	triples := []triple.Triple{
+		{
+			Subject:   triple.IRI{Value: "http://example.org/alice"},
+			Predicate: triple.IRI{Value: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"},
+			Object:    triple.IRI{Value: "http://example.org/Person"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/note1"},
+			Predicate: triple.IRI{Value: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"},
+			Object:    triple.IRI{Value: "http://example.org/Note"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/misc"},
+			Predicate: triple.IRI{Value: "http://example.org/title"},
+			Object:    triple.Literal{Value: "Untyped"},
+		},
+		{
+			Subject:   triple.IRI{Value: "http://example.org/bob"},
+			Predicate: triple.IRI{Value: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"},
+			Object:    triple.IRI{Value: "http://example.org/Person"},
+		},
+	}
+
+	expected := `@prefix ex: <http://example.org/> .
+
+ex:alice a ex:Person .
+
+ex:bob a ex:Person .
+
+ex:note1 a ex:Note .
+
+ex:misc ex:title "Untyped" .
+`
+
+	result, err := Encode(triples, Turtle, EncodeOptions{
+		Prefixes: map[string]string{"ex": "http://example.org/"},
+		Compact:  true,
+		Turtle:   TurtleStyle{GroupByType: true},
+	})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if result != expected {
+		t.Errorf("Encode() = %q, want %q", result, expected)
+	}
+}
+
+func TestFormatTurtle(t *testing.T) {
+	input := `@prefix ex: <http://example.org/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+ex:note1 ex:count "7"^^xsd:integer ; a ex:Note ; ex:ratio 0.5 ; ex:path <http://example.org/a/b> .`
+
+	expected := `@prefix ex: <http://example.org/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+ex:note1 a ex:Note ;
+    ex:count 7 ;
+    ex:ratio 0.5 ;
+    ex:path <http://example.org/a/b> .
+`
+
+	opts := EncodeOptions{Indent: 4, Turtle: DefaultTurtleStyle()}
+
+	result, err := FormatTurtle(input, opts)
+	if err != nil {
+		t.Fatalf("FormatTurtle() error = %v", err)
+	}
+	if result != expected {
+		t.Errorf("FormatTurtle() = %q, want %q", result, expected)
+	}
+
+	again, err := FormatTurtle(result, opts)
+	if err != nil {
+		t.Fatalf("FormatTurtle() second pass error = %v", err)
+	}
+	if again != result {
+		t.Errorf("FormatTurtle() is not idempotent: %q != %q", again, result)
+	}
+}
+
+func TestFormatTurtleKeepsComments(t *testing.T) {
+	input := `# Header
+@base <http://example.org/> .
+@prefix ex: <http://example.org/> .
+ex:bob ex:name "Bob" ; a ex:Person .   # trailing
+# about alice
+
+
+ex:alice ex:knows <bob> .
+ex:x ex:p [ ex:q 1 ] .
+ex:y ex:p ex:z ; # inside
+  ex:r ex:s .
+# end`
+
+	expected := `# Header
+@base <http://example.org/> .
+@prefix ex: <http://example.org/> .
+
+ex:bob a ex:Person ;
+    ex:name "Bob" . # trailing
+
+# about alice
+
+ex:alice ex:knows ex:bob .
+
+ex:x ex:p [ ex:q 1 ] .
+
+ex:y ex:p ex:z ; # inside
+    ex:r ex:s .
+# end
+`
+
+	opts := EncodeOptions{Indent: 4, Turtle: DefaultTurtleStyle()}
+
+	result, err := FormatTurtle(input, opts)
+	if err != nil {
+		t.Fatalf("FormatTurtle() error = %v", err)
+	}
+	if result != expected {
+		t.Errorf("FormatTurtle() = %q, want %q", result, expected)
+	}
+
+	again, err := FormatTurtle(result, opts)
+	if err != nil {
+		t.Fatalf("FormatTurtle() second pass error = %v", err)
+	}
+	if again != result {
+		t.Errorf("FormatTurtle() is not idempotent: %q != %q", again, result)
+	}
+}
+
+func TestFormatTurtleLayout(t *testing.T) {
+	prefixes := "@prefix ex: <http://example.org/> .\n\n"
+	tests := []struct {
+		name     string
+		indent   int
+		input    string
+		expected string
+	}{
+		{
+			name:   "nested property list",
+			indent: 4,
+			input: `ex:x ex:p [
+ex:q 1 ;
+      ex:r "2"^^<http://www.w3.org/2001/XMLSchema#integer>
+] ; a ex:T .`,
+			expected: `ex:x a ex:T ;
+    ex:p [
+        ex:q 1 ;
+        ex:r 2
+    ] .
+`,
+		},
+		{
+			name:   "nested property list aligned",
+			indent: 0,
+			input: `ex:x ex:p [
+ex:q 1 ;
+      ex:r 2
+] ; a ex:T .`,
+			expected: `ex:x a ex:T ;
+     ex:p [ ex:q 1 ;
+            ex:r 2 ] .
+`,
+		},
+		{
+			name:     "short property list",
+			indent:   4,
+			input:    "ex:x ex:p [   ex:q 1 ] , [] .",
+			expected: "ex:x ex:p [ ex:q 1 ], [] .\n",
+		},
+		{
+			name:   "property list subject",
+			indent: 4,
+			input:  "[ ex:q 1 ; ex:r 2 ] ex:s 3 ; ex:t 4 .",
+			expected: `[
+    ex:q 1 ;
+    ex:r 2
+] ex:s 3 ;
+    ex:t 4 .
+`,
+		},
+		{
+			name:     "collection holding a property list",
+			indent:   4,
+			input:    "ex:c ex:p (1 2 [ex:q 1]) .",
+			expected: "ex:c ex:p ( 1 2 [ ex:q 1 ] ) .\n",
+		},
+		{
+			name:     "reified triple",
+			indent:   4,
+			input:    "<<ex:a ex:b ex:c ~ ex:r>>   ex:saidBy <http://example.org/bob> .",
+			expected: "<< ex:a ex:b ex:c ~ ex:r >> ex:saidBy ex:bob .\n",
+		},
+		{
+			name:   "annotation",
+			indent: 4,
+			input:  "ex:a ex:b ex:c ~ex:r {| ex:since 2020 ; ex:by ex:x |} ; ex:d ex:e .",
+			expected: `ex:a ex:b ex:c ~ ex:r {|
+        ex:since 2020 ;
+        ex:by ex:x
+    |} ;
+    ex:d ex:e .
+`,
+		},
+		{
+			name:     "triple term",
+			indent:   4,
+			input:    `ex:a ex:b <<(ex:s ex:p "o"@en)>> .`,
+			expected: "ex:a ex:b <<( ex:s ex:p \"o\"@en )>> .\n",
+		},
+		{
+			name:     "undeclared prefixes",
+			indent:   4,
+			input:    `ex:z ex:p foo:bar,"x"^^foo:t ; ex:q <http://example.org/y> .`,
+			expected: "ex:z ex:p foo:bar, \"x\"^^foo:t ;\n    ex:q ex:y .\n",
+		},
+		{
+			name:   "comments",
+			indent: 4,
+			input: `ex:w # after subject
+  ex:p # after verb
+  ex:o , # after comma
+   ex:o2 ; # after semicolon
+  ex:q [ # after bracket
+    ex:r 1 # after object
+  ] .
+ex:v ex:p ex:o # first
+# second
+ ; ex:q 1 .`,
+			expected: `ex:w # after subject
+    ex:p # after verb
+         ex:o, # after comma
+         ex:o2 ; # after semicolon
+    ex:q [ # after bracket
+        ex:r 1 # after object
+    ] .
+
+ex:v ex:p ex:o ; # first
+    # second
+    ex:q 1 .
+`,
+		},
+		{
+			name:   "comments aligned",
+			indent: 0,
+			input: `ex:w ex:p ex:o ; # after semicolon
+  ex:q [ # after bracket
+    ex:r 1 # after object
+  ] .`,
+			expected: `ex:w ex:p ex:o ; # after semicolon
+     ex:q [ # after bracket
+            ex:r 1 # after object
+          ] .
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := EncodeOptions{Indent: tt.indent, LineWidth: 80, Turtle: DefaultTurtleStyle()}
+			result, err := FormatTurtle(prefixes+tt.input, opts)
+			if err != nil {
+				t.Fatalf("FormatTurtle() error = %v", err)
+			}
+			if result != prefixes+tt.expected {
+				t.Errorf("FormatTurtle() = %q, want %q", result, prefixes+tt.expected)
+			}
+
+			again, err := FormatTurtle(result, opts)
+			if err != nil {
+				t.Fatalf("FormatTurtle() second pass error = %v", err)
+			}
+			if again != result {
+				t.Errorf("FormatTurtle() is not idempotent: %q != %q", again, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,538 @@
+package encoder
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// layoutTerm is a term of a statement laid out by layoutStatement: a plain
+// term, written as text, or a bracketed term holding a property list ([ ]
+// and {| |}) or items (( ), << >> and <<( )>>).
+type layoutTerm struct {
+	text  string
+	close string
+	preds []*layoutPredicate
+	items []*layoutTerm
+	// annotations holds the reifiers and {| |} blocks after an object.
+	annotations []*layoutTerm
+	// comments follow the term's opening token, trailing its closing one.
+	// A plain term only has trailing comments.
+	comments []string
+	trailing []string
+}
+
+type layoutPredicate struct {
+	verb    *layoutTerm
+	objects []*layoutTerm
+	isType  bool
+}
+
+// hasComments reports whether there are comments inside t, not counting
+// those after it.
+func (t *layoutTerm) hasComments() bool {
+	if len(t.comments) > 0 {
+		return true
+	}
+	inner := append(append([]*layoutTerm{}, t.items...), t.annotations...)
+	for _, pg := range t.preds {
+		inner = append(append(inner, pg.verb), pg.objects...)
+	}
+	for _, u := range inner {
+		if len(u.trailing) > 0 || u.hasComments() {
+			return true
+		}
+	}
+	return false
+}
+
+// layoutStatement lays out a statement that the compact writer cannot:
+// one holding comments, [ ] property lists, << >> reified triples,
+// annotations or names with undeclared prefixes. Terms are written as the
+// compact writer would, names with undeclared prefixes as they are, and
+// each comment stays at the end of the line of the token it follows.
+func layoutStatement(text string, prefixes map[string]string, base string, opts EncodeOptions) (string, error) {
+	tokens, err := tokenizeTurtle(text)
+	if err != nil {
+		return "", err
+	}
+	p := &layoutParser{
+		prefixes: prefixes,
+		terms:    &turtleParser{src: text, resolver: NewPrefixResolver(prefixes), base: base, used: make(map[string]bool)},
+		writer:   &turtleWriter{resolver: NewPrefixResolver(prefixes), opts: opts},
+		last:     new([]string),
+	}
+	for _, tok := range tokens {
+		if tok.kind != tokWhitespace {
+			p.tokens = append(p.tokens, tok)
+		}
+	}
+
+	subject, preds, err := p.parseStatement()
+	if err != nil {
+		return "", err
+	}
+	out := &layoutPrinter{opts: opts}
+	out.statement(subject, preds)
+	return out.String(), nil
+}
+
+type layoutParser struct {
+	tokens   []turtleToken
+	pos      int
+	prefixes map[string]string
+	// terms resolves plain terms, which writer then writes.
+	terms  *turtleParser
+	writer *turtleWriter
+	// last holds the comments of the token read last, which the comments
+	// that follow it join.
+	last *[]string
+}
+
+// peek returns the next token that is not a comment, attaching the comments
+// before it to the last token read.
+func (p *layoutParser) peek() turtleToken {
+	for p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokComment {
+		*p.last = append(*p.last, strings.TrimSpace(p.tokens[p.pos].text))
+		p.pos++
+	}
+	if p.pos >= len(p.tokens) {
+		return turtleToken{kind: tokEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *layoutParser) next() turtleToken {
+	tok := p.peek()
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *layoutParser) expect(text string) error {
+	if tok := p.next(); !tok.is(tokPunct, text) {
+		return fmt.Errorf("expected %q, found %q", text, tok.text)
+	}
+	return nil
+}
+
+func (p *layoutParser) parseStatement() (*layoutTerm, []*layoutPredicate, error) {
+	subject, err := p.parseObject()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var preds []*layoutPredicate
+	if !p.peek().is(tokPunct, ".") {
+		if preds, err = p.parsePredicates(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := p.expect("."); err != nil {
+		return nil, nil, err
+	}
+	return subject, preds, nil
+}
+
+func (p *layoutParser) parsePredicates() ([]*layoutPredicate, error) {
+	var preds []*layoutPredicate
+	for {
+		verb, err := p.parsePlain(true)
+		if err != nil {
+			return nil, err
+		}
+		pg := &layoutPredicate{verb: verb, isType: verb.text == "a"}
+		for {
+			object, err := p.parseObject()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.parseAnnotations(object); err != nil {
+				return nil, err
+			}
+			pg.objects = append(pg.objects, object)
+			if !p.peek().is(tokPunct, ",") {
+				break
+			}
+			p.next()
+		}
+		preds = append(preds, pg)
+
+		if !p.peek().is(tokPunct, ";") {
+			break
+		}
+		for p.peek().is(tokPunct, ";") {
+			p.next()
+		}
+		if tok := p.peek(); tok.is(tokPunct, ".") || tok.is(tokPunct, "]") || tok.is(tokPunct, "|}") || tok.kind == tokEOF {
+			break
+		}
+	}
+
+	if p.writer.opts.Turtle.TypeFirst {
+		ordered := make([]*layoutPredicate, 0, len(preds))
+		for _, pg := range preds {
+			if pg.isType {
+				ordered = append(ordered, pg)
+			}
+		}
+		for _, pg := range preds {
+			if !pg.isType {
+				ordered = append(ordered, pg)
+			}
+		}
+		preds = ordered
+	}
+	return preds, nil
+}
+
+func (p *layoutParser) parseObject() (*layoutTerm, error) {
+	tok := p.peek()
+	switch {
+	case tok.is(tokPunct, "["):
+		return p.parseList("[", "]")
+	case tok.is(tokPunct, "("):
+		return p.parseItems("(", ")")
+	case tok.is(tokPunct, "<<("):
+		return p.parseItems("<<(", ")>>")
+	case tok.is(tokPunct, "<<"):
+		return p.parseItems("<<", ">>")
+	}
+	return p.parsePlain(false)
+}
+
+// parseList parses a [ ] or {| |} property list.
+func (p *layoutParser) parseList(open, close string) (*layoutTerm, error) {
+	t := &layoutTerm{text: open, close: close}
+	p.next()
+	p.last = &t.comments
+	if !p.peek().is(tokPunct, close) {
+		preds, err := p.parsePredicates()
+		if err != nil {
+			return nil, err
+		}
+		t.preds = preds
+	}
+	if err := p.expect(close); err != nil {
+		return nil, err
+	}
+	p.last = &t.trailing
+	return t, nil
+}
+
+// parseItems parses a collection, reified triple or triple term, keeping a
+// reifier's ~ as an item of its own.
+func (p *layoutParser) parseItems(open, close string) (*layoutTerm, error) {
+	t := &layoutTerm{text: open, close: close}
+	p.next()
+	p.last = &t.comments
+	for !p.peek().is(tokPunct, close) {
+		var item *layoutTerm
+		var err error
+		switch tok := p.peek(); {
+		case tok.kind == tokEOF:
+			return nil, fmt.Errorf("expected %q", close)
+		case tok.is(tokPunct, "~"):
+			p.next()
+			item = &layoutTerm{text: "~"}
+			p.last = &item.trailing
+		case len(t.items) == 1 && open != "(":
+			item, err = p.parsePlain(true)
+		default:
+			item, err = p.parseObject()
+		}
+		if err != nil {
+			return nil, err
+		}
+		t.items = append(t.items, item)
+	}
+	p.next()
+	p.last = &t.trailing
+	return t, nil
+}
+
+// parseAnnotations parses the reifiers and {| |} blocks after object. The
+// comments after them join the object's, so they end up after the
+// punctuation that follows.
+func (p *layoutParser) parseAnnotations(object *layoutTerm) error {
+	for {
+		switch tok := p.peek(); {
+		case tok.is(tokPunct, "~"):
+			p.next()
+			object.annotations = append(object.annotations, &layoutTerm{text: "~"})
+			p.last = &object.trailing
+			if tok := p.peek(); tok.kind == tokIRI || tok.kind == tokPrefixedName || tok.kind == tokBlankNode {
+				term, err := p.parsePlain(false)
+				if err != nil {
+					return err
+				}
+				object.annotations = append(object.annotations, term)
+			}
+		case tok.is(tokPunct, "{|"):
+			block, err := p.parseList("{|", "|}")
+			if err != nil {
+				return err
+			}
+			object.annotations = append(object.annotations, block)
+		default:
+			return nil
+		}
+		p.last = &object.trailing
+	}
+}
+
+// parsePlain parses a term without brackets, or a verb, and writes it as
+// the compact writer would. Terms using a prefix that is not declared are
+// written as they are.
+func (p *layoutParser) parsePlain(verb bool) (*layoutTerm, error) {
+	first := p.next()
+	tokens := []turtleToken{first}
+	if first.kind == tokString {
+		switch next := p.peek(); next.kind {
+		case tokLangTag:
+			tokens = append(tokens, p.next())
+		case tokDatatypeMarker:
+			tokens = append(tokens, p.next(), p.next())
+		}
+	}
+	t := &layoutTerm{}
+	p.last = &t.trailing
+
+	var raw strings.Builder
+	declared := true
+	for _, tok := range tokens {
+		raw.WriteString(tok.text)
+		if tok.kind == tokPrefixedName {
+			prefix, _, _ := strings.Cut(tok.text, ":")
+			if _, ok := p.prefixes[prefix]; !ok {
+				declared = false
+			}
+		}
+	}
+	p.terms.tokens, p.terms.pos = tokens, 0
+
+	var term cstTerm
+	var err error
+	if verb {
+		term, err = p.terms.parseVerb()
+	} else {
+		term, err = p.terms.parseObject()
+	}
+	switch {
+	case err != nil:
+		return nil, err
+	case p.terms.pos != len(tokens):
+		return nil, fmt.Errorf("unexpected %q", first.text)
+	case !declared:
+		t.text = raw.String()
+	case verb:
+		t.text = p.writer.predicate(term.node)
+	default:
+		t.text = p.writer.node(term.node)
+	}
+	return t, nil
+}
+
+// layoutPrinter writes laid out statements. A comment ends its line, so
+// the next token goes on a new line at the column its context gives.
+type layoutPrinter struct {
+	out  bytes.Buffer
+	opts EncodeOptions
+	// broken is set when a comment ended the line; pending holds further
+	// comments to write on lines of their own before the next token.
+	broken  bool
+	pending []string
+}
+
+func (p *layoutPrinter) String() string {
+	p.flush(0)
+	return p.out.String()
+}
+
+func (p *layoutPrinter) write(s string) {
+	p.out.WriteString(s)
+}
+
+func (p *layoutPrinter) column() int {
+	b := p.out.Bytes()
+	return len(b) - (bytes.LastIndexByte(b, '\n') + 1)
+}
+
+func (p *layoutPrinter) comments(comments []string) {
+	for _, c := range comments {
+		if p.broken {
+			p.pending = append(p.pending, c)
+			continue
+		}
+		p.write(" " + c)
+		p.broken = true
+	}
+}
+
+func (p *layoutPrinter) flush(column int) {
+	for _, c := range p.pending {
+		p.write("\n" + strings.Repeat(" ", column) + c)
+	}
+	p.pending = nil
+}
+
+// space separates the next token from the last: by a space, or by a line
+// break and indentation to column if a comment ended the line.
+func (p *layoutPrinter) space(column int) {
+	if !p.broken {
+		p.write(" ")
+		return
+	}
+	p.flush(column)
+	p.write("\n" + strings.Repeat(" ", column))
+	p.broken = false
+}
+
+func (p *layoutPrinter) statement(subject *layoutTerm, preds []*layoutPredicate) {
+	p.term(subject, 0)
+	if len(preds) == 0 {
+		p.write(" .")
+		p.comments(subject.trailing)
+		return
+	}
+
+	column := p.opts.Indent
+	if column <= 0 {
+		column = p.column() + 1
+	}
+	p.comments(subject.trailing)
+	p.space(column)
+	rest := p.predicates(preds, column)
+	p.write(" .")
+	p.comments(rest)
+}
+
+// predicates writes a predicate list whose continuation lines start at
+// column and returns the comments of its last object, which belong after
+// the token that closes the list.
+func (p *layoutPrinter) predicates(preds []*layoutPredicate, column int) []string {
+	width := 0
+	for _, pg := range preds {
+		width = max(width, len(pg.verb.text))
+	}
+
+	var rest []string
+	for j, pg := range preds {
+		if j > 0 {
+			p.broken = true
+			p.space(column)
+		}
+		name := pg.verb.text
+		if p.opts.Turtle.AlignPredicates {
+			name += strings.Repeat(" ", width-len(name))
+		}
+		p.write(name)
+		objectColumn := p.column() + 1
+		p.comments(pg.verb.trailing)
+
+		wrap := false
+		if p.opts.LineWidth > 0 && len(pg.objects) > 1 {
+			length := objectColumn + 2
+			for k, obj := range pg.objects {
+				length += len(obj.text)
+				if k > 0 {
+					length += 2
+				}
+			}
+			wrap = length > p.opts.LineWidth
+		}
+
+		for k, obj := range pg.objects {
+			if k > 0 && wrap {
+				p.broken = true
+			}
+			p.space(objectColumn)
+			p.term(obj, column)
+			switch {
+			case k < len(pg.objects)-1:
+				p.write(",")
+			case j < len(preds)-1:
+				p.write(" ;")
+			default:
+				rest = obj.trailing
+				continue
+			}
+			p.comments(obj.trailing)
+		}
+	}
+	return rest
+}
+
+// term writes t, except for its trailing comments, which go after the
+// punctuation that follows it. outer is the column continuation lines of
+// the enclosing predicate list start at.
+func (p *layoutPrinter) term(t *layoutTerm, outer int) {
+	start := p.column()
+	switch {
+	case t.close == "":
+		p.write(t.text)
+	case t.preds == nil && t.items == nil:
+		p.write(t.text)
+		if len(t.comments) > 0 {
+			p.comments(t.comments)
+			p.space(start)
+		}
+		p.write(t.close)
+	case t.preds != nil:
+		p.list(t, start, outer)
+	default:
+		p.write(t.text)
+		p.comments(t.comments)
+		column := start + len(t.text) + 1
+		for _, item := range t.items {
+			p.space(column)
+			p.term(item, outer)
+			p.comments(item.trailing)
+		}
+		p.space(start)
+		p.write(t.close)
+	}
+
+	for _, a := range t.annotations {
+		p.space(start)
+		p.term(a, outer)
+	}
+}
+
+// list writes a [ ] or {| |} property list. One without comments and with a
+// single predicate stays on its line if it fits; others put each predicate
+// on a line of its own, indented one step from outer, or aligned after the
+// opening bracket with no fixed indent.
+func (p *layoutPrinter) list(t *layoutTerm, start, outer int) {
+	if len(t.preds) == 1 && !t.hasComments() {
+		mark := p.out.Len()
+		p.write(t.text + " ")
+		p.predicates(t.preds, start)
+		p.write(" " + t.close)
+		line := p.out.Bytes()[mark:]
+		if !bytes.ContainsRune(line, '\n') && (p.opts.LineWidth <= 0 || p.column() <= p.opts.LineWidth) {
+			return
+		}
+		p.out.Truncate(mark)
+	}
+
+	p.write(t.text)
+	p.comments(t.comments)
+	if p.opts.Indent > 0 {
+		column := outer + p.opts.Indent
+		p.broken = true
+		p.space(column)
+		p.comments(p.predicates(t.preds, column))
+		p.broken = true
+		p.space(outer)
+		p.write(t.close)
+		return
+	}
+
+	column := start + len(t.text) + 1
+	p.space(column)
+	p.comments(p.predicates(t.preds, column))
+	p.space(start)
+	p.write(t.close)
+}
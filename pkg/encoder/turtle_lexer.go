@@ -0,0 +1,255 @@
+package encoder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type turtleTokenKind int
+
+const (
+	tokEOF turtleTokenKind = iota
+	tokWhitespace
+	tokComment
+	tokIRI
+	tokPrefixedName
+	tokBlankNode
+	tokString
+	tokLangTag
+	tokDatatypeMarker
+	tokInteger
+	tokDecimal
+	tokDouble
+	tokKeyword
+	tokPunct
+)
+
+type turtleToken struct {
+	kind   turtleTokenKind
+	text   string
+	start  int
+	line   int
+	column int
+}
+
+func (t turtleToken) end() int {
+	return t.start + len(t.text)
+}
+
+func (t turtleToken) is(kind turtleTokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+var turtleNumber = regexp.MustCompile(`^[+-]?([0-9]+\.[0-9]*[eE][+-]?[0-9]+|\.[0-9]+[eE][+-]?[0-9]+|[0-9]+[eE][+-]?[0-9]+|[0-9]*\.[0-9]+|[0-9]+)`)
+
+type turtleLexer struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+func tokenizeTurtle(src string) ([]turtleToken, error) {
+	lx := &turtleLexer{src: src, line: 1, column: 1}
+	var tokens []turtleToken
+
+	for lx.pos < len(src) {
+		tok, err := lx.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+	}
+
+	return tokens, nil
+}
+
+func (lx *turtleLexer) error(msg string) error {
+	return (&parseContext{line: lx.line, column: lx.column}).error(msg)
+}
+
+func (lx *turtleLexer) emit(kind turtleTokenKind, length int) turtleToken {
+	tok := turtleToken{
+		kind:   kind,
+		text:   lx.src[lx.pos : lx.pos+length],
+		start:  lx.pos,
+		line:   lx.line,
+		column: lx.column,
+	}
+	for _, ch := range tok.text {
+		if ch == '\n' {
+			lx.line++
+			lx.column = 1
+		} else {
+			lx.column++
+		}
+	}
+	lx.pos += length
+	return tok
+}
+
+func (lx *turtleLexer) next() (turtleToken, error) {
+	rest := lx.src[lx.pos:]
+	ch := rest[0]
+
+	switch {
+	case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+		n := 0
+		for n < len(rest) && strings.IndexByte(" \t\r\n", rest[n]) >= 0 {
+			n++
+		}
+		return lx.emit(tokWhitespace, n), nil
+	case ch == '#':
+		n := strings.IndexByte(rest, '\n')
+		if n == -1 {
+			n = len(rest)
+		}
+		return lx.emit(tokComment, n), nil
+	case strings.HasPrefix(rest, "<<("), strings.HasPrefix(rest, ")>>"):
+		return lx.emit(tokPunct, 3), nil
+	case strings.HasPrefix(rest, "<<"), strings.HasPrefix(rest, ">>"),
+		strings.HasPrefix(rest, "{|"), strings.HasPrefix(rest, "|}"):
+		return lx.emit(tokPunct, 2), nil
+	case ch == '<':
+		n := strings.IndexAny(rest[1:], ">\n")
+		if n == -1 || rest[1+n] != '>' {
+			return turtleToken{}, lx.error("unclosed IRI")
+		}
+		return lx.emit(tokIRI, n+2), nil
+	case ch == '"' || ch == '\'':
+		n, err := lx.scanString(rest)
+		if err != nil {
+			return turtleToken{}, err
+		}
+		return lx.emit(tokString, n), nil
+	case ch == '@':
+		n := 1
+		for n < len(rest) && (isASCIILetter(rest[n]) || (n > 1 && (rest[n] == '-' || isASCIIDigit(rest[n])))) {
+			n++
+		}
+		if n == 1 {
+			return turtleToken{}, lx.error("expected language tag or directive after @")
+		}
+		if word := rest[:n]; word == "@prefix" || word == "@base" {
+			return lx.emit(tokKeyword, n), nil
+		}
+		return lx.emit(tokLangTag, n), nil
+	case strings.HasPrefix(rest, "^^"):
+		return lx.emit(tokDatatypeMarker, 2), nil
+	case strings.HasPrefix(rest, "_:"):
+		n := 2 + scanTurtleName(rest[2:])
+		if n == 2 {
+			return turtleToken{}, lx.error("empty blank node label")
+		}
+		return lx.emit(tokBlankNode, n), nil
+	case isASCIIDigit(ch) || ((ch == '+' || ch == '-' || ch == '.') && len(rest) > 1 && (isASCIIDigit(rest[1]) || rest[1] == '.')):
+		if m := turtleNumber.FindString(rest); m != "" {
+			kind := tokInteger
+			if strings.ContainsAny(m, "eE") {
+				kind = tokDouble
+			} else if strings.Contains(m, ".") {
+				kind = tokDecimal
+			}
+			return lx.emit(kind, len(m)), nil
+		}
+		if ch == '.' {
+			return lx.emit(tokPunct, 1), nil
+		}
+	case strings.IndexByte(".;,[]()~", ch) >= 0:
+		return lx.emit(tokPunct, 1), nil
+	}
+
+	n := scanTurtleName(rest)
+	if n == 0 {
+		r, _ := utf8.DecodeRuneInString(rest)
+		return turtleToken{}, lx.error(fmt.Sprintf("unexpected character %q", r))
+	}
+
+	word := rest[:n]
+	switch {
+	case strings.Contains(word, ":"):
+		return lx.emit(tokPrefixedName, n), nil
+	case word == "a" || word == "true" || word == "false",
+		strings.EqualFold(word, "PREFIX"), strings.EqualFold(word, "BASE"):
+		return lx.emit(tokKeyword, n), nil
+	}
+	return turtleToken{}, lx.error(fmt.Sprintf("unexpected token %q", word))
+}
+
+func (lx *turtleLexer) scanString(rest string) (int, error) {
+	quote := rest[:1]
+	long := strings.Repeat(quote, 3)
+
+	if strings.HasPrefix(rest, long) {
+		for i := 3; i < len(rest); i++ {
+			if rest[i] == '\\' {
+				i++
+				continue
+			}
+			if strings.HasPrefix(rest[i:], long) {
+				end := i + 3
+				for end < len(rest) && rest[end:end+1] == quote {
+					end++
+				}
+				return end, nil
+			}
+		}
+		return 0, lx.error("unclosed long string")
+	}
+
+	for i := 1; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			i++
+		case '\n', '\r':
+			return 0, lx.error("newline in string")
+		case quote[0]:
+			return i + 1, nil
+		}
+	}
+	return 0, lx.error("unclosed string")
+}
+
+func scanTurtleName(s string) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case r == '\\' && n+1 < len(s):
+			n += 2
+			continue
+		case r == '_' || r == '-' || r == '.' || r == ':' || r == '%' || r == 0xB7,
+			unicode.IsLetter(r), unicode.IsDigit(r), unicode.Is(unicode.Mn, r):
+		default:
+			return trimTrailingDots(s, n)
+		}
+		n += size
+	}
+	return trimTrailingDots(s, n)
+}
+
+func trimTrailingDots(s string, n int) int {
+	for n > 0 && s[n-1] == '.' {
+		n--
+	}
+	return n
+}
+
+func isASCIILetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isASCIIDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func unescapeTurtleString(tok string) (string, error) {
+	quoteLen := 1
+	if len(tok) >= 6 && (strings.HasPrefix(tok, `"""`) || strings.HasPrefix(tok, `'''`)) {
+		quoteLen = 3
+	}
+	return unescapeString(tok[quoteLen : len(tok)-quoteLen])
+}
@@ -0,0 +1,140 @@
+package graphstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// Client talks to a Graph Store Protocol endpoint. Graph arguments are
+// graph IRIs; "" selects the default graph.
+type Client struct {
+	// Endpoint is the URL of the graph store, such as
+	// http://localhost:8080/rdf-graphs.
+	Endpoint string
+	// Format is used for request bodies and preferred for responses.
+	// Empty means Turtle.
+	Format encoder.Format
+	// HTTPClient sends the requests. Nil means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// StatusError is returned for a response with a status other than 2xx.
+type StatusError struct {
+	StatusCode int
+	// Message is the body of the response, which for this package's
+	// handler explains the error.
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s", http.StatusText(e.StatusCode), e.Message)
+}
+
+// Get returns the triples of a graph and the prefixes the response declared.
+func (c *Client) Get(ctx context.Context, graph string) ([]triple.Triple, map[string]string, error) {
+	accept := c.format().MediaType()
+	for _, f := range []encoder.Format{encoder.Turtle, encoder.NTriples, encoder.JSONLD} {
+		if f != c.format() {
+			accept += ", " + f.MediaType() + ";q=0.5"
+		}
+	}
+
+	body, header, err := c.do(ctx, http.MethodGet, graph, accept, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	format, err := encoder.FormatForMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return encoder.Decode(body, format, encoder.DecodeOptions{})
+}
+
+// Put replaces the contents of a graph, creating it if needed. The prefixes
+// only shorten the request body.
+func (c *Client) Put(ctx context.Context, graph string, triples []triple.Triple, prefixes map[string]string) error {
+	return c.send(ctx, http.MethodPut, graph, triples, prefixes)
+}
+
+// Post adds triples to a graph, creating it if needed.
+func (c *Client) Post(ctx context.Context, graph string, triples []triple.Triple, prefixes map[string]string) error {
+	return c.send(ctx, http.MethodPost, graph, triples, prefixes)
+}
+
+// Delete removes a graph. Deleting the default graph empties it.
+func (c *Client) Delete(ctx context.Context, graph string) error {
+	_, _, err := c.do(ctx, http.MethodDelete, graph, "", nil)
+	return err
+}
+
+func (c *Client) send(ctx context.Context, method, graph string, triples []triple.Triple, prefixes map[string]string) error {
+	body, err := encoder.Encode(triples, c.format(), encoder.EncodeOptions{Prefixes: prefixes, Compact: true})
+	if err != nil {
+		return err
+	}
+	_, _, err = c.do(ctx, method, graph, "", strings.NewReader(body))
+	return err
+}
+
+func (c *Client) format() encoder.Format {
+	if c.Format == "" {
+		return encoder.Turtle
+	}
+	return c.Format
+}
+
+// do sends a request for graph and returns the body of a successful
+// response.
+func (c *Client) do(ctx context.Context, method, graph, accept string, body io.Reader) (string, http.Header, error) {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return "", nil, err
+	}
+	params := u.Query()
+	if graph == "" {
+		params.Set("default", "")
+	} else {
+		params.Set("graph", graph)
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return "", nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", c.format().MediaType())
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", nil, &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	return string(data), resp.Header, nil
+}
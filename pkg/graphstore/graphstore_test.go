@@ -0,0 +1,180 @@
+package graphstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func ex(name string) triple.IRI { return triple.IRI{Value: "http://example.org/" + name} }
+
+func TestClientAndHandler(t *testing.T) {
+	def := triple.NewGraph([]triple.Triple{{Subject: ex("a"), Predicate: ex("p"), Object: ex("b")}})
+	srv := httptest.NewServer(NewHandler(def, HandlerOptions{Prefixes: map[string]string{"ex": "http://example.org/"}}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	for _, format := range []encoder.Format{encoder.Turtle, encoder.NTriples, encoder.JSONLD} {
+		t.Run(string(format), func(t *testing.T) {
+			c := &Client{Endpoint: srv.URL, Format: format}
+			graph := "http://example.org/graphs/" + string(format)
+
+			if _, _, err := c.Get(ctx, graph); !isStatus(err, http.StatusNotFound) {
+				t.Fatalf("Get() of a missing graph error = %v, want 404", err)
+			}
+
+			blank := triple.BlankNode{Value: "x"}
+			first := []triple.Triple{
+				{Subject: ex("s"), Predicate: ex("p"), Object: blank},
+				{Subject: blank, Predicate: ex("name"), Object: triple.Literal{Value: "x"}},
+			}
+			if err := c.Put(ctx, graph, first, nil); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			second := []triple.Triple{{Subject: ex("s"), Predicate: ex("p"), Object: triple.Literal{Value: "2"}}}
+			if err := c.Post(ctx, graph, second, nil); err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			got, _, err := c.Get(ctx, graph)
+			if err != nil || len(got) != 3 {
+				t.Fatalf("Get() after PUT and POST = %v, %v, want 3 triples", got, err)
+			}
+
+			if err := c.Put(ctx, graph, second, nil); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			if got, _, _ := c.Get(ctx, graph); len(got) != 1 || got[0] != second[0] {
+				t.Errorf("Get() after replacing PUT = %v, want %v", got, second)
+			}
+
+			if err := c.Delete(ctx, graph); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if err := c.Delete(ctx, graph); !isStatus(err, http.StatusNotFound) {
+				t.Errorf("second Delete() error = %v, want 404", err)
+			}
+		})
+	}
+
+	// The default graph is the one given to the handler.
+	c := &Client{Endpoint: srv.URL}
+	got, prefixes, err := c.Get(ctx, "")
+	if err != nil || len(got) != 1 || prefixes["ex"] != "http://example.org/" {
+		t.Fatalf("Get(default) = %v, %v, %v", got, prefixes, err)
+	}
+	if err := c.Post(ctx, "", []triple.Triple{{Subject: ex("c"), Predicate: ex("p"), Object: ex("d")}}, nil); err != nil {
+		t.Fatalf("Post(default) error = %v", err)
+	}
+	if def.Len() != 2 {
+		t.Errorf("default graph has %d triples after POST, want 2", def.Len())
+	}
+	if err := c.Delete(ctx, ""); err != nil || def.Len() != 0 {
+		t.Errorf("Delete(default) error = %v, %d triples left", err, def.Len())
+	}
+}
+
+func TestHandlerRequests(t *testing.T) {
+	h := NewHandler(triple.NewGraph(nil), HandlerOptions{})
+
+	serve := func(method, target, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Direct identification: the request URL names the graph, and relative
+	// IRIs resolve against it.
+	if rec := serve("PUT", "http://example.org/graphs/g1", "text/turtle", `<#me> <knows> <you> .`); rec.Code != http.StatusCreated {
+		t.Fatalf("PUT status = %d: %s", rec.Code, rec.Body)
+	}
+	rec := serve("GET", "http://example.org/rdf-graphs?graph=http%3A%2F%2Fexample.org%2Fgraphs%2Fg1", "", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<http://example.org/graphs/g1#me>") {
+		t.Errorf("GET = %d %q", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/turtle; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"unsupported media type", "PUT", "/?default", "application/rdf+xml", "<rdf:RDF/>", http.StatusUnsupportedMediaType},
+		{"syntax error", "POST", "/?default", "text/turtle", "<a> <b>", http.StatusBadRequest},
+		{"default and graph", "GET", "/?default&graph=http://example.org/g", "", "", http.StatusBadRequest},
+		{"relative graph IRI", "GET", "/?graph=g", "", "", http.StatusBadRequest},
+		{"method", "PATCH", "/?default", "", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if rec := serve(tt.method, tt.target, tt.contentType, tt.body); rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+		}
+	}
+
+	defaultOnly := NewHandler(triple.NewGraph(nil), HandlerOptions{DefaultOnly: true})
+	rec = httptest.NewRecorder()
+	defaultOnly.ServeHTTP(rec, httptest.NewRequest("PUT", "/?graph=http://example.org/g", strings.NewReader("<a> <b> <c> .")))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("default-only named PUT status = %d", rec.Code)
+	}
+
+	// A page on another origin cannot write, and gets no CORS header.
+	crossOrigin := httptest.NewRequest("DELETE", "http://localhost:8080/rdf-graphs?default", nil)
+	crossOrigin.Header.Set("Origin", "https://evil.example")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, crossOrigin)
+	if rec.Code != http.StatusForbidden || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("cross-origin DELETE status = %d, headers %v", rec.Code, rec.Header())
+	}
+	allowed := NewHandler(triple.NewGraph(nil), HandlerOptions{AllowOrigin: "*"})
+	rec = httptest.NewRecorder()
+	allowed.ServeHTTP(rec, crossOrigin)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("DELETE allowed from any origin: status = %d, headers %v", rec.Code, rec.Header())
+	}
+
+	readOnly := NewHandler(triple.NewGraph(nil), HandlerOptions{ReadOnly: true})
+	rec = httptest.NewRecorder()
+	readOnly.ServeHTTP(rec, httptest.NewRequest("DELETE", "/?default", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("read-only DELETE status = %d", rec.Code)
+	}
+}
+
+// TestHandlerBlankNodes checks that the blank nodes of separate writes stay
+// distinct, also between handlers serving the same graph one after another.
+func TestHandlerBlankNodes(t *testing.T) {
+	g := triple.NewGraph(nil)
+	for i := 0; i < 2; i++ {
+		h := NewHandler(g, HandlerOptions{})
+		req := httptest.NewRequest("POST", "/?default", strings.NewReader(`_:b <http://example.org/p> "x" .`))
+		req.Header.Set("Content-Type", "text/turtle")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("POST status = %d: %s", rec.Code, rec.Body)
+		}
+	}
+	if g.Len() != 2 {
+		t.Errorf("graph has %d triples, want 2: %v", g.Len(), g.Triples())
+	}
+}
+
+func isStatus(err error, code int) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == code
+}
@@ -0,0 +1,302 @@
+// Package graphstore implements the SPARQL 1.1 Graph Store HTTP Protocol:
+// reading, replacing, merging and deleting whole graphs over HTTP.
+package graphstore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/DeDude/tripl/internal/httputil"
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// HandlerOptions configures a Graph Store handler.
+type HandlerOptions struct {
+	// Prefixes shorten IRIs in Turtle and JSON-LD responses.
+	Prefixes map[string]string
+	// ReadOnly rejects PUT, POST and DELETE with 403 Forbidden.
+	ReadOnly bool
+	// AllowOrigin is the origin of the web pages, or "*" for any, that may
+	// use the handler from a browser. Writes from pages on other origins are
+	// rejected with 403 Forbidden. Empty allows only the server's own.
+	AllowOrigin string
+	// Lock guards the graphs. Share it with any other handler serving the
+	// default graph, such as a SPARQL endpoint. Nil gives the handler its
+	// own.
+	Lock *sync.RWMutex
+	// Commit, if set, is called with the lock held after each change to
+	// the default graph, and an error from it fails the request. A
+	// transactional store uses it to make each request all or nothing.
+	Commit func() error
+	// DefaultOnly answers writes to named graphs with 501 Not Implemented.
+	// Set it when the default graph is persisted and named graphs, which
+	// live in memory only, would be lost.
+	DefaultOnly bool
+}
+
+// Graph is a default graph the handler can serve, such as *triple.Graph or
+// a *store.Store.
+type Graph interface {
+	Triples() []triple.Triple
+	Add(t triple.Triple) bool
+	Remove(t triple.Triple) bool
+}
+
+// Handler serves a default graph and any number of named graphs. A request
+// names its graph with ?graph=IRI, selects the default graph with ?default,
+// or, with neither, names the graph by its own URL. Named graphs are created
+// by PUT or POST and removed by DELETE; deleting the default graph empties
+// it. The blank nodes of each PUT and POST get a random label prefix, so
+// they stay distinct from those of every other request, even across
+// restarts of a persisted graph.
+type Handler struct {
+	def   Graph
+	named map[string]*triple.Graph
+	opts  HandlerOptions
+	mu    *sync.RWMutex
+}
+
+func NewHandler(defaultGraph Graph, opts HandlerOptions) *Handler {
+	mu := opts.Lock
+	if mu == nil {
+		mu = new(sync.RWMutex)
+	}
+	return &Handler{def: defaultGraph, named: make(map[string]*triple.Graph), opts: opts, mu: mu}
+}
+
+// mediaTypes lists the media types offered for GET, most preferred first.
+var mediaTypes = []string{
+	encoder.Turtle.MediaType(),
+	encoder.NTriples.MediaType(),
+	encoder.JSONLD.MediaType(),
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	httputil.AllowOrigin(w, h.opts.AllowOrigin)
+
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST, DELETE, OPTIONS")
+		if h.opts.AllowOrigin != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, PUT, POST, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type")
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && httputil.CrossOrigin(r, h.opts.AllowOrigin) {
+		httputil.Error(w, http.StatusForbidden, "writes from pages on %s are not allowed", r.Header.Get("Origin"))
+		return
+	}
+
+	iri, err := graphIRI(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.serveGet(w, r, iri)
+	case http.MethodPut, http.MethodPost:
+		h.serveWrite(w, r, iri)
+	case http.MethodDelete:
+		h.serveDelete(w, iri)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST, DELETE, OPTIONS")
+		httputil.Error(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+// graphIRI returns the IRI of the graph a request targets, "" for the
+// default graph.
+func graphIRI(r *http.Request) (string, error) {
+	params := r.URL.Query()
+	_, isDefault := params["default"]
+	_, isNamed := params["graph"]
+	switch {
+	case isDefault && isNamed:
+		return "", fmt.Errorf("a request may use ?default or ?graph, not both")
+	case isDefault:
+		return "", nil
+	case isNamed:
+		iri := params.Get("graph")
+		if err := triple.ValidateIRI(iri); err != nil {
+			return "", fmt.Errorf("graph: %w", err)
+		}
+		return iri, nil
+	}
+	return httputil.RequestURL(r), nil
+}
+
+// graph returns the graph named iri, or the default graph for "". It
+// returns nil when a named graph does not exist.
+func (h *Handler) graph(iri string) Graph {
+	if iri == "" {
+		return h.def
+	}
+	if g, ok := h.named[iri]; ok {
+		return g
+	}
+	return nil
+}
+
+func (h *Handler) serveGet(w http.ResponseWriter, r *http.Request, iri string) {
+	mediaType, ok := httputil.Negotiate(r.Header.Get("Accept"), mediaTypes)
+	if !ok {
+		httputil.Error(w, http.StatusNotAcceptable, "acceptable media types: %s", strings.Join(mediaTypes, ", "))
+		return
+	}
+	format, _ := encoder.FormatForMediaType(mediaType)
+
+	h.mu.RLock()
+	var triples []triple.Triple
+	g := h.graph(iri)
+	if g != nil {
+		triples = g.Triples()
+	}
+	h.mu.RUnlock()
+	if g == nil {
+		httputil.Error(w, http.StatusNotFound, "no graph <%s>", iri)
+		return
+	}
+
+	body, err := encoder.Encode(triples, format, encoder.EncodeOptions{Prefixes: h.opts.Prefixes, Compact: true})
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.Header().Set("Content-Type", httputil.ContentType(mediaType))
+	w.Header().Set("Vary", "Accept")
+	io.WriteString(w, body)
+}
+
+// serveWrite handles PUT, which replaces the graph, and POST, which merges
+// into it. Relative IRIs in the body resolve against the graph IRI.
+func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, iri string) {
+	if h.opts.ReadOnly {
+		httputil.Error(w, http.StatusForbidden, "the graph store is read-only")
+		return
+	}
+	if iri != "" && h.opts.DefaultOnly {
+		httputil.Error(w, http.StatusNotImplemented, "this graph store only has a default graph; use ?default")
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		httputil.Error(w, http.StatusUnsupportedMediaType, "missing or invalid Content-Type")
+		return
+	}
+	format, err := encoder.FormatForMediaType(mediaType)
+	if err != nil {
+		httputil.Error(w, http.StatusUnsupportedMediaType, "%v", err)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "reading request: %v", err)
+		return
+	}
+	base := iri
+	if base == "" {
+		base = httputil.RequestURL(r)
+	}
+	triples, _, err := encoder.Decode(string(body), format, encoder.DecodeOptions{Base: base})
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	var nonce [6]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	label := "w" + hex.EncodeToString(nonce[:]) + "_"
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	g := h.graph(iri)
+	created := g == nil
+	switch {
+	case created:
+		named := triple.NewGraph(nil)
+		h.named[iri] = named
+		g = named
+	case r.Method == http.MethodPut:
+		for _, t := range g.Triples() {
+			g.Remove(t)
+		}
+	}
+	for _, t := range triples {
+		g.Add(relabelBlankNodes(t, label))
+	}
+	if iri == "" && !h.commit(w) {
+		return
+	}
+
+	if created {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) serveDelete(w http.ResponseWriter, iri string) {
+	if h.opts.ReadOnly {
+		httputil.Error(w, http.StatusForbidden, "the graph store is read-only")
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if iri == "" {
+		for _, t := range h.def.Triples() {
+			h.def.Remove(t)
+		}
+		if !h.commit(w) {
+			return
+		}
+	} else if _, ok := h.named[iri]; ok {
+		delete(h.named, iri)
+	} else {
+		httputil.Error(w, http.StatusNotFound, "no graph <%s>", iri)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// commit calls the Commit option, answering with an error and returning
+// false if it fails.
+func (h *Handler) commit(w http.ResponseWriter) bool {
+	if h.opts.Commit == nil {
+		return true
+	}
+	if err := h.opts.Commit(); err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "%v", err)
+		return false
+	}
+	return true
+}
+
+func relabelBlankNodes(t triple.Triple, prefix string) triple.Triple {
+	relabel := func(n triple.Node) triple.Node {
+		switch node := n.(type) {
+		case triple.BlankNode:
+			return triple.BlankNode{Value: prefix + node.Value}
+		case triple.TripleTerm:
+			return triple.TripleTerm{Triple: relabelBlankNodes(node.Triple, prefix)}
+		}
+		return n
+	}
+	return triple.Triple{Subject: relabel(t.Subject), Predicate: t.Predicate, Object: relabel(t.Object)}
+}
@@ -0,0 +1,124 @@
+package linkeddata
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+td, th { border-bottom: 1px solid #ddd; padding: 0.3em 1em 0.3em 0; text-align: left; vertical-align: top; }
+code { word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><code>{{.IRI}}</code></p>
+<p>Also as {{range $i, $a := .Alternates}}{{if $i}}, {{end}}<a href="{{$a.Href}}">{{$a.Text}}</a>{{end}}.</p>
+{{range .Sections}}
+<h2{{with .ID}} id="{{.}}"{{end}}>{{template "term" .Subject}}</h2>
+<table>
+{{range .Rows}}<tr><th>{{template "term" .First}}</th><td>{{template "term" .Second}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Inbound}}
+<h2>Referenced by</h2>
+<table>
+{{range .Inbound}}<tr><td>{{template "term" .First}}</td><th>{{template "term" .Second}}</th></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+{{define "term"}}{{if .Href}}<a href="{{.Href}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{end}}
+`))
+
+type htmlTerm struct {
+	Text string
+	Href string
+}
+
+type htmlRow struct {
+	First, Second htmlTerm
+}
+
+type htmlSection struct {
+	ID      string
+	Subject htmlTerm
+	Rows    []htmlRow
+}
+
+// renderHTML writes a description as a page. IRIs under base link to their
+// local path so the data can be browsed from the server, and blank nodes
+// link to their section on the page.
+func renderHTML(d description, base string, prefixes map[string]string) (string, error) {
+	term := func(n triple.Node) htmlTerm {
+		t := htmlTerm{Text: encoder.FormatTurtleTerm(n, prefixes)}
+		switch node := n.(type) {
+		case triple.IRI:
+			t.Href = node.Value
+			if rel, ok := strings.CutPrefix(node.Value, base); ok {
+				t.Href = "/" + rel
+			}
+		case triple.BlankNode:
+			if _, ok := d.outbound[node]; ok {
+				t.Href = "#" + blankNodeID(node)
+			}
+		}
+		return t
+	}
+
+	name := documentName(strings.TrimPrefix(d.iri, base))
+	data := struct {
+		Title      string
+		IRI        string
+		Alternates []htmlTerm
+		Sections   []htmlSection
+		Inbound    []htmlRow
+	}{
+		Title: encoder.FormatTurtleTerm(triple.IRI{Value: d.iri}, prefixes),
+		IRI:   d.iri,
+	}
+	for _, f := range []encoder.Format{encoder.Turtle, encoder.JSONLD, encoder.NTriples} {
+		data.Alternates = append(data.Alternates, htmlTerm{Text: string(f), Href: "/" + name + f.Extension()})
+	}
+
+	for _, s := range d.subjects {
+		section := htmlSection{Subject: term(s)}
+		switch node := s.(type) {
+		case triple.BlankNode:
+			section.ID = blankNodeID(node)
+		case triple.IRI:
+			// A hash IRI's section can be linked to by its fragment; other
+			// sections have no id.
+			if _, fragment, ok := strings.Cut(node.Value, "#"); ok && fragment != "" {
+				section.ID = fragment
+			}
+		}
+		for _, t := range d.outbound[s] {
+			section.Rows = append(section.Rows, htmlRow{First: term(t.Predicate), Second: term(t.Object)})
+		}
+		data.Sections = append(data.Sections, section)
+	}
+	for _, t := range d.inbound {
+		data.Inbound = append(data.Inbound, htmlRow{First: term(t.Subject), Second: term(t.Predicate)})
+	}
+
+	var b strings.Builder
+	if err := page.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func blankNodeID(b triple.BlankNode) string {
+	return "_" + b.Value
+}
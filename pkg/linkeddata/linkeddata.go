@@ -0,0 +1,256 @@
+// Package linkeddata publishes a graph as Linked Data: every IRI under a
+// base URL can be dereferenced to a description of the resource, in RDF or
+// as an HTML page.
+//
+// A resource IRI such as http://example.org/people/alice is answered with a
+// 303 See Other to a document for the format the client accepts,
+// /people/alice.ttl, .jsonld, .nt or .html. The document describes the
+// resource and every hash IRI built on it, such as
+// http://example.org/people/alice#me, so both slash and hash IRIs resolve.
+// The document for the base itself, or any IRI ending in a slash, is named
+// index, as in /index.ttl.
+package linkeddata
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DeDude/tripl/internal/httputil"
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// Source is a graph the handler can describe resources from, such as
+// *triple.Graph or a *store.Store.
+type Source interface {
+	triple.Matcher
+	Triples() []triple.Triple
+}
+
+// HandlerOptions configures a Linked Data handler.
+type HandlerOptions struct {
+	// Base is the IRI that the root of the handler stands for, such as
+	// "http://example.org/". Empty means the URL the request was sent to,
+	// so the graph must use the server's own address.
+	Base string
+	// Inbound adds the triples that have a resource as object to its
+	// description.
+	Inbound bool
+	// Prefixes shorten IRIs in Turtle, JSON-LD and HTML responses.
+	Prefixes map[string]string
+	// Lock guards the graph against writers elsewhere. Nil gives the
+	// handler its own.
+	Lock *sync.RWMutex
+}
+
+// Handler answers GET requests for resources and their documents.
+//
+// Hash IRIs are found through an index built by NewHandler, so a graph that
+// changes later still serves its slash IRIs as they are, but only the hash
+// IRIs it had then.
+type Handler struct {
+	graph Source
+	opts  HandlerOptions
+	mu    *sync.RWMutex
+	// hashes maps an IRI to the subjects that are it plus a fragment.
+	hashes map[string][]triple.IRI
+}
+
+// NewHandler returns a handler serving g. It builds the index of hash IRIs
+// once, from the subjects g holds now, so the documents only describe hash
+// IRIs that existed when the handler was made; g should not change later.
+func NewHandler(g Source, opts HandlerOptions) *Handler {
+	mu := opts.Lock
+	if mu == nil {
+		mu = new(sync.RWMutex)
+	}
+	h := &Handler{graph: g, opts: opts, mu: mu, hashes: make(map[string][]triple.IRI)}
+
+	mu.RLock()
+	seen := make(map[string]bool)
+	for _, t := range g.Triples() {
+		s, ok := t.Subject.(triple.IRI)
+		if !ok || seen[s.Value] {
+			continue
+		}
+		seen[s.Value] = true
+		if doc, _, found := strings.Cut(s.Value, "#"); found {
+			h.hashes[doc] = append(h.hashes[doc], s)
+		}
+	}
+	mu.RUnlock()
+	for _, subjects := range h.hashes {
+		sort.Slice(subjects, func(i, j int) bool { return subjects[i].Value < subjects[j].Value })
+	}
+	return h
+}
+
+const htmlMediaType = "text/html"
+
+// documents maps document extensions to media types, and mediaTypes lists
+// the media types offered, most preferred first.
+var (
+	documents = map[string]string{
+		encoder.Turtle.Extension():   encoder.Turtle.MediaType(),
+		encoder.JSONLD.Extension():   encoder.JSONLD.MediaType(),
+		encoder.NTriples.Extension(): encoder.NTriples.MediaType(),
+		".html":                      htmlMediaType,
+	}
+	mediaTypes = []string{
+		encoder.Turtle.MediaType(),
+		encoder.JSONLD.MediaType(),
+		encoder.NTriples.MediaType(),
+		htmlMediaType,
+	}
+)
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		httputil.Error(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+
+	base := h.opts.Base
+	if base == "" {
+		base = httputil.Origin(r) + "/"
+	}
+	rel := strings.TrimPrefix(r.URL.EscapedPath(), "/")
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	// A document name wins over a resource IRI that happens to end in an
+	// extension only if the resource it names has a description.
+	if ext := path.Ext(rel); documents[ext] != "" {
+		for _, iri := range documentResources(base, strings.TrimSuffix(rel, ext)) {
+			if desc := h.describe(iri); desc.len() > 0 {
+				h.serveDocument(w, desc, documents[ext], base)
+				return
+			}
+		}
+	}
+
+	iri := base + rel
+	if h.describe(iri).len() == 0 {
+		httputil.Error(w, http.StatusNotFound, "no description of <%s>", iri)
+		return
+	}
+	mediaType, ok := httputil.Negotiate(r.Header.Get("Accept"), mediaTypes)
+	if !ok {
+		httputil.Error(w, http.StatusNotAcceptable, "acceptable media types: %s", strings.Join(mediaTypes, ", "))
+		return
+	}
+	w.Header().Set("Vary", "Accept")
+	http.Redirect(w, r, "/"+documentName(rel)+extension(mediaType), http.StatusSeeOther)
+}
+
+// documentName returns the document path, without extension, for the
+// resource at rel.
+func documentName(rel string) string {
+	if rel == "" || strings.HasSuffix(rel, "/") {
+		return rel + "index"
+	}
+	return rel
+}
+
+// documentResources returns the IRIs a document name may stand for: the
+// name itself and, for an index, the directory IRI it replaces.
+func documentResources(base, name string) []string {
+	iris := []string{base + name}
+	if name == "index" || strings.HasSuffix(name, "/index") {
+		iris = append(iris, base+strings.TrimSuffix(name, "index"))
+	}
+	return iris
+}
+
+func extension(mediaType string) string {
+	for ext, mt := range documents {
+		if mt == mediaType {
+			return ext
+		}
+	}
+	return ""
+}
+
+// description is what a document says about a resource: the triples of the
+// resource and its hash IRIs, the blank nodes they reach and, if asked for,
+// the triples pointing at them.
+type description struct {
+	iri      string
+	subjects []triple.Node
+	outbound map[triple.Node][]triple.Triple
+	inbound  []triple.Triple
+}
+
+func (d description) len() int {
+	n := len(d.inbound)
+	for _, triples := range d.outbound {
+		n += len(triples)
+	}
+	return n
+}
+
+func (d description) triples() []triple.Triple {
+	var all []triple.Triple
+	for _, s := range d.subjects {
+		all = append(all, d.outbound[s]...)
+	}
+	return append(all, d.inbound...)
+}
+
+// describe must be called with the lock held.
+func (h *Handler) describe(iri string) description {
+	d := description{iri: iri, outbound: make(map[triple.Node][]triple.Triple)}
+	resources := append([]triple.IRI{{Value: iri}}, h.hashes[iri]...)
+
+	var add func(s triple.Node)
+	add = func(s triple.Node) {
+		if _, ok := d.outbound[s]; ok {
+			return
+		}
+		triples := h.graph.Match(s, nil, nil)
+		d.outbound[s] = triples
+		if len(triples) > 0 {
+			d.subjects = append(d.subjects, s)
+		}
+		for _, t := range triples {
+			if b, ok := t.Object.(triple.BlankNode); ok {
+				add(b)
+			}
+		}
+	}
+	for _, r := range resources {
+		add(r)
+	}
+
+	if h.opts.Inbound {
+		for _, r := range resources {
+			d.inbound = append(d.inbound, h.graph.Match(nil, nil, r)...)
+		}
+	}
+	return d
+}
+
+func (h *Handler) serveDocument(w http.ResponseWriter, d description, mediaType, base string) {
+	var body string
+	var err error
+	if mediaType == htmlMediaType {
+		body, err = renderHTML(d, base, h.opts.Prefixes)
+	} else {
+		format, _ := encoder.FormatForMediaType(mediaType)
+		body, err = encoder.Encode(d.triples(), format, encoder.EncodeOptions{Prefixes: h.opts.Prefixes, Compact: true})
+	}
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", httputil.ContentType(mediaType))
+	io.WriteString(w, body)
+}
@@ -0,0 +1,168 @@
+package linkeddata
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+const testData = `@prefix ex: <http://example.org/> .
+@prefix foaf: <http://xmlns.com/foaf/0.1/> .
+
+ex:alice foaf:name "Alice" ;
+    foaf:knows ex:bob ;
+    ex:address [ ex:city "Oslo" ] .
+ex:bob foaf:name "Bob" .
+<http://example.org/vocab#Person> ex:label "Person" .
+<http://example.org/vocab#name> ex:label "name" .
+ex: ex:title "Example" .
+<http://other.org/carol> foaf:knows ex:alice .
+`
+
+func testHandler(t *testing.T, inbound bool) *Handler {
+	t.Helper()
+	triples, prefixes, err := encoder.Decode(testData, encoder.Turtle, encoder.DecodeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(triple.NewGraph(triples), HandlerOptions{Base: "http://example.org/", Inbound: inbound, Prefixes: prefixes})
+}
+
+func get(h *Handler, target, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRedirect(t *testing.T) {
+	h := testHandler(t, false)
+
+	tests := []struct {
+		target, accept, location string
+	}{
+		{"/alice", "", "/alice.ttl"},
+		{"/alice", "application/ld+json", "/alice.jsonld"},
+		{"/alice", "application/n-triples", "/alice.nt"},
+		{"/alice", "text/html,application/xhtml+xml,*/*;q=0.8", "/alice.html"},
+		{"/vocab", "text/turtle", "/vocab.ttl"},
+		{"/", "", "/index.ttl"},
+	}
+	for _, tt := range tests {
+		rec := get(h, tt.target, tt.accept)
+		if rec.Code != 303 {
+			t.Errorf("%s (%s): status %d, want 303", tt.target, tt.accept, rec.Code)
+			continue
+		}
+		if got := rec.Header().Get("Location"); got != tt.location {
+			t.Errorf("%s (%s): Location %q, want %q", tt.target, tt.accept, got, tt.location)
+		}
+		if rec.Header().Get("Vary") != "Accept" {
+			t.Errorf("%s: missing Vary: Accept", tt.target)
+		}
+	}
+
+	if rec := get(h, "/nobody", ""); rec.Code != 404 {
+		t.Errorf("unknown resource: status %d, want 404", rec.Code)
+	}
+	if rec := get(h, "/alice", "image/png"); rec.Code != 406 {
+		t.Errorf("unacceptable: status %d, want 406", rec.Code)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		inbound     bool
+		contentType string
+		want        []string
+		exclude     []string
+	}{
+		{
+			name:        "slash IRI with blank node",
+			target:      "/alice.ttl",
+			contentType: "text/turtle; charset=utf-8",
+			want:        []string{`foaf:name "Alice"`, "foaf:knows ex:bob", `ex:city "Oslo"`},
+			exclude:     []string{`"Bob"`, "other.org"},
+		},
+		{
+			name:        "inbound links",
+			target:      "/alice.nt",
+			inbound:     true,
+			contentType: "application/n-triples",
+			want:        []string{"<http://other.org/carol> <http://xmlns.com/foaf/0.1/knows> <http://example.org/alice> ."},
+		},
+		{
+			name:        "hash IRIs",
+			target:      "/vocab.ttl",
+			contentType: "text/turtle; charset=utf-8",
+			want:        []string{`<http://example.org/vocab#Person> ex:label "Person"`, `<http://example.org/vocab#name> ex:label "name"`},
+		},
+		{
+			name:        "index",
+			target:      "/index.jsonld",
+			contentType: "application/ld+json",
+			want:        []string{`"Example"`},
+		},
+		{
+			name:        "html",
+			target:      "/alice.html",
+			inbound:     true,
+			contentType: "text/html; charset=utf-8",
+			want: []string{
+				"<title>ex:alice</title>",
+				`<a href="/bob">ex:bob</a>`,
+				`<a href="http://xmlns.com/foaf/0.1/name">foaf:name</a>`,
+				`<a href="/alice.ttl">turtle</a>`,
+				"Referenced by",
+				`<a href="http://other.org/carol">`,
+				`<h2><a href="/alice">ex:alice</a></h2>`,
+			},
+			exclude: []string{`id=""`},
+		},
+		{
+			name:        "html with hash IRIs",
+			target:      "/vocab.html",
+			contentType: "text/html; charset=utf-8",
+			want:        []string{`<h2 id="Person">`, `<h2 id="name">`},
+			exclude:     []string{`id=""`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(testHandler(t, tt.inbound), tt.target, "")
+			if rec.Code != 200 {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type %q, want %q", got, tt.contentType)
+			}
+			body := rec.Body.String()
+			for _, s := range tt.want {
+				if !strings.Contains(body, s) {
+					t.Errorf("missing %q in:\n%s", s, body)
+				}
+			}
+			for _, s := range tt.exclude {
+				if strings.Contains(body, s) {
+					t.Errorf("unexpected %q in:\n%s", s, body)
+				}
+			}
+		})
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	testHandler(t, false).ServeHTTP(rec, httptest.NewRequest("POST", "/alice", nil))
+	if rec.Code != 405 || rec.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("status %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
@@ -0,0 +1,132 @@
+package marshal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+)
+
+// field is a struct field with an rdf tag.
+type field struct {
+	name      string
+	index     []int
+	predicate triple.IRI
+	language  string
+	datatype  string
+	iri       bool
+	omitEmpty bool
+}
+
+// structInfo is the parsed rdf tags of a struct type.
+type structInfo struct {
+	id     *field
+	types  []triple.IRI
+	fields []field
+}
+
+var structCache sync.Map // map[reflect.Type]*structInfo
+
+func cachedStructInfo(t reflect.Type) (*structInfo, error) {
+	if info, ok := structCache.Load(t); ok {
+		return info.(*structInfo), nil
+	}
+	info, err := parseStruct(t)
+	if err != nil {
+		return nil, err
+	}
+	structCache.Store(t, info)
+	return info, nil
+}
+
+// parseStruct reads the rdf tags of t. Fields of embedded structs without a
+// tag are promoted, as in encoding/json.
+func parseStruct(t reflect.Type) (*structInfo, error) {
+	info := &structInfo{}
+	if err := info.add(t, nil); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (info *structInfo) add(t reflect.Type, index []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup("rdf")
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if !hasTag {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				if err := info.add(sf.Type, fieldIndex); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		switch {
+		case strings.HasPrefix(name, "@type="):
+			iri, err := triple.NewIRI(strings.TrimPrefix(name, "@type="))
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", t.Name(), sf.Name, err)
+			}
+			info.types = append(info.types, iri)
+			continue
+		case !sf.IsExported():
+			return fmt.Errorf("%s.%s: rdf tag on unexported field", t.Name(), sf.Name)
+		}
+
+		f := field{name: sf.Name, index: fieldIndex}
+		switch name {
+		case "@id":
+			if info.id != nil {
+				return fmt.Errorf("%s: more than one @id field", t.Name())
+			}
+			info.id = &f
+		case "@type":
+			f.predicate = rdf.Type
+			f.iri = true
+		default:
+			iri, err := triple.NewIRI(name)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", t.Name(), sf.Name, err)
+			}
+			f.predicate = iri
+		}
+
+		for _, opt := range strings.Split(opts, ",") {
+			key, value, _ := strings.Cut(opt, "=")
+			switch key {
+			case "":
+			case "omitempty":
+				f.omitEmpty = true
+			case "iri":
+				f.iri = true
+			case "lang":
+				if err := triple.ValidateLanguageTag(value); err != nil {
+					return fmt.Errorf("%s.%s: %w", t.Name(), sf.Name, err)
+				}
+				f.language = value
+			case "datatype":
+				if err := triple.ValidateIRI(value); err != nil {
+					return fmt.Errorf("%s.%s: %w", t.Name(), sf.Name, err)
+				}
+				f.datatype = value
+			default:
+				return fmt.Errorf("%s.%s: unknown rdf tag option %q", t.Name(), sf.Name, key)
+			}
+		}
+
+		if name != "@id" {
+			info.fields = append(info.fields, f)
+		}
+	}
+	return nil
+}
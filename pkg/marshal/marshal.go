@@ -0,0 +1,328 @@
+// Package marshal converts between Go structs and RDF triples, in the style of
+// encoding/json. Fields are mapped with rdf struct tags:
+//
+//	type Person struct {
+//		_       struct{}          `rdf:"@type=http://schema.org/Person"`
+//		ID      string            `rdf:"@id"`
+//		Name    string            `rdf:"http://schema.org/name"`
+//		Title   string            `rdf:"http://schema.org/jobTitle,lang=en,omitempty"`
+//		Born    time.Time         `rdf:"http://schema.org/birthDate,datatype=http://www.w3.org/2001/XMLSchema#date"`
+//		Knows   []*Person         `rdf:"http://schema.org/knows"`
+//		Address *Address          `rdf:"http://schema.org/address,omitempty"`
+//		Labels  map[string]string `rdf:"http://www.w3.org/2000/01/rdf-schema#label"`
+//	}
+//
+// The @id field holds the subject: an IRI, "_:label" for a blank node, or ""
+// for a fresh blank node. "@type=IRI" on a blank field adds a fixed rdf:type;
+// a field tagged "@type" holds further types. Options:
+//
+//   - omitempty skips zero values.
+//   - iri writes a string field as an IRI instead of a literal.
+//   - lang=tag writes strings with a language tag and, when reading, prefers
+//     values in that language.
+//   - datatype=IRI overrides the XSD datatype chosen from the Go type.
+//
+// Strings map to plain literals, bools to xsd:boolean, integers to xsd:integer,
+// float32 and float64 to xsd:float and xsd:double, time.Time to xsd:dateTime,
+// time.Duration to xsd:duration and []byte to xsd:base64Binary. A
+// map[string]string holds one value per language. Slices hold multiple values.
+// Nested structs become linked resources; pointers to the same struct, or
+// resources with the same subject, are shared.
+package marshal
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+var (
+	nodeType       = reflect.TypeOf((*triple.Node)(nil)).Elem()
+	iriType        = reflect.TypeOf(triple.IRI{})
+	blankNodeType  = reflect.TypeOf(triple.BlankNode{})
+	literalType    = reflect.TypeOf(triple.Literal{})
+	tripleTermType = reflect.TypeOf(triple.TripleTerm{})
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+)
+
+// Marshal returns the triples describing v, a struct, a pointer to a struct
+// or a slice of them.
+func Marshal(v any) ([]triple.Triple, error) {
+	e := &encodeState{seen: make(map[pointerKey]triple.Node)}
+	rv := indirect(reflect.ValueOf(v))
+
+	switch {
+	case rv.Kind() == reflect.Struct:
+		if _, err := e.pointer(reflect.ValueOf(v)); err != nil {
+			return nil, err
+		}
+	case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elem := indirect(rv.Index(i))
+			if elem.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("cannot marshal %s: want a struct or slice of structs", rv.Type())
+			}
+			if _, err := e.pointer(rv.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("cannot marshal %T: want a struct or slice of structs", v)
+	}
+	return e.triples, nil
+}
+
+type pointerKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type encodeState struct {
+	triples []triple.Triple
+	blank   int
+	seen    map[pointerKey]triple.Node
+}
+
+func (e *encodeState) emit(s, p, o triple.Node) {
+	e.triples = append(e.triples, triple.Triple{Subject: s, Predicate: p, Object: o})
+}
+
+// pointer marshals a struct or pointer to a struct, describing each pointed-to
+// struct only once so shared and cyclic pointers work.
+func (e *encodeState) pointer(v reflect.Value) (triple.Node, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Pointer {
+		return e.resource(v)
+	}
+	if v.Elem().Kind() == reflect.Pointer {
+		return e.pointer(v.Elem())
+	}
+	key := pointerKey{v.Pointer(), v.Type()}
+	if node, ok := e.seen[key]; ok {
+		return node, nil
+	}
+	info, err := cachedStructInfo(v.Elem().Type())
+	if err != nil {
+		return nil, err
+	}
+	subject, err := e.subject(v.Elem(), info)
+	if err != nil {
+		return nil, err
+	}
+	e.seen[key] = subject
+	return subject, e.describe(subject, v.Elem(), info)
+}
+
+func (e *encodeState) resource(v reflect.Value) (triple.Node, error) {
+	info, err := cachedStructInfo(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	subject, err := e.subject(v, info)
+	if err != nil {
+		return nil, err
+	}
+	return subject, e.describe(subject, v, info)
+}
+
+func (e *encodeState) subject(v reflect.Value, info *structInfo) (triple.Node, error) {
+	var id reflect.Value
+	if info.id != nil {
+		id = v.FieldByIndex(info.id.index)
+	}
+
+	switch {
+	case !id.IsValid() || id.IsZero():
+		node := triple.BlankNode{Value: "b" + strconv.Itoa(e.blank)}
+		e.blank++
+		return node, nil
+	case id.Kind() == reflect.String:
+		return parseID(id.String())
+	case id.Type() == iriType || id.Type() == blankNodeType:
+		return id.Interface().(triple.Node), nil
+	case id.Type() == nodeType:
+		switch node := id.Interface().(type) {
+		case triple.IRI:
+			return node, nil
+		case triple.BlankNode:
+			return node, nil
+		}
+	}
+	return nil, fmt.Errorf("field %s: @id must be a string, triple.IRI or triple.BlankNode", info.id.name)
+}
+
+func parseID(id string) (triple.Node, error) {
+	if label, ok := strings.CutPrefix(id, "_:"); ok {
+		return triple.BlankNode{Value: label}, nil
+	}
+	return triple.NewIRI(id)
+}
+
+func (e *encodeState) describe(subject triple.Node, v reflect.Value, info *structInfo) error {
+	for _, t := range info.types {
+		e.emit(subject, rdf.Type, t)
+	}
+
+	for _, f := range info.fields {
+		fv := v.FieldByIndex(f.index)
+		if f.omitEmpty && isEmpty(fv) {
+			continue
+		}
+
+		objects, err := e.values(fv, f)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.name, err)
+		}
+		for _, o := range objects {
+			e.emit(subject, f.predicate, o)
+		}
+	}
+	return nil
+}
+
+func (e *encodeState) values(v reflect.Value, f field) ([]triple.Node, error) {
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		lit := triple.Literal{Value: xsd.FormatBase64Binary(v.Bytes()), Datatype: xsd.Base64Binary}
+		return []triple.Node{lit}, nil
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		var nodes []triple.Node
+		for i := 0; i < v.Len(); i++ {
+			elem, err := e.values(v.Index(i), f)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, elem...)
+		}
+		return nodes, nil
+	case v.Kind() == reflect.Map:
+		return languageMap(v)
+	case (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil():
+		return nil, nil
+	}
+
+	node, err := e.value(v, f)
+	if err != nil {
+		return nil, err
+	}
+	return []triple.Node{node}, nil
+}
+
+func (e *encodeState) value(v reflect.Value, f field) (triple.Node, error) {
+	switch v.Type() {
+	case nodeType, iriType, blankNodeType, literalType, tripleTermType:
+		return v.Interface().(triple.Node), nil
+	case timeType:
+		t := v.Interface().(time.Time)
+		if f.datatype == xsd.Date {
+			return triple.NewDateLiteral(t), nil
+		}
+		if f.datatype != "" {
+			return triple.NewTypedLiteral(xsd.FormatDateTime(t), f.datatype)
+		}
+		return triple.NewDateTimeLiteral(t), nil
+	case durationType:
+		d := xsd.DurationValue{Nanoseconds: v.Int()}
+		return triple.Literal{Value: d.String(), Datatype: xsd.Duration}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.Elem().Kind() == reflect.Struct {
+			return e.pointer(v)
+		}
+		return e.value(v.Elem(), f)
+	case reflect.Interface:
+		return e.value(v.Elem(), f)
+	case reflect.Struct:
+		return e.resource(v)
+	case reflect.String:
+		switch {
+		case f.iri:
+			return triple.NewIRI(v.String())
+		case f.datatype != "":
+			return triple.NewTypedLiteral(v.String(), f.datatype)
+		case f.language != "":
+			return triple.Literal{Value: v.String(), Language: f.language}, nil
+		}
+		return triple.Literal{Value: v.String()}, nil
+	case reflect.Bool:
+		return typed(xsd.FormatBoolean(v.Bool()), xsd.Boolean, f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return typed(strconv.FormatInt(v.Int(), 10), xsd.Integer, f)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typed(strconv.FormatUint(v.Uint(), 10), xsd.Integer, f)
+	case reflect.Float32:
+		if f.datatype == "" {
+			return triple.Literal{Value: xsd.FormatFloat(float32(v.Float())), Datatype: xsd.Float}, nil
+		}
+		return typed(strconv.FormatFloat(v.Float(), 'f', -1, 32), "", f)
+	case reflect.Float64:
+		if f.datatype == "" {
+			return triple.NewDoubleLiteral(v.Float()), nil
+		}
+		return typed(strconv.FormatFloat(v.Float(), 'f', -1, 64), "", f)
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// typed returns a literal with the field's datatype, or datatype if the field
+// has none, checking the lexical form against it.
+func typed(lexical, datatype string, f field) (triple.Node, error) {
+	if f.datatype != "" {
+		datatype = f.datatype
+	}
+	return triple.NewTypedLiteral(lexical, datatype)
+}
+
+// languageMap writes a map[string]string of language tag to text.
+func languageMap(v reflect.Value) ([]triple.Node, error) {
+	if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+		return nil, fmt.Errorf("unsupported map type %s, want map[string]string", v.Type())
+	}
+
+	languages := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		languages = append(languages, key.String())
+	}
+	sort.Strings(languages)
+
+	nodes := make([]triple.Node, 0, len(languages))
+	for _, language := range languages {
+		text := v.MapIndex(reflect.ValueOf(language).Convert(v.Type().Key())).String()
+		lit, err := triple.NewLangLiteral(text, language)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, lit)
+	}
+	return nodes, nil
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
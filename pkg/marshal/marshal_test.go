@@ -0,0 +1,193 @@
+package marshal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+const schema = "http://schema.org/"
+
+type address struct {
+	Street string `rdf:"http://schema.org/streetAddress"`
+	City   string `rdf:"http://schema.org/addressLocality,omitempty"`
+}
+
+type person struct {
+	_        struct{}          `rdf:"@type=http://schema.org/Person"`
+	ID       string            `rdf:"@id"`
+	Types    []string          `rdf:"@type"`
+	Name     string            `rdf:"http://schema.org/name"`
+	Title    string            `rdf:"http://schema.org/jobTitle,lang=en,omitempty"`
+	Labels   map[string]string `rdf:"http://www.w3.org/2000/01/rdf-schema#label,omitempty"`
+	Age      int               `rdf:"http://schema.org/age"`
+	Height   float64           `rdf:"http://schema.org/height,omitempty"`
+	Active   bool              `rdf:"http://schema.org/active,omitempty"`
+	Born     time.Time         `rdf:"http://schema.org/birthDate,datatype=http://www.w3.org/2001/XMLSchema#date,omitempty"`
+	Homepage string            `rdf:"http://schema.org/url,iri,omitempty"`
+	Address  address           `rdf:"http://schema.org/address"`
+	Knows    []*person         `rdf:"http://schema.org/knows,omitempty"`
+	Nicks    []string          `rdf:"http://schema.org/alternateName,omitempty"`
+	Secret   string
+}
+
+func TestMarshal(t *testing.T) {
+	alice := person{
+		ID:       "http://example.org/alice",
+		Name:     "Alice",
+		Title:    "Engineer",
+		Labels:   map[string]string{"en": "Alice", "fr": "Alice"},
+		Age:      42,
+		Active:   true,
+		Born:     time.Date(1982, 3, 4, 0, 0, 0, 0, time.UTC),
+		Homepage: "http://example.org/~alice",
+		Address:  address{Street: "1 Main St"},
+		Nicks:    []string{"Al", "Ali"},
+		Secret:   "not marshaled",
+	}
+
+	got, err := Marshal(&alice)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	s := triple.IRI{Value: "http://example.org/alice"}
+	p := func(name string) triple.IRI { return triple.IRI{Value: schema + name} }
+	addr := triple.BlankNode{Value: "b0"}
+	expected := []triple.Triple{
+		{Subject: s, Predicate: rdf.Type, Object: p("Person")},
+		{Subject: s, Predicate: p("name"), Object: triple.Literal{Value: "Alice"}},
+		{Subject: s, Predicate: p("jobTitle"), Object: triple.Literal{Value: "Engineer", Language: "en"}},
+		{Subject: s, Predicate: triple.IRI{Value: "http://www.w3.org/2000/01/rdf-schema#label"}, Object: triple.Literal{Value: "Alice", Language: "en"}},
+		{Subject: s, Predicate: triple.IRI{Value: "http://www.w3.org/2000/01/rdf-schema#label"}, Object: triple.Literal{Value: "Alice", Language: "fr"}},
+		{Subject: s, Predicate: p("age"), Object: triple.Literal{Value: "42", Datatype: xsd.Integer}},
+		{Subject: s, Predicate: p("active"), Object: triple.Literal{Value: "true", Datatype: xsd.Boolean}},
+		{Subject: s, Predicate: p("birthDate"), Object: triple.Literal{Value: "1982-03-04", Datatype: xsd.Date}},
+		{Subject: s, Predicate: p("url"), Object: triple.IRI{Value: "http://example.org/~alice"}},
+		{Subject: addr, Predicate: p("streetAddress"), Object: triple.Literal{Value: "1 Main St"}},
+		{Subject: s, Predicate: p("address"), Object: addr},
+		{Subject: s, Predicate: p("alternateName"), Object: triple.Literal{Value: "Al"}},
+		{Subject: s, Predicate: p("alternateName"), Object: triple.Literal{Value: "Ali"}},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Marshal() =\n%v\nwant\n%v", got, expected)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	alice := &person{ID: "http://example.org/alice", Name: "Alice", Age: 42, Height: 1.7, Title: "Engineer"}
+	bob := &person{ID: "_:bob", Name: "Bob", Age: 7, Types: []string{schema + "Patient"}}
+	alice.Knows = []*person{bob}
+	bob.Knows = []*person{alice}
+
+	triples, err := Marshal([]*person{alice, bob})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var people []*person
+	if err := Unmarshal(triples, &people); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(people) != 2 {
+		t.Fatalf("Unmarshal() got %d people, want 2", len(people))
+	}
+
+	a, b := people[0], people[1]
+	if a.ID != alice.ID || a.Name != "Alice" || a.Age != 42 || a.Height != 1.7 || a.Title != "Engineer" {
+		t.Errorf("Unmarshal() alice = %+v", a)
+	}
+	if b.ID != "_:bob" || b.Name != "Bob" || !reflect.DeepEqual(b.Types, []string{schema + "Person", schema + "Patient"}) {
+		t.Errorf("Unmarshal() bob = %+v", b)
+	}
+	if len(a.Knows) != 1 || a.Knows[0] != b || len(b.Knows) != 1 || b.Knows[0] != a {
+		t.Error("Unmarshal() did not share pointers between alice and bob")
+	}
+}
+
+func TestUnmarshalLanguage(t *testing.T) {
+	s := triple.IRI{Value: "http://example.org/alice"}
+	title := triple.IRI{Value: schema + "jobTitle"}
+	triples := []triple.Triple{
+		{Subject: s, Predicate: rdf.Type, Object: triple.IRI{Value: schema + "Person"}},
+		{Subject: s, Predicate: title, Object: triple.Literal{Value: "Ingénieure", Language: "fr"}},
+		{Subject: s, Predicate: title, Object: triple.Literal{Value: "Engineer", Language: "EN"}},
+	}
+
+	var p person
+	if err := Unmarshal(triples, &p); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if p.Title != "Engineer" {
+		t.Errorf("Title = %q, want Engineer", p.Title)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	s := triple.IRI{Value: "http://example.org/alice"}
+	typed := triple.Triple{Subject: s, Predicate: rdf.Type, Object: triple.IRI{Value: schema + "Person"}}
+	name := triple.IRI{Value: schema + "name"}
+	age := triple.IRI{Value: schema + "age"}
+
+	tests := []struct {
+		name    string
+		triples []triple.Triple
+	}{
+		{"no resource", nil},
+		{"two names", []triple.Triple{typed,
+			{Subject: s, Predicate: name, Object: triple.Literal{Value: "A"}},
+			{Subject: s, Predicate: name, Object: triple.Literal{Value: "B"}},
+		}},
+		{"untyped age", []triple.Triple{typed, {Subject: s, Predicate: age, Object: triple.Literal{Value: "42"}}}},
+		{"IRI name", []triple.Triple{typed, {Subject: s, Predicate: name, Object: triple.IRI{Value: "http://example.org/x"}}}},
+		{"literal address", []triple.Triple{typed, {Subject: s, Predicate: triple.IRI{Value: schema + "address"}, Object: triple.Literal{Value: "x"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p person
+			if err := Unmarshal(tt.triples, &p); err == nil {
+				t.Errorf("Unmarshal() expected error, got %+v", p)
+			}
+		})
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	type badTag struct {
+		Name string `rdf:"name"`
+	}
+	type badOption struct {
+		Name string `rdf:"http://schema.org/name,sideways"`
+	}
+	type badID struct {
+		ID string `rdf:"@id"`
+	}
+	type badValue struct {
+		Ch chan int `rdf:"http://schema.org/ch"`
+	}
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"relative predicate", badTag{Name: "x"}},
+		{"unknown option", badOption{}},
+		{"relative id", badID{ID: "alice"}},
+		{"unsupported type", badValue{Ch: make(chan int)}},
+		{"not a struct", 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Marshal(tt.value); err == nil {
+				t.Error("Marshal() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package marshal
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+// Unmarshal fills v, a pointer to a struct or to a slice of structs, from
+// triples. The resources are those with the struct's fixed rdf:types, or the
+// subjects that are not the object of any triple if it has none. A struct
+// whose @id field is already set is filled from that subject.
+func Unmarshal(triples []triple.Triple, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal into %T: want a non-nil pointer", v)
+	}
+	d := newDecodeState(triples)
+	target := rv.Elem()
+
+	if target.Kind() == reflect.Slice {
+		elemType := target.Type().Elem()
+		structType := elemType
+		if structType.Kind() == reflect.Pointer {
+			structType = structType.Elem()
+		}
+		if structType.Kind() != reflect.Struct {
+			return fmt.Errorf("cannot unmarshal into %T: want a struct or slice of structs", v)
+		}
+		info, err := cachedStructInfo(structType)
+		if err != nil {
+			return err
+		}
+
+		subjects := d.candidates(info)
+		slice := reflect.MakeSlice(target.Type(), len(subjects), len(subjects))
+		for i, subject := range subjects {
+			if err := d.value(slice.Index(i), subject, field{}); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	}
+
+	if target.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal into %T: want a struct or slice of structs", v)
+	}
+	info, err := cachedStructInfo(target.Type())
+	if err != nil {
+		return err
+	}
+
+	if info.id != nil && !target.FieldByIndex(info.id.index).IsZero() {
+		subject, err := (&encodeState{}).subject(target, info)
+		if err != nil {
+			return err
+		}
+		return d.pointer(rv, subject)
+	}
+
+	subjects := d.candidates(info)
+	switch len(subjects) {
+	case 0:
+		return fmt.Errorf("no resource found for %s", target.Type())
+	case 1:
+		return d.pointer(rv, subjects[0])
+	}
+	return fmt.Errorf("%d resources found for %s, want one (use a slice or set the @id field)", len(subjects), target.Type())
+}
+
+// UnmarshalResource fills the struct v points to with the description of
+// subject.
+func UnmarshalResource(triples []triple.Triple, subject triple.Node, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal into %T: want a non-nil pointer to a struct", v)
+	}
+	return newDecodeState(triples).pointer(rv, subject)
+}
+
+type resourceKey struct {
+	node triple.Node
+	typ  reflect.Type
+}
+
+type decodeState struct {
+	subjects   []triple.Node
+	properties map[triple.Node]map[triple.Node][]triple.Node
+	objects    map[triple.Node]bool
+	pointers   map[resourceKey]reflect.Value
+	decoding   map[resourceKey]bool
+}
+
+func newDecodeState(triples []triple.Triple) *decodeState {
+	d := &decodeState{
+		properties: make(map[triple.Node]map[triple.Node][]triple.Node),
+		objects:    make(map[triple.Node]bool),
+		pointers:   make(map[resourceKey]reflect.Value),
+		decoding:   make(map[resourceKey]bool),
+	}
+	for _, t := range triples {
+		props := d.properties[t.Subject]
+		if props == nil {
+			props = make(map[triple.Node][]triple.Node)
+			d.properties[t.Subject] = props
+			d.subjects = append(d.subjects, t.Subject)
+		}
+		props[t.Predicate] = append(props[t.Predicate], t.Object)
+		d.objects[t.Object] = true
+	}
+	return d
+}
+
+// candidates returns the subjects with all of the struct's fixed types, in
+// order of appearance, or the unreferenced subjects if it has none.
+func (d *decodeState) candidates(info *structInfo) []triple.Node {
+	var subjects []triple.Node
+	for _, s := range d.subjects {
+		if len(info.types) == 0 {
+			if !d.objects[s] {
+				subjects = append(subjects, s)
+			}
+			continue
+		}
+		if d.hasTypes(s, info.types) {
+			subjects = append(subjects, s)
+		}
+	}
+	return subjects
+}
+
+func (d *decodeState) hasTypes(subject triple.Node, types []triple.IRI) bool {
+	for _, t := range types {
+		found := false
+		for _, o := range d.properties[subject][rdf.Type] {
+			if o == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// pointer fills the struct that ptr points to, recording it so later
+// references to the same subject share the pointer.
+func (d *decodeState) pointer(ptr reflect.Value, subject triple.Node) error {
+	d.pointers[resourceKey{subject, ptr.Type()}] = ptr
+	return d.resource(ptr.Elem(), subject)
+}
+
+func (d *decodeState) resource(v reflect.Value, subject triple.Node) error {
+	switch subject.(type) {
+	case triple.IRI, triple.BlankNode:
+	default:
+		return fmt.Errorf("cannot unmarshal %s into %s: not a resource", nodeString(subject), v.Type())
+	}
+
+	key := resourceKey{subject, v.Type()}
+	if d.decoding[key] {
+		return fmt.Errorf("cycle at %s: use a pointer field for %s", nodeString(subject), v.Type())
+	}
+	d.decoding[key] = true
+	defer delete(d.decoding, key)
+
+	info, err := cachedStructInfo(v.Type())
+	if err != nil {
+		return err
+	}
+
+	if info.id != nil {
+		if err := setID(v.FieldByIndex(info.id.index), subject); err != nil {
+			return fmt.Errorf("field %s: %w", info.id.name, err)
+		}
+	}
+
+	props := d.properties[subject]
+	for _, f := range info.fields {
+		if err := d.values(v.FieldByIndex(f.index), props[f.predicate], f); err != nil {
+			return fmt.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+func setID(v reflect.Value, subject triple.Node) error {
+	switch {
+	case v.Kind() == reflect.String:
+		if b, ok := subject.(triple.BlankNode); ok {
+			v.SetString("_:" + b.Value)
+		} else {
+			v.SetString(subject.(triple.IRI).Value)
+		}
+		return nil
+	case v.Type() == nodeType, v.Type() == reflect.TypeOf(subject):
+		v.Set(reflect.ValueOf(subject))
+		return nil
+	}
+	return fmt.Errorf("cannot store %s in %s", nodeString(subject), v.Type())
+}
+
+func (d *decodeState) values(v reflect.Value, objects []triple.Node, f field) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+	case v.Kind() == reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), len(objects), len(objects))
+		for i, o := range objects {
+			if err := d.value(slice.Index(i), o, f); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case v.Kind() == reflect.Map:
+		return setLanguageMap(v, objects)
+	}
+
+	if f.language != "" {
+		objects = preferLanguage(objects, f.language)
+	}
+	if len(objects) > 1 {
+		return fmt.Errorf("%d values for %s, want one (use a slice)", len(objects), f.predicate.Value)
+	}
+	return d.value(v, objects[0], f)
+}
+
+// preferLanguage returns the literals in language if there are any.
+func preferLanguage(objects []triple.Node, language string) []triple.Node {
+	var matching []triple.Node
+	for _, o := range objects {
+		if lit, ok := o.(triple.Literal); ok && strings.EqualFold(lit.Language, language) {
+			matching = append(matching, o)
+		}
+	}
+	if len(matching) == 0 {
+		return objects
+	}
+	return matching
+}
+
+func setLanguageMap(v reflect.Value, objects []triple.Node) error {
+	if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+		return fmt.Errorf("unsupported map type %s, want map[string]string", v.Type())
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	for _, o := range objects {
+		lit, ok := o.(triple.Literal)
+		if !ok {
+			return fmt.Errorf("cannot store %s in %s", nodeString(o), v.Type())
+		}
+		key := reflect.ValueOf(lit.Language).Convert(v.Type().Key())
+		v.SetMapIndex(key, reflect.ValueOf(lit.Value).Convert(v.Type().Elem()))
+	}
+	return nil
+}
+
+func (d *decodeState) value(v reflect.Value, object triple.Node, f field) error {
+	switch v.Type() {
+	case nodeType:
+		v.Set(reflect.ValueOf(object))
+		return nil
+	case iriType, blankNodeType, literalType, tripleTermType:
+		if reflect.TypeOf(object) != v.Type() {
+			return mismatch(object, v)
+		}
+		v.Set(reflect.ValueOf(object))
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.Type().Elem().Kind() == reflect.Struct {
+			if ptr, ok := d.pointers[resourceKey{object, v.Type()}]; ok {
+				v.Set(ptr)
+				return nil
+			}
+			ptr := reflect.New(v.Type().Elem())
+			v.Set(ptr)
+			return d.pointer(ptr, object)
+		}
+		ptr := reflect.New(v.Type().Elem())
+		if err := d.value(ptr.Elem(), object, f); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	if v.Kind() == reflect.Struct && v.Type() != timeType {
+		return d.resource(v, object)
+	}
+
+	if v.Kind() == reflect.String && f.iri {
+		iri, ok := object.(triple.IRI)
+		if !ok {
+			return mismatch(object, v)
+		}
+		v.SetString(iri.Value)
+		return nil
+	}
+
+	lit, ok := object.(triple.Literal)
+	if !ok {
+		return mismatch(object, v)
+	}
+	return setLiteral(v, lit)
+}
+
+func setLiteral(v reflect.Value, lit triple.Literal) error {
+	switch v.Type() {
+	case timeType:
+		t, err := lit.Time()
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := lit.Duration()
+		if err != nil {
+			return err
+		}
+		if d.Months != 0 {
+			return fmt.Errorf("duration %q has a month part and does not fit time.Duration", lit.Value)
+		}
+		v.SetInt(d.Nanoseconds)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(lit.Value)
+	case reflect.Bool:
+		b, err := lit.Bool()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := lit.Int()
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(n) {
+			return fmt.Errorf("value %s overflows %s", lit.Value, v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !xsd.IsInteger(lit.Datatype) {
+			return fmt.Errorf("literal %q has datatype %s, not integer", lit.Value, lit.DatatypeIRI())
+		}
+		n, err := strconv.ParseUint(strings.TrimPrefix(lit.Value, "+"), 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return fmt.Errorf("value %s does not fit %s", lit.Value, v.Type())
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := lit.Float()
+		if err != nil {
+			return err
+		}
+		if v.OverflowFloat(f) {
+			return fmt.Errorf("value %s overflows %s", lit.Value, v.Type())
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		b, err := lit.Bytes()
+		if err != nil {
+			return err
+		}
+		v.SetBytes(b)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func mismatch(object triple.Node, v reflect.Value) error {
+	return fmt.Errorf("cannot store %s in %s", nodeString(object), v.Type())
+}
+
+func nodeString(n triple.Node) string {
+	switch n := n.(type) {
+	case triple.IRI:
+		return "<" + n.Value + ">"
+	case triple.BlankNode:
+		return "_:" + n.Value
+	case triple.Literal:
+		return strconv.Quote(n.Value)
+	case triple.TripleTerm:
+		return "triple term"
+	}
+	return fmt.Sprint(n)
+}
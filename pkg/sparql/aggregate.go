@@ -0,0 +1,208 @@
+package sparql
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// aggregates are the names of the SPARQL set functions.
+var aggregates = map[string]bool{
+	"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true,
+	"GROUP_CONCAT": true, "SAMPLE": true,
+}
+
+// aggregateExpr is an aggregate call in SELECT, HAVING or ORDER BY. Grouping
+// computes its value for each group and stores it under key, a hidden
+// variable; eval then reads it from the grouped solution.
+type aggregateExpr struct {
+	key      string
+	name     string
+	distinct bool
+	// arg is nil for COUNT(*).
+	arg       expression
+	separator string
+}
+
+func (e *aggregateExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	if n, ok := b[e.key]; ok {
+		return n, nil
+	}
+	return nil, typeErrorf("%s has no value", e.name)
+}
+
+// values evaluates the argument over the solutions of a group. Solutions
+// where it is an error are left out; COUNT(*) yields one placeholder per
+// solution.
+func (e *aggregateExpr) values(ctx *evalContext, solutions []Binding) []triple.Node {
+	var values []triple.Node
+	seen := make(map[string]bool)
+	for _, b := range solutions {
+		var v triple.Node
+		key := ""
+		if e.arg == nil {
+			v = triple.NewBooleanLiteral(true)
+			key = solutionKey(b)
+		} else {
+			var err error
+			if v, err = e.arg.eval(ctx, b); err != nil {
+				continue
+			}
+			key = termKey(v)
+		}
+		if e.distinct {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func (e *aggregateExpr) compute(ctx *evalContext, solutions []Binding) (triple.Node, error) {
+	values := e.values(ctx, solutions)
+
+	switch e.name {
+	case "COUNT":
+		return triple.NewIntegerLiteral(int64(len(values))), nil
+	case "SUM", "AVG":
+		sum := integerNumber(0)
+		for _, v := range values {
+			n, err := toNumber(v)
+			if err != nil {
+				return nil, err
+			}
+			if sum, err = arithmetic("+", sum, n); err != nil {
+				return nil, err
+			}
+		}
+		if e.name == "AVG" && len(values) > 0 {
+			avg, err := arithmetic("/", sum, integerNumber(int64(len(values))))
+			if err != nil {
+				return nil, err
+			}
+			return avg.literal(), nil
+		}
+		return sum.literal(), nil
+	case "MIN", "MAX":
+		if len(values) == 0 {
+			return nil, typeErrorf("%s of no values", e.name)
+		}
+		best := values[0]
+		for _, v := range values[1:] {
+			c := orderTerms(v, best)
+			if e.name == "MIN" && c < 0 || e.name == "MAX" && c > 0 {
+				best = v
+			}
+		}
+		return best, nil
+	case "SAMPLE":
+		if len(values) == 0 {
+			return nil, typeErrorf("SAMPLE of no values")
+		}
+		return values[0], nil
+	case "GROUP_CONCAT":
+		// Like STR, so that numbers and IRIs can be concatenated too.
+		parts := make([]string, 0, len(values))
+		for _, v := range values {
+			s, err := fnStr(ctx, nil, []triple.Node{v})
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, s.(triple.Literal).Value)
+		}
+		return triple.Literal{Value: strings.Join(parts, e.separator)}, nil
+	}
+	return nil, typeErrorf("unknown aggregate %s", e.name)
+}
+
+// solutionKey identifies a whole solution, for COUNT(DISTINCT *).
+func solutionKey(b Binding) string {
+	names := make([]string, 0, len(b))
+	for name := range b {
+		if !isHidden(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	for _, name := range names {
+		key.WriteString(name + "=" + termKey(b[name]) + "\x00")
+	}
+	return key.String()
+}
+
+// groupCondition is a GROUP BY expression. name is the variable the key is
+// bound to in the grouped solution, empty for an unnamed expression.
+type groupCondition struct {
+	expr expression
+	name string
+}
+
+// group partitions the solutions by the GROUP BY keys and returns one
+// solution per group holding the keys and the value of every aggregate.
+// Without GROUP BY all solutions form one group, even when there are none.
+func group(ctx *evalContext, solutions []Binding, conds []groupCondition, aggs []*aggregateExpr) []Binding {
+	type groupEntry struct {
+		key     Binding
+		members []Binding
+	}
+	var groups []*groupEntry
+	index := make(map[string]*groupEntry)
+
+	for _, b := range solutions {
+		key := Binding{}
+		var id strings.Builder
+		for _, cond := range conds {
+			if v, err := cond.expr.eval(ctx, b); err == nil {
+				id.WriteString(termKey(v))
+				if cond.name != "" {
+					key[cond.name] = v
+				}
+			}
+			id.WriteByte(0)
+		}
+
+		g, ok := index[id.String()]
+		if !ok {
+			g = &groupEntry{key: key}
+			index[id.String()] = g
+			groups = append(groups, g)
+		}
+		g.members = append(g.members, b)
+	}
+	if len(conds) == 0 && len(groups) == 0 {
+		groups = append(groups, &groupEntry{key: Binding{}})
+	}
+
+	result := make([]Binding, len(groups))
+	for i, g := range groups {
+		result[i] = g.key
+		for _, agg := range aggs {
+			if v, err := agg.compute(ctx, g.members); err == nil {
+				result[i][agg.key] = v
+			}
+		}
+	}
+	return result
+}
+
+// subqueryPattern is a nested SELECT. It is evaluated on its own and joined
+// with the rest of the group through its projected variables.
+type subqueryPattern struct {
+	query *Query
+}
+
+func (p *subqueryPattern) eval(ctx *evalContext) ([]Binding, error) {
+	inner := *ctx
+	inner.outer = nil
+	return p.query.solutions(&inner)
+}
+
+func (p *subqueryPattern) vars() []string {
+	return p.query.Vars
+}
@@ -0,0 +1,132 @@
+package sparql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAggregates(t *testing.T) {
+	g := testGraph(t)
+
+	const integer = "^^<http://www.w3.org/2001/XMLSchema#integer>"
+	const decimal = "^^<http://www.w3.org/2001/XMLSchema#decimal>"
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "count per class",
+			query: `SELECT ?c (COUNT(?s) AS ?n) { ?s a ?c } GROUP BY ?c`,
+			want:  []string{`c=Person n="3"` + integer},
+		},
+		{
+			name:  "count star without group by",
+			query: `SELECT (COUNT(*) AS ?n) { ?s foaf:knows ?o }`,
+			want:  []string{`n="3"` + integer},
+		},
+		{
+			name:  "count over no solutions",
+			query: `SELECT (COUNT(*) AS ?n) (SUM(?x) AS ?sum) { ?s ex:missing ?x }`,
+			want:  []string{`n="0"` + integer + ` sum="0"` + integer},
+		},
+		{
+			name:  "count distinct",
+			query: `SELECT (COUNT(DISTINCT ?o) AS ?n) { ?s foaf:knows ?o }`,
+			want:  []string{`n="2"` + integer},
+		},
+		{
+			name:  "sum and average promote to decimal",
+			query: `SELECT (SUM(?a) AS ?sum) (AVG(?a) AS ?avg) { { ?p foaf:age ?a } UNION { ?p ex:score ?a } }`,
+			want:  []string{`sum="63.5"` + decimal + ` avg="21.166666666666668"` + decimal},
+		},
+		{
+			name:  "average of integers is decimal",
+			query: `SELECT (AVG(?a) AS ?avg) { ?p foaf:age ?a }`,
+			want:  []string{`avg="30.5"` + decimal},
+		},
+		{
+			name:  "min and max",
+			query: `SELECT (MIN(?a) AS ?min) (MAX(?n) AS ?max) { ?p foaf:name ?n OPTIONAL { ?p foaf:age ?a } }`,
+			want:  []string{`min="27"` + integer + ` max="Carol"`},
+		},
+		{
+			name:  "group concat with separator",
+			query: `SELECT ?p (GROUP_CONCAT(?o; SEPARATOR=", ") AS ?known) { ?p foaf:knows ?o } GROUP BY ?p ORDER BY ?p`,
+			want: []string{
+				`p=alice known="http://example.org/bob, http://example.org/carol"`,
+				`p=bob known="http://example.org/carol"`,
+			},
+		},
+		{
+			name:  "sample",
+			query: `SELECT ?p (SAMPLE(?o) AS ?any) { ?p foaf:knows ?o FILTER(?p = ex:bob) } GROUP BY ?p`,
+			want:  []string{"p=bob any=carol"},
+		},
+		{
+			name:  "having",
+			query: `SELECT ?p (COUNT(*) AS ?n) { ?p foaf:knows ?o } GROUP BY ?p HAVING (COUNT(*) > 1)`,
+			want:  []string{`p=alice n="2"` + integer},
+		},
+		{
+			name:  "order by aggregate",
+			query: `SELECT ?o { ?s foaf:knows ?o } GROUP BY ?o ORDER BY DESC(COUNT(?s))`,
+			want:  []string{"o=carol", "o=bob"},
+		},
+		{
+			name:  "group by expression",
+			query: `SELECT ?old (COUNT(*) AS ?n) { ?p foaf:age ?a } GROUP BY (?a > 30 AS ?old) ORDER BY ?old`,
+			want: []string{
+				`old="false"^^<http://www.w3.org/2001/XMLSchema#boolean> n="1"` + integer,
+				`old="true"^^<http://www.w3.org/2001/XMLSchema#boolean> n="1"` + integer,
+			},
+		},
+		{
+			name: "subquery",
+			query: `SELECT ?p ?n {
+				?p foaf:name ?name
+				{ SELECT ?p (COUNT(?o) AS ?n) { ?p foaf:knows ?o } GROUP BY ?p }
+			} ORDER BY ?p`,
+			want: []string{`p=alice n="2"` + integer, `p=bob n="1"` + integer},
+		},
+		{
+			name:  "subquery with limit",
+			query: `SELECT ?p { { SELECT ?p { ?p a foaf:Person } ORDER BY DESC(?p) LIMIT 1 } }`,
+			want:  []string{"p=carol"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Exec(g, testPrefixes+tt.query, ParseOptions{})
+			if err != nil {
+				t.Fatalf("Exec() error = %v", err)
+			}
+			if got := rows(res); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Exec() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`SELECT ?s { ?s <p> ?o FILTER(COUNT(?o) > 1) }`, "COUNT is only allowed in SELECT, HAVING and ORDER BY"},
+		{`SELECT ?s ?o { ?s <p> ?o } GROUP BY ?s`, "variable ?o is projected but neither grouped nor aggregated"},
+		{`SELECT * { ?s <p> ?o } GROUP BY ?s`, "SELECT * cannot be used with GROUP BY"},
+		{`SELECT (SUM(COUNT(?o)) AS ?n) { ?s <p> ?o }`, "COUNT is only allowed"},
+		{`SELECT ?s { ?s <p> ?o } GROUP BY`, "expected GROUP BY condition"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.query, ParseOptions{})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%s) error = %v, want %q", tt.query, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package sparql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+)
+
+// Client sends queries and updates to a SPARQL 1.1 Protocol endpoint.
+type Client struct {
+	// Endpoint is the query endpoint URL.
+	Endpoint string
+	// UpdateEndpoint receives updates. Empty means Endpoint.
+	UpdateEndpoint string
+	// HTTPClient sends the requests. Nil means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// clientAccept asks for any results format or RDF serialization tripl can
+// read, since the query form is only known to the endpoint.
+var clientAccept = strings.Join([]string{
+	ResultsJSON.MediaType(),
+	ResultsXML.MediaType() + ";q=0.9",
+	ResultsTSV.MediaType() + ";q=0.8",
+	ResultsCSV.MediaType() + ";q=0.5",
+	encoder.Turtle.MediaType(),
+	encoder.NTriples.MediaType() + ";q=0.9",
+	encoder.JSONLD.MediaType() + ";q=0.8",
+}, ", ")
+
+// Query runs a query on the endpoint. The query is sent as is, so it may use
+// features this package cannot evaluate. Solutions come back as Select or Ask
+// results; triples, from CONSTRUCT or DESCRIBE, as Construct results with
+// the prefixes the response declared.
+func (c *Client) Query(ctx context.Context, query string) (*Results, error) {
+	body, contentType, err := c.post(ctx, c.Endpoint, "query", query, clientAccept)
+	if err != nil {
+		return nil, err
+	}
+
+	if format, err := ResultsFormatForMediaType(contentType); err == nil {
+		return DecodeResults(body, format)
+	}
+	format, err := encoder.FormatForMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint returned unsupported Content-Type %q", contentType)
+	}
+	triples, prefixes, err := encoder.Decode(body, format, encoder.DecodeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &Results{Form: Construct, Triples: triples, Prefixes: prefixes}, nil
+}
+
+// Update sends an update to the endpoint.
+func (c *Client) Update(ctx context.Context, update string) error {
+	endpoint := c.UpdateEndpoint
+	if endpoint == "" {
+		endpoint = c.Endpoint
+	}
+	_, _, err := c.post(ctx, endpoint, "update", update, "")
+	return err
+}
+
+// post sends a form with one parameter, the way every SPARQL 1.1 Protocol
+// endpoint must accept, and returns the body and media type of a successful
+// response.
+func (c *Client) post(ctx context.Context, endpoint, param, value, accept string) (string, string, error) {
+	form := url.Values{param: {value}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 500 {
+			msg = msg[:500] + "..."
+		}
+		return "", "", fmt.Errorf("endpoint returned %s: %s", resp.Status, msg)
+	}
+	return string(data), resp.Header.Get("Content-Type"), nil
+}
@@ -0,0 +1,90 @@
+package sparql
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func TestClient(t *testing.T) {
+	g := testGraph(t)
+	srv := httptest.NewServer(NewHandler(g, HandlerOptions{
+		Prefixes: map[string]string{"ex": "http://example.org/", "foaf": "http://xmlns.com/foaf/0.1/"},
+	}))
+	defer srv.Close()
+	c := &Client{Endpoint: srv.URL}
+	ctx := context.Background()
+
+	query := `SELECT ?p ?age WHERE { ?p foaf:age ?age } ORDER BY ?age`
+	res, err := c.Query(ctx, query)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	local, _ := Exec(g, testPrefixes+query, ParseOptions{})
+	if !reflect.DeepEqual(res.Bindings, local.Bindings) || !reflect.DeepEqual(res.Vars, local.Vars) {
+		t.Errorf("Query() = %v, want %v", res.Bindings, local.Bindings)
+	}
+
+	res, err = c.Query(ctx, `CONSTRUCT WHERE { ex:bob foaf:knows ?o }`)
+	if err != nil {
+		t.Fatalf("Query(CONSTRUCT) error = %v", err)
+	}
+	want := []string{"<bob> <knows> <carol>"}
+	if got := ntriples(res.Triples); res.Form != Construct || !reflect.DeepEqual(got, want) || res.Prefixes["ex"] == "" {
+		t.Errorf("Query(CONSTRUCT) = %s %q %v, want %q", res.Form, got, res.Prefixes, want)
+	}
+
+	if err := c.Update(ctx, `DELETE DATA { ex:bob foaf:knows ex:carol }`); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if res, _ := c.Query(ctx, `ASK { ex:bob foaf:knows ex:carol }`); res == nil || res.Form != Ask || res.Boolean {
+		t.Errorf("ASK after Update() = %v, want false", res)
+	}
+
+	_, err = c.Query(ctx, `SELECT nonsense`)
+	if err == nil || !strings.Contains(err.Error(), "400 Bad Request") {
+		t.Errorf("Query() of a bad query error = %v, want 400", err)
+	}
+}
+
+func TestClientResultFormats(t *testing.T) {
+	// A stand-in endpoint that answers with fixed bodies, as other servers
+	// might.
+	responses := map[string]string{
+		"application/sparql-results+xml": `<?xml version="1.0"?>
+<sparql xmlns="http://www.w3.org/2005/sparql-results#"><head><variable name="x"/></head>
+<results><result><binding name="x"><uri>http://example.org/a</uri></binding></result></results></sparql>`,
+		"text/csv; charset=utf-8":                  "x\r\nhttp://example.org/a\r\n",
+		"text/tab-separated-values; charset=utf-8": "?x\n<http://example.org/a>\n",
+		"application/json":                         `{"head": {"vars": ["x"]}, "results": {"bindings": [{"x": {"type": "uri", "value": "http://example.org/a"}}]}}`,
+	}
+
+	for contentType, body := range responses {
+		t.Run(contentType, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil || r.PostForm.Get("query") == "" {
+					http.Error(w, "missing query", http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", contentType)
+				io.WriteString(w, body)
+			}))
+			defer srv.Close()
+
+			res, err := (&Client{Endpoint: srv.URL}).Query(context.Background(), "SELECT ?x {}")
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			want := []Binding{{"x": triple.IRI{Value: "http://example.org/a"}}}
+			if !reflect.DeepEqual(res.Bindings, want) || !reflect.DeepEqual(res.Vars, []string{"x"}) {
+				t.Errorf("Query() = %v %v, want %v", res.Vars, res.Bindings, want)
+			}
+		})
+	}
+}
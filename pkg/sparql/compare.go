@@ -0,0 +1,372 @@
+package sparql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+// errType is the SPARQL type error. A FILTER treats it as false and a BIND
+// leaves its variable unbound.
+var errType = errors.New("type error")
+
+func typeErrorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", errType, fmt.Sprintf(format, args...))
+}
+
+// numericKind orders the XSD numeric types for type promotion.
+type numericKind int
+
+const (
+	kindInteger numericKind = iota
+	kindDecimal
+	kindFloat
+	kindDouble
+)
+
+type number struct {
+	kind numericKind
+	i    int64
+	f    float64
+}
+
+func integerNumber(i int64) number {
+	return number{kind: kindInteger, i: i, f: float64(i)}
+}
+
+func isNumeric(n triple.Node) bool {
+	lit, ok := n.(triple.Literal)
+	return ok && xsd.IsNumeric(lit.Datatype)
+}
+
+func toNumber(n triple.Node) (number, error) {
+	lit, ok := n.(triple.Literal)
+	if !ok || !xsd.IsNumeric(lit.Datatype) {
+		return number{}, typeErrorf("%s is not numeric", termKey(n))
+	}
+
+	switch {
+	case xsd.IsInteger(lit.Datatype):
+		i, err := xsd.ParseInteger(lit.Datatype, lit.Value)
+		if err != nil {
+			return number{}, fmt.Errorf("%w: %v", errType, err)
+		}
+		return integerNumber(i), nil
+	case lit.Datatype == xsd.Decimal:
+		f, err := xsd.ParseDecimal(lit.Value)
+		if err != nil {
+			return number{}, fmt.Errorf("%w: %v", errType, err)
+		}
+		return number{kind: kindDecimal, f: f}, nil
+	case lit.Datatype == xsd.Float:
+		f, err := xsd.ParseFloat(lit.Value)
+		if err != nil {
+			return number{}, fmt.Errorf("%w: %v", errType, err)
+		}
+		return number{kind: kindFloat, f: float64(f)}, nil
+	}
+	f, err := xsd.ParseDouble(lit.Value)
+	if err != nil {
+		return number{}, fmt.Errorf("%w: %v", errType, err)
+	}
+	return number{kind: kindDouble, f: f}, nil
+}
+
+func (n number) literal() triple.Literal {
+	switch n.kind {
+	case kindInteger:
+		return triple.NewIntegerLiteral(n.i)
+	case kindDecimal:
+		return triple.NewDecimalLiteral(n.f)
+	case kindFloat:
+		return triple.Literal{Value: xsd.FormatFloat(float32(n.f)), Datatype: xsd.Float}
+	}
+	return triple.NewDoubleLiteral(n.f)
+}
+
+// promote converts n to kind, which must not be narrower than n.kind.
+func (n number) promote(kind numericKind) number {
+	if n.kind == kindInteger && kind != kindInteger {
+		n.f = float64(n.i)
+	}
+	n.kind = kind
+	return n
+}
+
+// arithmetic applies op to a and b after promoting both to the wider type.
+// Dividing two integers gives a decimal.
+func arithmetic(op string, a, b number) (number, error) {
+	kind := max(a.kind, b.kind)
+	if op == "/" && kind == kindInteger {
+		kind = kindDecimal
+	}
+	a, b = a.promote(kind), b.promote(kind)
+
+	if kind == kindInteger {
+		switch op {
+		case "+":
+			return integerNumber(a.i + b.i), nil
+		case "-":
+			return integerNumber(a.i - b.i), nil
+		case "*":
+			return integerNumber(a.i * b.i), nil
+		}
+	}
+
+	var f float64
+	switch op {
+	case "+":
+		f = a.f + b.f
+	case "-":
+		f = a.f - b.f
+	case "*":
+		f = a.f * b.f
+	case "/":
+		if b.f == 0 && kind == kindDecimal {
+			return number{}, typeErrorf("division by zero")
+		}
+		f = a.f / b.f
+	}
+	return number{kind: kind, f: f}, nil
+}
+
+func compareNumbers(a, b number) int {
+	if a.kind == kindInteger && b.kind == kindInteger {
+		return cmpInt(a.i, b.i)
+	}
+	switch {
+	case a.f < b.f:
+		return -1
+	case a.f > b.f:
+		return 1
+	}
+	return 0
+}
+
+func cmpInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// isStringLiteral reports whether n is a simple literal or an xsd:string.
+func isStringLiteral(n triple.Node) bool {
+	lit, ok := n.(triple.Literal)
+	return ok && lit.Language == "" && (lit.Datatype == "" || lit.Datatype == xsd.String)
+}
+
+// valueKind classifies literals whose values, rather than terms, are compared.
+func valueKind(n triple.Node) string {
+	lit, ok := n.(triple.Literal)
+	switch {
+	case !ok:
+		return ""
+	case xsd.IsNumeric(lit.Datatype):
+		return "numeric"
+	case isStringLiteral(lit):
+		return "string"
+	case lit.Language != "":
+		return "langString"
+	case lit.Datatype == xsd.Boolean:
+		return "boolean"
+	case lit.Datatype == xsd.DateTime || lit.Datatype == xsd.Date:
+		return lit.Datatype
+	}
+	return ""
+}
+
+// compareValues compares two terms by value for the <, >, <= and >=
+// operators. It fails with a type error when the values are not comparable.
+func compareValues(a, b triple.Node) (int, error) {
+	kind := valueKind(a)
+	if kind == "" || kind == "langString" || kind != valueKind(b) {
+		return 0, typeErrorf("cannot compare %s and %s", termKey(a), termKey(b))
+	}
+
+	la, lb := a.(triple.Literal), b.(triple.Literal)
+	switch kind {
+	case "numeric":
+		na, err := toNumber(a)
+		if err != nil {
+			return 0, err
+		}
+		nb, err := toNumber(b)
+		if err != nil {
+			return 0, err
+		}
+		if math.IsNaN(na.f) || math.IsNaN(nb.f) {
+			return 0, typeErrorf("NaN is not ordered")
+		}
+		return compareNumbers(na, nb), nil
+	case "string":
+		return strings.Compare(la.Value, lb.Value), nil
+	case "boolean":
+		ba, err := la.Bool()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", errType, err)
+		}
+		bb, err := lb.Bool()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", errType, err)
+		}
+		return cmpInt(boolInt(ba), boolInt(bb)), nil
+	}
+
+	ta, err := la.Time()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errType, err)
+	}
+	tb, err := lb.Time()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errType, err)
+	}
+	return ta.Compare(tb), nil
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// equalValues implements the = operator: literals of known types are compared
+// by value, other terms by identity. Distinct literals of unknown datatypes
+// give a type error, since they may still denote the same value.
+func equalValues(a, b triple.Node) (bool, error) {
+	if a == b {
+		return true, nil
+	}
+
+	if kind := valueKind(a); kind != "" && kind == valueKind(b) {
+		if kind == "langString" {
+			la, lb := a.(triple.Literal), b.(triple.Literal)
+			return la.Value == lb.Value && strings.EqualFold(la.Language, lb.Language) && la.Direction == lb.Direction, nil
+		}
+		c, err := compareValues(a, b)
+		return err == nil && c == 0, err
+	}
+
+	la, aLit := a.(triple.Literal)
+	lb, bLit := b.(triple.Literal)
+	if aLit && bLit && !knownDatatype(la) && !knownDatatype(lb) {
+		return false, typeErrorf("cannot compare %s and %s", termKey(a), termKey(b))
+	}
+	return false, nil
+}
+
+func knownDatatype(lit triple.Literal) bool {
+	return lit.Datatype == "" || lit.Language != "" || xsd.Supported(lit.Datatype)
+}
+
+// orderTerms is the ORDER BY ordering: unbound, then blank nodes, IRIs,
+// literals and triple terms. Literals that cannot be compared by value are
+// ordered by their lexical form.
+func orderTerms(a, b triple.Node) int {
+	if ra, rb := termRank(a), termRank(b); ra != rb {
+		return cmpInt(int64(ra), int64(rb))
+	}
+
+	switch x := a.(type) {
+	case nil:
+		return 0
+	case triple.BlankNode:
+		return strings.Compare(x.Value, b.(triple.BlankNode).Value)
+	case triple.IRI:
+		return strings.Compare(x.Value, b.(triple.IRI).Value)
+	case triple.Literal:
+		if c, err := compareValues(a, b); err == nil && c != 0 {
+			return c
+		}
+		y := b.(triple.Literal)
+		if c := strings.Compare(x.Value, y.Value); c != 0 {
+			return c
+		}
+		if c := strings.Compare(x.Language, y.Language); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Datatype, y.Datatype)
+	}
+	return strings.Compare(termKey(a), termKey(b))
+}
+
+func termRank(n triple.Node) int {
+	switch n.(type) {
+	case nil:
+		return 0
+	case triple.BlankNode:
+		return 1
+	case triple.IRI:
+		return 2
+	case triple.Literal:
+		return 3
+	}
+	return 4
+}
+
+// effectiveBooleanValue evaluates expr against b as a FILTER condition;
+// errors count as false.
+func effectiveBooleanValue(ctx *evalContext, expr expression, b Binding) bool {
+	v, err := expr.eval(ctx, b)
+	if err != nil {
+		return false
+	}
+	ebv, err := toBoolean(v)
+	return err == nil && ebv
+}
+
+// toBoolean returns the effective boolean value of n.
+func toBoolean(n triple.Node) (bool, error) {
+	lit, ok := n.(triple.Literal)
+	if !ok {
+		return false, typeErrorf("%s has no boolean value", termKey(n))
+	}
+	switch {
+	case lit.Datatype == xsd.Boolean:
+		b, err := lit.Bool()
+		if err != nil {
+			return false, nil
+		}
+		return b, nil
+	case xsd.IsNumeric(lit.Datatype):
+		num, err := toNumber(lit)
+		if err != nil {
+			return false, nil
+		}
+		if num.kind == kindInteger {
+			return num.i != 0, nil
+		}
+		return num.f != 0 && !math.IsNaN(num.f), nil
+	case isStringLiteral(lit):
+		return lit.Value != "", nil
+	}
+	return false, typeErrorf("%s has no boolean value", termKey(n))
+}
+
+// termKey returns the N-Triples form of n, which identifies the term.
+func termKey(n triple.Node) string {
+	return encoder.EncodeTerm(n)
+}
+
+// timeOf returns the value of an xsd:dateTime or xsd:date literal.
+func timeOf(n triple.Node) (time.Time, triple.Literal, error) {
+	lit, ok := n.(triple.Literal)
+	if !ok || (lit.Datatype != xsd.DateTime && lit.Datatype != xsd.Date) {
+		return time.Time{}, triple.Literal{}, typeErrorf("%s is not a dateTime", termKey(n))
+	}
+	t, err := lit.Time()
+	if err != nil {
+		return time.Time{}, triple.Literal{}, fmt.Errorf("%w: %v", errType, err)
+	}
+	return t, lit, nil
+}
@@ -0,0 +1,103 @@
+package sparql
+
+import (
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// instantiate fills the CONSTRUCT template with each solution. Blank nodes in
+// the template become new blank nodes for every solution. Triples with an
+// unbound variable or a term that cannot appear in its position are left out,
+// and each triple is returned once.
+func instantiate(ctx *evalContext, template []triplePattern, solutions []Binding) []triple.Triple {
+	var result []triple.Triple
+	seen := make(map[triple.Triple]bool)
+
+	for _, b := range solutions {
+		bnodes := make(map[string]triple.Node)
+		resolve := func(t term) (triple.Node, bool) {
+			switch {
+			case !t.isVar():
+				return t.node, true
+			case isHidden(t.name):
+				n, ok := bnodes[t.name]
+				if !ok {
+					n = ctx.newBlankNode()
+					bnodes[t.name] = n
+				}
+				return n, true
+			}
+			n, ok := b[t.name]
+			return n, ok
+		}
+
+		for _, tp := range template {
+			s, ok := resolve(tp.subject)
+			if !ok {
+				continue
+			}
+			p, ok := resolve(tp.predicate)
+			if !ok {
+				continue
+			}
+			o, ok := resolve(tp.object)
+			if !ok {
+				continue
+			}
+
+			t := triple.Triple{Subject: s, Predicate: p, Object: o}
+			if !isValidTriple(t) || seen[t] {
+				continue
+			}
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func isValidTriple(t triple.Triple) bool {
+	switch t.Subject.(type) {
+	case triple.IRI, triple.BlankNode:
+	default:
+		return false
+	}
+	_, ok := t.Predicate.(triple.IRI)
+	return ok
+}
+
+// describe returns the concise bounded description of each resource: the
+// triples with the resource as subject and, recursively, the descriptions of
+// blank nodes they point to. Variables are described with every IRI or blank
+// node bound to them in the solutions.
+func describe(g triple.Matcher, resources []term, solutions []Binding) []triple.Triple {
+	var result []triple.Triple
+	visited := make(map[triple.Node]bool)
+
+	var visit func(n triple.Node)
+	visit = func(n triple.Node) {
+		if visited[n] {
+			return
+		}
+		visited[n] = true
+		for _, t := range g.Match(n, nil, nil) {
+			result = append(result, t)
+			if bn, ok := t.Object.(triple.BlankNode); ok {
+				visit(bn)
+			}
+		}
+	}
+
+	for _, r := range resources {
+		if !r.isVar() {
+			visit(r.node)
+			continue
+		}
+		for _, b := range solutions {
+			switch n := b[r.name].(type) {
+			case triple.IRI, triple.BlankNode:
+				visit(n)
+			}
+		}
+	}
+	return result
+}
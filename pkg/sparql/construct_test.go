@@ -0,0 +1,123 @@
+package sparql
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// ntriples renders triples as sorted N-Triples lines with IRIs shortened to
+// their local name.
+func ntriples(triples []triple.Triple) []string {
+	var lines []string
+	for _, t := range triples {
+		line := strings.TrimSuffix(encoder.EncodeNTriple(t), " .")
+		line = strings.NewReplacer(
+			"<http://example.org/", "<",
+			"<http://xmlns.com/foaf/0.1/", "<",
+		).Replace(line)
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestConstruct(t *testing.T) {
+	g := testGraph(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "template",
+			query: `CONSTRUCT { ?b ex:knownBy ?a } WHERE { ?a foaf:knows ?b } ORDER BY ?a LIMIT 2`,
+			want: []string{
+				"<bob> <knownBy> <alice>",
+				"<carol> <knownBy> <alice>",
+			},
+		},
+		{
+			name:  "blank nodes per solution",
+			query: `CONSTRUCT { ?p ex:card _:c . _:c ex:name ?n } WHERE { ?p foaf:age ?a ; foaf:name ?n }`,
+			want: []string{
+				`<alice> <card> _:b1`,
+				`<bob> <card> _:b2`,
+				`_:b1 <name> "Alice"`,
+				`_:b2 <name> "Bob"@en`,
+			},
+		},
+		{
+			name:  "unbound and invalid triples are skipped",
+			query: `CONSTRUCT { ?p ex:mbox ?m . ?n ex:of ?p } WHERE { ?p foaf:name ?n OPTIONAL { ?p foaf:mbox ?m } }`,
+			want:  []string{"<carol> <mbox> <mailto:carol@example.org>"},
+		},
+		{
+			name:  "short form",
+			query: `CONSTRUCT WHERE { ex:bob foaf:knows ?x }`,
+			want:  []string{"<bob> <knows> <carol>"},
+		},
+		{
+			name:  "duplicates removed",
+			query: `CONSTRUCT { ex:x ex:y ex:z } WHERE { ?p a foaf:Person }`,
+			want:  []string{"<x> <y> <z>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triples, err := ExecConstruct(g, testPrefixes+tt.query, ParseOptions{})
+			if err != nil {
+				t.Fatalf("ExecConstruct() error = %v", err)
+			}
+			if got := ntriples(triples); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ExecConstruct() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	triples, _, err := encoder.Decode(`
+@prefix ex: <http://example.org/> .
+ex:a ex:name "A" ; ex:addr [ ex:city "Oslo" ; ex:geo [ ex:lat 59 ] ] ; ex:knows ex:b .
+ex:b ex:name "B" ; ex:knows ex:a .
+ex:c ex:knows ex:a .
+`, encoder.Turtle, encoder.DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	g := triple.NewGraph(triples)
+
+	triples, err = ExecConstruct(g, testPrefixes+`DESCRIBE ex:a`, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ExecConstruct() error = %v", err)
+	}
+	if len(triples) != 6 {
+		t.Errorf("DESCRIBE ex:a returned %d triples, want 6:\n%s", len(triples), strings.Join(ntriples(triples), "\n"))
+	}
+
+	triples, err = ExecConstruct(g, testPrefixes+`DESCRIBE ?x WHERE { ex:c ex:knows ?x . ?x ex:knows ?y }`, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ExecConstruct() error = %v", err)
+	}
+	if len(triples) != 6 {
+		t.Errorf("DESCRIBE ?x returned %d triples, want 6", len(triples))
+	}
+
+	triples, err = ExecConstruct(g, testPrefixes+`DESCRIBE * WHERE { ?x ex:knows ex:a }`, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ExecConstruct() error = %v", err)
+	}
+	if got := ntriples(triples); len(got) != 3 || got[0] != `<b> <knows> <a>` {
+		t.Errorf("DESCRIBE * = %v", got)
+	}
+
+	if _, err := ExecConstruct(g, `ASK {}`, ParseOptions{}); err == nil {
+		t.Error("ExecConstruct(ASK) succeeded, want error")
+	}
+}
@@ -0,0 +1,563 @@
+package sparql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// pattern is a node of the SPARQL algebra built from a group graph pattern.
+type pattern interface {
+	eval(ctx *evalContext) ([]Binding, error)
+	// vars returns the variables the pattern may bind, in order of appearance.
+	vars() []string
+}
+
+// seededPattern is a pattern that can be evaluated from an existing solution,
+// which makes joining with it cheaper than a nested loop over both sides.
+type seededPattern interface {
+	pattern
+	evalFrom(ctx *evalContext, seed Binding) ([]Binding, error)
+}
+
+type evalContext struct {
+	graph triple.Matcher
+	// outer is the solution an EXISTS pattern is evaluated against; its
+	// variables are visible inside the pattern.
+	outer Binding
+	*evalState
+}
+
+// evalState is shared by every context of one evaluation.
+type evalState struct {
+	base        string
+	now         time.Time
+	regexps     map[string]*regexp.Regexp
+	bnodes      int
+	bnodeLabels map[string]triple.Node
+}
+
+func newEvalContext(g triple.Matcher, base string) *evalContext {
+	return &evalContext{
+		graph: g,
+		evalState: &evalState{
+			base:        base,
+			now:         time.Now(),
+			regexps:     make(map[string]*regexp.Regexp),
+			bnodeLabels: make(map[string]triple.Node),
+		},
+	}
+}
+
+// newBlankNode returns a blank node not yet used in this evaluation nor in
+// the graph.
+func (ctx *evalContext) newBlankNode() triple.BlankNode {
+	for {
+		ctx.bnodes++
+		n := triple.BlankNode{Value: fmt.Sprintf("b%d", ctx.bnodes)}
+		if len(ctx.graph.Match(n, nil, nil)) == 0 && len(ctx.graph.Match(nil, nil, n)) == 0 {
+			return n
+		}
+	}
+}
+
+// lookup returns the term bound to name in b or, inside EXISTS, in the outer
+// solution.
+func (ctx *evalContext) lookup(b Binding, name string) (triple.Node, bool) {
+	if n, ok := b[name]; ok {
+		return n, true
+	}
+	n, ok := ctx.outer[name]
+	return n, ok
+}
+
+// isHidden reports whether name is a variable standing for a blank node in
+// the query, which is never projected.
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, "_:")
+}
+
+type bgp struct {
+	triples []triplePattern
+}
+
+func (p *bgp) eval(ctx *evalContext) ([]Binding, error) {
+	seed := Binding{}
+	for k, v := range ctx.outer {
+		seed[k] = v
+	}
+	return p.evalFrom(ctx, seed)
+}
+
+// evalFrom matches the triple patterns one at a time, always picking next the
+// pattern with the most bound terms.
+func (p *bgp) evalFrom(ctx *evalContext, seed Binding) ([]Binding, error) {
+	solutions := []Binding{seed}
+	remaining := append([]triplePattern(nil), p.triples...)
+
+	for len(remaining) > 0 && len(solutions) > 0 {
+		best := 0
+		for i := 1; i < len(remaining); i++ {
+			if boundTerms(remaining[i], solutions[0]) > boundTerms(remaining[best], solutions[0]) {
+				best = i
+			}
+		}
+		tp := remaining[best]
+		remaining = append(remaining[:best], remaining[best+1:]...)
+
+		var next []Binding
+		for _, b := range solutions {
+			s, pr, o := resolve(tp.subject, b), resolve(tp.predicate, b), resolve(tp.object, b)
+			if tp.path != nil {
+				for _, pair := range triple.MatchPath(ctx.graph, s, tp.path, o) {
+					if nb, ok := bindTriple(b, tp, triple.Triple{Subject: pair.Subject, Object: pair.Object}); ok {
+						next = append(next, nb)
+					}
+				}
+				continue
+			}
+			for _, t := range ctx.graph.Match(s, pr, o) {
+				if nb, ok := bindTriple(b, tp, t); ok {
+					next = append(next, nb)
+				}
+			}
+		}
+		solutions = next
+	}
+	return solutions, nil
+}
+
+func (p *bgp) vars() []string {
+	var names []string
+	for _, tp := range p.triples {
+		for _, t := range []term{tp.subject, tp.predicate, tp.object} {
+			if t.isVar() {
+				names = append(names, t.name)
+			}
+		}
+	}
+	return names
+}
+
+func boundTerms(tp triplePattern, b Binding) int {
+	n := 0
+	for _, t := range []term{tp.subject, tp.predicate, tp.object} {
+		if resolve(t, b) != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// resolve returns the node t stands for in b, or nil for an unbound variable.
+func resolve(t term, b Binding) triple.Node {
+	if !t.isVar() {
+		return t.node
+	}
+	return b[t.name]
+}
+
+func bindTriple(b Binding, tp triplePattern, t triple.Triple) (Binding, bool) {
+	nb := copyBinding(b)
+	for _, pair := range []struct {
+		term term
+		node triple.Node
+	}{{tp.subject, t.Subject}, {tp.predicate, t.Predicate}, {tp.object, t.Object}} {
+		if !pair.term.isVar() {
+			continue
+		}
+		if bound, ok := nb[pair.term.name]; ok && bound != pair.node {
+			return nil, false
+		}
+		nb[pair.term.name] = pair.node
+	}
+	return nb, true
+}
+
+type joinPattern struct {
+	left, right pattern
+}
+
+func (p *joinPattern) eval(ctx *evalContext) ([]Binding, error) {
+	left, err := p.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if seeded, ok := p.right.(seededPattern); ok {
+		var result []Binding
+		for _, l := range left {
+			solutions, err := seeded.evalFrom(ctx, l)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, solutions...)
+		}
+		return result, nil
+	}
+	right, err := p.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return join(left, right), nil
+}
+
+func (p *joinPattern) vars() []string {
+	return append(p.left.vars(), p.right.vars()...)
+}
+
+type leftJoinPattern struct {
+	left, right pattern
+	// filter is the condition of a FILTER inside the OPTIONAL block, or nil.
+	filter expression
+}
+
+func (p *leftJoinPattern) eval(ctx *evalContext) ([]Binding, error) {
+	left, err := p.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var right []Binding
+	seeded, isSeeded := p.right.(seededPattern)
+	if !isSeeded {
+		if right, err = p.right.eval(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []Binding
+	for _, l := range left {
+		var candidates []Binding
+		if isSeeded {
+			if candidates, err = seeded.evalFrom(ctx, l); err != nil {
+				return nil, err
+			}
+		} else {
+			for _, r := range right {
+				if compatible(l, r) {
+					candidates = append(candidates, merge(l, r))
+				}
+			}
+		}
+
+		matched := false
+		for _, c := range candidates {
+			if p.filter == nil || effectiveBooleanValue(ctx, p.filter, c) {
+				result = append(result, c)
+				matched = true
+			}
+		}
+		if !matched {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
+
+func (p *leftJoinPattern) vars() []string {
+	return append(p.left.vars(), p.right.vars()...)
+}
+
+type unionPattern struct {
+	left, right pattern
+}
+
+func (p *unionPattern) eval(ctx *evalContext) ([]Binding, error) {
+	left, err := p.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := p.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+func (p *unionPattern) vars() []string {
+	return append(p.left.vars(), p.right.vars()...)
+}
+
+type minusPattern struct {
+	left, right pattern
+}
+
+func (p *minusPattern) eval(ctx *evalContext) ([]Binding, error) {
+	left, err := p.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := p.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Binding
+	for _, l := range left {
+		excluded := false
+		for _, r := range right {
+			if sharesVariable(l, r) && compatible(l, r) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
+
+func (p *minusPattern) vars() []string {
+	return p.left.vars()
+}
+
+type filterPattern struct {
+	inner pattern
+	conds []expression
+}
+
+func (p *filterPattern) eval(ctx *evalContext) ([]Binding, error) {
+	solutions, err := p.inner.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filter(ctx, solutions, p.conds), nil
+}
+
+// filter keeps the solutions for which every condition is true.
+func filter(ctx *evalContext, solutions []Binding, conds []expression) []Binding {
+	var result []Binding
+	for _, b := range solutions {
+		keep := true
+		for _, cond := range conds {
+			if !effectiveBooleanValue(ctx, cond, b) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+func (p *filterPattern) vars() []string {
+	return p.inner.vars()
+}
+
+type extendPattern struct {
+	inner pattern
+	name  string
+	expr  expression
+}
+
+func (p *extendPattern) eval(ctx *evalContext) ([]Binding, error) {
+	solutions, err := p.inner.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return extend(ctx, solutions, p.name, p.expr), nil
+}
+
+func (p *extendPattern) vars() []string {
+	return append(p.inner.vars(), p.name)
+}
+
+// valuesPattern is inline data from a VALUES block. A group with no content
+// is a valuesPattern with a single empty row.
+type valuesPattern struct {
+	names []string
+	rows  []Binding
+}
+
+func (p *valuesPattern) eval(ctx *evalContext) ([]Binding, error) {
+	if len(ctx.outer) == 0 {
+		return copyBindings(p.rows), nil
+	}
+	return p.evalFrom(ctx, ctx.outer)
+}
+
+func (p *valuesPattern) evalFrom(ctx *evalContext, seed Binding) ([]Binding, error) {
+	var result []Binding
+	for _, row := range p.rows {
+		if compatible(seed, row) {
+			result = append(result, merge(seed, row))
+		}
+	}
+	return result, nil
+}
+
+func (p *valuesPattern) vars() []string {
+	return p.names
+}
+
+func extend(ctx *evalContext, solutions []Binding, name string, expr expression) []Binding {
+	result := make([]Binding, len(solutions))
+	for i, b := range solutions {
+		result[i] = b
+		if value, err := expr.eval(ctx, b); err == nil {
+			result[i] = copyBinding(b)
+			result[i][name] = value
+		}
+	}
+	return result
+}
+
+func join(left, right []Binding) []Binding {
+	var result []Binding
+	for _, l := range left {
+		for _, r := range right {
+			if compatible(l, r) {
+				result = append(result, merge(l, r))
+			}
+		}
+	}
+	return result
+}
+
+func compatible(a, b Binding) bool {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	for k, v := range a {
+		if w, ok := b[k]; ok && w != v {
+			return false
+		}
+	}
+	return true
+}
+
+func sharesVariable(a, b Binding) bool {
+	for k := range a {
+		if _, ok := b[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func merge(a, b Binding) Binding {
+	m := make(Binding, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+	for k, v := range b {
+		m[k] = v
+	}
+	return m
+}
+
+func copyBinding(b Binding) Binding {
+	c := make(Binding, len(b)+1)
+	for k, v := range b {
+		c[k] = v
+	}
+	return c
+}
+
+func copyBindings(bindings []Binding) []Binding {
+	result := make([]Binding, len(bindings))
+	for i, b := range bindings {
+		result[i] = copyBinding(b)
+	}
+	return result
+}
+
+func orderSolutions(ctx *evalContext, solutions []Binding, conds []orderCondition) {
+	keys := make([][]triple.Node, len(solutions))
+	for i, b := range solutions {
+		keys[i] = make([]triple.Node, len(conds))
+		for j, cond := range conds {
+			keys[i][j], _ = cond.expr.eval(ctx, b)
+		}
+	}
+
+	index := make([]int, len(solutions))
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(x, y int) bool {
+		a, b := keys[index[x]], keys[index[y]]
+		for j, cond := range conds {
+			c := orderTerms(a[j], b[j])
+			if c == 0 {
+				continue
+			}
+			if cond.descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+
+	sorted := make([]Binding, len(solutions))
+	for i, j := range index {
+		sorted[i] = solutions[j]
+	}
+	copy(solutions, sorted)
+}
+
+func project(solutions []Binding, names []string) []Binding {
+	result := make([]Binding, len(solutions))
+	for i, b := range solutions {
+		p := make(Binding, len(names))
+		for _, name := range names {
+			if v, ok := b[name]; ok {
+				p[name] = v
+			}
+		}
+		result[i] = p
+	}
+	return result
+}
+
+func distinct(solutions []Binding, names []string) []Binding {
+	seen := make(map[string]bool, len(solutions))
+	var result []Binding
+	for _, b := range solutions {
+		var key strings.Builder
+		for _, name := range names {
+			if v, ok := b[name]; ok {
+				key.WriteString(termKey(v))
+			}
+			key.WriteByte(0)
+		}
+		if !seen[key.String()] {
+			seen[key.String()] = true
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+func slice(solutions []Binding, offset, limit int) []Binding {
+	if offset >= len(solutions) {
+		return nil
+	}
+	solutions = solutions[offset:]
+	if limit >= 0 && limit < len(solutions) {
+		solutions = solutions[:limit]
+	}
+	return solutions
+}
+
+// inScope returns the distinct visible variables of p, in order of
+// appearance.
+func inScope(p pattern) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range p.vars() {
+		if !seen[name] && !isHidden(name) {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
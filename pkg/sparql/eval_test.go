@@ -0,0 +1,347 @@
+package sparql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+const testData = `
+@prefix ex: <http://example.org/> .
+@prefix foaf: <http://xmlns.com/foaf/0.1/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+ex:alice a foaf:Person ;
+    foaf:name "Alice" ;
+    foaf:age 34 ;
+    foaf:knows ex:bob, ex:carol .
+
+ex:bob a foaf:Person ;
+    foaf:name "Bob"@en ;
+    foaf:age 27 ;
+    foaf:knows ex:carol .
+
+ex:carol a foaf:Person ;
+    foaf:name "Carol" ;
+    foaf:mbox <mailto:carol@example.org> .
+
+ex:doc1 ex:created "2024-03-01T10:00:00Z"^^xsd:dateTime ;
+    ex:score 2.5 .
+`
+
+const testPrefixes = `PREFIX ex: <http://example.org/>
+PREFIX foaf: <http://xmlns.com/foaf/0.1/>
+PREFIX xsd: <http://www.w3.org/2001/XMLSchema#>
+`
+
+func testGraph(t *testing.T) *triple.Graph {
+	t.Helper()
+	triples, _, err := encoder.Decode(testData, encoder.Turtle, encoder.DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	return triple.NewGraph(triples)
+}
+
+// rows renders each solution as "var=value" pairs in projection order, with
+// IRIs shortened to their local name.
+func rows(res *Results) []string {
+	var out []string
+	for _, b := range res.Bindings {
+		var parts []string
+		for _, v := range res.Vars {
+			n, ok := b[v]
+			if !ok {
+				continue
+			}
+			s := termKey(n)
+			if iri, ok := n.(triple.IRI); ok {
+				s = iri.Value[strings.LastIndexAny(iri.Value, "/#")+1:]
+			}
+			parts = append(parts, v+"="+s)
+		}
+		out = append(out, strings.Join(parts, " "))
+	}
+	return out
+}
+
+func TestSelect(t *testing.T) {
+	g := testGraph(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "basic graph pattern",
+			query: `SELECT ?p WHERE { ?p a foaf:Person ; foaf:age ?age } ORDER BY ?p`,
+			want:  []string{"p=alice", "p=bob"},
+		},
+		{
+			name:  "filter comparison",
+			query: `SELECT ?p ?age { ?p foaf:age ?age FILTER(?age > 30) }`,
+			want:  []string{`p=alice age="34"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		},
+		{
+			name:  "optional",
+			query: `SELECT ?p ?mbox { ?p a foaf:Person OPTIONAL { ?p foaf:mbox ?mbox } } ORDER BY DESC(?p)`,
+			want:  []string{"p=carol mbox=mailto:carol@example.org", "p=bob", "p=alice"},
+		},
+		{
+			name:  "optional with filter",
+			query: `SELECT ?p ?age { ?p a foaf:Person OPTIONAL { ?p foaf:age ?age FILTER(?age < 30) } } ORDER BY ?p`,
+			want:  []string{"p=alice", `p=bob age="27"^^<http://www.w3.org/2001/XMLSchema#integer>`, "p=carol"},
+		},
+		{
+			name:  "union",
+			query: `SELECT ?x { { ex:alice foaf:knows ?x } UNION { ?x foaf:mbox ?m } }`,
+			want:  []string{"x=bob", "x=carol", "x=carol"},
+		},
+		{
+			name:  "distinct",
+			query: `SELECT DISTINCT ?x { { ex:alice foaf:knows ?x } UNION { ?x foaf:mbox ?m } }`,
+			want:  []string{"x=bob", "x=carol"},
+		},
+		{
+			name:  "minus",
+			query: `SELECT ?p { ?p a foaf:Person MINUS { ?p foaf:mbox ?m } } ORDER BY ?p`,
+			want:  []string{"p=alice", "p=bob"},
+		},
+		{
+			name:  "not exists",
+			query: `SELECT ?p { ?p a foaf:Person FILTER NOT EXISTS { ?x foaf:knows ?p } }`,
+			want:  []string{"p=alice"},
+		},
+		{
+			name:  "bind and expression projection",
+			query: `SELECT ?p ?next (UCASE(?n) AS ?upper) { ?p foaf:name ?n ; foaf:age ?a BIND(?a + 1 AS ?next) } ORDER BY ?next`,
+			want: []string{
+				`p=bob next="28"^^<http://www.w3.org/2001/XMLSchema#integer> upper="BOB"@en`,
+				`p=alice next="35"^^<http://www.w3.org/2001/XMLSchema#integer> upper="ALICE"`,
+			},
+		},
+		{
+			name:  "values",
+			query: `SELECT ?p ?n { VALUES ?p { ex:bob ex:carol } ?p foaf:name ?n }`,
+			want:  []string{`p=bob n="Bob"@en`, `p=carol n="Carol"`},
+		},
+		{
+			name:  "trailing values with undef",
+			query: `SELECT ?p ?n { ?p foaf:name ?n } VALUES (?p ?n) { (ex:alice UNDEF) (UNDEF "Carol") }`,
+			want:  []string{`p=alice n="Alice"`, `p=carol n="Carol"`},
+		},
+		{
+			name:  "limit and offset",
+			query: `SELECT ?n { ?p foaf:name ?n } ORDER BY STR(?n) LIMIT 1 OFFSET 1`,
+			want:  []string{`n="Bob"@en`},
+		},
+		{
+			name:  "regex and lang",
+			query: `SELECT ?p { ?p foaf:name ?n FILTER(REGEX(?n, "^b", "i") && LANGMATCHES(LANG(?n), "en")) }`,
+			want:  []string{"p=bob"},
+		},
+		{
+			name:  "in",
+			query: `SELECT ?p { ?p foaf:name ?n FILTER(STR(?n) IN ("Bob", "Carol")) } ORDER BY ?p`,
+			want:  []string{"p=bob", "p=carol"},
+		},
+		{
+			name:  "numeric promotion",
+			query: `SELECT ?v { ex:doc1 ex:score ?s BIND(?s * 2 AS ?v) }`,
+			want:  []string{`v="5.0"^^<http://www.w3.org/2001/XMLSchema#decimal>`},
+		},
+		{
+			name:  "dateTime functions",
+			query: `SELECT ?y ?tz { ex:doc1 ex:created ?d BIND(YEAR(?d) AS ?y) BIND(TZ(?d) AS ?tz) FILTER(?d < "2025-01-01T00:00:00Z"^^xsd:dateTime) }`,
+			want:  []string{`y="2024"^^<http://www.w3.org/2001/XMLSchema#integer> tz="Z"`},
+		},
+		{
+			name:  "blank node property list",
+			query: `SELECT ?n { [ foaf:knows ex:carol ; foaf:name ?n ] }`,
+			want:  []string{`n="Alice"`, `n="Bob"@en`},
+		},
+		{
+			name:  "select star hides blank nodes",
+			query: `SELECT * { _:x foaf:knows ?y . ?y foaf:mbox ?m }`,
+			want:  []string{"y=carol m=mailto:carol@example.org", "y=carol m=mailto:carol@example.org"},
+		},
+		{
+			name:  "bind error leaves variable unbound",
+			query: `SELECT ?p ?x { ?p foaf:mbox ?m BIND(?m + 1 AS ?x) }`,
+			want:  []string{"p=carol"},
+		},
+		{
+			name:  "cast",
+			query: `SELECT ?p { ?p foaf:age ?a FILTER(xsd:string(?a) = "27") }`,
+			want:  []string{"p=bob"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Exec(g, testPrefixes+tt.query, ParseOptions{})
+			if err != nil {
+				t.Fatalf("Exec() error = %v", err)
+			}
+			if got := rows(res); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Exec() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAsk(t *testing.T) {
+	g := testGraph(t)
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`ASK { ex:alice foaf:knows ex:bob }`, true},
+		{`ASK { ex:bob foaf:knows ex:alice }`, false},
+		{`ASK { ?p foaf:age ?a FILTER(?a >= 34) }`, true},
+		{`ASK { FILTER(1 + 1 = 2.0) }`, true},
+	}
+
+	for _, tt := range tests {
+		res, err := Exec(g, testPrefixes+tt.query, ParseOptions{})
+		if err != nil {
+			t.Fatalf("Exec(%s) error = %v", tt.query, err)
+		}
+		if res.Form != Ask || res.Boolean != tt.want {
+			t.Errorf("Exec(%s) = %v, want %v", tt.query, res.Boolean, tt.want)
+		}
+	}
+}
+
+func TestExpressions(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`1 + 2 * 3`, `"7"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		{`7 / 2`, `"3.5"^^<http://www.w3.org/2001/XMLSchema#decimal>`},
+		{`1.5e0 + 1`, `"2.5E0"^^<http://www.w3.org/2001/XMLSchema#double>`},
+		{`-(2 - 5)`, `"3"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		{`CONCAT("a", "b"@en)`, `"ab"`},
+		{`CONCAT("a"@en, "b"@en)`, `"ab"@en`},
+		{`SUBSTR("hello", 2, 3)`, `"ell"`},
+		{`STRLEN("héllo")`, `"5"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		{`STRBEFORE("a-b-c", "-")`, `"a"`},
+		{`STRAFTER("a-b-c", "-")`, `"b-c"`},
+		{`REPLACE("banana", "a(n)", "o$1")`, `"bonona"`},
+		{`ENCODE_FOR_URI("a b/c")`, `"a%20b%2Fc"`},
+		{`ROUND(2.5)`, `"3.0"^^<http://www.w3.org/2001/XMLSchema#decimal>`},
+		{`ABS(-3)`, `"3"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		{`IF(1 < 2, "yes", "no")`, `"yes"`},
+		{`COALESCE(?unbound, 1 / 0, "x")`, `"x"`},
+		{`!BOUND(?unbound) && true`, `"true"^^<http://www.w3.org/2001/XMLSchema#boolean>`},
+		{`?unbound || true`, `"true"^^<http://www.w3.org/2001/XMLSchema#boolean>`},
+		{`DATATYPE("x"@en)`, `<http://www.w3.org/1999/02/22-rdf-syntax-ns#langString>`},
+		{`STRDT("5", xsd:integer) = 5`, `"true"^^<http://www.w3.org/2001/XMLSchema#boolean>`},
+		{`MD5("abc")`, `"900150983cd24fb0d6963f7d28e17f72"`},
+		{`xsd:integer("042")`, `"42"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		{`xsd:boolean(0)`, `"false"^^<http://www.w3.org/2001/XMLSchema#boolean>`},
+		{`IRI("x")`, `<http://example.org/base/x>`},
+		{`isNUMERIC("1")`, `"false"^^<http://www.w3.org/2001/XMLSchema#boolean>`},
+		{`"abc" < "abd"`, `"true"^^<http://www.w3.org/2001/XMLSchema#boolean>`},
+	}
+
+	for _, tt := range tests {
+		query := testPrefixes + "BASE <http://example.org/base/> SELECT ?v { BIND(" + tt.expr + " AS ?v) }"
+		res, err := Exec(triple.NewGraph(nil), query, ParseOptions{})
+		if err != nil {
+			t.Fatalf("%s: Exec() error = %v", tt.expr, err)
+		}
+		if got := termKey(res.Bindings[0]["v"]); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	g := triple.NewGraph(nil)
+
+	for _, expr := range []string{`1 / 0`, `"a" < 1`, `?unbound`, `"x"^^<http://example.org/t> = "y"^^<http://example.org/t>`, `UCASE(<http://example.org/>)`} {
+		res, err := Exec(g, "SELECT * { FILTER("+expr+") }", ParseOptions{})
+		if err != nil {
+			t.Fatalf("%s: Exec() error = %v", expr, err)
+		}
+		if len(res.Bindings) != 0 {
+			t.Errorf("FILTER(%s) kept the solution", expr)
+		}
+	}
+}
+
+func TestPropertyPaths(t *testing.T) {
+	g := testGraph(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "one or more",
+			query: `SELECT ?x { ex:alice foaf:knows+ ?x } ORDER BY ?x`,
+			want:  []string{"x=bob", "x=carol"},
+		},
+		{
+			name:  "zero or more from the object",
+			query: `SELECT ?x { ?x foaf:knows* ex:carol } ORDER BY ?x`,
+			want:  []string{"x=alice", "x=bob", "x=carol"},
+		},
+		{
+			name:  "sequence and inverse",
+			query: `SELECT ?n { ex:carol ^foaf:knows/foaf:name ?n } ORDER BY STR(?n)`,
+			want:  []string{`n="Alice"`, `n="Bob"@en`},
+		},
+		{
+			name:  "alternative",
+			query: `SELECT ?v { ex:carol (foaf:name|foaf:mbox) ?v } ORDER BY ?v`,
+			want:  []string{"v=mailto:carol@example.org", `v="Carol"`},
+		},
+		{
+			name:  "zero or one",
+			query: `SELECT ?x { ex:bob foaf:knows? ?x } ORDER BY ?x`,
+			want:  []string{"x=bob", "x=carol"},
+		},
+		{
+			name:  "negated property set",
+			query: `SELECT DISTINCT ?p { ?p !(a|foaf:name|foaf:knows|foaf:age) ?o } ORDER BY ?p`,
+			want:  []string{"p=carol", "p=doc1"},
+		},
+		{
+			name:  "rdf:type with a",
+			query: `ASK { ex:alice a/^a ex:bob }`,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Exec(g, testPrefixes+tt.query, ParseOptions{})
+			if err != nil {
+				t.Fatalf("Exec() error = %v", err)
+			}
+			if res.Form == Ask {
+				if !res.Boolean {
+					t.Errorf("Exec() = false, want true")
+				}
+				return
+			}
+			if got := rows(res); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Exec() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+
+	_, err := Parse(`CONSTRUCT { ?s <p>+ ?o } WHERE { ?s <p> ?o }`, ParseOptions{})
+	if err == nil || !strings.Contains(err.Error(), "property paths are not allowed in a template") {
+		t.Errorf("Parse(path in template) error = %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package sparql
+
+import (
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+type expression interface {
+	eval(ctx *evalContext, b Binding) (triple.Node, error)
+}
+
+type varExpr struct {
+	name string
+}
+
+func (e varExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	if n, ok := ctx.lookup(b, e.name); ok {
+		return n, nil
+	}
+	return nil, typeErrorf("?%s is unbound", e.name)
+}
+
+type constExpr struct {
+	node triple.Node
+}
+
+func (e constExpr) eval(*evalContext, Binding) (triple.Node, error) {
+	return e.node, nil
+}
+
+// orExpr and andExpr follow the SPARQL error rules: an error on one side is
+// masked when the other side decides the result.
+type orExpr struct {
+	left, right expression
+}
+
+func (e orExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	l, lerr := evalBoolean(ctx, e.left, b)
+	if lerr == nil && l {
+		return triple.NewBooleanLiteral(true), nil
+	}
+	r, rerr := evalBoolean(ctx, e.right, b)
+	switch {
+	case rerr == nil && r:
+		return triple.NewBooleanLiteral(true), nil
+	case lerr != nil:
+		return nil, lerr
+	case rerr != nil:
+		return nil, rerr
+	}
+	return triple.NewBooleanLiteral(false), nil
+}
+
+type andExpr struct {
+	left, right expression
+}
+
+func (e andExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	l, lerr := evalBoolean(ctx, e.left, b)
+	if lerr == nil && !l {
+		return triple.NewBooleanLiteral(false), nil
+	}
+	r, rerr := evalBoolean(ctx, e.right, b)
+	switch {
+	case rerr == nil && !r:
+		return triple.NewBooleanLiteral(false), nil
+	case lerr != nil:
+		return nil, lerr
+	case rerr != nil:
+		return nil, rerr
+	}
+	return triple.NewBooleanLiteral(true), nil
+}
+
+type notExpr struct {
+	operand expression
+}
+
+func (e notExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	v, err := evalBoolean(ctx, e.operand, b)
+	if err != nil {
+		return nil, err
+	}
+	return triple.NewBooleanLiteral(!v), nil
+}
+
+func evalBoolean(ctx *evalContext, expr expression, b Binding) (bool, error) {
+	v, err := expr.eval(ctx, b)
+	if err != nil {
+		return false, err
+	}
+	return toBoolean(v)
+}
+
+type compareExpr struct {
+	op          string
+	left, right expression
+}
+
+func (e compareExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	l, err := e.left.eval(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.right.eval(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var result bool
+	switch e.op {
+	case "=", "!=":
+		eq, err := equalValues(l, r)
+		if err != nil {
+			return nil, err
+		}
+		result = eq == (e.op == "=")
+	default:
+		c, err := compareValues(l, r)
+		if err != nil {
+			return nil, err
+		}
+		switch e.op {
+		case "<":
+			result = c < 0
+		case ">":
+			result = c > 0
+		case "<=":
+			result = c <= 0
+		case ">=":
+			result = c >= 0
+		}
+	}
+	return triple.NewBooleanLiteral(result), nil
+}
+
+type arithmeticExpr struct {
+	op          string
+	left, right expression
+}
+
+func (e arithmeticExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	l, err := evalNumber(ctx, e.left, b)
+	if err != nil {
+		return nil, err
+	}
+	r, err := evalNumber(ctx, e.right, b)
+	if err != nil {
+		return nil, err
+	}
+	n, err := arithmetic(e.op, l, r)
+	if err != nil {
+		return nil, err
+	}
+	return n.literal(), nil
+}
+
+// negateExpr is unary minus, or unary plus when plus is set. Both require a
+// numeric operand.
+type negateExpr struct {
+	operand expression
+	plus    bool
+}
+
+func (e negateExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	n, err := evalNumber(ctx, e.operand, b)
+	if err != nil {
+		return nil, err
+	}
+	if !e.plus {
+		n.i, n.f = -n.i, -n.f
+	}
+	return n.literal(), nil
+}
+
+func evalNumber(ctx *evalContext, expr expression, b Binding) (number, error) {
+	v, err := expr.eval(ctx, b)
+	if err != nil {
+		return number{}, err
+	}
+	return toNumber(v)
+}
+
+type inExpr struct {
+	operand expression
+	list    []expression
+	negated bool
+}
+
+// eval returns true as soon as a member equals the operand. Otherwise an
+// error comparing any member is the result, as for a chain of ||.
+func (e inExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	v, err := e.operand.eval(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+	for _, member := range e.list {
+		m, err := member.eval(ctx, b)
+		if err == nil {
+			var eq bool
+			if eq, err = equalValues(v, m); err == nil && eq {
+				return triple.NewBooleanLiteral(!e.negated), nil
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return triple.NewBooleanLiteral(e.negated), nil
+}
+
+type boundExpr struct {
+	name string
+}
+
+func (e boundExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	_, ok := ctx.lookup(b, e.name)
+	return triple.NewBooleanLiteral(ok), nil
+}
+
+type ifExpr struct {
+	cond, then, otherwise expression
+}
+
+func (e ifExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	cond, err := evalBoolean(ctx, e.cond, b)
+	if err != nil {
+		return nil, err
+	}
+	if cond {
+		return e.then.eval(ctx, b)
+	}
+	return e.otherwise.eval(ctx, b)
+}
+
+type coalesceExpr struct {
+	args []expression
+}
+
+func (e coalesceExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	for _, arg := range e.args {
+		if v, err := arg.eval(ctx, b); err == nil {
+			return v, nil
+		}
+	}
+	return nil, typeErrorf("COALESCE has no bound argument")
+}
+
+type existsExpr struct {
+	pattern pattern
+	negated bool
+}
+
+func (e existsExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	inner := *ctx
+	inner.outer = merge(ctx.outer, b)
+	solutions, err := e.pattern.eval(&inner)
+	if err != nil {
+		return nil, err
+	}
+	return triple.NewBooleanLiteral((len(solutions) > 0) != e.negated), nil
+}
+
+// callExpr calls a built-in function or an XSD cast with its evaluated
+// arguments.
+type callExpr struct {
+	fn   builtin
+	args []expression
+}
+
+func (e callExpr) eval(ctx *evalContext, b Binding) (triple.Node, error) {
+	args := make([]triple.Node, len(e.args))
+	for i, arg := range e.args {
+		v, err := arg.eval(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return e.fn(ctx, b, args)
+}
@@ -0,0 +1,747 @@
+package sparql
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"math"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+type builtin func(ctx *evalContext, b Binding, args []triple.Node) (triple.Node, error)
+
+type function struct {
+	minArgs, maxArgs int
+	fn               builtin
+}
+
+// variadic marks a function with no upper bound on its arguments.
+const variadic = -1
+
+// builtins holds the functions called with evaluated arguments, keyed by
+// upper-case name. BOUND, IF, COALESCE, EXISTS, IN and the operators have
+// their own expression types because they control evaluation.
+var builtins = map[string]function{
+	"STR":            {1, 1, fnStr},
+	"LANG":           {1, 1, fnLang},
+	"LANGDIR":        {1, 1, fnLangDir},
+	"LANGMATCHES":    {2, 2, fnLangMatches},
+	"DATATYPE":       {1, 1, fnDatatype},
+	"IRI":            {1, 1, fnIRI},
+	"URI":            {1, 1, fnIRI},
+	"BNODE":          {0, 1, fnBNode},
+	"RAND":           {0, 0, fnRand},
+	"ABS":            {1, 1, numericFunction(math.Abs)},
+	"CEIL":           {1, 1, numericFunction(math.Ceil)},
+	"FLOOR":          {1, 1, numericFunction(math.Floor)},
+	"ROUND":          {1, 1, numericFunction(func(f float64) float64 { return math.Floor(f + 0.5) })},
+	"CONCAT":         {0, variadic, fnConcat},
+	"SUBSTR":         {2, 3, fnSubstr},
+	"STRLEN":         {1, 1, fnStrlen},
+	"REPLACE":        {3, 4, fnReplace},
+	"UCASE":          {1, 1, stringFunction(strings.ToUpper)},
+	"LCASE":          {1, 1, stringFunction(strings.ToLower)},
+	"ENCODE_FOR_URI": {1, 1, fnEncodeForURI},
+	"CONTAINS":       {2, 2, stringTest(strings.Contains)},
+	"STRSTARTS":      {2, 2, stringTest(strings.HasPrefix)},
+	"STRENDS":        {2, 2, stringTest(strings.HasSuffix)},
+	"STRBEFORE":      {2, 2, fnStrBefore},
+	"STRAFTER":       {2, 2, fnStrAfter},
+	"YEAR":           {1, 1, dateTimeField(func(v dateTimeParts) int { return v.year })},
+	"MONTH":          {1, 1, dateTimeField(func(v dateTimeParts) int { return v.month })},
+	"DAY":            {1, 1, dateTimeField(func(v dateTimeParts) int { return v.day })},
+	"HOURS":          {1, 1, dateTimeField(func(v dateTimeParts) int { return v.hour })},
+	"MINUTES":        {1, 1, dateTimeField(func(v dateTimeParts) int { return v.minute })},
+	"SECONDS":        {1, 1, fnSeconds},
+	"TIMEZONE":       {1, 1, fnTimezone},
+	"TZ":             {1, 1, fnTZ},
+	"NOW":            {0, 0, fnNow},
+	"UUID":           {0, 0, fnUUID},
+	"STRUUID":        {0, 0, fnStrUUID},
+	"MD5":            {1, 1, hashFunction(md5.New)},
+	"SHA1":           {1, 1, hashFunction(sha1.New)},
+	"SHA256":         {1, 1, hashFunction(sha256.New)},
+	"SHA384":         {1, 1, hashFunction(sha512.New384)},
+	"SHA512":         {1, 1, hashFunction(sha512.New)},
+	"STRLANG":        {2, 2, fnStrLang},
+	"STRLANGDIR":     {3, 3, fnStrLangDir},
+	"STRDT":          {2, 2, fnStrDT},
+	"SAMETERM":       {2, 2, fnSameTerm},
+	"ISIRI":          {1, 1, termTest(func(n triple.Node) bool { _, ok := n.(triple.IRI); return ok })},
+	"ISURI":          {1, 1, termTest(func(n triple.Node) bool { _, ok := n.(triple.IRI); return ok })},
+	"ISBLANK":        {1, 1, termTest(func(n triple.Node) bool { _, ok := n.(triple.BlankNode); return ok })},
+	"ISLITERAL":      {1, 1, termTest(func(n triple.Node) bool { _, ok := n.(triple.Literal); return ok })},
+	"ISNUMERIC":      {1, 1, termTest(func(n triple.Node) bool { _, err := toNumber(n); return err == nil })},
+	"ISTRIPLE":       {1, 1, termTest(func(n triple.Node) bool { _, ok := n.(triple.TripleTerm); return ok })},
+	"REGEX":          {2, 3, fnRegex},
+	"TRIPLE":         {3, 3, fnTriple},
+	"SUBJECT":        {1, 1, tripleComponent(func(t triple.Triple) triple.Node { return t.Subject })},
+	"PREDICATE":      {1, 1, tripleComponent(func(t triple.Triple) triple.Node { return t.Predicate })},
+	"OBJECT":         {1, 1, tripleComponent(func(t triple.Triple) triple.Node { return t.Object })},
+}
+
+// casts are the XSD constructor functions, such as xsd:integer(?x).
+var casts = map[string]bool{
+	xsd.String:   true,
+	xsd.Integer:  true,
+	xsd.Decimal:  true,
+	xsd.Double:   true,
+	xsd.Float:    true,
+	xsd.Boolean:  true,
+	xsd.DateTime: true,
+	xsd.Date:     true,
+}
+
+func simpleLiteral(s string) triple.Literal {
+	return triple.Literal{Value: s}
+}
+
+func fnStr(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	switch n := args[0].(type) {
+	case triple.IRI:
+		return simpleLiteral(n.Value), nil
+	case triple.Literal:
+		return simpleLiteral(n.Value), nil
+	}
+	return nil, typeErrorf("STR of %s", termKey(args[0]))
+}
+
+func fnLang(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	lit, ok := args[0].(triple.Literal)
+	if !ok {
+		return nil, typeErrorf("LANG of %s", termKey(args[0]))
+	}
+	return simpleLiteral(lit.Language), nil
+}
+
+func fnLangDir(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	lit, ok := args[0].(triple.Literal)
+	if !ok {
+		return nil, typeErrorf("LANGDIR of %s", termKey(args[0]))
+	}
+	return simpleLiteral(lit.Direction), nil
+}
+
+func fnLangMatches(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	tag, err := plainString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	langRange, err := plainString(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var match bool
+	switch {
+	case langRange == "*":
+		match = tag != ""
+	default:
+		tag, langRange = strings.ToLower(tag), strings.ToLower(langRange)
+		match = tag == langRange || strings.HasPrefix(tag, langRange+"-")
+	}
+	return triple.NewBooleanLiteral(match), nil
+}
+
+func fnDatatype(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	lit, ok := args[0].(triple.Literal)
+	if !ok {
+		return nil, typeErrorf("DATATYPE of %s", termKey(args[0]))
+	}
+	return triple.IRI{Value: lit.DatatypeIRI()}, nil
+}
+
+func fnIRI(ctx *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	switch n := args[0].(type) {
+	case triple.IRI:
+		return n, nil
+	case triple.Literal:
+		if !isStringLiteral(n) {
+			break
+		}
+		if ctx.base == "" {
+			return triple.IRI{Value: n.Value}, nil
+		}
+		base, err := url.Parse(ctx.base)
+		if err != nil {
+			return nil, typeErrorf("invalid base %s", ctx.base)
+		}
+		ref, err := url.Parse(n.Value)
+		if err != nil {
+			return nil, typeErrorf("invalid IRI %q", n.Value)
+		}
+		return triple.IRI{Value: base.ResolveReference(ref).String()}, nil
+	}
+	return nil, typeErrorf("IRI of %s", termKey(args[0]))
+}
+
+func fnBNode(ctx *evalContext, b Binding, args []triple.Node) (triple.Node, error) {
+	if len(args) == 0 {
+		return ctx.newBlankNode(), nil
+	}
+
+	label, err := plainString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	// The same label gives the same blank node within one solution.
+	key := fmt.Sprintf("%p %s", b, label)
+	node, ok := ctx.bnodeLabels[key]
+	if !ok {
+		node = ctx.newBlankNode()
+		ctx.bnodeLabels[key] = node
+	}
+	return node, nil
+}
+
+func fnRand(*evalContext, Binding, []triple.Node) (triple.Node, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1<<53))
+	if err != nil {
+		return nil, err
+	}
+	return triple.NewDoubleLiteral(float64(n.Int64()) / (1 << 53)), nil
+}
+
+// numericFunction applies fn to non-integer numbers and keeps the type of
+// the argument.
+func numericFunction(fn func(float64) float64) builtin {
+	return func(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+		n, err := toNumber(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if n.kind == kindInteger {
+			return integerNumber(int64(fn(float64(n.i)))).literal(), nil
+		}
+		n.f = fn(n.f)
+		return n.literal(), nil
+	}
+}
+
+// stringArg returns a string literal argument: a simple literal, an
+// xsd:string or a language-tagged string.
+func stringArg(n triple.Node) (triple.Literal, error) {
+	lit, ok := n.(triple.Literal)
+	if !ok || (lit.Language == "" && !isStringLiteral(lit)) {
+		return triple.Literal{}, typeErrorf("%s is not a string", termKey(n))
+	}
+	return lit, nil
+}
+
+// plainString returns the value of a simple literal or xsd:string.
+func plainString(n triple.Node) (string, error) {
+	if !isStringLiteral(n) {
+		return "", typeErrorf("%s is not a simple literal", termKey(n))
+	}
+	return n.(triple.Literal).Value, nil
+}
+
+// compatibleArgs checks the two string arguments of CONTAINS and similar
+// functions: the second must be plain or share the first's language.
+func compatibleArgs(args []triple.Node) (triple.Literal, triple.Literal, error) {
+	a, err := stringArg(args[0])
+	if err != nil {
+		return a, a, err
+	}
+	b, err := stringArg(args[1])
+	if err != nil {
+		return a, b, err
+	}
+	if b.Language != "" && !strings.EqualFold(a.Language, b.Language) {
+		return a, b, typeErrorf("incompatible languages %q and %q", a.Language, b.Language)
+	}
+	return a, b, nil
+}
+
+func withValue(lit triple.Literal, value string) triple.Literal {
+	lit.Value = value
+	return lit
+}
+
+func stringFunction(fn func(string) string) builtin {
+	return func(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+		lit, err := stringArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return withValue(lit, fn(lit.Value)), nil
+	}
+}
+
+func stringTest(fn func(string, string) bool) builtin {
+	return func(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+		a, b, err := compatibleArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		return triple.NewBooleanLiteral(fn(a.Value, b.Value)), nil
+	}
+}
+
+func fnConcat(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	var sb strings.Builder
+	result := triple.Literal{}
+	for i, arg := range args {
+		lit, err := stringArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		sb.WriteString(lit.Value)
+		lit.Value = ""
+		if i == 0 {
+			result = lit
+		} else if lit != result {
+			result = triple.Literal{}
+		}
+	}
+	return withValue(result, sb.String()), nil
+}
+
+func fnSubstr(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	lit, err := stringArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	start, err := toNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	runes := []rune(lit.Value)
+	// Positions are 1-based and rounded, as in XPath fn:substring.
+	from := math.Floor(start.promote(kindDouble).f + 0.5)
+	to := math.Inf(1)
+	if len(args) == 3 {
+		length, err := toNumber(args[2])
+		if err != nil {
+			return nil, err
+		}
+		to = from + math.Floor(length.promote(kindDouble).f+0.5)
+	}
+
+	var sb strings.Builder
+	for i, r := range runes {
+		if pos := float64(i + 1); pos >= from && pos < to {
+			sb.WriteRune(r)
+		}
+	}
+	return withValue(lit, sb.String()), nil
+}
+
+func fnStrlen(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	lit, err := stringArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return triple.NewIntegerLiteral(int64(utf8.RuneCountInString(lit.Value))), nil
+}
+
+func fnReplace(ctx *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	lit, err := stringArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexArg(ctx, args[1], args[3:])
+	if err != nil {
+		return nil, err
+	}
+	replacement, err := plainString(args[2])
+	if err != nil {
+		return nil, err
+	}
+	return withValue(lit, re.ReplaceAllString(lit.Value, replacement)), nil
+}
+
+func fnRegex(ctx *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	lit, err := stringArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexArg(ctx, args[1], args[2:])
+	if err != nil {
+		return nil, err
+	}
+	return triple.NewBooleanLiteral(re.MatchString(lit.Value)), nil
+}
+
+// regexArg compiles an XPath regular expression with optional flags; the
+// flags i, m, s and x map onto Go's.
+func regexArg(ctx *evalContext, patternArg triple.Node, flagArgs []triple.Node) (*regexp.Regexp, error) {
+	expr, err := plainString(patternArg)
+	if err != nil {
+		return nil, err
+	}
+	flags := ""
+	if len(flagArgs) > 0 {
+		if flags, err = plainString(flagArgs[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	key := flags + "/" + expr
+	if re, ok := ctx.regexps[key]; ok {
+		return re, nil
+	}
+
+	var goFlags string
+	for _, f := range flags {
+		switch f {
+		case 'i', 'm', 's':
+			goFlags += string(f)
+		case 'x':
+			expr = regexp.MustCompile(`\s+`).ReplaceAllString(expr, "")
+		case 'q':
+			expr = regexp.QuoteMeta(expr)
+		default:
+			return nil, typeErrorf("unsupported regex flag %q", f)
+		}
+	}
+	if goFlags != "" {
+		expr = "(?" + goFlags + ")" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errType, err)
+	}
+	ctx.regexps[key] = re
+	return re, nil
+}
+
+func fnEncodeForURI(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	lit, err := stringArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	for _, c := range []byte(lit.Value) {
+		if isASCIILetter(c) || isASCIIDigit(c) || strings.IndexByte("-._~", c) >= 0 {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return simpleLiteral(sb.String()), nil
+}
+
+func fnStrBefore(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	a, b, err := compatibleArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	before, _, found := strings.Cut(a.Value, b.Value)
+	if !found {
+		return simpleLiteral(""), nil
+	}
+	return withValue(a, before), nil
+}
+
+func fnStrAfter(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	a, b, err := compatibleArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	_, after, found := strings.Cut(a.Value, b.Value)
+	if !found {
+		return simpleLiteral(""), nil
+	}
+	return withValue(a, after), nil
+}
+
+type dateTimeParts struct {
+	year, month, day, hour, minute int
+	second                         float64
+	// zone is "Z", "+hh:mm", "-hh:mm" or empty.
+	zone string
+}
+
+var dateTimeZone = regexp.MustCompile(`(Z|[+-][0-9]{2}:[0-9]{2})$`)
+
+func dateTimeArg(n triple.Node) (dateTimeParts, error) {
+	t, lit, err := timeOf(n)
+	if err != nil {
+		return dateTimeParts{}, err
+	}
+	return dateTimeParts{
+		year:   t.Year(),
+		month:  int(t.Month()),
+		day:    t.Day(),
+		hour:   t.Hour(),
+		minute: t.Minute(),
+		second: float64(t.Second()) + float64(t.Nanosecond())/1e9,
+		zone:   dateTimeZone.FindString(lit.Value),
+	}, nil
+}
+
+func dateTimeField(field func(dateTimeParts) int) builtin {
+	return func(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+		v, err := dateTimeArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return triple.NewIntegerLiteral(int64(field(v))), nil
+	}
+}
+
+func fnSeconds(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	v, err := dateTimeArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return triple.NewDecimalLiteral(v.second), nil
+}
+
+func fnTimezone(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	v, err := dateTimeArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if v.zone == "" {
+		return nil, typeErrorf("%s has no timezone", termKey(args[0]))
+	}
+	if v.zone == "Z" {
+		return triple.Literal{Value: "PT0S", Datatype: xsd.Namespace + "dayTimeDuration"}, nil
+	}
+
+	hours, _ := strconv.Atoi(v.zone[1:3])
+	minutes, _ := strconv.Atoi(v.zone[4:6])
+	var sb strings.Builder
+	if v.zone[0] == '-' {
+		sb.WriteByte('-')
+	}
+	sb.WriteString("PT")
+	if hours > 0 {
+		fmt.Fprintf(&sb, "%dH", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&sb, "%dM", minutes)
+	}
+	if hours == 0 && minutes == 0 {
+		sb.WriteString("0S")
+	}
+	return triple.Literal{Value: sb.String(), Datatype: xsd.Namespace + "dayTimeDuration"}, nil
+}
+
+func fnTZ(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	v, err := dateTimeArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return simpleLiteral(v.zone), nil
+}
+
+func fnNow(ctx *evalContext, _ Binding, _ []triple.Node) (triple.Node, error) {
+	return triple.NewDateTimeLiteral(ctx.now), nil
+}
+
+func newUUID() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	h := hex.EncodeToString(u[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+func fnUUID(*evalContext, Binding, []triple.Node) (triple.Node, error) {
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	return triple.IRI{Value: "urn:uuid:" + id}, nil
+}
+
+func fnStrUUID(*evalContext, Binding, []triple.Node) (triple.Node, error) {
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	return simpleLiteral(id), nil
+}
+
+func hashFunction(newHash func() hash.Hash) builtin {
+	return func(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+		s, err := plainString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		h := newHash()
+		h.Write([]byte(s))
+		return simpleLiteral(hex.EncodeToString(h.Sum(nil))), nil
+	}
+}
+
+func fnStrLang(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	s, err := plainString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	lang, err := plainString(args[1])
+	if err != nil {
+		return nil, err
+	}
+	lit, err := triple.NewLangLiteral(s, lang)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errType, err)
+	}
+	return lit, nil
+}
+
+func fnStrLangDir(ctx *evalContext, b Binding, args []triple.Node) (triple.Node, error) {
+	lit, err := fnStrLang(ctx, b, args[:2])
+	if err != nil {
+		return nil, err
+	}
+	dir, err := plainString(args[2])
+	if err != nil {
+		return nil, err
+	}
+	if dir != "ltr" && dir != "rtl" {
+		return nil, typeErrorf("invalid direction %q", dir)
+	}
+	l := lit.(triple.Literal)
+	l.Direction = dir
+	return l, nil
+}
+
+func fnStrDT(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	s, err := plainString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	datatype, ok := args[1].(triple.IRI)
+	if !ok {
+		return nil, typeErrorf("STRDT datatype %s is not an IRI", termKey(args[1]))
+	}
+	return triple.Literal{Value: s, Datatype: datatype.Value}, nil
+}
+
+func fnSameTerm(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	return triple.NewBooleanLiteral(args[0] == args[1]), nil
+}
+
+func termTest(test func(triple.Node) bool) builtin {
+	return func(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+		return triple.NewBooleanLiteral(test(args[0])), nil
+	}
+}
+
+func fnTriple(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+	t := triple.Triple{Subject: args[0], Predicate: args[1], Object: args[2]}
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errType, err)
+	}
+	return triple.TripleTerm{Triple: t}, nil
+}
+
+func tripleComponent(component func(triple.Triple) triple.Node) builtin {
+	return func(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+		term, ok := args[0].(triple.TripleTerm)
+		if !ok {
+			return nil, typeErrorf("%s is not a triple term", termKey(args[0]))
+		}
+		return component(term.Triple), nil
+	}
+}
+
+func castFunction(datatype string) builtin {
+	return func(_ *evalContext, _ Binding, args []triple.Node) (triple.Node, error) {
+		return cast(args[0], datatype)
+	}
+}
+
+// cast converts n to datatype following the XPath casting rules that SPARQL
+// requires for the XSD constructor functions.
+func cast(n triple.Node, datatype string) (triple.Node, error) {
+	var lit triple.Literal
+	switch node := n.(type) {
+	case triple.IRI:
+		if datatype == xsd.String {
+			return triple.Literal{Value: node.Value, Datatype: xsd.String}, nil
+		}
+		return nil, typeErrorf("cannot cast an IRI to %s", datatype)
+	case triple.Literal:
+		if node.Language != "" {
+			return nil, typeErrorf("cannot cast a language-tagged string to %s", datatype)
+		}
+		lit = node
+	default:
+		return nil, typeErrorf("cannot cast %s", termKey(n))
+	}
+
+	if datatype == xsd.String {
+		return triple.Literal{Value: lit.Value, Datatype: xsd.String}, nil
+	}
+
+	if isNumeric(lit) {
+		num, err := toNumber(lit)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case datatype == xsd.Boolean:
+			return triple.NewBooleanLiteral(num.promote(kindDouble).f != 0 && !math.IsNaN(num.promote(kindDouble).f)), nil
+		case datatype == xsd.Integer, datatype == xsd.Decimal:
+			f := num.promote(kindDouble).f
+			if num.kind != kindInteger && (math.IsNaN(f) || math.IsInf(f, 0)) {
+				return nil, typeErrorf("cannot cast %s to %s", lit.Value, datatype)
+			}
+			if datatype == xsd.Integer {
+				if num.kind == kindInteger {
+					return num.literal(), nil
+				}
+				return triple.NewIntegerLiteral(int64(f)), nil
+			}
+			return triple.NewDecimalLiteral(f), nil
+		case datatype == xsd.Double:
+			return triple.NewDoubleLiteral(num.promote(kindDouble).f), nil
+		case datatype == xsd.Float:
+			return number{kind: kindFloat, f: num.promote(kindDouble).f}.literal(), nil
+		}
+		return nil, typeErrorf("cannot cast %s to %s", lit.Value, datatype)
+	}
+
+	if lit.Datatype == xsd.Boolean && xsd.IsNumeric(datatype) {
+		b, err := lit.Bool()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errType, err)
+		}
+		return number{kind: kindInteger, i: boolInt(b), f: float64(boolInt(b))}.promote(numericKindOf(datatype)).literal(), nil
+	}
+
+	if !isStringLiteral(lit) && lit.Datatype != datatype {
+		return nil, typeErrorf("cannot cast %s to %s", termKey(lit), datatype)
+	}
+	canonical, err := xsd.Canonical(datatype, strings.TrimSpace(lit.Value))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errType, err)
+	}
+	return triple.Literal{Value: canonical, Datatype: datatype}, nil
+}
+
+func numericKindOf(datatype string) numericKind {
+	switch datatype {
+	case xsd.Decimal:
+		return kindDecimal
+	case xsd.Float:
+		return kindFloat
+	case xsd.Double:
+		return kindDouble
+	}
+	return kindInteger
+}
@@ -0,0 +1,264 @@
+package sparql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIRI
+	tokPrefixedName
+	tokVar
+	tokBlankNode
+	tokString
+	tokLangTag
+	tokInteger
+	tokDecimal
+	tokDouble
+	tokWord
+	tokPunct
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	line   int
+	column int
+}
+
+func (t token) is(text string) bool {
+	return t.kind == tokPunct && t.text == text
+}
+
+// isWord reports whether t is the keyword word, ignoring case.
+func (t token) isWord(word string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, word)
+}
+
+var (
+	sparqlNumber = regexp.MustCompile(`^([0-9]+\.[0-9]*[eE][+-]?[0-9]+|\.[0-9]+[eE][+-]?[0-9]+|[0-9]+[eE][+-]?[0-9]+|[0-9]*\.[0-9]+|[0-9]+)`)
+	sparqlIRI    = regexp.MustCompile(`^<[^<>"{}|^` + "`" + `\\\x00-\x20]*>`)
+)
+
+// Punctuation and operators, longest first.
+var punctuation = []string{
+	"<<(", ")>>", "^^", "&&", "||", "!=", "<=", ">=",
+	"{", "}", "(", ")", "[", "]", ".", ",", ";", "*", "+", "-", "/",
+	"!", "=", "<", ">", "|", "^", "?",
+}
+
+type lexer struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+func tokenize(src string) ([]token, error) {
+	lx := &lexer{src: src, line: 1, column: 1}
+	var tokens []token
+
+	for {
+		lx.skipSpace()
+		if lx.pos >= len(lx.src) {
+			tokens = append(tokens, token{kind: tokEOF, line: lx.line, column: lx.column})
+			return tokens, nil
+		}
+		tok, err := lx.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+func (lx *lexer) error(msg string) error {
+	return fmt.Errorf("%s at line %d, column %d", msg, lx.line, lx.column)
+}
+
+func (lx *lexer) emit(kind tokenKind, length int) token {
+	tok := token{kind: kind, text: lx.src[lx.pos : lx.pos+length], line: lx.line, column: lx.column}
+	lx.advance(length)
+	return tok
+}
+
+func (lx *lexer) advance(length int) {
+	for _, ch := range lx.src[lx.pos : lx.pos+length] {
+		if ch == '\n' {
+			lx.line++
+			lx.column = 1
+		} else {
+			lx.column++
+		}
+	}
+	lx.pos += length
+}
+
+func (lx *lexer) skipSpace() {
+	for lx.pos < len(lx.src) {
+		switch ch := lx.src[lx.pos]; {
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+			lx.advance(1)
+		case ch == '#':
+			n := strings.IndexByte(lx.src[lx.pos:], '\n')
+			if n == -1 {
+				n = len(lx.src) - lx.pos
+			}
+			lx.advance(n)
+		default:
+			return
+		}
+	}
+}
+
+func (lx *lexer) next() (token, error) {
+	rest := lx.src[lx.pos:]
+	ch := rest[0]
+
+	switch {
+	case ch == '<' && !strings.HasPrefix(rest, "<<("):
+		if m := sparqlIRI.FindString(rest); m != "" {
+			return lx.emit(tokIRI, len(m)), nil
+		}
+	case ch == '"' || ch == '\'':
+		n, err := lx.scanString(rest)
+		if err != nil {
+			return token{}, err
+		}
+		return lx.emit(tokString, n), nil
+	case ch == '@':
+		n := 1
+		for n < len(rest) && (isASCIILetter(rest[n]) || (n > 1 && (rest[n] == '-' || isASCIIDigit(rest[n])))) {
+			n++
+		}
+		if n == 1 {
+			return token{}, lx.error("expected language tag after @")
+		}
+		return lx.emit(tokLangTag, n), nil
+	case ch == '?' || ch == '$':
+		if n := scanVarName(rest[1:]); n > 0 {
+			return lx.emit(tokVar, 1+n), nil
+		}
+	case strings.HasPrefix(rest, "_:"):
+		n := 2 + scanName(rest[2:])
+		if n == 2 {
+			return token{}, lx.error("empty blank node label")
+		}
+		return lx.emit(tokBlankNode, n), nil
+	case isASCIIDigit(ch) || (ch == '.' && len(rest) > 1 && isASCIIDigit(rest[1])):
+		m := sparqlNumber.FindString(rest)
+		kind := tokInteger
+		if strings.ContainsAny(m, "eE") {
+			kind = tokDouble
+		} else if strings.Contains(m, ".") {
+			kind = tokDecimal
+		}
+		return lx.emit(kind, len(m)), nil
+	}
+
+	for _, p := range punctuation {
+		if strings.HasPrefix(rest, p) {
+			return lx.emit(tokPunct, len(p)), nil
+		}
+	}
+
+	n := scanName(rest)
+	if n == 0 {
+		r, _ := utf8.DecodeRuneInString(rest)
+		return token{}, lx.error(fmt.Sprintf("unexpected character %q", r))
+	}
+	if strings.Contains(rest[:n], ":") {
+		return lx.emit(tokPrefixedName, n), nil
+	}
+	return lx.emit(tokWord, n), nil
+}
+
+func (lx *lexer) scanString(rest string) (int, error) {
+	quote := rest[:1]
+	long := strings.Repeat(quote, 3)
+
+	if strings.HasPrefix(rest, long) {
+		for i := 3; i < len(rest); i++ {
+			if rest[i] == '\\' {
+				i++
+				continue
+			}
+			if strings.HasPrefix(rest[i:], long) {
+				end := i + 3
+				for end < len(rest) && rest[end:end+1] == quote {
+					end++
+				}
+				return end, nil
+			}
+		}
+		return 0, lx.error("unclosed long string")
+	}
+
+	for i := 1; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			i++
+		case '\n', '\r':
+			return 0, lx.error("newline in string")
+		case quote[0]:
+			return i + 1, nil
+		}
+	}
+	return 0, lx.error("unclosed string")
+}
+
+func scanVarName(s string) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r != '_' && r != 0xB7 && !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// scanName returns the length of the prefixed name, keyword or blank node
+// label at the start of s.
+func scanName(s string) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case r == '\\' && n+1 < len(s):
+			n += 2
+			continue
+		case r == '%' && n+2 < len(s):
+			n += 3
+			continue
+		case r == '_' || r == ':' || r == 0xB7, unicode.IsLetter(r), unicode.IsDigit(r), unicode.Is(unicode.Mn, r):
+		case (r == '-' || r == '.') && n > 0:
+		default:
+			return trimTrailingDots(s, n)
+		}
+		n += size
+	}
+	return trimTrailingDots(s, n)
+}
+
+func trimTrailingDots(s string, n int) int {
+	for n > 0 && s[n-1] == '.' {
+		n--
+	}
+	return n
+}
+
+func isASCIILetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isASCIIDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
@@ -0,0 +1,1579 @@
+package sparql
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+type parser struct {
+	tokens   []token
+	pos      int
+	resolver *encoder.PrefixResolver
+	base     string
+	// anon numbers the variables that stand for [] and collection cells.
+	anon int
+	// aggregates collects the aggregate calls of the query being parsed;
+	// allowAggregates is set where they may appear.
+	aggregates      []*aggregateExpr
+	allowAggregates bool
+}
+
+// Parse parses a SPARQL query. Prefixed names are expanded with the query's
+// PREFIX declarations and opts.Prefixes.
+func Parse(query string, opts ParseOptions) (*Query, error) {
+	p, err := newParser(query, opts)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseQuery()
+}
+
+func newParser(src string, opts ParseOptions) (*parser, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := make(map[string]string, len(opts.Prefixes))
+	for k, v := range opts.Prefixes {
+		prefixes[k] = v
+	}
+	return &parser{tokens: tokens, resolver: encoder.NewPrefixResolver(prefixes), base: opts.Base}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	tok := p.peek()
+	return fmt.Errorf("%s at line %d, column %d", fmt.Sprintf(format, args...), tok.line, tok.column)
+}
+
+func (p *parser) unexpected(want string) error {
+	tok := p.peek()
+	if tok.kind == tokEOF {
+		return p.errorf("expected %s, found end of query", want)
+	}
+	return p.errorf("expected %s, found %q", want, tok.text)
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek().is(punct) {
+		return p.unexpected(fmt.Sprintf("%q", punct))
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) expectWord(word string) error {
+	if !p.peek().isWord(word) {
+		return p.unexpected(word)
+	}
+	p.next()
+	return nil
+}
+
+// acceptWord consumes the next token if it is the keyword word.
+func (p *parser) acceptWord(word string) bool {
+	if p.peek().isWord(word) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) accept(punct string) bool {
+	if p.peek().is(punct) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	if err := p.parsePrologue(); err != nil {
+		return nil, err
+	}
+
+	q := &Query{limit: -1}
+	switch {
+	case p.acceptWord("SELECT"):
+		q.Form = Select
+		if err := p.parseSelectClause(q); err != nil {
+			return nil, err
+		}
+	case p.acceptWord("ASK"):
+		q.Form = Ask
+	case p.acceptWord("CONSTRUCT"):
+		q.Form = Construct
+	case p.acceptWord("DESCRIBE"):
+		q.Form = Describe
+		if err := p.parseDescribeClause(q); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.unexpected("SELECT, CONSTRUCT, DESCRIBE or ASK")
+	}
+
+	// CONSTRUCT WHERE { ... } uses its pattern as the template.
+	shortConstruct := q.Form == Construct && p.peek().isWord("WHERE")
+	if q.Form == Construct && !shortConstruct {
+		template, err := p.parseTriplesTemplate()
+		if err != nil {
+			return nil, err
+		}
+		q.template = template
+	}
+
+	if p.peek().isWord("FROM") {
+		return nil, p.errorf("FROM is not supported; the dataset is the graph the query runs against")
+	}
+
+	var err error
+	switch {
+	case shortConstruct:
+		p.next()
+		if q.template, err = p.parseTriplesTemplate(); err != nil {
+			return nil, err
+		}
+		q.where = &bgp{triples: q.template}
+	case q.Form == Describe && !p.peek().isWord("WHERE") && !p.peek().is("{"):
+		q.where = emptyGroup()
+	default:
+		p.acceptWord("WHERE")
+		if q.where, err = p.parseGroupGraphPattern(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.parseSolutionModifiers(q); err != nil {
+		return nil, err
+	}
+
+	if p.acceptWord("VALUES") {
+		if q.values, err = p.parseValues(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek().kind != tokEOF {
+		return nil, p.unexpected("end of query")
+	}
+
+	if err := p.finishQuery(q); err != nil {
+		return nil, err
+	}
+	q.Prefixes = p.resolver.All()
+	q.Base = p.base
+	return q, nil
+}
+
+// finishQuery fills in what depends on the whole query: the variables of
+// SELECT * and DESCRIBE *, and the aggregates. It checks that a grouped
+// SELECT only projects grouped variables.
+func (p *parser) finishQuery(q *Query) error {
+	q.aggregates = p.aggregates
+	grouped := q.groupBy != nil || len(q.aggregates) > 0
+
+	if (q.Form == Select || q.Form == Describe) && q.Vars == nil {
+		if grouped {
+			return fmt.Errorf("%s * cannot be used with GROUP BY or aggregates", q.Form)
+		}
+		q.Vars = inScope(q.where)
+		if q.values != nil {
+			q.Vars = inScope(&joinPattern{q.where, q.values})
+		}
+		if q.Form == Describe {
+			for _, name := range q.Vars {
+				q.describe = append(q.describe, term{name: name})
+			}
+		}
+	}
+
+	if grouped && q.Form == Select {
+		keys := make(map[string]bool, len(q.groupBy))
+		for _, cond := range q.groupBy {
+			keys[cond.name] = true
+		}
+		for _, proj := range q.projections {
+			if proj.expr == nil && !keys[proj.name] {
+				return fmt.Errorf("variable ?%s is projected but neither grouped nor aggregated", proj.name)
+			}
+		}
+	}
+	return nil
+}
+
+// parseSubSelect parses a SELECT query nested in a group graph pattern, up to
+// and including the closing brace.
+func (p *parser) parseSubSelect() (pattern, error) {
+	outerAggregates, outerAllow := p.aggregates, p.allowAggregates
+	p.aggregates, p.allowAggregates = nil, false
+	defer func() { p.aggregates, p.allowAggregates = outerAggregates, outerAllow }()
+
+	p.next()
+	q := &Query{Form: Select, limit: -1}
+	if err := p.parseSelectClause(q); err != nil {
+		return nil, err
+	}
+
+	p.acceptWord("WHERE")
+	var err error
+	if q.where, err = p.parseGroupGraphPattern(); err != nil {
+		return nil, err
+	}
+	if err := p.parseSolutionModifiers(q); err != nil {
+		return nil, err
+	}
+	if p.acceptWord("VALUES") {
+		if q.values, err = p.parseValues(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.finishQuery(q); err != nil {
+		return nil, err
+	}
+	return &subqueryPattern{query: q}, p.expect("}")
+}
+
+func (p *parser) parsePrologue() error {
+	for {
+		switch {
+		case p.acceptWord("BASE"):
+			tok := p.next()
+			if tok.kind != tokIRI {
+				return p.unexpected("IRI after BASE")
+			}
+			p.base = p.resolveIRI(tok.text)
+		case p.acceptWord("PREFIX"):
+			name := p.next()
+			if name.kind != tokPrefixedName || !strings.HasSuffix(name.text, ":") {
+				return p.unexpected("prefix name after PREFIX")
+			}
+			iri := p.next()
+			if iri.kind != tokIRI {
+				return p.unexpected("IRI after PREFIX " + name.text)
+			}
+			p.resolver.Set(strings.TrimSuffix(name.text, ":"), p.resolveIRI(iri.text))
+		default:
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseSelectClause(q *Query) error {
+	p.allowAggregates = true
+	defer func() { p.allowAggregates = false }()
+
+	switch {
+	case p.acceptWord("DISTINCT"):
+		q.Distinct = true
+	case p.acceptWord("REDUCED"):
+		q.Reduced = true
+	}
+
+	if p.accept("*") {
+		return nil
+	}
+
+	q.Vars = []string{}
+	for {
+		tok := p.peek()
+		switch {
+		case tok.kind == tokVar:
+			p.next()
+			q.Vars = append(q.Vars, tok.text[1:])
+			q.projections = append(q.projections, projection{name: tok.text[1:]})
+		case tok.is("("):
+			p.next()
+			expr, err := p.parseExpression()
+			if err != nil {
+				return err
+			}
+			if err := p.expectWord("AS"); err != nil {
+				return err
+			}
+			v := p.next()
+			if v.kind != tokVar {
+				return p.unexpected("variable after AS")
+			}
+			if err := p.expect(")"); err != nil {
+				return err
+			}
+			for _, name := range q.Vars {
+				if name == v.text[1:] {
+					return p.errorf("variable ?%s is projected twice", name)
+				}
+			}
+			q.Vars = append(q.Vars, v.text[1:])
+			q.projections = append(q.projections, projection{name: v.text[1:], expr: expr})
+		default:
+			if len(q.Vars) == 0 {
+				return p.unexpected("variable or * in SELECT")
+			}
+			return nil
+		}
+	}
+}
+
+// parseDescribeClause parses the resources of a DESCRIBE query. For
+// DESCRIBE * it leaves q.Vars nil to be filled from the WHERE clause.
+func (p *parser) parseDescribeClause(q *Query) error {
+	if p.accept("*") {
+		return nil
+	}
+
+	for {
+		tok := p.peek()
+		if tok.kind != tokVar && tok.kind != tokIRI && tok.kind != tokPrefixedName {
+			break
+		}
+		t, err := p.parseVarOrTerm()
+		if err != nil {
+			return err
+		}
+		if t.isVar() {
+			q.Vars = append(q.Vars, t.name)
+		}
+		q.describe = append(q.describe, t)
+	}
+	if len(q.describe) == 0 {
+		return p.unexpected("variable, IRI or * in DESCRIBE")
+	}
+	if q.Vars == nil {
+		q.Vars = []string{}
+	}
+	return nil
+}
+
+// parseTriplesTemplate parses a braced list of triple patterns, the template
+// of a CONSTRUCT query.
+func (p *parser) parseTriplesTemplate() ([]triplePattern, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	triples := []triplePattern{}
+	for !p.accept("}") {
+		if p.accept(".") {
+			continue
+		}
+		if p.peek().kind == tokEOF {
+			return nil, p.unexpected("}")
+		}
+		if err := p.parseTriplesSameSubject(&triples); err != nil {
+			return nil, err
+		}
+		if !p.peek().is(".") && !p.peek().is("}") {
+			return nil, p.unexpected(`"." or "}"`)
+		}
+	}
+	for _, tp := range triples {
+		if tp.path != nil {
+			return nil, p.errorf("property paths are not allowed in a template")
+		}
+	}
+	return triples, nil
+}
+
+func (p *parser) parseSolutionModifiers(q *Query) error {
+	if p.acceptWord("GROUP") {
+		if err := p.expectWord("BY"); err != nil {
+			return err
+		}
+		for {
+			cond, ok, err := p.parseGroupCondition()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			q.groupBy = append(q.groupBy, cond)
+		}
+		if len(q.groupBy) == 0 {
+			return p.unexpected("GROUP BY condition")
+		}
+	}
+
+	p.allowAggregates = true
+	defer func() { p.allowAggregates = false }()
+
+	if p.acceptWord("HAVING") {
+		for {
+			tok := p.peek()
+			if !tok.is("(") && (tok.kind != tokWord || isClauseKeyword(tok)) && tok.kind != tokIRI && tok.kind != tokPrefixedName {
+				break
+			}
+			expr, err := p.parseConstraint()
+			if err != nil {
+				return err
+			}
+			q.having = append(q.having, expr)
+		}
+		if len(q.having) == 0 {
+			return p.unexpected("HAVING condition")
+		}
+	}
+
+	if p.acceptWord("ORDER") {
+		if err := p.expectWord("BY"); err != nil {
+			return err
+		}
+		for {
+			cond, ok, err := p.parseOrderCondition()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			q.orderBy = append(q.orderBy, cond)
+		}
+		if len(q.orderBy) == 0 {
+			return p.unexpected("ORDER BY condition")
+		}
+	}
+
+	for {
+		switch {
+		case p.acceptWord("LIMIT"):
+			n, err := p.parseCount("LIMIT")
+			if err != nil {
+				return err
+			}
+			q.limit = n
+		case p.acceptWord("OFFSET"):
+			n, err := p.parseCount("OFFSET")
+			if err != nil {
+				return err
+			}
+			q.offset = n
+		default:
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseCount(clause string) (int, error) {
+	tok := p.peek()
+	if tok.kind != tokInteger {
+		return 0, p.unexpected("integer after " + clause)
+	}
+	p.next()
+	n, err := strconv.Atoi(tok.text)
+	if err != nil {
+		return 0, p.errorf("invalid %s %s", clause, tok.text)
+	}
+	return n, nil
+}
+
+// parseOrderCondition returns ok false when the next token cannot start an
+// ORDER BY condition.
+func (p *parser) parseOrderCondition() (orderCondition, bool, error) {
+	tok := p.peek()
+	switch {
+	case tok.isWord("ASC"), tok.isWord("DESC"):
+		p.next()
+		if !p.peek().is("(") {
+			return orderCondition{}, false, p.unexpected("( after " + strings.ToUpper(tok.text))
+		}
+		expr, err := p.parsePrimary()
+		if err != nil {
+			return orderCondition{}, false, err
+		}
+		return orderCondition{expr: expr, descending: tok.isWord("DESC")}, true, nil
+	case tok.kind == tokVar, tok.is("("),
+		tok.kind == tokWord && !isClauseKeyword(tok),
+		(tok.kind == tokIRI || tok.kind == tokPrefixedName) && p.peekAt(1).is("("):
+		expr, err := p.parsePrimary()
+		if err != nil {
+			return orderCondition{}, false, err
+		}
+		return orderCondition{expr: expr}, true, nil
+	}
+	return orderCondition{}, false, nil
+}
+
+// parseGroupCondition returns ok false when the next token cannot start a
+// GROUP BY condition.
+func (p *parser) parseGroupCondition() (groupCondition, bool, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokVar:
+		p.next()
+		return groupCondition{expr: varExpr{name: tok.text[1:]}, name: tok.text[1:]}, true, nil
+	case tok.is("("):
+		p.next()
+		expr, err := p.parseExpression()
+		if err != nil {
+			return groupCondition{}, false, err
+		}
+		cond := groupCondition{expr: expr}
+		if p.acceptWord("AS") {
+			v := p.next()
+			if v.kind != tokVar {
+				return groupCondition{}, false, p.unexpected("variable after AS")
+			}
+			cond.name = v.text[1:]
+		}
+		return cond, true, p.expect(")")
+	case tok.kind == tokWord && !isClauseKeyword(tok),
+		(tok.kind == tokIRI || tok.kind == tokPrefixedName) && p.peekAt(1).is("("):
+		expr, err := p.parsePrimary()
+		if err != nil {
+			return groupCondition{}, false, err
+		}
+		return groupCondition{expr: expr}, true, nil
+	}
+	return groupCondition{}, false, nil
+}
+
+func isClauseKeyword(tok token) bool {
+	for _, word := range []string{"GROUP", "HAVING", "ORDER", "LIMIT", "OFFSET", "VALUES"} {
+		if tok.isWord(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupBuilder assembles the algebra of a group graph pattern as described in
+// section 18.2.2 of the SPARQL 1.1 specification. Adjacent triple blocks
+// form a single basic graph pattern and filters apply to the whole group.
+type groupBuilder struct {
+	result  pattern
+	triples []triplePattern
+	filters []expression
+}
+
+func (gb *groupBuilder) flush() {
+	if len(gb.triples) > 0 {
+		triples := gb.triples
+		gb.triples = nil
+		gb.join(&bgp{triples: triples})
+	}
+}
+
+func (gb *groupBuilder) join(p pattern) {
+	gb.flush()
+	if gb.result == nil {
+		gb.result = p
+		return
+	}
+	gb.result = &joinPattern{left: gb.result, right: p}
+}
+
+// current returns the pattern built so far, or the empty group.
+func (gb *groupBuilder) current() pattern {
+	gb.flush()
+	if gb.result == nil {
+		return emptyGroup()
+	}
+	return gb.result
+}
+
+func (gb *groupBuilder) build() pattern {
+	result := gb.current()
+	if len(gb.filters) > 0 {
+		result = &filterPattern{inner: result, conds: gb.filters}
+	}
+	return result
+}
+
+func emptyGroup() pattern {
+	return &valuesPattern{rows: []Binding{{}}}
+}
+
+func (p *parser) parseGroupGraphPattern() (pattern, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	if p.peek().isWord("SELECT") {
+		return p.parseSubSelect()
+	}
+
+	gb := &groupBuilder{}
+	for !p.accept("}") {
+		tok := p.peek()
+		switch {
+		case tok.kind == tokEOF:
+			return nil, p.unexpected("}")
+		case tok.is("."):
+			p.next()
+		case tok.is("{"):
+			group, err := p.parseGroupOrUnion()
+			if err != nil {
+				return nil, err
+			}
+			gb.join(group)
+		case tok.isWord("OPTIONAL"):
+			p.next()
+			group, err := p.parseGroupGraphPattern()
+			if err != nil {
+				return nil, err
+			}
+			lj := &leftJoinPattern{left: gb.current(), right: group}
+			if f, ok := group.(*filterPattern); ok {
+				lj.right, lj.filter = f.inner, conjunction(f.conds)
+			}
+			gb.result = lj
+		case tok.isWord("MINUS"):
+			p.next()
+			group, err := p.parseGroupGraphPattern()
+			if err != nil {
+				return nil, err
+			}
+			gb.result = &minusPattern{left: gb.current(), right: group}
+		case tok.isWord("FILTER"):
+			p.next()
+			expr, err := p.parseConstraint()
+			if err != nil {
+				return nil, err
+			}
+			gb.filters = append(gb.filters, expr)
+		case tok.isWord("BIND"):
+			p.next()
+			if err := p.parseBind(gb); err != nil {
+				return nil, err
+			}
+		case tok.isWord("VALUES"):
+			p.next()
+			values, err := p.parseValues()
+			if err != nil {
+				return nil, err
+			}
+			gb.join(values)
+		case tok.isWord("GRAPH"), tok.isWord("SERVICE"):
+			return nil, p.errorf("%s is not supported", strings.ToUpper(tok.text))
+		default:
+			if err := p.parseTriplesSameSubject(&gb.triples); err != nil {
+				return nil, err
+			}
+			if !p.peek().is(".") && !p.peek().is("}") && !isGroupKeyword(p.peek()) && !p.peek().is("{") {
+				return nil, p.unexpected(`"." or "}"`)
+			}
+		}
+	}
+	return gb.build(), nil
+}
+
+func isGroupKeyword(tok token) bool {
+	for _, word := range []string{"OPTIONAL", "MINUS", "FILTER", "BIND", "VALUES", "GRAPH", "SERVICE"} {
+		if tok.isWord(word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseGroupOrUnion() (pattern, error) {
+	left, err := p.parseGroupGraphPattern()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptWord("UNION") {
+		right, err := p.parseGroupGraphPattern()
+		if err != nil {
+			return nil, err
+		}
+		left = &unionPattern{left: left, right: right}
+	}
+	return left, nil
+}
+
+func conjunction(exprs []expression) expression {
+	result := exprs[0]
+	for _, e := range exprs[1:] {
+		result = andExpr{left: result, right: e}
+	}
+	return result
+}
+
+func (p *parser) parseBind(gb *groupBuilder) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	expr, err := p.parseExpression()
+	if err != nil {
+		return err
+	}
+	if err := p.expectWord("AS"); err != nil {
+		return err
+	}
+	v := p.next()
+	if v.kind != tokVar {
+		return p.unexpected("variable after AS")
+	}
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+
+	inner := gb.current()
+	name := v.text[1:]
+	for _, bound := range inner.vars() {
+		if bound == name {
+			return p.errorf("BIND variable ?%s is already in scope", name)
+		}
+	}
+	gb.result = &extendPattern{inner: inner, name: name, expr: expr}
+	return nil
+}
+
+func (p *parser) parseValues() (*valuesPattern, error) {
+	values := &valuesPattern{}
+	single := false
+
+	switch tok := p.peek(); {
+	case tok.kind == tokVar:
+		p.next()
+		values.names = []string{tok.text[1:]}
+		single = true
+	case tok.is("("):
+		p.next()
+		for p.peek().kind == tokVar {
+			values.names = append(values.names, p.next().text[1:])
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.unexpected("variable or ( after VALUES")
+	}
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		if !single {
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+		}
+		row := Binding{}
+		for i := range values.names {
+			if !single && p.peek().is(")") {
+				return nil, p.errorf("VALUES row has %d values, want %d", i, len(values.names))
+			}
+			if p.acceptWord("UNDEF") {
+				continue
+			}
+			node, err := p.parseDataTerm()
+			if err != nil {
+				return nil, err
+			}
+			row[values.names[i]] = node
+		}
+		if !single {
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		values.rows = append(values.rows, row)
+	}
+	return values, nil
+}
+
+// parseDataTerm parses an IRI or literal, as allowed in VALUES.
+func (p *parser) parseDataTerm() (triple.Node, error) {
+	t, err := p.parseVarOrTerm()
+	if err != nil {
+		return nil, err
+	}
+	switch t.node.(type) {
+	case triple.IRI, triple.Literal, triple.TripleTerm:
+		return t.node, nil
+	}
+	return nil, p.errorf("expected IRI or literal in VALUES")
+}
+
+// parseTriplesSameSubject parses a subject with its property list and appends
+// the resulting triple patterns.
+func (p *parser) parseTriplesSameSubject(triples *[]triplePattern) error {
+	tok := p.peek()
+	if tok.is("[") && !p.peekAt(1).is("]") || tok.is("(") && !p.peekAt(1).is(")") {
+		subject, err := p.parseGraphNode(triples)
+		if err != nil {
+			return err
+		}
+		if p.peek().is(".") || p.peek().is("}") {
+			return nil
+		}
+		return p.parsePropertyList(subject, triples)
+	}
+
+	subject, err := p.parseVarOrTerm()
+	if err != nil {
+		return err
+	}
+	return p.parsePropertyList(subject, triples)
+}
+
+func (p *parser) parsePropertyList(subject term, triples *[]triplePattern) error {
+	for {
+		verb, path, err := p.parseVerb()
+		if err != nil {
+			return err
+		}
+		for {
+			object, err := p.parseGraphNode(triples)
+			if err != nil {
+				return err
+			}
+			*triples = append(*triples, triplePattern{subject: subject, predicate: verb, object: object, path: path})
+			if !p.accept(",") {
+				break
+			}
+		}
+
+		if !p.accept(";") {
+			return nil
+		}
+		for p.accept(";") {
+		}
+		if tok := p.peek(); tok.is(".") || tok.is("]") || tok.is("}") || tok.kind == tokEOF {
+			return nil
+		}
+	}
+}
+
+// parseVerb parses a predicate: a variable or a property path. A path that
+// is a single IRI is returned as a term and a nil path.
+func (p *parser) parseVerb() (term, triple.Path, error) {
+	if tok := p.peek(); tok.kind == tokVar {
+		p.next()
+		return term{name: tok.text[1:]}, nil, nil
+	}
+	path, err := p.parsePath()
+	if err != nil {
+		return term{}, nil, err
+	}
+	if iri, ok := path.(triple.IRI); ok {
+		return term{node: iri}, nil, nil
+	}
+	return term{}, path, nil
+}
+
+// parsePath parses a property path. Alternatives bind loosest, then
+// sequences, then ^ and the modifiers *, + and ?.
+func (p *parser) parsePath() (triple.Path, error) {
+	var alts []triple.Path
+	for {
+		seq, err := p.parsePathSequence()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, seq)
+		if !p.accept("|") {
+			break
+		}
+	}
+	if len(alts) == 1 {
+		return alts[0], nil
+	}
+	return triple.AlternativePath{Paths: alts}, nil
+}
+
+func (p *parser) parsePathSequence() (triple.Path, error) {
+	var steps []triple.Path
+	for {
+		step, err := p.parsePathElt()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+		if !p.accept("/") {
+			break
+		}
+	}
+	if len(steps) == 1 {
+		return steps[0], nil
+	}
+	return triple.SequencePath{Paths: steps}, nil
+}
+
+func (p *parser) parsePathElt() (triple.Path, error) {
+	inverse := p.accept("^")
+	path, err := p.parsePathPrimary()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.accept("*"):
+		path = triple.ZeroOrMorePath{Path: path}
+	case p.accept("+"):
+		path = triple.OneOrMorePath{Path: path}
+	case p.accept("?"):
+		path = triple.ZeroOrOnePath{Path: path}
+	}
+	if inverse {
+		path = triple.InversePath{Path: path}
+	}
+	return path, nil
+}
+
+func (p *parser) parsePathPrimary() (triple.Path, error) {
+	switch tok := p.peek(); {
+	case tok.is("!"):
+		p.next()
+		return p.parseNegatedPropertySet()
+	case tok.is("("):
+		p.next()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		return path, p.expect(")")
+	case tok.kind == tokString || isNumberToken(tok) || tok.isWord("true") || tok.isWord("false"):
+		return nil, p.errorf("a literal cannot be a predicate")
+	}
+	return p.parsePathIRI()
+}
+
+// parsePathIRI parses an IRI, prefixed name or a in a property path.
+func (p *parser) parsePathIRI() (triple.IRI, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokWord && tok.text == "a":
+		p.next()
+		return rdf.Type, nil
+	case tok.kind == tokIRI, tok.kind == tokPrefixedName:
+		node, err := p.parseConstant()
+		if err != nil {
+			return triple.IRI{}, err
+		}
+		return node.(triple.IRI), nil
+	}
+	return triple.IRI{}, p.unexpected("IRI or property path")
+}
+
+// parseNegatedPropertySet parses what follows !: an IRI, ^IRI or a
+// parenthesized list of them separated by |.
+func (p *parser) parseNegatedPropertySet() (triple.Path, error) {
+	var neg triple.NegatedPath
+	member := func() error {
+		inverse := p.accept("^")
+		iri, err := p.parsePathIRI()
+		if err != nil {
+			return err
+		}
+		if inverse {
+			neg.Inverse = append(neg.Inverse, iri)
+		} else {
+			neg.Forward = append(neg.Forward, iri)
+		}
+		return nil
+	}
+
+	if !p.accept("(") {
+		return neg, member()
+	}
+	if p.accept(")") {
+		return neg, nil
+	}
+	for {
+		if err := member(); err != nil {
+			return nil, err
+		}
+		if !p.accept("|") {
+			break
+		}
+	}
+	return neg, p.expect(")")
+}
+
+// parseGraphNode parses a term, a blank node property list or a collection.
+// The triples of nested structures are appended to triples.
+func (p *parser) parseGraphNode(triples *[]triplePattern) (term, error) {
+	switch tok := p.peek(); {
+	case tok.is("[") && !p.peekAt(1).is("]"):
+		p.next()
+		node := p.freshVar()
+		if err := p.parsePropertyList(node, triples); err != nil {
+			return term{}, err
+		}
+		return node, p.expect("]")
+	case tok.is("(") && !p.peekAt(1).is(")"):
+		p.next()
+		var items []term
+		for !p.accept(")") {
+			if p.peek().kind == tokEOF {
+				return term{}, p.unexpected(")")
+			}
+			item, err := p.parseGraphNode(triples)
+			if err != nil {
+				return term{}, err
+			}
+			items = append(items, item)
+		}
+		return p.collection(items, triples), nil
+	}
+	return p.parseVarOrTerm()
+}
+
+func (p *parser) collection(items []term, triples *[]triplePattern) term {
+	head := term{node: rdf.Nil}
+	for i := len(items) - 1; i >= 0; i-- {
+		cell := p.freshVar()
+		*triples = append(*triples,
+			triplePattern{subject: cell, predicate: term{node: rdf.First}, object: items[i]},
+			triplePattern{subject: cell, predicate: term{node: rdf.Rest}, object: head},
+		)
+		head = cell
+	}
+	return head
+}
+
+func (p *parser) freshVar() term {
+	p.anon++
+	return term{name: fmt.Sprintf("_:#%d", p.anon)}
+}
+
+// parseVarOrTerm parses a variable, IRI, literal, blank node, () or []. Blank
+// nodes become hidden variables.
+func (p *parser) parseVarOrTerm() (term, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokVar:
+		p.next()
+		return term{name: tok.text[1:]}, nil
+	case tok.kind == tokBlankNode:
+		p.next()
+		return term{name: tok.text}, nil
+	case tok.is("[") && p.peekAt(1).is("]"):
+		p.next()
+		p.next()
+		return p.freshVar(), nil
+	case tok.is("(") && p.peekAt(1).is(")"):
+		p.next()
+		p.next()
+		return term{node: rdf.Nil}, nil
+	case tok.is("<<("):
+		node, err := p.parseTripleTerm()
+		return term{node: node}, err
+	}
+
+	node, err := p.parseConstant()
+	if err != nil {
+		return term{}, err
+	}
+	return term{node: node}, nil
+}
+
+// parseTripleTerm parses a triple term with constant terms, <<( s p o )>>.
+func (p *parser) parseTripleTerm() (triple.Node, error) {
+	p.next()
+	var parts [3]triple.Node
+	for i := range parts {
+		t, err := p.parseVarOrTerm()
+		if err != nil {
+			return nil, err
+		}
+		if t.isVar() {
+			return nil, p.errorf("variables in triple terms are not supported")
+		}
+		parts[i] = t.node
+	}
+	if err := p.expect(")>>"); err != nil {
+		return nil, err
+	}
+	return triple.TripleTerm{Triple: triple.Triple{Subject: parts[0], Predicate: parts[1], Object: parts[2]}}, nil
+}
+
+// parseConstant parses an IRI, prefixed name, literal, number or boolean.
+func (p *parser) parseConstant() (triple.Node, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokIRI:
+		p.next()
+		return triple.IRI{Value: p.resolveIRI(tok.text)}, nil
+	case tokPrefixedName:
+		p.next()
+		return p.expandPrefixedName(tok)
+	case tokString:
+		return p.parseLiteral()
+	case tokInteger, tokDecimal, tokDouble:
+		p.next()
+		return numericLiteral(tok, ""), nil
+	case tokPunct:
+		if (tok.text == "+" || tok.text == "-") && isNumberToken(p.peekAt(1)) {
+			p.next()
+			return numericLiteral(p.next(), tok.text), nil
+		}
+	case tokWord:
+		switch {
+		case tok.isWord("true"), tok.isWord("false"):
+			p.next()
+			return triple.Literal{Value: strings.ToLower(tok.text), Datatype: xsd.Boolean}, nil
+		case tok.text == "a":
+			p.next()
+			return rdf.Type, nil
+		}
+	}
+	return nil, p.unexpected("term")
+}
+
+func isNumberToken(tok token) bool {
+	return tok.kind == tokInteger || tok.kind == tokDecimal || tok.kind == tokDouble
+}
+
+func numericLiteral(tok token, sign string) triple.Literal {
+	datatype := xsd.Integer
+	switch tok.kind {
+	case tokDecimal:
+		datatype = xsd.Decimal
+	case tokDouble:
+		datatype = xsd.Double
+	}
+	return triple.Literal{Value: sign + tok.text, Datatype: datatype}
+}
+
+func (p *parser) parseLiteral() (triple.Node, error) {
+	tok := p.next()
+	quote := 1
+	if len(tok.text) >= 6 && (strings.HasPrefix(tok.text, `"""`) || strings.HasPrefix(tok.text, `'''`)) {
+		quote = 3
+	}
+	value, err := unescapeString(tok.text[quote : len(tok.text)-quote])
+	if err != nil {
+		return nil, fmt.Errorf("%v at line %d, column %d", err, tok.line, tok.column)
+	}
+	lit := triple.Literal{Value: value}
+
+	switch next := p.peek(); {
+	case next.kind == tokLangTag:
+		p.next()
+		lit.Language, lit.Direction, _ = strings.Cut(next.text[1:], "--")
+	case next.is("^^"):
+		p.next()
+		dt := p.next()
+		switch dt.kind {
+		case tokIRI:
+			lit.Datatype = p.resolveIRI(dt.text)
+		case tokPrefixedName:
+			iri, err := p.expandPrefixedName(dt)
+			if err != nil {
+				return nil, err
+			}
+			lit.Datatype = iri.Value
+		default:
+			return nil, p.unexpected("datatype IRI after ^^")
+		}
+	}
+	return lit, nil
+}
+
+func (p *parser) resolveIRI(tok string) string {
+	ref := tok[1 : len(tok)-1]
+	if unescaped, err := unescapeString(ref); err == nil {
+		ref = unescaped
+	}
+	if p.base == "" {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil || refURL.IsAbs() {
+		return ref
+	}
+	baseURL, err := url.Parse(p.base)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+func (p *parser) expandPrefixedName(tok token) (triple.IRI, error) {
+	prefix, local, _ := strings.Cut(tok.text, ":")
+	namespace, ok := p.resolver.Get(prefix)
+	if !ok {
+		return triple.IRI{}, fmt.Errorf("undefined prefix %q at line %d, column %d", prefix, tok.line, tok.column)
+	}
+	local = unescapeLocalName(local)
+	return triple.IRI{Value: namespace + local}, nil
+}
+
+// unescapeLocalName removes the backslashes of reserved-character escapes in
+// a prefixed name's local part.
+func unescapeLocalName(local string) string {
+	if !strings.Contains(local, `\`) {
+		return local
+	}
+	var b strings.Builder
+	for i := 0; i < len(local); i++ {
+		if local[i] == '\\' && i+1 < len(local) {
+			i++
+		}
+		b.WriteByte(local[i])
+	}
+	return b.String()
+}
+
+func unescapeString(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("dangling escape")
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'b':
+			b.WriteByte('\b')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case '"', '\'', '\\':
+			b.WriteByte(s[i])
+		case 'u', 'U':
+			size := 4
+			if s[i] == 'U' {
+				size = 8
+			}
+			if i+size >= len(s) {
+				return "", fmt.Errorf("short unicode escape")
+			}
+			code, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i-1:i+1+size])
+			}
+			b.WriteRune(rune(code))
+			i += size
+		default:
+			return "", fmt.Errorf("invalid escape \\%c", s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// parseConstraint parses the condition of a FILTER: a bracketed expression
+// or a function call.
+func (p *parser) parseConstraint() (expression, error) {
+	tok := p.peek()
+	if tok.is("(") || tok.kind == tokWord || tok.kind == tokIRI || tok.kind == tokPrefixedName {
+		return p.parsePrimary()
+	}
+	return nil, p.unexpected("( or function call after FILTER")
+}
+
+func (p *parser) parseExpression() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parseRelational()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseRelational()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseRelational() (expression, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	switch {
+	case tok.kind == tokPunct && (tok.text == "=" || tok.text == "!=" || tok.text == "<" || tok.text == ">" || tok.text == "<=" || tok.text == ">="):
+		p.next()
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return compareExpr{op: tok.text, left: left, right: right}, nil
+	case tok.isWord("IN"):
+		p.next()
+		list, err := p.parseExpressionList()
+		return inExpr{operand: left, list: list}, err
+	case tok.isWord("NOT") && p.peekAt(1).isWord("IN"):
+		p.next()
+		p.next()
+		list, err := p.parseExpressionList()
+		return inExpr{operand: left, list: list, negated: true}, err
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (expression, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if !tok.is("+") && !tok.is("-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticExpr{op: tok.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if !tok.is("*") && !tok.is("/") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticExpr{op: tok.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	switch tok := p.peek(); {
+	case tok.is("!"):
+		p.next()
+		operand, err := p.parseUnary()
+		return notExpr{operand: operand}, err
+	case tok.is("+"), tok.is("-"):
+		p.next()
+		operand, err := p.parseUnary()
+		return negateExpr{operand: operand, plus: tok.text == "+"}, err
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expression, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokVar:
+		p.next()
+		return varExpr{name: tok.text[1:]}, nil
+	case tokIRI, tokPrefixedName:
+		node, err := p.parseConstant()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().is("(") {
+			return p.parseFunctionCall(node.(triple.IRI))
+		}
+		return constExpr{node: node}, nil
+	case tokString, tokInteger, tokDecimal, tokDouble:
+		node, err := p.parseConstant()
+		return constExpr{node: node}, err
+	case tokWord:
+		if tok.isWord("true") || tok.isWord("false") {
+			node, err := p.parseConstant()
+			return constExpr{node: node}, err
+		}
+		return p.parseBuiltInCall()
+	case tokPunct:
+		switch tok.text {
+		case "(":
+			p.next()
+			expr, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		case "<<(":
+			node, err := p.parseTripleTerm()
+			return constExpr{node: node}, err
+		}
+	}
+	return nil, p.unexpected("expression")
+}
+
+func (p *parser) parseFunctionCall(iri triple.IRI) (expression, error) {
+	if !casts[iri.Value] {
+		return nil, p.errorf("unsupported function <%s>", iri.Value)
+	}
+	args, err := p.parseExpressionList()
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 1 {
+		return nil, p.errorf("<%s> takes one argument, got %d", iri.Value, len(args))
+	}
+	return callExpr{fn: castFunction(iri.Value), args: args}, nil
+}
+
+// parseAggregate parses the arguments of an aggregate call such as
+// COUNT(DISTINCT ?x) or GROUP_CONCAT(?x; SEPARATOR=", ").
+func (p *parser) parseAggregate(tok token, name string) (expression, error) {
+	if !p.allowAggregates {
+		return nil, fmt.Errorf("%s is only allowed in SELECT, HAVING and ORDER BY at line %d, column %d", name, tok.line, tok.column)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	agg := &aggregateExpr{key: p.freshVar().name, name: name, separator: " "}
+	agg.distinct = p.acceptWord("DISTINCT")
+	if name != "COUNT" || !p.accept("*") {
+		p.allowAggregates = false
+		arg, err := p.parseExpression()
+		p.allowAggregates = true
+		if err != nil {
+			return nil, err
+		}
+		agg.arg = arg
+	}
+
+	if name == "GROUP_CONCAT" && p.accept(";") {
+		if err := p.expectWord("SEPARATOR"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokString {
+			return nil, p.unexpected("string after SEPARATOR=")
+		}
+		sep, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		agg.separator = sep.(triple.Literal).Value
+	}
+
+	p.aggregates = append(p.aggregates, agg)
+	return agg, p.expect(")")
+}
+
+func (p *parser) parseBuiltInCall() (expression, error) {
+	tok := p.next()
+	name := strings.ToUpper(tok.text)
+
+	if aggregates[name] {
+		return p.parseAggregate(tok, name)
+	}
+
+	switch name {
+	case "NOT":
+		if err := p.expectWord("EXISTS"); err != nil {
+			return nil, err
+		}
+		group, err := p.parseGroupGraphPattern()
+		return existsExpr{pattern: group, negated: true}, err
+	case "EXISTS":
+		group, err := p.parseGroupGraphPattern()
+		return existsExpr{pattern: group}, err
+	case "BOUND":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		v := p.next()
+		if v.kind != tokVar {
+			return nil, p.unexpected("variable in BOUND")
+		}
+		return boundExpr{name: v.text[1:]}, p.expect(")")
+	}
+
+	args, err := p.parseExpressionList()
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "IF":
+		if len(args) != 3 {
+			return nil, p.errorf("IF takes 3 arguments, got %d", len(args))
+		}
+		return ifExpr{cond: args[0], then: args[1], otherwise: args[2]}, nil
+	case "COALESCE":
+		return coalesceExpr{args: args}, nil
+	}
+
+	fn, ok := builtins[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s at line %d, column %d", tok.text, tok.line, tok.column)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs != variadic && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments to %s at line %d, column %d", name, tok.line, tok.column)
+	}
+	return callExpr{fn: fn.fn, args: args}, nil
+}
+
+// parseExpressionList parses a parenthesised, comma-separated list of
+// expressions, which may be empty.
+func (p *parser) parseExpressionList() ([]expression, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var list []expression
+	if p.accept(")") {
+		return list, nil
+	}
+	for {
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, expr)
+		if p.accept(")") {
+			return list, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package sparql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`SELECT * WHERE { ?s ex:p ?o OPTIONAL { ?o ex:q ?x } BIND(1 AS ?one) }`, ParseOptions{
+		Prefixes: map[string]string{"ex": "http://example.org/"},
+	})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if q.Form != Select {
+		t.Errorf("Form = %v, want SELECT", q.Form)
+	}
+	if want := []string{"s", "o", "x", "one"}; !reflect.DeepEqual(q.Vars, want) {
+		t.Errorf("Vars = %v, want %v", q.Vars, want)
+	}
+	if q.Prefixes["ex"] != "http://example.org/" {
+		t.Errorf("Prefixes = %v", q.Prefixes)
+	}
+
+	q, err = Parse("prefix ex: <http://example.org/>\nselect distinct ?s { ?s a ex:C } order by desc(?s) limit 5 offset 2", ParseOptions{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !q.Distinct || q.limit != 5 || q.offset != 2 || len(q.orderBy) != 1 || !q.orderBy[0].descending {
+		t.Errorf("Parse() modifiers = distinct %v, limit %d, offset %d, order %v", q.Distinct, q.limit, q.offset, q.orderBy)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`SELECT ?s { ?s ex:p ?o }`, `undefined prefix "ex" at line 1, column 16`},
+		{`SELECT ?s { ?s <p> ?o `, `expected "." or "}", found end of query`},
+		{`SELECT { ?s <p> ?o }`, "expected variable or * in SELECT"},
+		{`SELECT ?s { ?s <p> ?o FILTER(NOPE(?o)) }`, "unknown function NOPE"},
+		{`SELECT ?s { ?s <p> ?o BIND(1 AS ?o) }`, "BIND variable ?o is already in scope"},
+		{`SELECT ?s FROM <g> { ?s <p> ?o }`, "FROM is not supported"},
+		{`SELECT ?s { ?s <p> "open }`, "unclosed string at line 1, column 20"},
+		{`SELECT ?s { ?s <p> ?o FILTER(STRLEN(?o, 1)) }`, "wrong number of arguments to STRLEN"},
+		{`SELECT (1 AS ?x) (2 AS ?x) {}`, "variable ?x is projected twice"},
+		{`LOAD <x>`, "expected SELECT, CONSTRUCT, DESCRIBE or ASK"},
+		{`CONSTRUCT { ?s <p> } WHERE { ?s <p> ?o }`, `expected term, found "}"`},
+		{`DESCRIBE WHERE { ?s <p> ?o }`, "expected variable, IRI or * in DESCRIBE"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.query, ParseOptions{})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%s) error = %v, want %q", tt.query, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package sparql
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/DeDude/tripl/internal/httputil"
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// HandlerOptions configures a SPARQL Protocol handler.
+type HandlerOptions struct {
+	// Prefixes are available to queries and updates without PREFIX and are
+	// used to shorten CONSTRUCT and DESCRIBE results.
+	Prefixes map[string]string
+	// ReadOnly rejects updates with 403 Forbidden.
+	ReadOnly bool
+	// AllowOrigin is the origin of the web pages, or "*" for any, that may
+	// use the endpoint from a browser. Updates from pages on other origins
+	// are rejected with 403 Forbidden. Empty allows only the server's own.
+	AllowOrigin string
+	// Lock guards the graph. Handlers that share a graph, such as a Graph
+	// Store Protocol handler for the same data, must share the lock. Nil
+	// gives the handler its own.
+	Lock *sync.RWMutex
+	// Commit, if set, is called with the lock held after an update request
+	// applies without error, and an error from it fails the request.
+	// Rollback is called instead when applying fails. A transactional store
+	// uses them to make each request all or nothing. Without Rollback the
+	// handler undoes the changes of a failed request itself.
+	Commit   func() error
+	Rollback func()
+}
+
+// Handler serves the SPARQL 1.1 Protocol over one graph: queries by GET or
+// POST, updates by POST. Queries run concurrently; an update waits for
+// running queries and blocks new ones until it is applied.
+type Handler struct {
+	graph Updatable
+	opts  HandlerOptions
+	mu    *sync.RWMutex
+}
+
+func NewHandler(g Updatable, opts HandlerOptions) *Handler {
+	mu := opts.Lock
+	if mu == nil {
+		mu = new(sync.RWMutex)
+	}
+	return &Handler{graph: g, opts: opts, mu: mu}
+}
+
+// resultsMediaTypes lists the media types offered for SELECT and ASK
+// results, most preferred first.
+var resultsMediaTypes = []string{
+	ResultsJSON.MediaType(),
+	ResultsXML.MediaType(),
+	ResultsCSV.MediaType(),
+	ResultsTSV.MediaType(),
+	"application/json",
+	"application/xml",
+}
+
+// graphMediaTypes lists the media types offered for CONSTRUCT and DESCRIBE
+// results, most preferred first.
+var graphMediaTypes = []string{
+	encoder.Turtle.MediaType(),
+	encoder.NTriples.MediaType(),
+	encoder.JSONLD.MediaType(),
+	"text/plain",
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	httputil.AllowOrigin(w, h.opts.AllowOrigin)
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		params := r.URL.Query()
+		if params.Has("update") {
+			httputil.Error(w, http.StatusBadRequest, "updates must be sent with POST")
+			return
+		}
+		if !params.Has("query") {
+			httputil.Error(w, http.StatusBadRequest, "missing query parameter")
+			return
+		}
+		h.serveQuery(w, r, params.Get("query"), params)
+	case http.MethodPost:
+		h.servePost(w, r)
+	case http.MethodOptions:
+		w.Header().Set("Allow", "GET, HEAD, POST, OPTIONS")
+		if h.opts.AllowOrigin != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST, OPTIONS")
+		httputil.Error(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func (h *Handler) servePost(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		httputil.Error(w, http.StatusUnsupportedMediaType, "missing or invalid Content-Type")
+		return
+	}
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			httputil.Error(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+		switch query, update := r.PostForm.Has("query"), r.PostForm.Has("update"); {
+		case query && update:
+			httputil.Error(w, http.StatusBadRequest, "a request may contain a query or an update, not both")
+		case query:
+			h.serveQuery(w, r, r.PostForm.Get("query"), r.Form)
+		case update:
+			h.serveUpdate(w, r, r.PostForm.Get("update"), r.Form)
+		default:
+			httputil.Error(w, http.StatusBadRequest, "missing query or update parameter")
+		}
+	case "application/sparql-query", "application/sparql-update":
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			httputil.Error(w, http.StatusBadRequest, "reading request: %v", err)
+			return
+		}
+		if mediaType == "application/sparql-query" {
+			h.serveQuery(w, r, string(body), r.URL.Query())
+		} else {
+			h.serveUpdate(w, r, string(body), r.URL.Query())
+		}
+	default:
+		httputil.Error(w, http.StatusUnsupportedMediaType, "unsupported Content-Type %s", mediaType)
+	}
+}
+
+func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, query string, params map[string][]string) {
+	if name := datasetParam(params, "default-graph-uri", "named-graph-uri"); name != "" {
+		httputil.Error(w, http.StatusBadRequest, "%s is not supported: the endpoint serves one default graph", name)
+		return
+	}
+
+	q, err := Parse(query, ParseOptions{Prefixes: h.opts.Prefixes})
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	offers := resultsMediaTypes
+	if q.Form == Construct || q.Form == Describe {
+		offers = graphMediaTypes
+	}
+	mediaType, ok := httputil.Negotiate(r.Header.Get("Accept"), offers)
+	if !ok {
+		httputil.Error(w, http.StatusNotAcceptable, "acceptable media types: %s", strings.Join(offers, ", "))
+		return
+	}
+
+	h.mu.RLock()
+	res, err := q.Eval(h.graph)
+	h.mu.RUnlock()
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	var body string
+	if q.Form == Construct || q.Form == Describe {
+		format := encoder.NTriples
+		if mediaType != "text/plain" {
+			format, _ = encoder.FormatForMediaType(mediaType)
+		}
+		prefixes := make(map[string]string, len(h.opts.Prefixes)+len(q.Prefixes))
+		for k, v := range h.opts.Prefixes {
+			prefixes[k] = v
+		}
+		for k, v := range q.Prefixes {
+			prefixes[k] = v
+		}
+		body, err = encoder.Encode(res.Triples, format, encoder.EncodeOptions{Prefixes: prefixes, Compact: true})
+	} else {
+		format, _ := ResultsFormatForMediaType(mediaType)
+		body, err = EncodeResults(res, format)
+	}
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", httputil.ContentType(mediaType))
+	w.Header().Set("Vary", "Accept")
+	io.WriteString(w, body)
+}
+
+func (h *Handler) serveUpdate(w http.ResponseWriter, r *http.Request, update string, params map[string][]string) {
+	if h.opts.ReadOnly {
+		httputil.Error(w, http.StatusForbidden, "updates are disabled")
+		return
+	}
+	if httputil.CrossOrigin(r, h.opts.AllowOrigin) {
+		httputil.Error(w, http.StatusForbidden, "updates from pages on %s are not allowed", r.Header.Get("Origin"))
+		return
+	}
+	if name := datasetParam(params, "using-graph-uri", "using-named-graph-uri"); name != "" {
+		httputil.Error(w, http.StatusBadRequest, "%s is not supported: the endpoint serves one default graph", name)
+		return
+	}
+
+	u, err := ParseUpdate(update, ParseOptions{Prefixes: h.opts.Prefixes})
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	h.mu.Lock()
+	if h.opts.Rollback != nil {
+		err = u.Apply(h.graph, UpdateOptions{})
+		if err != nil {
+			h.opts.Rollback()
+		}
+	} else {
+		changes := &undoLog{Updatable: h.graph}
+		err = u.Apply(changes, UpdateOptions{})
+		if err != nil {
+			changes.undo()
+		}
+	}
+	if err == nil && h.opts.Commit != nil {
+		err = h.opts.Commit()
+	}
+	h.mu.Unlock()
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// undoLog records the changes made through it, so that an update that fails
+// partway can be undone.
+type undoLog struct {
+	Updatable
+	changes []undoChange
+}
+
+type undoChange struct {
+	t     triple.Triple
+	added bool
+}
+
+func (l *undoLog) Add(t triple.Triple) bool {
+	if !l.Updatable.Add(t) {
+		return false
+	}
+	l.changes = append(l.changes, undoChange{t, true})
+	return true
+}
+
+func (l *undoLog) Remove(t triple.Triple) bool {
+	if !l.Updatable.Remove(t) {
+		return false
+	}
+	l.changes = append(l.changes, undoChange{t, false})
+	return true
+}
+
+// undo reverts the changes, latest first.
+func (l *undoLog) undo() {
+	for i := len(l.changes) - 1; i >= 0; i-- {
+		if c := l.changes[i]; c.added {
+			l.Updatable.Remove(c.t)
+		} else {
+			l.Updatable.Add(c.t)
+		}
+	}
+	l.changes = nil
+}
+
+// datasetParam returns the first of names present in params. The protocol
+// uses these parameters to choose the graphs a request runs against.
+func datasetParam(params map[string][]string, names ...string) string {
+	for _, name := range names {
+		if _, ok := params[name]; ok {
+			return name
+		}
+	}
+	return ""
+}
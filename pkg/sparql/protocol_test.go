@@ -0,0 +1,254 @@
+package sparql
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	g := testGraph(t)
+	prefixes := map[string]string{"ex": "http://example.org/", "foaf": "http://xmlns.com/foaf/0.1/"}
+	srv := httptest.NewServer(NewHandler(g, HandlerOptions{Prefixes: prefixes}))
+	defer srv.Close()
+
+	do := func(method, target, contentType, accept, body string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+target, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+
+	selectNames := url.QueryEscape(`SELECT ?n WHERE { ex:alice foaf:name ?n }`)
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		accept      string
+		body        string
+		status      int
+		wantType    string
+		wantBody    string
+	}{
+		{
+			name:     "GET defaults to JSON",
+			method:   "GET",
+			target:   "/?query=" + selectNames,
+			status:   http.StatusOK,
+			wantType: "application/sparql-results+json",
+			wantBody: `"value": "Alice"`,
+		},
+		{
+			name:     "GET negotiates CSV",
+			method:   "GET",
+			target:   "/?query=" + selectNames,
+			accept:   "text/html;q=0.9, text/csv",
+			status:   http.StatusOK,
+			wantType: "text/csv; charset=utf-8",
+			wantBody: "n\r\nAlice\r\n",
+		},
+		{
+			name:     "wildcard with exclusion",
+			method:   "GET",
+			target:   "/?query=" + selectNames,
+			accept:   "*/*, application/sparql-results+json;q=0",
+			status:   http.StatusOK,
+			wantType: "application/sparql-results+xml",
+		},
+		{
+			name:        "POST query body",
+			method:      "POST",
+			target:      "/",
+			contentType: "application/sparql-query",
+			accept:      "text/tab-separated-values",
+			body:        `ASK { ex:bob foaf:knows ex:carol }`,
+			status:      http.StatusOK,
+			wantBody:    "?_askResult\ntrue\n",
+		},
+		{
+			name:        "POST form CONSTRUCT as N-Triples",
+			method:      "POST",
+			target:      "/",
+			contentType: "application/x-www-form-urlencoded",
+			accept:      "application/n-triples",
+			body:        "query=" + url.QueryEscape(`CONSTRUCT WHERE { ex:bob foaf:age ?a }`),
+			status:      http.StatusOK,
+			wantType:    "application/n-triples",
+			wantBody:    `<http://example.org/bob> <http://xmlns.com/foaf/0.1/age> "27"^^<http://www.w3.org/2001/XMLSchema#integer> .`,
+		},
+		{
+			name:     "CONSTRUCT defaults to Turtle",
+			method:   "GET",
+			target:   "/?query=" + url.QueryEscape(`DESCRIBE ex:carol`),
+			status:   http.StatusOK,
+			wantType: "text/turtle; charset=utf-8",
+			wantBody: "ex:carol",
+		},
+		{
+			name:   "unacceptable",
+			method: "GET",
+			target: "/?query=" + selectNames,
+			accept: "image/png",
+			status: http.StatusNotAcceptable,
+		},
+		{
+			name:     "syntax error",
+			method:   "GET",
+			target:   "/?query=" + url.QueryEscape("SELECT WHERE"),
+			status:   http.StatusBadRequest,
+			wantType: "text/plain; charset=utf-8",
+		},
+		{
+			name:   "update by GET",
+			method: "GET",
+			target: "/?update=" + url.QueryEscape("CLEAR ALL"),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "dataset parameters",
+			method: "GET",
+			target: "/?query=" + selectNames + "&default-graph-uri=http://example.org/g",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:        "unsupported content type",
+			method:      "POST",
+			target:      "/",
+			contentType: "text/plain",
+			body:        "SELECT * {}",
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			name:   "method",
+			method: "PUT",
+			target: "/",
+			status: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := do(tt.method, tt.target, tt.contentType, tt.accept, tt.body)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.status, body)
+			}
+			if tt.wantType != "" && resp.Header.Get("Content-Type") != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", resp.Header.Get("Content-Type"), tt.wantType)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", body, tt.wantBody)
+			}
+		})
+	}
+
+	// Updates by form and by body change what later queries see.
+	resp, body := do("POST", "/", "application/x-www-form-urlencoded", "",
+		"update="+url.QueryEscape(`INSERT DATA { ex:dave foaf:name "Dave" }`))
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("form update status = %d: %s", resp.StatusCode, body)
+	}
+	resp, body = do("POST", "/", "application/sparql-update", "", `DELETE WHERE { ex:alice foaf:name ?n }`)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("update status = %d: %s", resp.StatusCode, body)
+	}
+	_, body = do("GET", "/?query="+url.QueryEscape(`SELECT ?n WHERE { ?p foaf:name ?n } ORDER BY ?n`), "", "text/csv", "")
+	if body != "n\r\nBob\r\nCarol\r\nDave\r\n" {
+		t.Errorf("names after updates = %q", body)
+	}
+}
+
+func TestHandlerReadOnly(t *testing.T) {
+	h := NewHandler(testGraph(t), HandlerOptions{ReadOnly: true})
+	req := httptest.NewRequest("POST", "/", strings.NewReader("CLEAR ALL"))
+	req.Header.Set("Content-Type", "application/sparql-update")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+// TestHandlerOrigins checks that only pages from allowed origins can use the
+// endpoint from a browser: others get no CORS header and cannot update.
+func TestHandlerOrigins(t *testing.T) {
+	update := func(h *Handler, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "http://localhost:8080/sparql",
+			strings.NewReader("update="+url.QueryEscape("CLEAR ALL")))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	g := testGraph(t)
+	n := g.Len()
+	h := NewHandler(g, HandlerOptions{})
+	rec := update(h, "https://evil.example")
+	if rec.Code != http.StatusForbidden || g.Len() != n {
+		t.Errorf("cross-origin update: status %d, %d triples left", rec.Code, g.Len())
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q without AllowOrigin", got)
+	}
+	if rec := update(h, "http://localhost:8080"); rec.Code != http.StatusNoContent || g.Len() != 0 {
+		t.Errorf("same-origin update: status %d, %d triples left", rec.Code, g.Len())
+	}
+
+	g = testGraph(t)
+	h = NewHandler(g, HandlerOptions{AllowOrigin: "http://localhost:3000"})
+	if rec := update(h, "https://evil.example"); rec.Code != http.StatusForbidden {
+		t.Errorf("update from another origin: status %d", rec.Code)
+	}
+	rec = update(h, "http://localhost:3000")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "http://localhost:3000" {
+		t.Errorf("update from the allowed origin: status %d, headers %v", rec.Code, rec.Header())
+	}
+}
+
+// TestHandlerFailedUpdate checks that an update failing partway leaves the
+// graph as it was.
+func TestHandlerFailedUpdate(t *testing.T) {
+	g := testGraph(t)
+	before := g.Triples()
+	h := NewHandler(g, HandlerOptions{})
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`
+		PREFIX ex: <http://example.org/>
+		DELETE WHERE { ex:alice ?p ?o } ;
+		INSERT DATA { ex:dave ex:age 40 } ;
+		LOAD <http://example.org/missing.ttl>`))
+	req.Header.Set("Content-Type", "application/sparql-update")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if g.Len() != len(before) {
+		t.Errorf("graph has %d triples after a failed update, want %d", g.Len(), len(before))
+	}
+	for _, tr := range before {
+		if !g.Has(tr) {
+			t.Errorf("failed update removed %v", tr)
+		}
+	}
+}
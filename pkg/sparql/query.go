@@ -0,0 +1,219 @@
+// Package sparql parses and evaluates SPARQL 1.1 queries over in-memory
+// triples.
+package sparql
+
+import (
+	"fmt"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// QueryForm is the kind of result a query produces.
+type QueryForm int
+
+const (
+	Select QueryForm = iota
+	Ask
+	Construct
+	Describe
+)
+
+func (f QueryForm) String() string {
+	switch f {
+	case Select:
+		return "SELECT"
+	case Ask:
+		return "ASK"
+	case Construct:
+		return "CONSTRUCT"
+	case Describe:
+		return "DESCRIBE"
+	}
+	return "unknown"
+}
+
+// Query is a parsed SPARQL query. Parse it once and evaluate it with Eval
+// against any number of graphs.
+type Query struct {
+	Form QueryForm
+	// Prefixes holds the prefixes declared in the query and those passed in
+	// ParseOptions.
+	Prefixes map[string]string
+	Base     string
+	// Vars are the variables a SELECT query projects, in order, or those a
+	// DESCRIBE query describes.
+	Vars     []string
+	Distinct bool
+	Reduced  bool
+
+	where       pattern
+	projections []projection
+	groupBy     []groupCondition
+	having      []expression
+	aggregates  []*aggregateExpr
+	orderBy     []orderCondition
+	limit       int
+	offset      int
+	values      *valuesPattern
+	// template holds the triple patterns of a CONSTRUCT query.
+	template []triplePattern
+	// describe holds the IRIs and variables of a DESCRIBE query.
+	describe []term
+}
+
+type ParseOptions struct {
+	// Prefixes are available to the query without PREFIX declarations.
+	Prefixes map[string]string
+	Base     string
+}
+
+// Binding maps variable names, without the leading ?, to the terms bound to
+// them in one solution. Unbound variables are absent.
+type Binding map[string]triple.Node
+
+// Results holds the answer to a query: the solutions of a SELECT query, the
+// boolean of an ASK query or the triples of a CONSTRUCT or DESCRIBE query.
+type Results struct {
+	Form     QueryForm
+	Vars     []string
+	Bindings []Binding
+	Boolean  bool
+	Triples  []triple.Triple
+	// Prefixes are those declared by a remote endpoint's CONSTRUCT or
+	// DESCRIBE response; see Client.
+	Prefixes map[string]string
+}
+
+type projection struct {
+	name string
+	// expr is set for (expression AS ?name) projections.
+	expr expression
+}
+
+type orderCondition struct {
+	expr       expression
+	descending bool
+}
+
+// term is a variable or an RDF term in a triple pattern.
+type term struct {
+	name string
+	node triple.Node
+}
+
+func (t term) isVar() bool {
+	return t.name != ""
+}
+
+// triplePattern matches triples, or with path set, pairs of subject and
+// object connected by a property path; predicate is then unused.
+type triplePattern struct {
+	subject, predicate, object term
+	path                       triple.Path
+}
+
+// Eval runs the query against g.
+func (q *Query) Eval(g triple.Matcher) (*Results, error) {
+	ctx := newEvalContext(g, q.Base)
+
+	switch q.Form {
+	case Ask:
+		solutions, err := q.grouped(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &Results{Form: Ask, Boolean: len(solutions) > 0}, nil
+	case Construct, Describe:
+		solutions, err := q.grouped(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(q.orderBy) > 0 {
+			orderSolutions(ctx, solutions, q.orderBy)
+		}
+		solutions = slice(solutions, q.offset, q.limit)
+		if q.Form == Construct {
+			return &Results{Form: Construct, Triples: instantiate(ctx, q.template, solutions)}, nil
+		}
+		return &Results{Form: Describe, Triples: describe(g, q.describe, solutions)}, nil
+	}
+
+	solutions, err := q.solutions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Results{Form: Select, Vars: append([]string(nil), q.Vars...), Bindings: solutions}, nil
+}
+
+// grouped evaluates the WHERE clause and applies grouping, HAVING and the
+// trailing VALUES clause.
+func (q *Query) grouped(ctx *evalContext) ([]Binding, error) {
+	solutions, err := q.where.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.groupBy != nil || len(q.aggregates) > 0 {
+		solutions = group(ctx, solutions, q.groupBy, q.aggregates)
+		if len(q.having) > 0 {
+			solutions = filter(ctx, solutions, q.having)
+		}
+	}
+
+	if q.values != nil {
+		solutions = join(solutions, q.values.rows)
+	}
+	return solutions, nil
+}
+
+// solutions returns the projected solutions of a SELECT query.
+func (q *Query) solutions(ctx *evalContext) ([]Binding, error) {
+	solutions, err := q.grouped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range q.projections {
+		if p.expr != nil {
+			solutions = extend(ctx, solutions, p.name, p.expr)
+		}
+	}
+
+	if len(q.orderBy) > 0 {
+		orderSolutions(ctx, solutions, q.orderBy)
+	}
+
+	solutions = project(solutions, q.Vars)
+
+	if q.Distinct || q.Reduced {
+		solutions = distinct(solutions, q.Vars)
+	}
+
+	return slice(solutions, q.offset, q.limit), nil
+}
+
+// Exec parses query and evaluates it against g.
+func Exec(g triple.Matcher, query string, opts ParseOptions) (*Results, error) {
+	q, err := Parse(query, opts)
+	if err != nil {
+		return nil, err
+	}
+	return q.Eval(g)
+}
+
+// ExecConstruct runs a CONSTRUCT or DESCRIBE query against g and returns the
+// triples it produces.
+func ExecConstruct(g triple.Matcher, query string, opts ParseOptions) ([]triple.Triple, error) {
+	q, err := Parse(query, opts)
+	if err != nil {
+		return nil, err
+	}
+	if q.Form != Construct && q.Form != Describe {
+		return nil, fmt.Errorf("%s query does not produce triples", q.Form)
+	}
+	res, err := q.Eval(g)
+	if err != nil {
+		return nil, err
+	}
+	return res.Triples, nil
+}
@@ -0,0 +1,284 @@
+package sparql
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// ResultsFormat is a SPARQL 1.1 query results serialization.
+type ResultsFormat string
+
+const (
+	ResultsJSON ResultsFormat = "json"
+	ResultsXML  ResultsFormat = "xml"
+	ResultsCSV  ResultsFormat = "csv"
+	ResultsTSV  ResultsFormat = "tsv"
+)
+
+func ParseResultsFormat(name string) (ResultsFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "json", "srj":
+		return ResultsJSON, nil
+	case "xml", "srx":
+		return ResultsXML, nil
+	case "csv":
+		return ResultsCSV, nil
+	case "tsv":
+		return ResultsTSV, nil
+	}
+	return "", fmt.Errorf("unsupported results format: %s", name)
+}
+
+// MediaType returns the media type the SPARQL 1.1 Protocol uses for the
+// format.
+func (f ResultsFormat) MediaType() string {
+	switch f {
+	case ResultsJSON:
+		return "application/sparql-results+json"
+	case ResultsXML:
+		return "application/sparql-results+xml"
+	case ResultsCSV:
+		return "text/csv"
+	case ResultsTSV:
+		return "text/tab-separated-values"
+	}
+	return ""
+}
+
+// askResultVar names the single column CSV and TSV use for an ASK result,
+// which those formats do not define.
+const askResultVar = "_askResult"
+
+// EncodeResults serializes SELECT or ASK results. The triples of CONSTRUCT
+// and DESCRIBE results are written with package encoder instead.
+func EncodeResults(res *Results, format ResultsFormat) (string, error) {
+	if res.Form == Construct || res.Form == Describe {
+		return "", fmt.Errorf("%s results are triples, not solutions", res.Form)
+	}
+	switch format {
+	case ResultsJSON:
+		return encodeResultsJSON(res)
+	case ResultsXML:
+		return encodeResultsXML(res), nil
+	case ResultsCSV:
+		return encodeResultsCSV(res)
+	case ResultsTSV:
+		return encodeResultsTSV(res), nil
+	}
+	return "", fmt.Errorf("unsupported results format: %s", format)
+}
+
+type jsonResults struct {
+	Head    jsonHead      `json:"head"`
+	Results *jsonBindings `json:"results,omitempty"`
+	Boolean *bool         `json:"boolean,omitempty"`
+}
+
+type jsonHead struct {
+	Vars []string `json:"vars,omitempty"`
+}
+
+type jsonBindings struct {
+	Bindings []map[string]jsonTerm `json:"bindings"`
+}
+
+type jsonTerm struct {
+	Type string `json:"type"`
+	// Value is a string, or a jsonTriple for a triple term.
+	Value     any    `json:"value"`
+	Lang      string `json:"xml:lang,omitempty"`
+	Direction string `json:"its:dir,omitempty"`
+	Datatype  string `json:"datatype,omitempty"`
+}
+
+type jsonTriple struct {
+	Subject   jsonTerm `json:"subject"`
+	Predicate jsonTerm `json:"predicate"`
+	Object    jsonTerm `json:"object"`
+}
+
+func toJSONTerm(n triple.Node) jsonTerm {
+	switch node := n.(type) {
+	case triple.IRI:
+		return jsonTerm{Type: "uri", Value: node.Value}
+	case triple.BlankNode:
+		return jsonTerm{Type: "bnode", Value: node.Value}
+	case triple.Literal:
+		term := jsonTerm{Type: "literal", Value: node.Value, Lang: node.Language, Direction: node.Direction}
+		if node.Language == "" {
+			term.Datatype = node.Datatype
+		}
+		return term
+	case triple.TripleTerm:
+		t := node.Triple
+		return jsonTerm{Type: "triple", Value: jsonTriple{
+			Subject:   toJSONTerm(t.Subject),
+			Predicate: toJSONTerm(t.Predicate),
+			Object:    toJSONTerm(t.Object),
+		}}
+	}
+	return jsonTerm{}
+}
+
+func encodeResultsJSON(res *Results) (string, error) {
+	doc := jsonResults{}
+	if res.Form == Ask {
+		doc.Boolean = &res.Boolean
+	} else {
+		doc.Head.Vars = res.Vars
+		doc.Results = &jsonBindings{Bindings: make([]map[string]jsonTerm, 0, len(res.Bindings))}
+		for _, b := range res.Bindings {
+			row := make(map[string]jsonTerm, len(b))
+			for name, n := range b {
+				row[name] = toJSONTerm(n)
+			}
+			doc.Results.Bindings = append(doc.Results.Bindings, row)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func encodeResultsXML(res *Results) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<sparql xmlns="http://www.w3.org/2005/sparql-results#">` + "\n")
+	b.WriteString("  <head>\n")
+	for _, v := range res.Vars {
+		fmt.Fprintf(&b, "    <variable name=\"%s\"/>\n", xmlEscape(v))
+	}
+	b.WriteString("  </head>\n")
+
+	if res.Form == Ask {
+		fmt.Fprintf(&b, "  <boolean>%t</boolean>\n", res.Boolean)
+	} else {
+		b.WriteString("  <results>\n")
+		for _, binding := range res.Bindings {
+			b.WriteString("    <result>\n")
+			for _, v := range res.Vars {
+				if n, ok := binding[v]; ok {
+					fmt.Fprintf(&b, "      <binding name=\"%s\">%s</binding>\n", xmlEscape(v), xmlTerm(n))
+				}
+			}
+			b.WriteString("    </result>\n")
+		}
+		b.WriteString("  </results>\n")
+	}
+
+	b.WriteString("</sparql>\n")
+	return b.String()
+}
+
+func xmlTerm(n triple.Node) string {
+	switch node := n.(type) {
+	case triple.IRI:
+		return "<uri>" + xmlEscape(node.Value) + "</uri>"
+	case triple.BlankNode:
+		return "<bnode>" + xmlEscape(node.Value) + "</bnode>"
+	case triple.Literal:
+		attrs := ""
+		switch {
+		case node.Language != "":
+			attrs = fmt.Sprintf(` xml:lang="%s"`, xmlEscape(node.Language))
+			if node.Direction != "" {
+				attrs += fmt.Sprintf(` its:dir="%s" xmlns:its="http://www.w3.org/2005/11/its"`, node.Direction)
+			}
+		case node.Datatype != "":
+			attrs = fmt.Sprintf(` datatype="%s"`, xmlEscape(node.Datatype))
+		}
+		return "<literal" + attrs + ">" + xmlEscape(node.Value) + "</literal>"
+	case triple.TripleTerm:
+		t := node.Triple
+		return "<triple><subject>" + xmlTerm(t.Subject) + "</subject><predicate>" + xmlTerm(t.Predicate) +
+			"</predicate><object>" + xmlTerm(t.Object) + "</object></triple>"
+	}
+	return ""
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func encodeResultsCSV(res *Results) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.UseCRLF = true
+
+	if res.Form == Ask {
+		w.Write([]string{askResultVar})
+		w.Write([]string{fmt.Sprint(res.Boolean)})
+	} else {
+		w.Write(res.Vars)
+		for _, b := range res.Bindings {
+			record := make([]string, len(res.Vars))
+			for i, v := range res.Vars {
+				if n, ok := b[v]; ok {
+					record[i] = csvTerm(n)
+				}
+			}
+			w.Write(record)
+		}
+	}
+
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// csvTerm gives the plain value of a term: CSV drops datatypes and language
+// tags.
+func csvTerm(n triple.Node) string {
+	switch node := n.(type) {
+	case triple.IRI:
+		return node.Value
+	case triple.BlankNode:
+		return "_:" + node.Value
+	case triple.Literal:
+		return node.Value
+	}
+	return termKey(n)
+}
+
+func encodeResultsTSV(res *Results) string {
+	var b strings.Builder
+
+	if res.Form == Ask {
+		fmt.Fprintf(&b, "?%s\n%t\n", askResultVar, res.Boolean)
+		return b.String()
+	}
+
+	for i, v := range res.Vars {
+		if i > 0 {
+			b.WriteByte('\t')
+		}
+		b.WriteString("?" + v)
+	}
+	b.WriteByte('\n')
+
+	for _, binding := range res.Bindings {
+		for i, v := range res.Vars {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			if n, ok := binding[v]; ok {
+				b.WriteString(strings.ReplaceAll(termKey(n), "\t", `\t`))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
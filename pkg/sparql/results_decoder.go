@@ -0,0 +1,322 @@
+package sparql
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+// ResultsFormatForMediaType returns the results format served as a media
+// type such as "application/sparql-results+json". Parameters like charset
+// are ignored.
+func ResultsFormatForMediaType(mediaType string) (ResultsFormat, error) {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case ResultsJSON.MediaType(), "application/json":
+		return ResultsJSON, nil
+	case ResultsXML.MediaType(), "application/xml":
+		return ResultsXML, nil
+	case ResultsCSV.MediaType():
+		return ResultsCSV, nil
+	case ResultsTSV.MediaType():
+		return ResultsTSV, nil
+	}
+	return "", fmt.Errorf("unsupported results media type: %s", mediaType)
+}
+
+// DecodeResults parses SELECT or ASK results. CSV does not record term
+// types, so CSV values that are absolute IRIs become IRIs, values starting
+// with _: blank nodes and all others plain literals.
+func DecodeResults(input string, format ResultsFormat) (*Results, error) {
+	// Some endpoints start CSV and TSV with a byte order mark.
+	input = strings.TrimPrefix(input, "\ufeff")
+	switch format {
+	case ResultsJSON:
+		return decodeResultsJSON(input)
+	case ResultsXML:
+		return decodeResultsXML(input)
+	case ResultsCSV:
+		return decodeResultsCSV(input)
+	case ResultsTSV:
+		return decodeResultsTSV(input)
+	}
+	return nil, fmt.Errorf("unsupported results format: %s", format)
+}
+
+// jsonTermIn is jsonTerm as read, with the value left raw because it is a
+// string or, for a triple term, an object.
+type jsonTermIn struct {
+	Type      string          `json:"type"`
+	Value     json.RawMessage `json:"value"`
+	Lang      string          `json:"xml:lang"`
+	Direction string          `json:"its:dir"`
+	Datatype  string          `json:"datatype"`
+}
+
+func decodeResultsJSON(input string) (*Results, error) {
+	var doc struct {
+		Head    jsonHead `json:"head"`
+		Boolean *bool    `json:"boolean"`
+		Results *struct {
+			Bindings []map[string]jsonTermIn `json:"bindings"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(input), &doc); err != nil {
+		return nil, fmt.Errorf("decoding JSON results: %w", err)
+	}
+
+	if doc.Boolean != nil {
+		return &Results{Form: Ask, Boolean: *doc.Boolean}, nil
+	}
+	if doc.Results == nil {
+		return nil, fmt.Errorf("decoding JSON results: neither results nor boolean")
+	}
+
+	res := &Results{Form: Select, Vars: doc.Head.Vars}
+	for _, row := range doc.Results.Bindings {
+		b := make(Binding, len(row))
+		for name, term := range row {
+			n, err := fromJSONTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("decoding JSON results: ?%s: %w", name, err)
+			}
+			b[name] = n
+		}
+		res.Bindings = append(res.Bindings, b)
+	}
+	return res, nil
+}
+
+func fromJSONTerm(term jsonTermIn) (triple.Node, error) {
+	if term.Type == "triple" {
+		var t struct {
+			Subject   jsonTermIn `json:"subject"`
+			Predicate jsonTermIn `json:"predicate"`
+			Object    jsonTermIn `json:"object"`
+		}
+		if err := json.Unmarshal(term.Value, &t); err != nil {
+			return nil, err
+		}
+		var nodes [3]triple.Node
+		for i, part := range []jsonTermIn{t.Subject, t.Predicate, t.Object} {
+			n, err := fromJSONTerm(part)
+			if err != nil {
+				return nil, err
+			}
+			nodes[i] = n
+		}
+		return triple.TripleTerm{Triple: triple.Triple{Subject: nodes[0], Predicate: nodes[1], Object: nodes[2]}}, nil
+	}
+
+	var value string
+	if err := json.Unmarshal(term.Value, &value); err != nil {
+		return nil, err
+	}
+	switch term.Type {
+	case "uri":
+		return triple.IRI{Value: value}, nil
+	case "bnode":
+		return triple.BlankNode{Value: value}, nil
+	case "literal", "typed-literal":
+		return newLiteral(value, term.Lang, term.Direction, term.Datatype), nil
+	}
+	return nil, fmt.Errorf("unknown term type %q", term.Type)
+}
+
+// newLiteral builds a literal. A language-tagged literal keeps no datatype,
+// although some endpoints send rdf:langString.
+func newLiteral(value, lang, direction, datatype string) triple.Literal {
+	if lang != "" {
+		datatype = ""
+	}
+	return triple.Literal{Value: value, Language: lang, Direction: direction, Datatype: datatype}
+}
+
+type xmlTermIn struct {
+	URI     *string `xml:"uri"`
+	BNode   *string `xml:"bnode"`
+	Literal *struct {
+		Value     string `xml:",chardata"`
+		Lang      string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+		Direction string `xml:"http://www.w3.org/2005/11/its dir,attr"`
+		Datatype  string `xml:"datatype,attr"`
+	} `xml:"literal"`
+	Triple *struct {
+		Subject   xmlTermIn `xml:"subject"`
+		Predicate xmlTermIn `xml:"predicate"`
+		Object    xmlTermIn `xml:"object"`
+	} `xml:"triple"`
+}
+
+func decodeResultsXML(input string) (*Results, error) {
+	var doc struct {
+		Head struct {
+			Variables []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"variable"`
+		} `xml:"head"`
+		Boolean *bool `xml:"boolean"`
+		Results *struct {
+			Results []struct {
+				Bindings []struct {
+					Name string `xml:"name,attr"`
+					xmlTermIn
+				} `xml:"binding"`
+			} `xml:"result"`
+		} `xml:"results"`
+	}
+	if err := xml.Unmarshal([]byte(input), &doc); err != nil {
+		return nil, fmt.Errorf("decoding XML results: %w", err)
+	}
+
+	if doc.Boolean != nil {
+		return &Results{Form: Ask, Boolean: *doc.Boolean}, nil
+	}
+	if doc.Results == nil {
+		return nil, fmt.Errorf("decoding XML results: neither results nor boolean")
+	}
+
+	res := &Results{Form: Select}
+	for _, v := range doc.Head.Variables {
+		res.Vars = append(res.Vars, v.Name)
+	}
+	for _, row := range doc.Results.Results {
+		b := make(Binding, len(row.Bindings))
+		for _, binding := range row.Bindings {
+			n, err := fromXMLTerm(binding.xmlTermIn)
+			if err != nil {
+				return nil, fmt.Errorf("decoding XML results: ?%s: %w", binding.Name, err)
+			}
+			b[binding.Name] = n
+		}
+		res.Bindings = append(res.Bindings, b)
+	}
+	return res, nil
+}
+
+func fromXMLTerm(term xmlTermIn) (triple.Node, error) {
+	switch {
+	case term.URI != nil:
+		return triple.IRI{Value: *term.URI}, nil
+	case term.BNode != nil:
+		return triple.BlankNode{Value: *term.BNode}, nil
+	case term.Literal != nil:
+		l := term.Literal
+		return newLiteral(l.Value, l.Lang, l.Direction, l.Datatype), nil
+	case term.Triple != nil:
+		var nodes [3]triple.Node
+		for i, part := range []xmlTermIn{term.Triple.Subject, term.Triple.Predicate, term.Triple.Object} {
+			n, err := fromXMLTerm(part)
+			if err != nil {
+				return nil, err
+			}
+			nodes[i] = n
+		}
+		return triple.TripleTerm{Triple: triple.Triple{Subject: nodes[0], Predicate: nodes[1], Object: nodes[2]}}, nil
+	}
+	return nil, fmt.Errorf("binding has no term")
+}
+
+func decodeResultsCSV(input string) (*Results, error) {
+	r := csv.NewReader(strings.NewReader(input))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("decoding CSV results: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("decoding CSV results: missing header")
+	}
+
+	header := records[0]
+	if len(header) == 1 && header[0] == askResultVar && len(records) == 2 {
+		return &Results{Form: Ask, Boolean: records[1][0] == "true"}, nil
+	}
+
+	res := &Results{Form: Select, Vars: header}
+	for _, record := range records[1:] {
+		b := make(Binding, len(header))
+		for i, value := range record {
+			if i >= len(header) || value == "" {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(value, "_:"):
+				b[header[i]] = triple.BlankNode{Value: value[2:]}
+			case isAbsoluteIRI(value):
+				b[header[i]] = triple.IRI{Value: value}
+			default:
+				b[header[i]] = triple.Literal{Value: value}
+			}
+		}
+		res.Bindings = append(res.Bindings, b)
+	}
+	return res, nil
+}
+
+var iriSchemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:\S*$`)
+
+func isAbsoluteIRI(s string) bool {
+	return iriSchemePattern.MatchString(s) && triple.ValidateIRI(s) == nil
+}
+
+func decodeResultsTSV(input string) (*Results, error) {
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(input, "\r\n", "\n"), "\n"), "\n")
+	if lines[0] == "" {
+		return nil, fmt.Errorf("decoding TSV results: missing header")
+	}
+
+	var vars []string
+	for _, v := range strings.Split(lines[0], "\t") {
+		vars = append(vars, strings.TrimPrefix(strings.TrimPrefix(v, "?"), "$"))
+	}
+	if len(vars) == 1 && vars[0] == askResultVar && len(lines) == 2 {
+		return &Results{Form: Ask, Boolean: lines[1] == "true"}, nil
+	}
+
+	res := &Results{Form: Select, Vars: vars}
+	for i, line := range lines[1:] {
+		b := make(Binding, len(vars))
+		for j, field := range strings.Split(line, "\t") {
+			if j >= len(vars) || field == "" {
+				continue
+			}
+			n, err := decodeTSVTerm(field)
+			if err != nil {
+				return nil, fmt.Errorf("decoding TSV results: line %d: ?%s: %w", i+2, vars[j], err)
+			}
+			b[vars[j]] = n
+		}
+		res.Bindings = append(res.Bindings, b)
+	}
+	return res, nil
+}
+
+var (
+	tsvInteger = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	tsvDecimal = regexp.MustCompile(`^[+-]?[0-9]*\.[0-9]+$`)
+	tsvDouble  = regexp.MustCompile(`^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)[eE][+-]?[0-9]+$`)
+)
+
+// decodeTSVTerm parses a TSV value: an N-Triples term, or a number or
+// boolean in the Turtle shorthand some endpoints use.
+func decodeTSVTerm(s string) (triple.Node, error) {
+	switch {
+	case s == "true" || s == "false":
+		return triple.Literal{Value: s, Datatype: xsd.Boolean}, nil
+	case tsvInteger.MatchString(s):
+		return triple.Literal{Value: s, Datatype: xsd.Integer}, nil
+	case tsvDecimal.MatchString(s):
+		return triple.Literal{Value: s, Datatype: xsd.Decimal}, nil
+	case tsvDouble.MatchString(s):
+		return triple.Literal{Value: s, Datatype: xsd.Double}, nil
+	}
+	return encoder.DecodeTerm(s)
+}
@@ -0,0 +1,171 @@
+package sparql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func TestEncodeResults(t *testing.T) {
+	res := &Results{
+		Form: Select,
+		Vars: []string{"s", "name", "n"},
+		Bindings: []Binding{
+			{
+				"s":    triple.IRI{Value: "http://example.org/a?x=1&y=2"},
+				"name": triple.Literal{Value: "Al\tice, \"A\"", Language: "en"},
+				"n":    triple.NewIntegerLiteral(3),
+			},
+			{"s": triple.BlankNode{Value: "b0"}},
+		},
+	}
+
+	tests := []struct {
+		format ResultsFormat
+		want   []string
+	}{
+		{ResultsJSON, []string{
+			`"vars": [`,
+			`"type": "uri",
+          "value": "http://example.org/a?x=1&y=2"`,
+			`"xml:lang": "en"`,
+			`"datatype": "http://www.w3.org/2001/XMLSchema#integer"`,
+			`"type": "bnode",
+          "value": "b0"`,
+		}},
+		{ResultsXML, []string{
+			`<variable name="name"/>`,
+			`<binding name="s"><uri>http://example.org/a?x=1&amp;y=2</uri></binding>`,
+			`<binding name="name"><literal xml:lang="en">Al&#x9;ice, &#34;A&#34;</literal></binding>`,
+			`<binding name="s"><bnode>b0</bnode></binding>`,
+		}},
+		{ResultsCSV, []string{
+			"s,name,n\r\n",
+			"http://example.org/a?x=1&y=2,\"Al\tice, \"\"A\"\"\",3\r\n",
+			"_:b0,,\r\n",
+		}},
+		{ResultsTSV, []string{
+			"?s\t?name\t?n\n",
+			"<http://example.org/a?x=1&y=2>\t\"Al\\tice, \\\"A\\\"\"@en\t\"3\"^^<http://www.w3.org/2001/XMLSchema#integer>\n",
+			"_:b0\t\t\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got, err := EncodeResults(res, tt.format)
+			if err != nil {
+				t.Fatalf("EncodeResults() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("EncodeResults() = %s\nwant to contain %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeAskResults(t *testing.T) {
+	res := &Results{Form: Ask, Boolean: true}
+
+	want := map[ResultsFormat]string{
+		ResultsJSON: `"boolean": true`,
+		ResultsXML:  "<boolean>true</boolean>",
+		ResultsCSV:  "_askResult\r\ntrue\r\n",
+		ResultsTSV:  "?_askResult\ntrue\n",
+	}
+	for format, w := range want {
+		got, err := EncodeResults(res, format)
+		if err != nil {
+			t.Fatalf("EncodeResults(%s) error = %v", format, err)
+		}
+		if !strings.Contains(got, w) {
+			t.Errorf("EncodeResults(%s) = %q, want to contain %q", format, got, w)
+		}
+	}
+
+	if _, err := ParseResultsFormat("yaml"); err == nil {
+		t.Error("ParseResultsFormat(yaml) succeeded, want error")
+	}
+}
+
+func TestDecodeResults(t *testing.T) {
+	res := &Results{
+		Form: Select,
+		Vars: []string{"s", "name", "n", "t"},
+		Bindings: []Binding{
+			{
+				"s":    triple.IRI{Value: "http://example.org/a?x=1&y=2"},
+				"name": triple.Literal{Value: "Al\tice, \"A\"", Language: "ar", Direction: "rtl"},
+				"n":    triple.NewIntegerLiteral(3),
+				"t": triple.TripleTerm{Triple: triple.Triple{
+					Subject:   triple.BlankNode{Value: "b1"},
+					Predicate: triple.IRI{Value: "http://example.org/p"},
+					Object:    triple.Literal{Value: "o", Datatype: "http://www.w3.org/2001/XMLSchema#string"},
+				}},
+			},
+			{"s": triple.BlankNode{Value: "b0"}},
+		},
+	}
+
+	// JSON, XML and TSV keep every term exactly.
+	for _, format := range []ResultsFormat{ResultsJSON, ResultsXML, ResultsTSV} {
+		t.Run(string(format), func(t *testing.T) {
+			encoded, err := EncodeResults(res, format)
+			if err != nil {
+				t.Fatalf("EncodeResults() error = %v", err)
+			}
+			got, err := DecodeResults(encoded, format)
+			if err != nil {
+				t.Fatalf("DecodeResults() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, res) {
+				t.Errorf("DecodeResults() = %#v\nwant %#v", got, res)
+			}
+
+			encoded, _ = EncodeResults(&Results{Form: Ask, Boolean: true}, format)
+			if got, err := DecodeResults(encoded, format); err != nil || got.Form != Ask || !got.Boolean {
+				t.Errorf("DecodeResults(ASK) = %v, %v", got, err)
+			}
+		})
+	}
+
+	// CSV keeps only the lexical forms.
+	got, err := DecodeResults("\ufeffs,name,n\r\nhttp://example.org/a,\"Al, ice\",3\r\n_:b0,,\r\n", ResultsCSV)
+	if err != nil {
+		t.Fatalf("DecodeResults(CSV) error = %v", err)
+	}
+	want := []Binding{
+		{"s": triple.IRI{Value: "http://example.org/a"}, "name": triple.Literal{Value: "Al, ice"}, "n": triple.Literal{Value: "3"}},
+		{"s": triple.BlankNode{Value: "b0"}},
+	}
+	if !reflect.DeepEqual(got.Bindings, want) {
+		t.Errorf("DecodeResults(CSV) = %v, want %v", got.Bindings, want)
+	}
+
+	// Other endpoints write numbers bare in TSV and rdf:langString in XML.
+	got, err = DecodeResults("?n\t?b\n1.5\ttrue\n", ResultsTSV)
+	if err != nil || got.Bindings[0]["n"] != (triple.Literal{Value: "1.5", Datatype: "http://www.w3.org/2001/XMLSchema#decimal"}) ||
+		got.Bindings[0]["b"] != (triple.Literal{Value: "true", Datatype: "http://www.w3.org/2001/XMLSchema#boolean"}) {
+		t.Errorf("DecodeResults(TSV shorthand) = %v, %v", got, err)
+	}
+	xmlInput := `<sparql xmlns="http://www.w3.org/2005/sparql-results#"><head><variable name="x"/></head><results><result>
+<binding name="x"><literal xml:lang="en" datatype="http://www.w3.org/1999/02/22-rdf-syntax-ns#langString">hi</literal></binding>
+</result></results></sparql>`
+	if got, err = DecodeResults(xmlInput, ResultsXML); err != nil || got.Bindings[0]["x"] != (triple.Literal{Value: "hi", Language: "en"}) {
+		t.Errorf("DecodeResults(XML langString) = %v, %v", got, err)
+	}
+
+	for format, input := range map[ResultsFormat]string{
+		ResultsJSON: `{"head": {}}`,
+		ResultsXML:  `<sparql>`,
+		ResultsTSV:  "?x\n<unterminated\n",
+	} {
+		if _, err := DecodeResults(input, format); err == nil {
+			t.Errorf("DecodeResults(%s, %q) succeeded", format, input)
+		}
+	}
+}
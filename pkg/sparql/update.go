@@ -0,0 +1,270 @@
+package sparql
+
+import (
+	"fmt"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// Updatable is a graph that SPARQL Update can change, such as *triple.Graph.
+type Updatable interface {
+	triple.Matcher
+	Add(t triple.Triple) bool
+	Remove(t triple.Triple) bool
+}
+
+// Update is a parsed SPARQL 1.1 Update request: a sequence of operations
+// applied in order.
+type Update struct {
+	Prefixes map[string]string
+	Base     string
+
+	operations []operation
+}
+
+// UpdateOptions configures parsing and applying an update.
+type UpdateOptions struct {
+	ParseOptions
+	// Load returns the triples of the document named by a LOAD operation.
+	// LOAD fails when it is nil.
+	Load func(iri string) ([]triple.Triple, error)
+}
+
+type operation interface {
+	apply(ctx *evalContext, g Updatable, opts UpdateOptions) error
+}
+
+// ParseUpdate parses a SPARQL Update request.
+func ParseUpdate(update string, opts ParseOptions) (*Update, error) {
+	p, err := newParser(update, opts)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseUpdate()
+}
+
+// Apply runs the operations against g. Operations before a failing one stay
+// applied.
+func (u *Update) Apply(g Updatable, opts UpdateOptions) error {
+	ctx := newEvalContext(g, u.Base)
+	for _, op := range u.operations {
+		if err := op.apply(ctx, g, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExecUpdate parses update and applies it to g.
+func ExecUpdate(g Updatable, update string, opts UpdateOptions) error {
+	u, err := ParseUpdate(update, opts.ParseOptions)
+	if err != nil {
+		return err
+	}
+	return u.Apply(g, opts)
+}
+
+// modifyOperation covers INSERT DATA, DELETE DATA, DELETE WHERE and
+// DELETE/INSERT ... WHERE: the templates are filled with the solutions of
+// where, all deletions are made and then all insertions.
+type modifyOperation struct {
+	delete, insert []triplePattern
+	where          pattern
+}
+
+func (op *modifyOperation) apply(ctx *evalContext, g Updatable, _ UpdateOptions) error {
+	solutions, err := op.where.eval(ctx)
+	if err != nil {
+		return err
+	}
+
+	deletes := instantiate(ctx, op.delete, solutions)
+	inserts := instantiate(ctx, op.insert, solutions)
+	for _, t := range deletes {
+		g.Remove(t)
+	}
+	for _, t := range inserts {
+		g.Add(t)
+	}
+	return nil
+}
+
+// clearOperation removes every triple, for CLEAR DEFAULT and CLEAR ALL.
+type clearOperation struct{}
+
+func (clearOperation) apply(_ *evalContext, g Updatable, _ UpdateOptions) error {
+	for _, t := range g.Match(nil, nil, nil) {
+		g.Remove(t)
+	}
+	return nil
+}
+
+type loadOperation struct {
+	iri    string
+	silent bool
+}
+
+func (op *loadOperation) apply(_ *evalContext, g Updatable, opts UpdateOptions) error {
+	if opts.Load == nil {
+		if op.silent {
+			return nil
+		}
+		return fmt.Errorf("LOAD <%s>: loading documents is not enabled", op.iri)
+	}
+	triples, err := opts.Load(op.iri)
+	if err != nil {
+		if op.silent {
+			return nil
+		}
+		return fmt.Errorf("LOAD <%s>: %w", op.iri, err)
+	}
+	for _, t := range triples {
+		g.Add(t)
+	}
+	return nil
+}
+
+func (p *parser) parseUpdate() (*Update, error) {
+	u := &Update{}
+	for {
+		if err := p.parsePrologue(); err != nil {
+			return nil, err
+		}
+		if p.peek().kind == tokEOF {
+			break
+		}
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		u.operations = append(u.operations, op)
+		if !p.accept(";") {
+			break
+		}
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.unexpected(`";" or end of update`)
+	}
+
+	u.Prefixes = p.resolver.All()
+	u.Base = p.base
+	return u, nil
+}
+
+func (p *parser) parseOperation() (operation, error) {
+	tok := p.next()
+	switch {
+	case tok.isWord("INSERT") && p.acceptWord("DATA"):
+		template, err := p.parseDataTemplate("INSERT DATA", true)
+		if err != nil {
+			return nil, err
+		}
+		return &modifyOperation{insert: template, where: emptyGroup()}, nil
+	case tok.isWord("DELETE") && p.acceptWord("DATA"):
+		template, err := p.parseDataTemplate("DELETE DATA", false)
+		if err != nil {
+			return nil, err
+		}
+		return &modifyOperation{delete: template, where: emptyGroup()}, nil
+	case tok.isWord("DELETE") && p.acceptWord("WHERE"):
+		template, err := p.parseDeleteTemplate()
+		if err != nil {
+			return nil, err
+		}
+		return &modifyOperation{delete: template, where: &bgp{triples: template}}, nil
+	case tok.isWord("DELETE"), tok.isWord("INSERT"):
+		return p.parseModify(tok)
+	case tok.isWord("CLEAR"):
+		p.acceptWord("SILENT")
+		switch {
+		case p.acceptWord("DEFAULT"), p.acceptWord("ALL"):
+			return clearOperation{}, nil
+		case p.peek().isWord("GRAPH"), p.peek().isWord("NAMED"):
+			return nil, p.errorf("named graphs are not supported")
+		}
+		return nil, p.unexpected("DEFAULT or ALL after CLEAR")
+	case tok.isWord("LOAD"):
+		op := &loadOperation{silent: p.acceptWord("SILENT")}
+		iri := p.peek()
+		if iri.kind != tokIRI {
+			return nil, p.unexpected("IRI after LOAD")
+		}
+		p.next()
+		op.iri = p.resolveIRI(iri.text)
+		if p.peek().isWord("INTO") {
+			return nil, p.errorf("named graphs are not supported")
+		}
+		return op, nil
+	case tok.isWord("WITH"), tok.isWord("DROP"), tok.isWord("CREATE"), tok.isWord("ADD"), tok.isWord("MOVE"), tok.isWord("COPY"):
+		return nil, fmt.Errorf("%s is not supported at line %d, column %d", tok.text, tok.line, tok.column)
+	}
+	p.pos--
+	return nil, p.unexpected("update operation")
+}
+
+// parseModify parses DELETE { ... } INSERT { ... } WHERE { ... }, where
+// either template may be left out. The DELETE or INSERT keyword has been
+// consumed.
+func (p *parser) parseModify(first token) (operation, error) {
+	op := &modifyOperation{}
+	var err error
+	if first.isWord("DELETE") {
+		if op.delete, err = p.parseDeleteTemplate(); err != nil {
+			return nil, err
+		}
+		if p.acceptWord("INSERT") {
+			if op.insert, err = p.parseTriplesTemplate(); err != nil {
+				return nil, err
+			}
+		}
+	} else if op.insert, err = p.parseTriplesTemplate(); err != nil {
+		return nil, err
+	}
+
+	if p.peek().isWord("USING") {
+		return nil, p.errorf("USING is not supported")
+	}
+	if err := p.expectWord("WHERE"); err != nil {
+		return nil, err
+	}
+	if op.where, err = p.parseGroupGraphPattern(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// parseDataTemplate parses the triples of INSERT DATA or DELETE DATA, which
+// may not contain variables. Blank nodes are only allowed when inserting.
+func (p *parser) parseDataTemplate(op string, blankNodes bool) ([]triplePattern, error) {
+	template, err := p.parseTriplesTemplate()
+	if err != nil {
+		return nil, err
+	}
+	for _, tp := range template {
+		for _, t := range []term{tp.subject, tp.predicate, tp.object} {
+			switch {
+			case !t.isVar():
+			case !isHidden(t.name):
+				return nil, fmt.Errorf("variable ?%s is not allowed in %s", t.name, op)
+			case !blankNodes:
+				return nil, fmt.Errorf("blank nodes are not allowed in %s", op)
+			}
+		}
+	}
+	return template, nil
+}
+
+func (p *parser) parseDeleteTemplate() ([]triplePattern, error) {
+	template, err := p.parseTriplesTemplate()
+	if err != nil {
+		return nil, err
+	}
+	for _, tp := range template {
+		for _, t := range []term{tp.subject, tp.predicate, tp.object} {
+			if t.isVar() && isHidden(t.name) {
+				return nil, fmt.Errorf("blank nodes are not allowed in DELETE")
+			}
+		}
+	}
+	return template, nil
+}
@@ -0,0 +1,152 @@
+package sparql
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		update string
+		want   []string
+	}{
+		{
+			name:   "insert data",
+			update: `INSERT DATA { ex:dave foaf:name "Dave" ; foaf:knows _:x . _:x foaf:name "X" }`,
+			want: []string{
+				"<alice> <knows> <bob>",
+				"<alice> <knows> <carol>",
+				"<bob> <knows> <carol>",
+				`<dave> <knows> _:b1`,
+				`<dave> <name> "Dave"`,
+				`_:b1 <name> "X"`,
+			},
+		},
+		{
+			name:   "delete data",
+			update: `DELETE DATA { ex:bob foaf:knows ex:carol ; foaf:age 99 }`,
+			want:   []string{"<alice> <knows> <bob>", "<alice> <knows> <carol>"},
+		},
+		{
+			name:   "delete insert where",
+			update: `DELETE { ?a foaf:knows ?b } INSERT { ?b ex:knownBy ?a } WHERE { ?a foaf:knows ?b FILTER(?b = ex:carol) }`,
+			want: []string{
+				"<alice> <knows> <bob>",
+				"<carol> <knownBy> <alice>",
+				"<carol> <knownBy> <bob>",
+			},
+		},
+		{
+			name:   "delete where",
+			update: `DELETE WHERE { ex:alice foaf:knows ?x }`,
+			want:   []string{"<bob> <knows> <carol>"},
+		},
+		{
+			name:   "insert where",
+			update: `INSERT { ?b foaf:knows ?a } WHERE { ?a foaf:knows ex:bob . ?a foaf:knows ?b FILTER(?b != ex:bob) }`,
+			want: []string{
+				"<alice> <knows> <bob>",
+				"<alice> <knows> <carol>",
+				"<bob> <knows> <carol>",
+				"<carol> <knows> <alice>",
+			},
+		},
+		{
+			name:   "sequence",
+			update: `CLEAR DEFAULT ; PREFIX x: <http://example.org/> INSERT DATA { x:a foaf:knows x:b }`,
+			want:   []string{"<a> <knows> <b>"},
+		},
+		{
+			name:   "clear",
+			update: `CLEAR ALL`,
+			want:   nil,
+		},
+	}
+
+	knows := triple.IRI{Value: "http://xmlns.com/foaf/0.1/knows"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := testGraph(t)
+			if err := ExecUpdate(g, testPrefixes+tt.update, UpdateOptions{}); err != nil {
+				t.Fatalf("ExecUpdate() error = %v", err)
+			}
+
+			// Compare the triples touched by the tests: foaf:knows,
+			// ex:knownBy and anything about ex:dave or blank nodes.
+			var got []triple.Triple
+			for _, tr := range g.Triples() {
+				_, blank := tr.Subject.(triple.BlankNode)
+				if tr.Predicate == knows || blank || tr.Subject == (triple.IRI{Value: "http://example.org/dave"}) ||
+					tr.Predicate == (triple.IRI{Value: "http://example.org/knownBy"}) {
+					got = append(got, tr)
+				}
+			}
+			if tt.name == "clear" && g.Len() != 0 {
+				t.Errorf("Len() = %d after CLEAR, want 0", g.Len())
+			}
+			if lines := ntriples(got); !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("graph = %q, want %q", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateLoad(t *testing.T) {
+	loaded := triple.Triple{
+		Subject:   triple.IRI{Value: "http://example.org/x"},
+		Predicate: triple.IRI{Value: "http://example.org/p"},
+		Object:    triple.Literal{Value: "loaded"},
+	}
+	opts := UpdateOptions{Load: func(iri string) ([]triple.Triple, error) {
+		if iri != "http://example.org/data.ttl" {
+			return nil, errors.New("not found")
+		}
+		return []triple.Triple{loaded}, nil
+	}}
+
+	g := triple.NewGraph(nil)
+	if err := ExecUpdate(g, `BASE <http://example.org/> LOAD <data.ttl>`, opts); err != nil {
+		t.Fatalf("ExecUpdate() error = %v", err)
+	}
+	if !g.Has(loaded) {
+		t.Errorf("LOAD did not add the document's triples")
+	}
+
+	if err := ExecUpdate(g, `LOAD <http://example.org/missing.ttl>`, opts); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("LOAD of a missing document: error = %v", err)
+	}
+	if err := ExecUpdate(g, `LOAD SILENT <http://example.org/missing.ttl>`, opts); err != nil {
+		t.Errorf("LOAD SILENT error = %v", err)
+	}
+	if err := ExecUpdate(g, `LOAD <http://example.org/data.ttl>`, UpdateOptions{}); err == nil {
+		t.Errorf("LOAD without a loader succeeded")
+	}
+}
+
+func TestParseUpdateErrors(t *testing.T) {
+	tests := []struct {
+		update string
+		want   string
+	}{
+		{`INSERT DATA { ?s <p> <o> }`, "variable ?s is not allowed in INSERT DATA"},
+		{`DELETE DATA { _:b <p> <o> }`, "blank nodes are not allowed in DELETE DATA"},
+		{`DELETE { _:b <p> ?o } WHERE { ?s <p> ?o }`, "blank nodes are not allowed in DELETE"},
+		{`INSERT { <s> <p> ?o }`, `expected WHERE`},
+		{`CLEAR GRAPH <g>`, "named graphs are not supported"},
+		{`DROP ALL`, "DROP is not supported"},
+		{`INSERT DATA { <s> <p> <o> } INSERT DATA { <s> <p> <o> }`, `";" or end of update`},
+		{`SELECT * WHERE { ?s ?p ?o }`, "expected update operation"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseUpdate(tt.update, ParseOptions{})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseUpdate(%q) error = %v, want %q", tt.update, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// dictionary maps terms to IDs. The terms file holds one term per line in
+// N-Triples syntax, and a term's ID is its line number, so the file is only
+// ever appended to. It is safe for concurrent use.
+type dictionary struct {
+	mu    sync.RWMutex
+	terms []triple.Node
+	ids   map[triple.Node]uint64
+	file  *os.File
+	w     *bufio.Writer
+}
+
+func openDictionary(path string) (*dictionary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// A line without its newline was cut short by a crash, and no index or
+	// log entry can refer to it yet.
+	size := bytes.LastIndexByte(data, '\n') + 1
+	d := &dictionary{ids: make(map[triple.Node]uint64)}
+	for rest := data[:size]; len(rest) > 0; {
+		var line []byte
+		line, rest, _ = bytes.Cut(rest, []byte("\n"))
+		n, err := encoder.DecodeTerm(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("%s: term %d: %w", path, len(d.terms)+1, err)
+		}
+		d.terms = append(d.terms, n)
+		d.ids[n] = uint64(len(d.terms))
+	}
+
+	if d.file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		return nil, err
+	}
+	if size < len(data) {
+		if err := d.file.Truncate(int64(size)); err != nil {
+			d.file.Close()
+			return nil, err
+		}
+	}
+	if _, err := d.file.Seek(0, io.SeekEnd); err != nil {
+		d.file.Close()
+		return nil, err
+	}
+	d.w = bufio.NewWriter(d.file)
+	return d, nil
+}
+
+// lookup returns the ID of n, or 0 if it has none.
+func (d *dictionary) lookup(n triple.Node) uint64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.ids[n]
+}
+
+// intern returns the ID of n, adding it to the dictionary if needed.
+func (d *dictionary) intern(n triple.Node) (uint64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if id, ok := d.ids[n]; ok {
+		return id, nil
+	}
+	if _, err := d.w.WriteString(encoder.EncodeTerm(n) + "\n"); err != nil {
+		return 0, err
+	}
+	d.terms = append(d.terms, n)
+	id := uint64(len(d.terms))
+	d.ids[n] = id
+	return id, nil
+}
+
+func (d *dictionary) term(id uint64) triple.Node {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.terms[id-1]
+}
+
+func (d *dictionary) len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.terms)
+}
+
+// sync writes buffered terms to disk. It must come before writing any log
+// entry that uses them.
+func (d *dictionary) sync() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.w.Flush(); err != nil {
+		return err
+	}
+	return d.file.Sync()
+}
+
+func (d *dictionary) close() error {
+	err := d.sync()
+	if cerr := d.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,238 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// Every committed transaction is a version of the store. Unlike the
+// write-ahead log, which checkpoints empty, the history keeps them all:
+//
+//	history     the changes of each version, in the write-ahead log's format
+//	versions    one line per version: ID, time, triples added and removed, and message
+//
+// A version is written to both before its transaction is committed to the
+// write-ahead log, and versions past the last committed transaction are
+// dropped when the store is opened.
+const (
+	historyName  = "history"
+	versionsName = "versions"
+)
+
+// Version describes a committed transaction. Version IDs count up from 1;
+// version 0 is the empty store.
+type Version struct {
+	ID      uint64
+	Time    time.Time
+	Message string
+	// Added and Removed are the numbers of triples the version added and
+	// removed.
+	Added, Removed int
+}
+
+// openHistory opens the history files for appending, dropping versions
+// after the last committed transaction.
+func (s *Store) openHistory(valid func(entry) bool) error {
+	hist, txs, err := openWAL(filepath.Join(s.dir, historyName), valid)
+	if err != nil {
+		return err
+	}
+	s.hist = hist
+	for i, tx := range txs {
+		if tx.seq > s.seq {
+			var end int64
+			if i > 0 {
+				end = txs[i-1].end
+			}
+			if err := hist.truncate(end); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	path := filepath.Join(s.dir, versionsName)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	_, size := parseVersions(data, s.seq)
+	if size < len(data) {
+		if err := s.replaceFile(versionsName, data[:size]); err != nil {
+			return err
+		}
+	}
+	s.versions, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	return err
+}
+
+// parseVersions parses the lines of the versions file up to version last,
+// and returns them with the length they take up. A line cut short by a
+// crash ends the file.
+func parseVersions(data []byte, last uint64) ([]Version, int) {
+	var versions []Version
+	size := 0
+	for size < len(data) {
+		n := bytes.IndexByte(data[size:], '\n')
+		if n < 0 {
+			break
+		}
+		v, ok := parseVersion(string(data[size : size+n]))
+		if !ok || v.ID > last {
+			break
+		}
+		versions = append(versions, v)
+		size += n + 1
+	}
+	return versions, size
+}
+
+func parseVersion(line string) (Version, bool) {
+	fields := strings.SplitN(line, "\t", 5)
+	if len(fields) != 5 {
+		return Version{}, false
+	}
+	var v Version
+	var err error
+	if v.ID, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+		return Version{}, false
+	}
+	if v.Time, err = time.Parse(time.RFC3339Nano, fields[1]); err != nil {
+		return Version{}, false
+	}
+	if v.Added, err = strconv.Atoi(fields[2]); err != nil {
+		return Version{}, false
+	}
+	if v.Removed, err = strconv.Atoi(fields[3]); err != nil {
+		return Version{}, false
+	}
+	if v.Message, err = strconv.Unquote(fields[4]); err != nil {
+		return Version{}, false
+	}
+	return v, true
+}
+
+// record writes a version to the history. The caller holds writer.
+func (s *Store) record(v Version, changes []entry) error {
+	for _, e := range changes {
+		if e.op == opAdd {
+			v.Added++
+		} else {
+			v.Removed++
+		}
+	}
+	line := fmt.Sprintf("%d\t%s\t%d\t%d\t%s\n", v.ID, v.Time.UTC().Format(time.RFC3339Nano), v.Added, v.Removed, strconv.Quote(v.Message))
+	if _, err := s.versions.WriteString(line); err != nil {
+		return err
+	}
+	if err := s.versions.Sync(); err != nil {
+		return err
+	}
+	return s.hist.commit(v.ID, changes)
+}
+
+// Version returns the ID of the last committed version.
+func (s *Store) Version() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.seq
+}
+
+// Log returns the committed versions, oldest first.
+func (s *Store) Log() ([]Version, error) {
+	last := s.Version()
+	data, err := os.ReadFile(filepath.Join(s.dir, versionsName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	versions, _ := parseVersions(data, last)
+	return versions, nil
+}
+
+// readHistory returns the changes of the versions after from, up to and
+// including to.
+func (s *Store) readHistory(from, to uint64) ([]transaction, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, historyName))
+	if err != nil {
+		return nil, err
+	}
+	txs, _ := readTransactions(data, func(entry) bool { return true })
+	var result []transaction
+	for _, tx := range txs {
+		if tx.seq > from && tx.seq <= to {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
+
+// At returns a read-only view of the store as it was when version id was
+// committed.
+func (s *Store) At(id uint64) (*Snapshot, error) {
+	snap := s.Snapshot()
+	if id > snap.version {
+		return nil, fmt.Errorf("store: no version %d", id)
+	}
+	if id == snap.version {
+		return snap, nil
+	}
+	// Undo the later versions, newest first.
+	txs, err := s.readHistory(id, snap.version)
+	if err != nil {
+		return nil, err
+	}
+	v := snap.view.clone()
+	for i := len(txs) - 1; i >= 0; i-- {
+		for _, e := range txs[i].changes {
+			v.apply(e.inverse())
+		}
+	}
+	return &Snapshot{dict: s.dict, view: v, version: id}, nil
+}
+
+// Diff returns the triples added and removed between two versions, sorted
+// by subject, predicate and object ID. If from is later than to, it
+// describes undoing the versions in between.
+func (s *Store) Diff(from, to uint64) (added, removed []triple.Triple, err error) {
+	last := s.Version()
+	for _, id := range []uint64{from, to} {
+		if id > last {
+			return nil, nil, fmt.Errorf("store: no version %d", id)
+		}
+	}
+	if from > to {
+		removed, added, err = s.Diff(to, from)
+		return added, removed, err
+	}
+	txs, err := s.readHistory(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := make(map[key]byte)
+	for _, tx := range txs {
+		for _, e := range tx.changes {
+			merge(changes, e)
+		}
+	}
+	keys := make([]key, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+	snap := &Snapshot{dict: s.dict}
+	for _, k := range keys {
+		if changes[k] == opAdd {
+			added = append(added, snap.triple(k))
+		} else {
+			removed = append(removed, snap.triple(k))
+		}
+	}
+	return added, removed, nil
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir)
+
+	commit := func(message string, add, remove int) {
+		t.Helper()
+		tx := s.Begin()
+		tx.Message = message
+		tx.AddAll(testTriples[:add])
+		tx.RemoveAll(testTriples[:remove])
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("first", 3, 0)
+	s.Begin().Commit() // no changes, no version
+	s.Checkpoint()
+	commit("second", 5, 1)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s = openStore(t, dir)
+	defer s.Close()
+	commit("third\twith a tab", 0, 4)
+
+	log, err := s.Log()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Version{{ID: 1, Message: "first", Added: 3}, {ID: 2, Message: "second", Added: 2, Removed: 1}, {ID: 3, Message: "third\twith a tab", Removed: 3}}
+	if len(log) != len(want) {
+		t.Fatalf("Log = %v", log)
+	}
+	for i, v := range log {
+		if v.ID != want[i].ID || v.Message != want[i].Message || v.Added != want[i].Added || v.Removed != want[i].Removed || v.Time.IsZero() {
+			t.Errorf("Log[%d] = %+v, want %+v", i, v, want[i])
+		}
+	}
+	if s.Version() != 3 {
+		t.Errorf("Version = %d", s.Version())
+	}
+
+	states := [][]string{nil, sorted(testTriples[:3]), sorted(testTriples[1:5]), sorted(testTriples[4:5])}
+	for id, want := range states {
+		snap, err := s.At(uint64(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sorted(snap.Triples()); !equal(got, want) || snap.Len() != len(want) || snap.Version() != uint64(id) {
+			t.Errorf("At(%d) = %v, want %v", id, got, want)
+		}
+	}
+	if _, err := s.At(4); err == nil {
+		t.Error("At(4) succeeded")
+	}
+
+	added, removed, err := s.Diff(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sorted(added), sorted(testTriples[4:5]); !equal(got, want) {
+		t.Errorf("Diff(1, 3) added %v, want %v", got, want)
+	}
+	if got, want := sorted(removed), sorted(testTriples[:3]); !equal(got, want) {
+		t.Errorf("Diff(1, 3) removed %v, want %v", got, want)
+	}
+	added, removed, _ = s.Diff(3, 1)
+	if got, want := sorted(added), sorted(testTriples[:3]); !equal(got, want) {
+		t.Errorf("Diff(3, 1) added %v, want %v", got, want)
+	}
+	if got, want := sorted(removed), sorted(testTriples[4:5]); !equal(got, want) {
+		t.Errorf("Diff(3, 1) removed %v, want %v", got, want)
+	}
+}
+
+// TestHistoryCrash leaves a version in the history whose transaction never
+// reached the log, and checks that reopening drops it.
+func TestHistoryCrash(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir)
+	s.Add(testTriples[0])
+	walPath := filepath.Join(dir, walName)
+	log, err := os.ReadFile(walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := s.Begin()
+	tx.Message = "lost"
+	tx.Add(testTriples[1])
+	tx.Commit()
+	crash(s)
+	if err := os.WriteFile(walPath, log, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s = openStore(t, dir)
+	defer s.Close()
+	if versions, _ := s.Log(); len(versions) != 1 {
+		t.Fatalf("Log = %v", versions)
+	}
+	tx = s.Begin()
+	tx.Message = "kept"
+	tx.Add(testTriples[2])
+	tx.Commit()
+	versions, _ := s.Log()
+	if len(versions) != 2 || versions[1].ID != 2 || versions[1].Message != "kept" {
+		t.Errorf("Log = %v", versions)
+	}
+	added, removed, err := s.Diff(1, 2)
+	if err != nil || len(removed) != 0 || len(added) != 1 || added[0] != testTriples[2] {
+		t.Errorf("Diff(1, 2) = %v, %v, %v", added, removed, err)
+	}
+}
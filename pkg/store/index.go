@@ -0,0 +1,119 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// key is a triple of term IDs in subject, predicate, object order.
+type key [3]uint64
+
+const recordSize = 24
+
+// order is a permutation of subject (0), predicate (1) and object (2): the
+// order in which an index file sorts its records.
+type order [3]int
+
+var (
+	spo    = order{0, 1, 2}
+	pos    = order{1, 2, 0}
+	osp    = order{2, 0, 1}
+	orders = [3]order{spo, pos, osp}
+	names  = [3]string{"spo", "pos", "osp"}
+)
+
+// index is the contents of one index file: records of three big-endian
+// uint64 IDs in the index's order, sorted.
+type index struct {
+	order order
+	data  []byte
+}
+
+func (ix index) len() int {
+	return len(ix.data) / recordSize
+}
+
+// at returns record i in subject, predicate, object order.
+func (ix index) at(i int) key {
+	rec := ix.data[i*recordSize:]
+	var k key
+	for j, part := range ix.order {
+		k[part] = binary.BigEndian.Uint64(rec[j*8:])
+	}
+	return k
+}
+
+// span returns the records whose first len(prefix) IDs, in index order,
+// are prefix.
+func (ix index) span(prefix []uint64) (lo, hi int) {
+	compare := func(i int) int {
+		rec := ix.data[i*recordSize:]
+		for j, id := range prefix {
+			v := binary.BigEndian.Uint64(rec[j*8:])
+			if v < id {
+				return -1
+			}
+			if v > id {
+				return 1
+			}
+		}
+		return 0
+	}
+	n := ix.len()
+	lo = sort.Search(n, func(i int) bool { return compare(i) >= 0 })
+	hi = lo + sort.Search(n-lo, func(i int) bool { return compare(lo+i) > 0 })
+	return lo, hi
+}
+
+func (ix index) has(k key) bool {
+	lo, hi := ix.span([]uint64{k[ix.order[0]], k[ix.order[1]], k[ix.order[2]]})
+	return lo < hi
+}
+
+// buildIndex sorts keys into the records of an index in order o.
+func buildIndex(keys []key, o order) index {
+	sorted := make([]key, len(keys))
+	for i, k := range keys {
+		sorted[i] = key{k[o[0]], k[o[1]], k[o[2]]}
+	}
+	sortKeys(sorted)
+
+	data := make([]byte, len(sorted)*recordSize)
+	for i, k := range sorted {
+		for j, id := range k {
+			binary.BigEndian.PutUint64(data[i*recordSize+j*8:], id)
+		}
+	}
+	return index{order: o, data: data}
+}
+
+func readIndex(path string, o order) (index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return index{}, err
+	}
+	if len(data)%recordSize != 0 {
+		return index{}, fmt.Errorf("%s: size is not a whole number of records", path)
+	}
+	return index{order: o, data: data}, nil
+}
+
+// writeFile writes data to path and syncs it, so that it is on disk before
+// anything refers to it.
+func writeFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
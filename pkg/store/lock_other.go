@@ -0,0 +1,24 @@
+//go:build !unix
+
+package store
+
+import "os"
+
+// lockFile creates the file at path, failing if it exists. Without file
+// locks the file is only removed by unlockFile, so after a crash it has to
+// be deleted by hand.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if os.IsExist(err) {
+		return nil, ErrInUse
+	}
+	return f, err
+}
+
+func unlockFile(f *os.File) error {
+	err := f.Close()
+	if rerr := os.Remove(f.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
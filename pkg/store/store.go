@@ -0,0 +1,482 @@
+// Package store keeps a graph in a directory on disk, so that it opens
+// without parsing RDF again. It uses only the standard library.
+//
+// A store directory holds:
+//
+//	terms       the dictionary, one N-Triples term per line; a term's ID is its line number
+//	spo.N       the triples as sorted subject, predicate, object IDs
+//	pos.N       the same triples sorted by predicate, object, subject
+//	osp.N       and by object, subject, predicate
+//	CURRENT     the generation N of the index files in use and the last transaction in them
+//	wal         the write-ahead log of transactions since the index files were written
+//	prefixes    prefix names and IRIs, one tab-separated pair per line
+//	history     the changes of every version
+//	versions    the ID, time and message of every version
+//	LOCK        locked by the Store that has the directory open
+//
+// Changes are made in transactions. Commit appends a transaction to the log
+// and syncs it, so committed changes survive a crash, and changes that did
+// not finish committing are dropped when the store is next opened. Readers
+// use snapshots, which keep seeing the store as it was when they were taken.
+// Checkpoint, which runs by itself as the log grows and on Close, writes a
+// new generation of index files and empties the log. Each transaction
+// committed is kept as a version, which At can show again and Diff compare.
+//
+// Only one Store, in one process, may have a directory open at a time: Open
+// locks the directory and fails with ErrInUse while another Store holds it.
+package store
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+const (
+	termsName    = "terms"
+	currentName  = "CURRENT"
+	walName      = "wal"
+	prefixesName = "prefixes"
+	lockName     = "LOCK"
+
+	// minCheckpoint is the number of changed triples below which a commit
+	// does not checkpoint; above it, a commit checkpoints once the changes
+	// reach a quarter of the indexed triples.
+	minCheckpoint = 1 << 14
+)
+
+// ErrInUse is returned by Open when another Store has the directory open.
+var ErrInUse = errors.New("store is in use")
+
+// Store is a graph kept on disk. It is safe for concurrent use: any number
+// of snapshots can be read while one transaction at a time writes. Only one
+// Store may have a directory open at a time.
+type Store struct {
+	dir  string
+	lock *os.File
+	dict *dictionary
+	// writer is held by the open transaction.
+	writer sync.Mutex
+	// log, hist and versions are only written with writer held.
+	log      *wal
+	hist     *wal
+	versions *os.File
+
+	mu  sync.RWMutex
+	cur *view
+	gen int
+	// seq is the sequence number of the last committed transaction.
+	seq      uint64
+	prefixes map[string]string
+	// err is the first write error, after which the store refuses changes.
+	err error
+}
+
+// Open opens the store in dir, creating the directory and an empty store if
+// they do not exist. Transactions in the log are replayed, and any that did
+// not finish committing are dropped. Open fails with ErrInUse if another
+// Store, in this process or another, has dir open.
+func Open(dir string) (_ *Store, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	lock, err := lockFile(filepath.Join(dir, lockName))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	defer func() {
+		if err != nil {
+			unlockFile(lock)
+		}
+	}()
+	s := &Store{dir: dir, lock: lock, prefixes: make(map[string]string)}
+
+	if s.gen, s.seq, err = readCurrent(filepath.Join(dir, currentName)); err != nil {
+		return nil, err
+	}
+	var indexes [3]index
+	for i, o := range orders {
+		indexes[i] = index{order: o}
+		if s.gen > 0 {
+			if indexes[i], err = readIndex(s.indexPath(i, s.gen), o); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := s.readPrefixes(); err != nil {
+		return nil, err
+	}
+
+	if s.dict, err = openDictionary(filepath.Join(dir, termsName)); err != nil {
+		return nil, err
+	}
+	terms := uint64(s.dict.len())
+	valid := func(e entry) bool {
+		for _, id := range e.key {
+			if id == 0 || id > terms {
+				return false
+			}
+		}
+		return true
+	}
+	log, txs, err := openWAL(filepath.Join(dir, walName), valid)
+	if err != nil {
+		s.dict.close()
+		return nil, err
+	}
+	s.log = log
+
+	s.cur = newView(indexes)
+	for _, tx := range txs {
+		for _, e := range tx.changes {
+			s.cur.apply(e)
+		}
+		s.seq = max(s.seq, tx.seq)
+	}
+
+	if err := s.openHistory(valid); err != nil {
+		s.closeFiles()
+		return nil, err
+	}
+	s.removeStaleIndexes()
+	return s, nil
+}
+
+// readCurrent reads the generation of the index files and the sequence
+// number of the last transaction in them.
+func readCurrent(path string) (int, uint64, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("%s: empty", path)
+	}
+	gen, err := strconv.Atoi(fields[0])
+	if err != nil || gen < 0 {
+		return 0, 0, fmt.Errorf("%s: invalid generation %q", path, fields[0])
+	}
+	var seq uint64
+	if len(fields) > 1 {
+		if seq, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("%s: invalid sequence number %q", path, fields[1])
+		}
+	}
+	return gen, seq, nil
+}
+
+func (s *Store) indexPath(i, gen int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.%d", names[i], gen))
+}
+
+// removeStaleIndexes deletes index files of other generations, left behind
+// by a checkpoint that crashed.
+func (s *Store) removeStaleIndexes() {
+	for i := range names {
+		paths, _ := filepath.Glob(filepath.Join(s.dir, names[i]+".*"))
+		for _, path := range paths {
+			if path != s.indexPath(i, s.gen) {
+				os.Remove(path)
+			}
+		}
+	}
+}
+
+// Snapshot returns a read-only view of the store as of the last commit.
+func (s *Store) Snapshot() *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &Snapshot{dict: s.dict, view: s.cur, version: s.seq}
+}
+
+// Begin starts a transaction. Transactions run one at a time, so Begin waits
+// until the open one, if any, commits or rolls back. Other processes cannot
+// write meanwhile, since the directory lock keeps them from opening the
+// store.
+func (s *Store) Begin() *Tx {
+	s.writer.Lock()
+	snap := s.Snapshot()
+	snap.view = snap.view.clone()
+	return &Tx{Snapshot: *snap, st: s, changes: make(map[key]byte)}
+}
+
+// commit logs and publishes a transaction. The caller holds writer.
+func (s *Store) commit(tx *Tx) error {
+	if len(tx.changes) == 0 {
+		return nil
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	keys := make([]key, 0, len(tx.changes))
+	for k := range tx.changes {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+	changes := make([]entry, len(keys))
+	for i, k := range keys {
+		changes[i] = entry{op: tx.changes[k], key: k}
+	}
+
+	// Terms go first, since log entries refer to them.
+	if err := s.dict.sync(); err != nil {
+		return s.fail(err)
+	}
+	seq := tx.version + 1
+	if err := s.record(Version{ID: seq, Time: time.Now(), Message: tx.Message}, changes); err != nil {
+		return s.fail(err)
+	}
+	if err := s.log.commit(seq, changes); err != nil {
+		return s.fail(err)
+	}
+
+	s.mu.Lock()
+	s.cur, s.seq = tx.view, seq
+	s.mu.Unlock()
+
+	if n := tx.view.changes(); n > minCheckpoint && n > tx.view.indexes[0].len()/4 {
+		// The transaction is committed whether or not this works, and a
+		// failed checkpoint is retried by the next one.
+		s.checkpoint()
+	}
+	return nil
+}
+
+func (s *Store) fail(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+	return err
+}
+
+// Err returns the error that stopped the store accepting changes, if any.
+func (s *Store) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.err
+}
+
+// Add adds t in a transaction of its own and reports whether it was not
+// already in the store. If committing fails, Add returns false and Err
+// reports why. Use Begin to add many triples at once.
+func (s *Store) Add(t triple.Triple) bool {
+	tx := s.Begin()
+	added := tx.Add(t)
+	if err := tx.Commit(); err != nil {
+		s.fail(err)
+		return false
+	}
+	return added
+}
+
+// Remove removes t in a transaction of its own, like Add.
+func (s *Store) Remove(t triple.Triple) bool {
+	tx := s.Begin()
+	removed := tx.Remove(t)
+	if err := tx.Commit(); err != nil {
+		s.fail(err)
+		return false
+	}
+	return removed
+}
+
+func (s *Store) Has(t triple.Triple) bool {
+	return s.Snapshot().Has(t)
+}
+
+func (s *Store) Len() int {
+	return s.Snapshot().Len()
+}
+
+// Triples returns every triple, sorted by subject, predicate and object ID,
+// followed by those added since the last checkpoint.
+func (s *Store) Triples() []triple.Triple {
+	return s.Snapshot().Triples()
+}
+
+func (s *Store) Match(subject, predicate, object triple.Node) []triple.Triple {
+	return s.Snapshot().Match(subject, predicate, object)
+}
+
+// MatchPage returns one page of the matches of a pattern, as
+// Snapshot.MatchPage does.
+func (s *Store) MatchPage(subject, predicate, object triple.Node, offset, limit int) []triple.Triple {
+	return s.Snapshot().MatchPage(subject, predicate, object, offset, limit)
+}
+
+// Count returns the number of triples matching a pattern.
+func (s *Store) Count(subject, predicate, object triple.Node) int {
+	return s.Snapshot().Count(subject, predicate, object)
+}
+
+// Prefixes returns a copy of the stored prefixes.
+func (s *Store) Prefixes() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	prefixes := make(map[string]string, len(s.prefixes))
+	for k, v := range s.prefixes {
+		prefixes[k] = v
+	}
+	return prefixes
+}
+
+// SetPrefixes stores prefixes, keeping those already stored under other
+// names.
+func (s *Store) SetPrefixes(prefixes map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range prefixes {
+		s.prefixes[k] = v
+	}
+	names := make([]string, 0, len(s.prefixes))
+	for k := range s.prefixes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s\t%s\n", name, s.prefixes[name])
+	}
+	return s.replaceFile(prefixesName, []byte(b.String()))
+}
+
+func (s *Store) readPrefixes() error {
+	f, err := os.Open(filepath.Join(s.dir, prefixesName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if name, iri, ok := strings.Cut(scanner.Text(), "\t"); ok {
+			s.prefixes[name] = iri
+		}
+	}
+	return scanner.Err()
+}
+
+// replaceFile writes a file in the store so that readers see either the old
+// or the new contents, even after a crash.
+func (s *Store) replaceFile(name string, data []byte) error {
+	tmp := filepath.Join(s.dir, name+".tmp")
+	if err := writeFile(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		return err
+	}
+	s.syncDir()
+	return nil
+}
+
+// syncDir makes renames in the directory durable. Not every system can sync
+// a directory, so it is best effort.
+func (s *Store) syncDir() {
+	if d, err := os.Open(s.dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+// Checkpoint writes the current triples to a new generation of index files
+// and empties the log. It waits for the open transaction, if any.
+func (s *Store) Checkpoint() error {
+	s.writer.Lock()
+	defer s.writer.Unlock()
+	return s.checkpoint()
+}
+
+// checkpoint does the work of Checkpoint with writer held.
+func (s *Store) checkpoint() error {
+	if err := s.Err(); err != nil {
+		return err
+	}
+	if s.log.size == 0 {
+		return nil
+	}
+
+	s.mu.RLock()
+	v, gen, seq := s.cur, s.gen+1, s.seq
+	s.mu.RUnlock()
+
+	keys := make([]key, 0, v.len())
+	v.scan(s.dict, nil, nil, nil, func(k key) bool {
+		keys = append(keys, k)
+		return true
+	})
+	var indexes [3]index
+	for i, o := range orders {
+		indexes[i] = buildIndex(keys, o)
+		if err := writeFile(s.indexPath(i, gen), indexes[i].data); err != nil {
+			return err
+		}
+	}
+	// Switching CURRENT commits the new generation. A crash before it
+	// leaves the old index files and the whole log; a crash after it
+	// replays the log over index files that already hold its changes.
+	if err := s.replaceFile(currentName, []byte(fmt.Sprintf("%d %d\n", gen, seq))); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.gen, s.cur = gen, newView(indexes)
+	s.mu.Unlock()
+	if err := s.log.reset(); err != nil {
+		return s.fail(err)
+	}
+	s.removeStaleIndexes()
+	return nil
+}
+
+// Close checkpoints the store, closes its files and unlocks the directory.
+// It waits for the open transaction, if any.
+func (s *Store) Close() error {
+	s.writer.Lock()
+	defer s.writer.Unlock()
+	err := s.checkpoint()
+	if cerr := s.closeFiles(); err == nil {
+		err = cerr
+	}
+	if cerr := unlockFile(s.lock); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// closeFiles closes the files a store keeps open, returning the first error.
+func (s *Store) closeFiles() error {
+	err := s.log.close()
+	if s.hist != nil {
+		if cerr := s.hist.close(); err == nil {
+			err = cerr
+		}
+	}
+	if s.versions != nil {
+		if cerr := s.versions.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if cerr := s.dict.close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,220 @@
+package store
+
+import (
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func ex(s string) triple.IRI { return triple.IRI{Value: "http://example.org/" + s} }
+
+var testTriples = []triple.Triple{
+	{Subject: ex("alice"), Predicate: ex("knows"), Object: ex("bob")},
+	{Subject: ex("alice"), Predicate: ex("name"), Object: triple.Literal{Value: "Alice", Language: "en"}},
+	{Subject: ex("bob"), Predicate: ex("knows"), Object: ex("carol")},
+	{Subject: ex("bob"), Predicate: ex("age"), Object: triple.NewIntegerLiteral(42)},
+	{Subject: triple.BlankNode{Value: "b0"}, Predicate: ex("note"), Object: triple.Literal{Value: "line\nbreak"}},
+	{Subject: ex("carol"), Predicate: ex("knows"), Object: ex("alice")},
+}
+
+// crash drops a store without closing it, as a crash would.
+func crash(s *Store) {
+	s.log.file.Close()
+	s.hist.file.Close()
+	s.versions.Close()
+	s.dict.file.Close()
+	unlockFile(s.lock)
+}
+
+func openStore(t *testing.T, dir string) *Store {
+	t.Helper()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// sorted returns triples in N-Triples syntax, sorted, for comparing sets.
+func sorted(triples []triple.Triple) []string {
+	lines := make([]string, len(triples))
+	for i, t := range triples {
+		lines[i] = encoder.EncodeNTriple(t)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStoreMatch(t *testing.T) {
+	s := openStore(t, t.TempDir())
+	defer s.Close()
+	for _, tr := range testTriples {
+		if !s.Add(tr) {
+			t.Fatalf("Add(%v) = false", tr)
+		}
+	}
+	if s.Add(testTriples[0]) {
+		t.Error("adding a duplicate returned true")
+	}
+
+	check := func(stage string) {
+		g := triple.NewGraph(testTriples)
+		patterns := [][3]triple.Node{
+			{nil, nil, nil},
+			{ex("alice"), nil, nil},
+			{nil, ex("knows"), nil},
+			{nil, nil, ex("alice")},
+			{ex("alice"), ex("knows"), nil},
+			{ex("bob"), nil, ex("carol")},
+			{nil, ex("knows"), ex("bob")},
+			{ex("alice"), ex("knows"), ex("bob")},
+			{ex("nobody"), nil, nil},
+		}
+		for _, p := range patterns {
+			want := sorted(g.Match(p[0], p[1], p[2]))
+			if got := sorted(s.Match(p[0], p[1], p[2])); !equal(got, want) {
+				t.Errorf("%s: Match%v = %v, want %v", stage, p, got, want)
+			}
+			if got := s.Count(p[0], p[1], p[2]); got != len(want) {
+				t.Errorf("%s: Count%v = %d, want %d", stage, p, got, len(want))
+			}
+		}
+		if s.Len() != len(testTriples) {
+			t.Errorf("%s: Len = %d, want %d", stage, s.Len(), len(testTriples))
+		}
+		page := s.MatchPage(nil, ex("knows"), nil, 1, 1)
+		if len(page) != 1 || page[0] != s.Match(nil, ex("knows"), nil)[1] {
+			t.Errorf("%s: MatchPage(1, 1) = %v", stage, page)
+		}
+	}
+	check("logged")
+	if err := s.Checkpoint(); err != nil {
+		t.Fatal(err)
+	}
+	check("checkpointed")
+}
+
+func TestStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir)
+	for _, tr := range testTriples {
+		s.Add(tr)
+	}
+	if err := s.SetPrefixes(map[string]string{"ex": "http://example.org/"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes after a checkpoint live in the log until the next one.
+	s = openStore(t, dir)
+	if !s.Remove(testTriples[0]) || s.Remove(testTriples[0]) {
+		t.Error("Remove should succeed once")
+	}
+	extra := triple.Triple{Subject: ex("dave"), Predicate: ex("knows"), Object: ex("alice")}
+	s.Add(extra)
+	crash(s)
+
+	want := sorted(append(append([]triple.Triple{}, testTriples[1:]...), extra))
+	s = openStore(t, dir)
+	if got := sorted(s.Triples()); !equal(got, want) {
+		t.Errorf("after replaying the log:\n%v\nwant\n%v", got, want)
+	}
+	if s.Prefixes()["ex"] != "http://example.org/" {
+		t.Errorf("Prefixes() = %v", s.Prefixes())
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s = openStore(t, dir)
+	defer s.Close()
+	if got := sorted(s.Triples()); !equal(got, want) {
+		t.Errorf("after checkpoint:\n%v\nwant\n%v", got, want)
+	}
+	if info, err := os.Stat(filepath.Join(dir, walName)); err != nil || info.Size() != 0 {
+		t.Errorf("log not emptied by Close: %v, %v", info, err)
+	}
+	if paths, _ := filepath.Glob(filepath.Join(dir, "spo.*")); len(paths) != 1 {
+		t.Errorf("index generations left: %v", paths)
+	}
+}
+
+func TestStoreInUse(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir)
+	if _, err := Open(dir); !errors.Is(err, ErrInUse) {
+		t.Fatalf("second Open: err = %v, want ErrInUse", err)
+	}
+	s.Add(testTriples[0])
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing, or crashing, releases the directory.
+	s = openStore(t, dir)
+	if s.Len() != 1 {
+		t.Errorf("Len = %d after reopening, want 1", s.Len())
+	}
+	crash(s)
+	openStore(t, dir).Close()
+}
+
+func TestStoreTornWrites(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir)
+	s.Add(testTriples[0])
+	crash(s)
+
+	// A crash can cut the last log entry and term short, or leave a
+	// committed transaction whose terms never reached the disk.
+	appendTo := func(name string, data []byte) {
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unknown := entry{op: opAdd, key: key{97, 98, 99}}.encode()
+	commit := entry{op: opCommit, key: key{1, uint64(crc32.ChecksumIEEE(unknown[:])), 2}}.encode()
+	appendTo(walName, append(unknown[:], commit[:]...))
+	appendTo(walName, []byte{opAdd, 0, 0})
+	appendTo(termsName, []byte("<http://example.org/cut"))
+
+	s = openStore(t, dir)
+	if s.Len() != 1 || !s.Has(testTriples[0]) {
+		t.Errorf("after recovery: %v", s.Triples())
+	}
+	// The store keeps working after dropping the damaged tail.
+	s.Add(testTriples[1])
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s = openStore(t, dir)
+	defer s.Close()
+	if got, want := sorted(s.Triples()), sorted(testTriples[:2]); !equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
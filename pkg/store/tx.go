@@ -0,0 +1,123 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// ErrTxDone is returned when committing a transaction that was already
+// committed or rolled back.
+var ErrTxDone = errors.New("store: transaction already committed or rolled back")
+
+// Tx is a transaction: changes that are committed together or not at all.
+// Its reads see the store as it was at Begin plus its own changes. A Tx is
+// not safe for concurrent use.
+type Tx struct {
+	Snapshot
+	// Message, if set, is recorded with the version the transaction
+	// commits.
+	Message string
+
+	st *Store
+	// changes holds the net operation on each triple since Begin.
+	changes map[key]byte
+	err     error
+	done    bool
+}
+
+// Add adds t and reports whether it was not already there.
+func (tx *Tx) Add(t triple.Triple) bool {
+	if tx.done || tx.err != nil {
+		return false
+	}
+	var k key
+	for i, n := range [3]triple.Node{t.Subject, t.Predicate, t.Object} {
+		id, err := tx.dict.intern(n)
+		if err != nil {
+			tx.err = err
+			return false
+		}
+		k[i] = id
+	}
+	if tx.view.has(k) {
+		return false
+	}
+	tx.change(entry{op: opAdd, key: k})
+	return true
+}
+
+// Remove removes t and reports whether it was there.
+func (tx *Tx) Remove(t triple.Triple) bool {
+	if tx.done || tx.err != nil {
+		return false
+	}
+	k, ok := lookupKey(tx.dict, t)
+	if !ok || !tx.view.has(k) {
+		return false
+	}
+	tx.change(entry{op: opRemove, key: k})
+	return true
+}
+
+// AddAll adds triples and returns how many were not already there.
+func (tx *Tx) AddAll(triples []triple.Triple) int {
+	n := 0
+	for _, t := range triples {
+		if tx.Add(t) {
+			n++
+		}
+	}
+	return n
+}
+
+// RemoveAll removes triples and returns how many were there.
+func (tx *Tx) RemoveAll(triples []triple.Triple) int {
+	n := 0
+	for _, t := range triples {
+		if tx.Remove(t) {
+			n++
+		}
+	}
+	return n
+}
+
+func (tx *Tx) change(e entry) {
+	merge(tx.changes, e)
+	tx.view.apply(e)
+}
+
+// merge adds a change to the net changes of a series of them. A change that
+// undoes an earlier one leaves nothing.
+func merge(changes map[key]byte, e entry) {
+	if prev, ok := changes[e.key]; ok && prev != e.op {
+		delete(changes, e.key)
+	} else {
+		changes[e.key] = e.op
+	}
+}
+
+// Commit writes the changes to the log and makes them visible to new
+// snapshots and transactions as a new version. When it returns nil the
+// changes are on disk. A transaction without changes makes no version.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	defer tx.st.writer.Unlock()
+	if tx.err != nil {
+		return tx.err
+	}
+	return tx.st.commit(tx)
+}
+
+// Rollback discards the changes. It does nothing after Commit, so it can be
+// deferred.
+func (tx *Tx) Rollback() {
+	if tx.done {
+		return
+	}
+	tx.done = true
+	tx.st.writer.Unlock()
+}
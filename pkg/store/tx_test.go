@@ -0,0 +1,284 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func TestTx(t *testing.T) {
+	s := openStore(t, t.TempDir())
+	defer s.Close()
+	s.Add(testTriples[0])
+
+	tx := s.Begin()
+	if n := tx.AddAll(testTriples); n != len(testTriples)-1 {
+		t.Errorf("AddAll = %d, want %d", n, len(testTriples)-1)
+	}
+	if n := tx.RemoveAll(testTriples[:2]); n != 2 {
+		t.Errorf("RemoveAll = %d, want 2", n)
+	}
+	// Adding back what the transaction removed cancels the change.
+	tx.Add(testTriples[0])
+
+	if tx.Len() != len(testTriples)-1 || !tx.Has(testTriples[0]) || tx.Has(testTriples[1]) {
+		t.Errorf("transaction does not see its own changes: %v", tx.Triples())
+	}
+	if s.Len() != 1 {
+		t.Errorf("store sees uncommitted changes: %v", s.Triples())
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != ErrTxDone {
+		t.Errorf("second Commit = %v, want ErrTxDone", err)
+	}
+	if s.Len() != len(testTriples)-1 || s.Has(testTriples[1]) {
+		t.Errorf("after commit: %v", s.Triples())
+	}
+
+	tx = s.Begin()
+	tx.RemoveAll(testTriples)
+	tx.Rollback()
+	tx.Rollback()
+	if s.Len() != len(testTriples)-1 {
+		t.Errorf("after rollback: %v", s.Triples())
+	}
+	if tx.Add(testTriples[1]) {
+		t.Error("Add after Rollback returned true")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	s := openStore(t, t.TempDir())
+	defer s.Close()
+	s.Add(testTriples[0])
+
+	snap := s.Snapshot()
+	tx := s.Begin()
+	tx.Remove(testTriples[0])
+	tx.AddAll(testTriples[1:])
+	tx.Commit()
+	s.Checkpoint()
+
+	if snap.Len() != 1 || !snap.Has(testTriples[0]) || len(snap.Match(nil, ex("knows"), nil)) != 1 {
+		t.Errorf("snapshot changed: %v", snap.Triples())
+	}
+	if s.Len() != len(testTriples)-1 || s.Has(testTriples[0]) {
+		t.Errorf("store: %v", s.Triples())
+	}
+}
+
+// TestConcurrentReaders is meant for go test -race: readers take snapshots
+// and check they stay whole while a writer commits batches.
+func TestConcurrentReaders(t *testing.T) {
+	s := openStore(t, t.TempDir())
+	defer s.Close()
+
+	const batches, batchSize = 20, 10
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				snap := s.Snapshot()
+				n := snap.Len()
+				if n%batchSize != 0 || len(snap.Triples()) != n || snap.Count(nil, ex("p"), nil) != n {
+					t.Errorf("snapshot of %d triples is not whole batches", n)
+					return
+				}
+			}
+		}()
+	}
+	for b := 0; b < batches; b++ {
+		tx := s.Begin()
+		for i := 0; i < batchSize; i++ {
+			tx.Add(triple.Triple{Subject: ex("s"), Predicate: ex("p"), Object: triple.NewIntegerLiteral(int64(b*batchSize + i))})
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if b%5 == 4 {
+			s.Checkpoint()
+		}
+	}
+	wg.Wait()
+	if s.Len() != batches*batchSize {
+		t.Errorf("Len = %d", s.Len())
+	}
+}
+
+// TestCrashMidCommit cuts the log at every point inside the last
+// transaction, as a crash while committing could, and checks that reopening
+// gives the store as it was before that transaction.
+func TestCrashMidCommit(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir)
+	tx := s.Begin()
+	tx.AddAll(testTriples[:3])
+	tx.Commit()
+	walPath := filepath.Join(dir, walName)
+	info, err := os.Stat(walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := info.Size()
+
+	tx = s.Begin()
+	tx.Remove(testTriples[0])
+	tx.AddAll(testTriples[3:])
+	tx.Commit()
+	crash(s)
+
+	full, err := os.ReadFile(walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantBefore := sorted(testTriples[:3])
+	wantAfter := sorted(testTriples[1:])
+
+	reopen := func(log []byte) []string {
+		t.Helper()
+		if err := os.WriteFile(walPath, log, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s := openStore(t, dir)
+		defer crash(s)
+		return sorted(s.Triples())
+	}
+
+	for cut := before; cut < int64(len(full)); cut++ {
+		if got := reopen(full[:cut]); !equal(got, wantBefore) {
+			t.Fatalf("log cut at %d of %d: got %v, want %v", cut, len(full), got, wantBefore)
+		}
+	}
+
+	// A damaged change fails the commit checksum.
+	damaged := append([]byte{}, full...)
+	damaged[before+entrySize-1] ^= 1
+	if got := reopen(damaged); !equal(got, wantBefore) {
+		t.Errorf("damaged transaction: got %v, want %v", got, wantBefore)
+	}
+
+	if got := reopen(full); !equal(got, wantAfter) {
+		t.Errorf("whole log: got %v, want %v", got, wantAfter)
+	}
+}
+
+// TestCrashMidCheckpoint leaves the store as a checkpoint interrupted after
+// switching to new index files, but before emptying the log, would.
+func TestCrashMidCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir)
+	tx := s.Begin()
+	tx.AddAll(testTriples[:4])
+	tx.Commit()
+	s.Checkpoint()
+	tx = s.Begin()
+	tx.Remove(testTriples[0])
+	tx.AddAll(testTriples[4:])
+	tx.Commit()
+	want := sorted(s.Triples())
+
+	walPath := filepath.Join(dir, walName)
+	log, err := os.ReadFile(walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Checkpoint(); err != nil {
+		t.Fatal(err)
+	}
+	crash(s)
+	if err := os.WriteFile(walPath, log, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Index files of a generation that never became current.
+	if err := os.WriteFile(filepath.Join(dir, "spo.99"), []byte("junk"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s = openStore(t, dir)
+	defer s.Close()
+	if got := sorted(s.Triples()); !equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "spo.99")); !os.IsNotExist(err) {
+		t.Error("stale index file not removed")
+	}
+	// Later transactions continue the sequence.
+	s.Add(testTriples[0])
+	if s.seq != 3 {
+		t.Errorf("seq = %d, want 3", s.seq)
+	}
+}
+
+// TestTxOtherProcess checks that a transaction open in one process keeps
+// another process from writing to the store, and that a commit made by the
+// other process once the store is closed is seen here.
+func TestTxOtherProcess(t *testing.T) {
+	if dir := os.Getenv("STORE_TEST_DIR"); dir != "" {
+		s, err := Open(dir)
+		if errors.Is(err, ErrInUse) {
+			os.Exit(3)
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		tx := s.Begin()
+		tx.Add(testTriples[1])
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	run := func(dir string) error {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestTxOtherProcess$")
+		cmd.Env = append(os.Environ(), "STORE_TEST_DIR="+dir)
+		out, err := cmd.CombinedOutput()
+		if err != nil && !isExit(err, 3) {
+			t.Fatalf("other process: %v\n%s", err, out)
+		}
+		return err
+	}
+
+	dir := t.TempDir()
+	s := openStore(t, dir)
+	tx := s.Begin()
+	tx.Add(testTriples[0])
+	if err := run(dir); err == nil {
+		t.Fatal("other process opened the store during a transaction")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run(dir); err != nil {
+		t.Fatalf("other process could not open the closed store: %v", err)
+	}
+	s = openStore(t, dir)
+	defer s.Close()
+	want := sorted(testTriples[:2])
+	if got := sorted(s.Triples()); !equal(got, want) {
+		t.Errorf("triples = %v, want %v", got, want)
+	}
+	if got := s.Version(); got != 2 {
+		t.Errorf("Version = %d, want 2", got)
+	}
+}
+
+func isExit(err error, code int) bool {
+	var e *exec.ExitError
+	return errors.As(err, &e) && e.ExitCode() == code
+}
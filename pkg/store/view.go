@@ -0,0 +1,244 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+// view is the set of triples at one moment: the index files plus the changes
+// committed since they were written. A published view never changes, so any
+// number of readers can use it while a transaction builds the next one.
+type view struct {
+	indexes [3]index
+	// added holds triples not in the index files, and removed triples
+	// that are in them but no longer in the view.
+	added   map[key]bool
+	removed map[key]bool
+}
+
+func newView(indexes [3]index) *view {
+	return &view{indexes: indexes, added: make(map[key]bool), removed: make(map[key]bool)}
+}
+
+func (v *view) clone() *view {
+	c := newView(v.indexes)
+	for k := range v.added {
+		c.added[k] = true
+	}
+	for k := range v.removed {
+		c.removed[k] = true
+	}
+	return c
+}
+
+// apply makes a change to an unpublished view. Changes are relative to the
+// index files, so replaying a log over index files that already hold some
+// of its changes gives the same result.
+func (v *view) apply(e entry) {
+	inIndex := v.indexes[0].has(e.key)
+	switch e.op {
+	case opAdd:
+		if inIndex {
+			delete(v.removed, e.key)
+		} else {
+			v.added[e.key] = true
+		}
+	case opRemove:
+		if inIndex {
+			v.removed[e.key] = true
+		} else {
+			delete(v.added, e.key)
+		}
+	}
+}
+
+func (v *view) has(k key) bool {
+	if v.added[k] {
+		return true
+	}
+	return !v.removed[k] && v.indexes[0].has(k)
+}
+
+func (v *view) len() int {
+	return v.indexes[0].len() - len(v.removed) + len(v.added)
+}
+
+// changes returns the number of triples in which the view differs from its
+// index files.
+func (v *view) changes() int {
+	return len(v.added) + len(v.removed)
+}
+
+// plan picks the index whose order starts with the bound terms of a pattern
+// and returns their IDs in that order. It returns false if a bound term is
+// not in the dictionary.
+func plan(dict *dictionary, indexes [3]index, subject, predicate, object triple.Node) (index, []uint64, bool) {
+	var ids [3]uint64
+	var bound [3]bool
+	for i, n := range [3]triple.Node{subject, predicate, object} {
+		if n == nil {
+			continue
+		}
+		if ids[i] = dict.lookup(n); ids[i] == 0 {
+			return index{}, nil, false
+		}
+		bound[i] = true
+	}
+
+	ix := indexes[0]
+	switch {
+	case bound[0] && !bound[1] && bound[2]:
+		ix = indexes[2]
+	case bound[0]:
+	case bound[1]:
+		ix = indexes[1]
+	case bound[2]:
+		ix = indexes[2]
+	}
+	var prefix []uint64
+	for _, part := range ix.order {
+		if !bound[part] {
+			break
+		}
+		prefix = append(prefix, ids[part])
+	}
+	return ix, prefix, true
+}
+
+// scan calls fn with the key of each triple matching a pattern until fn
+// returns false: first those in the index files, in index order, then those
+// added since, sorted.
+func (v *view) scan(dict *dictionary, subject, predicate, object triple.Node, fn func(key) bool) {
+	ix, prefix, ok := plan(dict, v.indexes, subject, predicate, object)
+	if !ok {
+		return
+	}
+	lo, hi := ix.span(prefix)
+	for i := lo; i < hi; i++ {
+		k := ix.at(i)
+		if v.removed[k] {
+			continue
+		}
+		if !fn(k) {
+			return
+		}
+	}
+
+	var want key
+	for i, n := range [3]triple.Node{subject, predicate, object} {
+		if n != nil {
+			want[i] = dict.lookup(n)
+		}
+	}
+	var added []key
+	for k := range v.added {
+		if (want[0] == 0 || k[0] == want[0]) && (want[1] == 0 || k[1] == want[1]) && (want[2] == 0 || k[2] == want[2]) {
+			added = append(added, k)
+		}
+	}
+	sortKeys(added)
+	for _, k := range added {
+		if !fn(k) {
+			return
+		}
+	}
+}
+
+func sortKeys(keys []key) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		for n := range a {
+			if a[n] != b[n] {
+				return a[n] < b[n]
+			}
+		}
+		return false
+	})
+}
+
+// Snapshot is a read-only view of a store as of one version. It does not
+// change as later transactions commit, and is safe for concurrent use.
+type Snapshot struct {
+	dict    *dictionary
+	view    *view
+	version uint64
+}
+
+// Version returns the ID of the version the snapshot shows. For a
+// transaction, it is the version the transaction started from.
+func (s *Snapshot) Version() uint64 {
+	return s.version
+}
+
+func (s *Snapshot) Has(t triple.Triple) bool {
+	k, ok := lookupKey(s.dict, t)
+	return ok && s.view.has(k)
+}
+
+func (s *Snapshot) Len() int {
+	return s.view.len()
+}
+
+// Triples returns every triple, sorted by subject, predicate and object ID,
+// followed by those added since the last checkpoint.
+func (s *Snapshot) Triples() []triple.Triple {
+	return s.Match(nil, nil, nil)
+}
+
+func (s *Snapshot) Match(subject, predicate, object triple.Node) []triple.Triple {
+	return s.MatchPage(subject, predicate, object, 0, -1)
+}
+
+// MatchPage returns the matches of a pattern, skipping the first offset and
+// returning at most limit of them; a negative limit means no limit. Matches
+// come from the index that has the bound terms as a prefix, in its order.
+func (s *Snapshot) MatchPage(subject, predicate, object triple.Node, offset, limit int) []triple.Triple {
+	var result []triple.Triple
+	s.view.scan(s.dict, subject, predicate, object, func(k key) bool {
+		if offset > 0 {
+			offset--
+			return true
+		}
+		if limit >= 0 && len(result) == limit {
+			return false
+		}
+		result = append(result, s.triple(k))
+		return true
+	})
+	return result
+}
+
+// Count returns the number of triples matching a pattern.
+func (s *Snapshot) Count(subject, predicate, object triple.Node) int {
+	if s.view.changes() == 0 {
+		ix, prefix, ok := plan(s.dict, s.view.indexes, subject, predicate, object)
+		if !ok {
+			return 0
+		}
+		lo, hi := ix.span(prefix)
+		return hi - lo
+	}
+	count := 0
+	s.view.scan(s.dict, subject, predicate, object, func(key) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+func (s *Snapshot) triple(k key) triple.Triple {
+	return triple.Triple{Subject: s.dict.term(k[0]), Predicate: s.dict.term(k[1]), Object: s.dict.term(k[2])}
+}
+
+// lookupKey returns the key of t, and false if a term is not in the
+// dictionary, in which case no stored triple can match.
+func lookupKey(dict *dictionary, t triple.Triple) (key, bool) {
+	var k key
+	for i, n := range [3]triple.Node{t.Subject, t.Predicate, t.Object} {
+		if k[i] = dict.lookup(n); k[i] == 0 {
+			return k, false
+		}
+	}
+	return k, true
+}
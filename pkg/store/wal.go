@@ -0,0 +1,177 @@
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// The write-ahead log records every transaction committed since the index
+// files were written. Entries have a fixed size: an operation byte and three
+// uint64s. A change is an add or remove with the term IDs of its triple; a
+// transaction is its changes followed by a commit entry holding their
+// number, their CRC-32 and the transaction's sequence number. Changes
+// without a valid commit entry after them never happened.
+const (
+	opAdd    = '+'
+	opRemove = '-'
+	opCommit = 'C'
+
+	entrySize = 1 + recordSize
+)
+
+type entry struct {
+	op  byte
+	key key
+}
+
+func (e entry) encode() [entrySize]byte {
+	var buf [entrySize]byte
+	buf[0] = e.op
+	for j, id := range e.key {
+		binary.BigEndian.PutUint64(buf[1+j*8:], id)
+	}
+	return buf
+}
+
+// inverse returns the change that undoes e.
+func (e entry) inverse() entry {
+	if e.op == opAdd {
+		e.op = opRemove
+	} else {
+		e.op = opAdd
+	}
+	return e
+}
+
+func decodeEntry(data []byte) entry {
+	e := entry{op: data[0]}
+	for j := range e.key {
+		e.key[j] = binary.BigEndian.Uint64(data[1+j*8:])
+	}
+	return e
+}
+
+// transaction is a committed transaction read back from the log.
+type transaction struct {
+	seq     uint64
+	changes []entry
+	// end is the offset in the log just after the transaction.
+	end int64
+}
+
+type wal struct {
+	file *os.File
+	w    *bufio.Writer
+	// size is the length of the log up to its last commit.
+	size int64
+}
+
+// openWAL opens the log and returns the transactions in it. Anything after
+// the last transaction that is complete and valid, such as a commit cut
+// short by a crash, is truncated away. valid checks a change, for example
+// that its terms reached the disk.
+func openWAL(path string, valid func(entry) bool) (*wal, []transaction, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	txs, committed := readTransactions(data, valid)
+	l := &wal{file: file, w: bufio.NewWriter(file), size: int64(len(data))}
+	if err := l.truncate(committed); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return l, txs, nil
+}
+
+// readTransactions parses the complete and valid transactions at the start
+// of data, and returns them with the length they take up.
+func readTransactions(data []byte, valid func(entry) bool) ([]transaction, int64) {
+	var txs []transaction
+	var pending []entry
+	var committed int64
+	crc := crc32.NewIEEE()
+	for off := 0; off+entrySize <= len(data); off += entrySize {
+		e := decodeEntry(data[off:])
+		switch e.op {
+		case opAdd, opRemove:
+			if !valid(e) {
+				return txs, committed
+			}
+			pending = append(pending, e)
+			crc.Write(data[off : off+entrySize])
+		case opCommit:
+			if e.key[0] != uint64(len(pending)) || e.key[1] != uint64(crc.Sum32()) {
+				return txs, committed
+			}
+			committed = int64(off + entrySize)
+			txs = append(txs, transaction{seq: e.key[2], changes: pending, end: committed})
+			pending = nil
+			crc.Reset()
+		default:
+			return txs, committed
+		}
+	}
+	return txs, committed
+}
+
+// commit appends a transaction and syncs the log. If it fails, the log may
+// end in a partial transaction, which the next open drops.
+func (l *wal) commit(seq uint64, changes []entry) error {
+	crc := crc32.NewIEEE()
+	for _, e := range changes {
+		buf := e.encode()
+		crc.Write(buf[:])
+		if _, err := l.w.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	commit := entry{op: opCommit, key: key{uint64(len(changes)), uint64(crc.Sum32()), seq}}.encode()
+	if _, err := l.w.Write(commit[:]); err != nil {
+		return err
+	}
+	if err := l.w.Flush(); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.size += int64(len(changes)+1) * entrySize
+	return nil
+}
+
+// reset empties the log once its transactions are in the index files.
+func (l *wal) reset() error {
+	if err := l.truncate(0); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// truncate cuts the log to size bytes, dropping what was written after.
+func (l *wal) truncate(size int64) error {
+	l.w.Reset(l.file)
+	if size < l.size {
+		if err := l.file.Truncate(size); err != nil {
+			return err
+		}
+	}
+	if _, err := l.file.Seek(size, io.SeekStart); err != nil {
+		return err
+	}
+	l.size = size
+	return nil
+}
+
+func (l *wal) close() error {
+	return l.file.Close()
+}
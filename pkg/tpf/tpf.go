@@ -0,0 +1,328 @@
+// Package tpf serves Triple Pattern Fragments: the triples matching one
+// subject, predicate and object pattern, a page at a time, with the count
+// and hypermedia controls clients need to query the data themselves.
+package tpf
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/DeDude/tripl/internal/httputil"
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+const (
+	hydraNS = "http://www.w3.org/ns/hydra/core#"
+	voidNS  = "http://rdfs.org/ns/void#"
+	foafNS  = "http://xmlns.com/foaf/0.1/"
+
+	// DefaultPageSize is the number of triples per page when
+	// HandlerOptions.PageSize is zero.
+	DefaultPageSize = 100
+)
+
+// Source is a graph that can count and page through the matches of a
+// pattern without collecting them all, such as *triple.Graph.
+type Source interface {
+	Count(subject, predicate, object triple.Node) int
+	MatchPage(subject, predicate, object triple.Node, offset, limit int) []triple.Triple
+}
+
+// HandlerOptions configures a fragments handler.
+type HandlerOptions struct {
+	// PageSize is the number of triples per page; zero means
+	// DefaultPageSize.
+	PageSize int
+	// Prefixes shorten IRIs in Turtle, TriG and JSON-LD responses.
+	Prefixes map[string]string
+	// Lock guards the source against writers elsewhere. Nil gives the
+	// handler its own.
+	Lock *sync.RWMutex
+}
+
+// Handler answers GET requests for ?subject=&predicate=&object=&page=
+// fragments. Terms use the explicit representation of the Triple Pattern
+// Fragments specification: IRIs as is, literals as "value", "value"@lang or
+// "value"^^datatype, and variables as ?name or left out.
+type Handler struct {
+	source Source
+	opts   HandlerOptions
+	mu     *sync.RWMutex
+}
+
+func NewHandler(source Source, opts HandlerOptions) *Handler {
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+	mu := opts.Lock
+	if mu == nil {
+		mu = new(sync.RWMutex)
+	}
+	return &Handler{source: source, opts: opts, mu: mu}
+}
+
+const trigMediaType = "application/trig"
+
+// mediaTypes lists the media types offered, most preferred first.
+var mediaTypes = []string{
+	encoder.Turtle.MediaType(),
+	trigMediaType,
+	encoder.JSONLD.MediaType(),
+}
+
+// pattern is a parsed fragment request.
+type pattern struct {
+	// params holds the subject, predicate and object parameters as given,
+	// "" for a variable.
+	params [3]string
+	terms  [3]triple.Node
+	page   int
+}
+
+var patternNames = [3]string{"subject", "predicate", "object"}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		httputil.Error(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+
+	pat, err := parsePattern(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	mediaType, ok := httputil.Negotiate(r.Header.Get("Accept"), mediaTypes)
+	if !ok {
+		httputil.Error(w, http.StatusNotAcceptable, "acceptable media types: %s", strings.Join(mediaTypes, ", "))
+		return
+	}
+
+	offset := (pat.page - 1) * h.opts.PageSize
+	s, p, o := pat.terms[0], pat.terms[1], pat.terms[2]
+	h.mu.RLock()
+	total := h.source.Count(s, p, o)
+	data := h.source.MatchPage(s, p, o, offset, h.opts.PageSize)
+	h.mu.RUnlock()
+
+	base := httputil.RequestURL(r)
+	metadata := h.metadata(base, pat, total, offset+len(data) < total)
+
+	prefixes := map[string]string{"hydra": hydraNS, "void": voidNS, "rdf": rdf.Namespace, "xsd": xsd.Namespace}
+	for k, v := range h.opts.Prefixes {
+		prefixes[k] = v
+	}
+
+	var body string
+	switch mediaType {
+	case trigMediaType:
+		if _, ok := prefixes["foaf"]; !ok {
+			prefixes["foaf"] = foafNS
+		}
+		body = encodeTriG(data, metadata, pat.url(base, pat.page)+"#metadata", prefixes)
+	default:
+		format, _ := encoder.FormatForMediaType(mediaType)
+		body, err = encoder.Encode(append(data, metadata...), format, encoder.EncodeOptions{Prefixes: prefixes, Compact: true})
+	}
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", httputil.ContentType(mediaType))
+	w.Header().Set("Vary", "Accept")
+	io.WriteString(w, body)
+}
+
+func parsePattern(r *http.Request) (pattern, error) {
+	params := r.URL.Query()
+	pat := pattern{page: 1}
+	for i, name := range patternNames {
+		value := params.Get(name)
+		if value == "" || strings.HasPrefix(value, "?") {
+			continue
+		}
+		n, err := ParseTerm(value)
+		if err != nil {
+			return pattern{}, fmt.Errorf("%s: %w", name, err)
+		}
+		pat.params[i], pat.terms[i] = value, n
+	}
+	if page := params.Get("page"); page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			return pattern{}, fmt.Errorf("page must be a positive integer, not %q", page)
+		}
+		pat.page = n
+	}
+	return pat, nil
+}
+
+// ParseTerm reads a term in the explicit representation: "value",
+// "value"@lang, "value"^^datatype (with or without angle brackets), _:label
+// or an IRI.
+func ParseTerm(s string) (triple.Node, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		end := strings.LastIndex(s, `"`)
+		if end == 0 {
+			return nil, fmt.Errorf("unterminated literal %s", s)
+		}
+		lit := triple.Literal{Value: s[1:end]}
+		switch suffix := s[end+1:]; {
+		case suffix == "":
+		case strings.HasPrefix(suffix, "@") && len(suffix) > 1:
+			lit.Language, lit.Direction, _ = strings.Cut(suffix[1:], "--")
+		case strings.HasPrefix(suffix, "^^"):
+			lit.Datatype = strings.TrimSuffix(strings.TrimPrefix(suffix[2:], "<"), ">")
+			// Clients spell simple literals either way; the decoders
+			// leave their datatype empty.
+			if lit.Datatype == xsd.String {
+				lit.Datatype = ""
+			}
+		default:
+			return nil, fmt.Errorf("invalid literal %s", s)
+		}
+		return lit, nil
+	case strings.HasPrefix(s, "_:"):
+		return triple.BlankNode{Value: s[2:]}, nil
+	}
+	iri := strings.TrimSuffix(strings.TrimPrefix(s, "<"), ">")
+	if err := triple.ValidateIRI(iri); err != nil {
+		return nil, err
+	}
+	return triple.IRI{Value: iri}, nil
+}
+
+// url returns the URL of a page of the fragment, with the parameters in a
+// fixed order so that each page has one URL.
+func (pat pattern) url(base string, page int) string {
+	var query []string
+	for i, name := range patternNames {
+		if pat.params[i] != "" {
+			query = append(query, name+"="+urlEscape(pat.params[i]))
+		}
+	}
+	if page > 1 {
+		query = append(query, "page="+strconv.Itoa(page))
+	}
+	if len(query) == 0 {
+		return base
+	}
+	return base + "?" + strings.Join(query, "&")
+}
+
+// urlEscape escapes a query value, keeping the characters IRIs commonly
+// contain readable.
+func urlEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUnreserved(c) || strings.IndexByte(":/@", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0
+}
+
+// metadata describes the fragment page: its counts, its neighbouring pages
+// and the search form that produces fragments of this dataset.
+func (h *Handler) metadata(base string, pat pattern, total int, hasNext bool) []triple.Triple {
+	fragment := triple.IRI{Value: pat.url(base, pat.page)}
+	dataset := triple.IRI{Value: base + "#dataset"}
+	search := triple.IRI{Value: base + "#triplePattern"}
+	hydra := func(name string) triple.IRI { return triple.IRI{Value: hydraNS + name} }
+	void := func(name string) triple.IRI { return triple.IRI{Value: voidNS + name} }
+	count := triple.NewIntegerLiteral(int64(total))
+
+	ts := []triple.Triple{
+		{Subject: fragment, Predicate: void("triples"), Object: count},
+		{Subject: fragment, Predicate: hydra("totalItems"), Object: count},
+		{Subject: fragment, Predicate: hydra("itemsPerPage"), Object: triple.NewIntegerLiteral(int64(h.opts.PageSize))},
+		{Subject: fragment, Predicate: hydra("first"), Object: triple.IRI{Value: pat.url(base, 1)}},
+	}
+	if pat.page > 1 {
+		ts = append(ts, triple.Triple{Subject: fragment, Predicate: hydra("previous"), Object: triple.IRI{Value: pat.url(base, pat.page-1)}})
+	}
+	if hasNext {
+		ts = append(ts, triple.Triple{Subject: fragment, Predicate: hydra("next"), Object: triple.IRI{Value: pat.url(base, pat.page+1)}})
+	}
+
+	ts = append(ts,
+		triple.Triple{Subject: dataset, Predicate: rdf.Type, Object: void("Dataset")},
+		triple.Triple{Subject: dataset, Predicate: rdf.Type, Object: hydra("Collection")},
+		triple.Triple{Subject: dataset, Predicate: void("subset"), Object: fragment},
+		triple.Triple{Subject: dataset, Predicate: hydra("search"), Object: search},
+		triple.Triple{Subject: search, Predicate: hydra("template"), Object: triple.Literal{Value: base + "{?subject,predicate,object}"}},
+		triple.Triple{Subject: search, Predicate: hydra("variableRepresentation"), Object: hydra("ExplicitRepresentation")},
+	)
+	for _, name := range patternNames {
+		mapping := triple.IRI{Value: base + "#" + name}
+		ts = append(ts,
+			triple.Triple{Subject: search, Predicate: hydra("mapping"), Object: mapping},
+			triple.Triple{Subject: mapping, Predicate: hydra("variable"), Object: triple.Literal{Value: name}},
+			triple.Triple{Subject: mapping, Predicate: hydra("property"), Object: triple.IRI{Value: rdf.Namespace + name}},
+		)
+	}
+	return ts
+}
+
+// encodeTriG writes the data in the default graph and the metadata in a
+// graph of its own, which the specification recommends so that clients can
+// tell them apart.
+func encodeTriG(data, metadata []triple.Triple, graph string, prefixes map[string]string) string {
+	metadata = append(metadata, triple.Triple{
+		Subject:   triple.IRI{Value: graph},
+		Predicate: triple.IRI{Value: foafNS + "primaryTopic"},
+		Object:    metadata[0].Subject,
+	})
+
+	// Encoding everything once gives the prefix declarations both parts
+	// need.
+	header, _ := splitPrefixes(encoder.EncodeTurtleCompact(append(data, metadata...), prefixes))
+	_, dataBody := splitPrefixes(encoder.EncodeTurtleCompact(data, prefixes))
+	_, metaBody := splitPrefixes(encoder.EncodeTurtleCompact(metadata, prefixes))
+
+	var b strings.Builder
+	b.WriteString(header)
+	if dataBody != "" {
+		b.WriteString("\n" + dataBody)
+	}
+	fmt.Fprintf(&b, "\n%s {\n", encoder.EncodeTerm(triple.IRI{Value: graph}))
+	for _, line := range strings.Split(strings.TrimRight(metaBody, "\n"), "\n") {
+		if line != "" {
+			line = "    " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// splitPrefixes separates the @prefix lines of a Turtle document from the
+// rest, dropping the blank lines between them.
+func splitPrefixes(doc string) (header, body string) {
+	lines := strings.Split(doc, "\n")
+	i := 0
+	for i < len(lines) && strings.HasPrefix(lines[i], "@prefix") {
+		header += lines[i] + "\n"
+		i++
+	}
+	body = strings.TrimLeft(strings.Join(lines[i:], "\n"), "\n")
+	return header, body
+}
@@ -0,0 +1,190 @@
+package tpf
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+	"github.com/DeDude/tripl/pkg/triple"
+)
+
+func testHandler() *Handler {
+	ex := func(s string) triple.IRI { return triple.IRI{Value: "http://example.org/" + s} }
+	var triples []triple.Triple
+	for i := 0; i < 25; i++ {
+		person := ex(fmt.Sprintf("p%d", i))
+		triples = append(triples,
+			triple.Triple{Subject: person, Predicate: ex("type"), Object: ex("Person")},
+			triple.Triple{Subject: person, Predicate: ex("age"), Object: triple.NewIntegerLiteral(int64(20 + i%3))},
+		)
+	}
+	triples = append(triples, triple.Triple{Subject: ex("p0"), Predicate: ex("name"), Object: triple.Literal{Value: "Zero", Language: "en"}})
+	return NewHandler(triple.NewGraph(triples), HandlerOptions{PageSize: 10, Prefixes: map[string]string{"ex": "http://example.org/"}})
+}
+
+func get(h http.Handler, target, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFragments(t *testing.T) {
+	h := testHandler()
+
+	tests := []struct {
+		name    string
+		target  string
+		triples int
+		total   int
+		want    []string
+		exclude []string
+	}{
+		{
+			name:    "predicate, first page",
+			target:  "/?predicate=http%3A%2F%2Fexample.org%2Ftype",
+			triples: 10,
+			total:   25,
+			want: []string{
+				"<http://example.com/?predicate=http://example.org/type&page=2>",
+				"hydra:first <http://example.com/?predicate=http://example.org/type>",
+				"hydra:template \"http://example.com/{?subject,predicate,object}\"",
+			},
+			exclude: []string{"hydra:previous"},
+		},
+		{
+			name:    "last page",
+			target:  "/?predicate=http://example.org/type&page=3",
+			triples: 5,
+			total:   25,
+			want:    []string{"hydra:previous <http://example.com/?predicate=http://example.org/type&page=2>"},
+			exclude: []string{"hydra:next"},
+		},
+		{
+			name:    "typed literal object",
+			target:  "/?object=%2221%22%5E%5Ehttp%3A%2F%2Fwww.w3.org%2F2001%2FXMLSchema%23integer",
+			triples: 8,
+			total:   8,
+		},
+		{
+			name:    "language literal and variables",
+			target:  "/?subject=%3Fs&predicate=&object=%22Zero%22%40en",
+			triples: 1,
+			total:   1,
+		},
+		{
+			name:    "no matches",
+			target:  "/?subject=http://example.org/nobody",
+			triples: 0,
+			total:   0,
+		},
+		{
+			name:    "everything",
+			target:  "/",
+			triples: 10,
+			total:   51,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(h, tt.target, "text/turtle")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			body := rec.Body.String()
+			triples, _, err := encoder.Decode(body, encoder.Turtle, encoder.DecodeOptions{})
+			if err != nil {
+				t.Fatalf("response is not Turtle: %v\n%s", err, body)
+			}
+
+			data := 0
+			for _, tr := range triples {
+				if strings.HasPrefix(tr.Subject.(triple.IRI).Value, "http://example.org/") {
+					data++
+				}
+			}
+			if data != tt.triples {
+				t.Errorf("%d data triples, want %d", data, tt.triples)
+			}
+			if want := fmt.Sprintf(`void:triples "%d"^^xsd:integer`, tt.total); !strings.Contains(body, want) {
+				t.Errorf("body lacks %q:\n%s", want, body)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body lacks %q:\n%s", want, body)
+				}
+			}
+			for _, exclude := range tt.exclude {
+				if strings.Contains(body, exclude) {
+					t.Errorf("body contains %q:\n%s", exclude, body)
+				}
+			}
+		})
+	}
+}
+
+func TestFragmentFormats(t *testing.T) {
+	h := testHandler()
+	target := "/?subject=http://example.org/p1"
+
+	rec := get(h, target, "application/trig")
+	body := rec.Body.String()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/trig" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	for _, want := range []string{
+		"@prefix hydra: <http://www.w3.org/ns/hydra/core#> .",
+		"ex:p1 ex:type ex:Person ;",
+		"<http://example.com/?subject=http://example.org/p1#metadata> {\n",
+		"foaf:primaryTopic",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("TriG lacks %q:\n%s", want, body)
+		}
+	}
+	// The metadata graph follows the data.
+	if strings.Index(body, "ex:p1 ex:type") > strings.Index(body, "#metadata> {") {
+		t.Errorf("TriG data is inside the metadata graph:\n%s", body)
+	}
+
+	rec = get(h, target, "application/ld+json")
+	if _, _, err := encoder.Decode(rec.Body.String(), encoder.JSONLD, encoder.DecodeOptions{}); err != nil || rec.Code != http.StatusOK {
+		t.Errorf("JSON-LD response: %d %v\n%s", rec.Code, err, rec.Body)
+	}
+
+	if rec := get(h, target, "application/rdf+xml"); rec.Code != http.StatusNotAcceptable {
+		t.Errorf("RDF/XML status = %d, want 406", rec.Code)
+	}
+	for _, bad := range []string{"/?page=0", "/?subject=%22open", "/?predicate=not%20an%20iri"} {
+		if rec := get(h, bad, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want 400", bad, rec.Code)
+		}
+	}
+}
+
+func TestParseTerm(t *testing.T) {
+	tests := []struct {
+		in   string
+		want triple.Node
+	}{
+		{"http://example.org/a", triple.IRI{Value: "http://example.org/a"}},
+		{"<http://example.org/a>", triple.IRI{Value: "http://example.org/a"}},
+		{`"a "quoted" value"`, triple.Literal{Value: `a "quoted" value`}},
+		{`"chat"@fr`, triple.Literal{Value: "chat", Language: "fr"}},
+		{`"x"^^<http://www.w3.org/2001/XMLSchema#string>`, triple.Literal{Value: "x"}},
+		{`"1"^^http://www.w3.org/2001/XMLSchema#integer`, triple.NewIntegerLiteral(1)},
+		{"_:b1", triple.BlankNode{Value: "b1"}},
+	}
+	for _, tt := range tests {
+		if got, err := ParseTerm(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseTerm(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package triple
+
+// Matcher finds the triples matching a pattern. A nil term matches anything.
+type Matcher interface {
+	Match(subject, predicate, object Node) []Triple
+}
+
+// Graph is an in-memory set of triples that keeps insertion order and indexes
+// triples by subject, predicate and object.
+type Graph struct {
+	triples    []Triple
+	seen       map[Triple]bool
+	subjects   map[Node][]int
+	predicates map[Node][]int
+	objects    map[Node][]int
+	// removed counts the zero Triples left in triples by Remove.
+	removed int
+}
+
+func NewGraph(triples []Triple) *Graph {
+	g := &Graph{
+		seen:       make(map[Triple]bool, len(triples)),
+		subjects:   make(map[Node][]int),
+		predicates: make(map[Node][]int),
+		objects:    make(map[Node][]int),
+	}
+	for _, t := range triples {
+		g.Add(t)
+	}
+	return g
+}
+
+// Add inserts t and reports whether it was not already in the graph.
+func (g *Graph) Add(t Triple) bool {
+	if g.seen[t] {
+		return false
+	}
+	g.seen[t] = true
+	g.index(t)
+	return true
+}
+
+func (g *Graph) index(t Triple) {
+	g.subjects[t.Subject] = append(g.subjects[t.Subject], len(g.triples))
+	g.predicates[t.Predicate] = append(g.predicates[t.Predicate], len(g.triples))
+	g.objects[t.Object] = append(g.objects[t.Object], len(g.triples))
+	g.triples = append(g.triples, t)
+}
+
+// Remove deletes t and reports whether it was in the graph.
+func (g *Graph) Remove(t Triple) bool {
+	if !g.seen[t] {
+		return false
+	}
+	delete(g.seen, t)
+	for _, i := range g.subjects[t.Subject] {
+		if g.triples[i] == t {
+			g.triples[i] = Triple{}
+			break
+		}
+	}
+	g.removed++
+	if g.removed > len(g.triples)/2 {
+		g.compact()
+	}
+	return true
+}
+
+// compact drops removed triples and rebuilds the indexes.
+func (g *Graph) compact() {
+	live := g.Triples()
+	g.triples = g.triples[:0]
+	g.subjects = make(map[Node][]int)
+	g.predicates = make(map[Node][]int)
+	g.objects = make(map[Node][]int)
+	g.removed = 0
+	for _, t := range live {
+		g.index(t)
+	}
+}
+
+func (g *Graph) Has(t Triple) bool {
+	return g.seen[t]
+}
+
+func (g *Graph) Len() int {
+	return len(g.seen)
+}
+
+// Triples returns the triples in insertion order.
+func (g *Graph) Triples() []Triple {
+	result := make([]Triple, 0, len(g.seen))
+	for _, t := range g.triples {
+		if t.Subject != nil {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func (g *Graph) Match(subject, predicate, object Node) []Triple {
+	return g.MatchPage(subject, predicate, object, 0, -1)
+}
+
+// MatchPage returns the matches of a pattern in insertion order, skipping
+// the first offset and returning at most limit of them; a negative limit
+// means no limit. Only the returned triples are collected, so paging through
+// a large result stays cheap.
+func (g *Graph) MatchPage(subject, predicate, object Node, offset, limit int) []Triple {
+	if subject != nil && predicate != nil && object != nil {
+		t := Triple{Subject: subject, Predicate: predicate, Object: object}
+		if g.seen[t] && offset == 0 && limit != 0 {
+			return []Triple{t}
+		}
+		return nil
+	}
+
+	var result []Triple
+	g.scan(subject, predicate, object, func(t Triple) bool {
+		if offset > 0 {
+			offset--
+			return true
+		}
+		if limit >= 0 && len(result) == limit {
+			return false
+		}
+		result = append(result, t)
+		return true
+	})
+	return result
+}
+
+// Count returns the number of triples matching a pattern.
+func (g *Graph) Count(subject, predicate, object Node) int {
+	candidates, all := g.candidates(subject, predicate, object)
+	bound := 0
+	for _, n := range []Node{subject, predicate, object} {
+		if n != nil {
+			bound++
+		}
+	}
+	switch {
+	case all:
+		return g.Len()
+	case bound == 3:
+		if g.seen[Triple{Subject: subject, Predicate: predicate, Object: object}] {
+			return 1
+		}
+		return 0
+	case bound == 1 && g.removed == 0:
+		// Every index entry is a live match.
+		return len(candidates)
+	}
+
+	count := 0
+	g.scan(subject, predicate, object, func(Triple) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// candidates returns the positions of the triples that can match a pattern:
+// the shortest index list among the bound terms, or all triples.
+func (g *Graph) candidates(subject, predicate, object Node) ([]int, bool) {
+	var best []int
+	found := false
+	for _, c := range []struct {
+		term  Node
+		index map[Node][]int
+	}{{subject, g.subjects}, {predicate, g.predicates}, {object, g.objects}} {
+		if c.term == nil {
+			continue
+		}
+		list := c.index[c.term]
+		if !found || len(list) < len(best) {
+			best, found = list, true
+		}
+	}
+	return best, !found
+}
+
+// scan calls fn with each triple matching a pattern, in insertion order,
+// until fn returns false.
+func (g *Graph) scan(subject, predicate, object Node, fn func(Triple) bool) {
+	matches := func(t Triple) bool {
+		return t.Subject != nil &&
+			(subject == nil || t.Subject == subject) &&
+			(predicate == nil || t.Predicate == predicate) &&
+			(object == nil || t.Object == object)
+	}
+
+	candidates, all := g.candidates(subject, predicate, object)
+	if all {
+		for _, t := range g.triples {
+			if t.Subject != nil && !fn(t) {
+				return
+			}
+		}
+		return
+	}
+	for _, i := range candidates {
+		if t := g.triples[i]; matches(t) && !fn(t) {
+			return
+		}
+	}
+}
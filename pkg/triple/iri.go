@@ -0,0 +1,173 @@
+package triple
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// NewIRI returns an IRI after checking it is an absolute RFC 3987 IRI.
+func NewIRI(value string) (IRI, error) {
+	if err := ValidateIRI(value); err != nil {
+		return IRI{}, err
+	}
+	return IRI{Value: value}, nil
+}
+
+func (i IRI) Validate() error {
+	return ValidateIRI(i.Value)
+}
+
+// ValidateIRI checks that s is an absolute IRI as defined by RFC 3987:
+// a scheme followed by characters that are unreserved, reserved,
+// percent-encoded or in the allowed Unicode ranges.
+func ValidateIRI(s string) error {
+	if s == "" {
+		return fmt.Errorf("empty IRI")
+	}
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("IRI <%s> is not valid UTF-8", s)
+	}
+
+	colon := strings.IndexByte(s, ':')
+	if colon <= 0 || !isScheme(s[:colon]) {
+		return fmt.Errorf("IRI <%s> has no scheme", s)
+	}
+
+	rest := s[colon+1:]
+	rest, fragment, hasFragment := strings.Cut(rest, "#")
+	rest, query, hasQuery := strings.Cut(rest, "?")
+
+	if strings.HasPrefix(rest, "//") {
+		authority := rest[2:]
+		if i := strings.IndexByte(authority, '/'); i >= 0 {
+			rest = authority[i:]
+			authority = authority[:i]
+		} else {
+			rest = ""
+		}
+		if err := checkAuthority(s, authority); err != nil {
+			return err
+		}
+	}
+
+	if err := checkIRIPart(s, rest, "/:@", false); err != nil {
+		return err
+	}
+	if hasQuery {
+		if err := checkIRIPart(s, query, "/:@?", true); err != nil {
+			return err
+		}
+	}
+	if hasFragment {
+		if err := checkIRIPart(s, fragment, "/:@?", false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isScheme(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isAlpha(c):
+		case i > 0 && (isDigit(c) || c == '+' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func checkAuthority(iri, authority string) error {
+	if at := strings.LastIndexByte(authority, '@'); at >= 0 {
+		if err := checkIRIPart(iri, authority[:at], ":", false); err != nil {
+			return err
+		}
+		authority = authority[at+1:]
+	}
+
+	host, port := authority, ""
+	if strings.HasPrefix(authority, "[") {
+		end := strings.IndexByte(authority, ']')
+		if end == -1 {
+			return fmt.Errorf("IRI <%s> has an unclosed IP literal", iri)
+		}
+		for _, c := range authority[1:end] {
+			if !(c < utf8.RuneSelf && (isAlpha(byte(c)) || isDigit(byte(c)) || strings.ContainsRune(":.-_~!$&'()*+,;=", c))) {
+				return fmt.Errorf("IRI <%s> has an invalid IP literal", iri)
+			}
+		}
+		host, port = "", authority[end+1:]
+		if port != "" && !strings.HasPrefix(port, ":") {
+			return fmt.Errorf("IRI <%s> has an invalid authority", iri)
+		}
+		port = strings.TrimPrefix(port, ":")
+	} else if i := strings.LastIndexByte(authority, ':'); i >= 0 {
+		host, port = authority[:i], authority[i+1:]
+	}
+
+	for i := 0; i < len(port); i++ {
+		if !isDigit(port[i]) {
+			return fmt.Errorf("IRI <%s> has an invalid port", iri)
+		}
+	}
+	return checkIRIPart(iri, host, "", false)
+}
+
+// checkIRIPart checks one component of an IRI. extra lists the gen-delims
+// allowed in the component; private allows the iprivate ranges (queries only).
+func checkIRIPart(iri, part, extra string, private bool) error {
+	for i := 0; i < len(part); {
+		c := part[i]
+		if c == '%' {
+			if i+2 >= len(part) || !isHex(part[i+1]) || !isHex(part[i+2]) {
+				return fmt.Errorf("IRI <%s> has an invalid percent-encoding", iri)
+			}
+			i += 3
+			continue
+		}
+
+		if c < utf8.RuneSelf {
+			if !isAlpha(c) && !isDigit(c) && !strings.ContainsRune("-._~!$&'()*+,;=", rune(c)) && !strings.ContainsRune(extra, rune(c)) {
+				return fmt.Errorf("IRI <%s> contains disallowed character %q", iri, c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(part[i:])
+		if !isUcschar(r) && !(private && isIPrivate(r)) {
+			return fmt.Errorf("IRI <%s> contains disallowed character %U", iri, r)
+		}
+		i += size
+	}
+	return nil
+}
+
+func isUcschar(r rune) bool {
+	switch {
+	case r >= 0xA0 && r <= 0xD7FF, r >= 0xF900 && r <= 0xFDCF, r >= 0xFDF0 && r <= 0xFFEF:
+		return true
+	case r >= 0x10000 && r <= 0xEFFFD:
+		return r&0xFFFF <= 0xFFFD
+	}
+	return false
+}
+
+func isIPrivate(r rune) bool {
+	return (r >= 0xE000 && r <= 0xF8FF) || (r >= 0xF0000 && r <= 0xFFFFD) || (r >= 0x100000 && r <= 0x10FFFD)
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,141 @@
+package triple
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NewLangLiteral returns a language-tagged string after checking the tag.
+func NewLangLiteral(value, language string) (Literal, error) {
+	if err := ValidateLanguageTag(language); err != nil {
+		return Literal{}, err
+	}
+	return Literal{Value: value, Language: language}, nil
+}
+
+// NewDirLangLiteral returns a language-tagged string with a base direction,
+// "ltr" or "rtl".
+func NewDirLangLiteral(value, language, direction string) (Literal, error) {
+	lit := Literal{Value: value, Language: language, Direction: direction}
+	if err := validateTerm(lit); err != nil {
+		return Literal{}, err
+	}
+	return lit, nil
+}
+
+// grandfathered tags from RFC 5646 that do not follow the regular syntax.
+var grandfathered = map[string]bool{
+	"en-gb-oed": true, "i-ami": true, "i-bnn": true, "i-default": true,
+	"i-enochian": true, "i-hak": true, "i-klingon": true, "i-lux": true,
+	"i-mingo": true, "i-navajo": true, "i-pwn": true, "i-tao": true,
+	"i-tay": true, "i-tsu": true, "sgn-be-fr": true, "sgn-be-nl": true,
+	"sgn-ch-de": true, "art-lojban": true, "cel-gaulish": true,
+	"no-bok": true, "no-nyn": true, "zh-guoyu": true, "zh-hakka": true,
+	"zh-min": true, "zh-min-nan": true, "zh-xiang": true,
+}
+
+// ValidateLanguageTag checks that tag is a well-formed BCP 47 language tag.
+// Well-formedness is syntactic; subtags are not checked against the registry.
+func ValidateLanguageTag(tag string) error {
+	lower := strings.ToLower(tag)
+	if grandfathered[lower] {
+		return nil
+	}
+
+	subtags := strings.Split(lower, "-")
+	for _, s := range subtags {
+		if s == "" || len(s) > 8 || !isAlphanumeric(s) {
+			return fmt.Errorf("invalid language tag %q", tag)
+		}
+	}
+
+	if subtags[0] == "x" {
+		if len(subtags) == 1 {
+			return fmt.Errorf("invalid language tag %q: empty private use", tag)
+		}
+		return nil
+	}
+
+	i := 0
+	language := subtags[i]
+	if !isAlphaString(language) || len(language) < 2 {
+		return fmt.Errorf("invalid language tag %q: bad primary language", tag)
+	}
+	i++
+
+	// Up to three extlang subtags follow a 2-3 letter language.
+	if len(language) <= 3 {
+		for n := 0; n < 3 && i < len(subtags) && len(subtags[i]) == 3 && isAlphaString(subtags[i]); n++ {
+			i++
+		}
+	}
+
+	if i < len(subtags) && len(subtags[i]) == 4 && isAlphaString(subtags[i]) {
+		i++ // script
+	}
+
+	if i < len(subtags) {
+		s := subtags[i]
+		if (len(s) == 2 && isAlphaString(s)) || (len(s) == 3 && isDigitString(s)) {
+			i++ // region
+		}
+	}
+
+	for i < len(subtags) && isVariant(subtags[i]) {
+		i++
+	}
+
+	for i < len(subtags) && len(subtags[i]) == 1 && subtags[i] != "x" {
+		i++
+		start := i
+		for i < len(subtags) && len(subtags[i]) >= 2 {
+			i++
+		}
+		if i == start {
+			return fmt.Errorf("invalid language tag %q: empty extension", tag)
+		}
+	}
+
+	if i < len(subtags) && subtags[i] == "x" {
+		if i == len(subtags)-1 {
+			return fmt.Errorf("invalid language tag %q: empty private use", tag)
+		}
+		return nil
+	}
+
+	if i != len(subtags) {
+		return fmt.Errorf("invalid language tag %q: unexpected subtag %q", tag, subtags[i])
+	}
+	return nil
+}
+
+func isVariant(s string) bool {
+	return (len(s) >= 5 && len(s) <= 8) || (len(s) == 4 && isDigit(s[0]))
+}
+
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isAlpha(s[i]) && !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphaString(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isAlpha(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigitString(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package triple
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	rdfFirst = RDFNamespace + "first"
+	rdfRest  = RDFNamespace + "rest"
+	rdfNil   = RDFNamespace + "nil"
+)
+
+// Errors returned by ReadList, wrapped with the offending node.
+var (
+	ErrMalformedList = errors.New("malformed rdf:List")
+	ErrCyclicList    = errors.New("cyclic rdf:List")
+	ErrBranchingList = errors.New("branching rdf:List")
+)
+
+// NewList returns the head of an rdf:List holding items and the rdf:first and
+// rdf:rest triples that build it. fresh returns the node for each cell, in
+// order; it is usually a blank node generator. The empty list is rdf:nil.
+func NewList(items []Node, fresh func() Node) (Node, []Triple) {
+	if len(items) == 0 {
+		return IRI{Value: rdfNil}, nil
+	}
+
+	cells := make([]Node, len(items))
+	for i := range items {
+		cells[i] = fresh()
+	}
+
+	triples := make([]Triple, 0, 2*len(items))
+	for i, item := range items {
+		var rest Node = IRI{Value: rdfNil}
+		if i+1 < len(cells) {
+			rest = cells[i+1]
+		}
+		triples = append(triples,
+			Triple{Subject: cells[i], Predicate: IRI{Value: rdfFirst}, Object: item},
+			Triple{Subject: cells[i], Predicate: IRI{Value: rdfRest}, Object: rest},
+		)
+	}
+	return cells[0], triples
+}
+
+// ReadList follows the rdf:List starting at head and returns its items and
+// its cell nodes. Every cell must have exactly one rdf:first and one
+// rdf:rest; a cell with several is branching, one without is malformed, and a
+// cell reached twice makes the list cyclic.
+func ReadList(g Matcher, head Node) (items, cells []Node, err error) {
+	visited := make(map[Node]bool)
+	for cell := head; cell != (IRI{Value: rdfNil}); {
+		switch cell.(type) {
+		case IRI, BlankNode:
+		default:
+			return nil, nil, fmt.Errorf("%w: %s is not a list node", ErrMalformedList, nodeLabel(cell))
+		}
+		if visited[cell] {
+			return nil, nil, fmt.Errorf("%w: %s is reached twice", ErrCyclicList, nodeLabel(cell))
+		}
+		visited[cell] = true
+
+		first, err := listObject(g, cell, rdfFirst)
+		if err != nil {
+			return nil, nil, err
+		}
+		rest, err := listObject(g, cell, rdfRest)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		items = append(items, first)
+		cells = append(cells, cell)
+		cell = rest
+	}
+	return items, cells, nil
+}
+
+func listObject(g Matcher, cell Node, predicate string) (Node, error) {
+	matches := g.Match(cell, IRI{Value: predicate}, nil)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s has no <%s>", ErrMalformedList, nodeLabel(cell), predicate)
+	case 1:
+		return matches[0].Object, nil
+	}
+	return nil, fmt.Errorf("%w: %s has %d <%s> values", ErrBranchingList, nodeLabel(cell), len(matches), predicate)
+}
+
+func nodeLabel(n Node) string {
+	switch node := n.(type) {
+	case IRI:
+		return "<" + node.Value + ">"
+	case BlankNode:
+		return "_:" + node.Value
+	case Literal:
+		return fmt.Sprintf("%q", node.Value)
+	}
+	return "triple term"
+}
@@ -0,0 +1,148 @@
+package triple
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestNewListReadList(t *testing.T) {
+	items := []Node{IRI{Value: "http://example.org/a"}, Literal{Value: "b"}, BlankNode{Value: "c"}}
+	n := 0
+	fresh := func() Node {
+		n++
+		return BlankNode{Value: "l" + strconv.Itoa(n)}
+	}
+
+	head, triples := NewList(items, fresh)
+	if head != (BlankNode{Value: "l1"}) || len(triples) != 6 {
+		t.Fatalf("NewList() = %v, %d triples", head, len(triples))
+	}
+	if last := triples[5]; last.Object != (IRI{Value: rdfNil}) {
+		t.Errorf("last rdf:rest = %v, want rdf:nil", last.Object)
+	}
+
+	got, cells, err := ReadList(NewGraph(triples), head)
+	if err != nil {
+		t.Fatalf("ReadList() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, items) {
+		t.Errorf("ReadList() items = %v, want %v", got, items)
+	}
+	if len(cells) != 3 || cells[2] != (BlankNode{Value: "l3"}) {
+		t.Errorf("ReadList() cells = %v", cells)
+	}
+
+	empty, triples := NewList(nil, fresh)
+	if empty != (IRI{Value: rdfNil}) || triples != nil {
+		t.Errorf("NewList(nil) = %v, %v", empty, triples)
+	}
+	if got, _, err := ReadList(NewGraph(nil), empty); err != nil || len(got) != 0 {
+		t.Errorf("ReadList(rdf:nil) = %v, %v", got, err)
+	}
+}
+
+func TestReadListErrors(t *testing.T) {
+	a, b := BlankNode{Value: "a"}, BlankNode{Value: "b"}
+	first, rest, nilList := IRI{Value: rdfFirst}, IRI{Value: rdfRest}, IRI{Value: rdfNil}
+	x := Literal{Value: "x"}
+
+	tests := []struct {
+		name    string
+		triples []Triple
+		head    Node
+		want    error
+	}{
+		{"missing rest", []Triple{{a, first, x}}, a, ErrMalformedList},
+		{"missing first", []Triple{{a, rest, nilList}}, a, ErrMalformedList},
+		{"literal cell", []Triple{{a, first, x}, {a, rest, x}}, a, ErrMalformedList},
+		{"cycle", []Triple{{a, first, x}, {a, rest, b}, {b, first, x}, {b, rest, a}}, a, ErrCyclicList},
+		{"two firsts", []Triple{{a, first, x}, {a, first, Literal{Value: "y"}}, {a, rest, nilList}}, a, ErrBranchingList},
+		{"two rests", []Triple{{a, first, x}, {a, rest, nilList}, {a, rest, b}, {b, first, x}, {b, rest, nilList}}, a, ErrBranchingList},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ReadList(NewGraph(tt.triples), tt.head)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ReadList() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphMatch(t *testing.T) {
+	s, p, q := IRI{Value: "http://example.org/s"}, IRI{Value: "http://example.org/p"}, IRI{Value: "http://example.org/q"}
+	one, two := Literal{Value: "1"}, Literal{Value: "2"}
+	g := NewGraph([]Triple{{s, p, one}, {s, q, two}, {one, p, two}})
+
+	if g.Add(Triple{s, p, one}) || g.Len() != 3 {
+		t.Errorf("Add() of a duplicate changed the graph")
+	}
+
+	tests := []struct {
+		name    string
+		s, p, o Node
+		want    int
+	}{
+		{"all", nil, nil, nil, 3},
+		{"subject", s, nil, nil, 2},
+		{"subject and predicate", s, p, nil, 1},
+		{"object", nil, nil, two, 2},
+		{"predicate", nil, p, nil, 2},
+		{"exact", s, q, two, 1},
+		{"missing", s, q, one, 0},
+	}
+	for _, tt := range tests {
+		if got := g.Match(tt.s, tt.p, tt.o); len(got) != tt.want {
+			t.Errorf("%s: Match() = %v, want %d triples", tt.name, got, tt.want)
+		}
+		if got := g.Count(tt.s, tt.p, tt.o); got != tt.want {
+			t.Errorf("%s: Count() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if got := g.MatchPage(nil, p, nil, 1, 5); len(got) != 1 || got[0] != (Triple{one, p, two}) {
+		t.Errorf("MatchPage(offset 1) = %v", got)
+	}
+	if got := g.MatchPage(nil, nil, nil, 0, 2); len(got) != 2 || got[1] != (Triple{s, q, two}) {
+		t.Errorf("MatchPage(limit 2) = %v", got)
+	}
+	g.Remove(Triple{s, p, one})
+	if got := g.Count(s, nil, nil); got != 1 {
+		t.Errorf("Count() after Remove() = %d, want 1", got)
+	}
+}
+
+func TestGraphRemove(t *testing.T) {
+	p := IRI{Value: "http://example.org/p"}
+	var triples []Triple
+	for i := 0; i < 6; i++ {
+		triples = append(triples, Triple{IRI{Value: "http://example.org/s" + strconv.Itoa(i)}, p, Literal{Value: strconv.Itoa(i)}})
+	}
+	g := NewGraph(triples)
+
+	if !g.Remove(triples[1]) || g.Remove(triples[1]) {
+		t.Errorf("Remove() should report only the first removal")
+	}
+	// Removing most triples compacts the graph; the indexes must stay valid.
+	for _, tr := range triples[2:5] {
+		g.Remove(tr)
+	}
+	if g.Len() != 2 || g.Has(triples[1]) {
+		t.Errorf("Len() = %d after removals, want 2", g.Len())
+	}
+	if got := g.Triples(); len(got) != 2 || got[0] != triples[0] || got[1] != triples[5] {
+		t.Errorf("Triples() = %v, want first and last", got)
+	}
+	if got := g.Match(triples[5].Subject, nil, nil); len(got) != 1 {
+		t.Errorf("Match() after compaction = %v", got)
+	}
+	if got := g.Match(nil, nil, triples[2].Object); len(got) != 0 {
+		t.Errorf("Match() found removed triple %v", got)
+	}
+	if !g.Add(triples[2]) || len(g.Match(nil, p, nil)) != 3 {
+		t.Errorf("Add() after Remove() did not restore the triple")
+	}
+}
@@ -0,0 +1,150 @@
+package triple
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+// RDFNamespace is the namespace of the RDF vocabulary. The IRIs used by this
+// package are built from it, and package vocab/rdf declares it as its own.
+const RDFNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// Implicit datatypes of language-tagged strings.
+const (
+	RDFLangString    = RDFNamespace + "langString"
+	RDFDirLangString = RDFNamespace + "dirLangString"
+)
+
+func NewIntegerLiteral(n int64) Literal {
+	return Literal{Value: strconv.FormatInt(n, 10), Datatype: xsd.Integer}
+}
+
+func NewDecimalLiteral(f float64) Literal {
+	return Literal{Value: xsd.FormatDecimal(f), Datatype: xsd.Decimal}
+}
+
+func NewDoubleLiteral(f float64) Literal {
+	return Literal{Value: xsd.FormatDouble(f), Datatype: xsd.Double}
+}
+
+func NewBooleanLiteral(b bool) Literal {
+	return Literal{Value: xsd.FormatBoolean(b), Datatype: xsd.Boolean}
+}
+
+func NewDateLiteral(t time.Time) Literal {
+	return Literal{Value: xsd.FormatDate(t), Datatype: xsd.Date}
+}
+
+func NewDateTimeLiteral(t time.Time) Literal {
+	return Literal{Value: xsd.FormatDateTime(t), Datatype: xsd.DateTime}
+}
+
+// NewTypedLiteral validates value against datatype and stores its canonical form.
+func NewTypedLiteral(value, datatype string) (Literal, error) {
+	canonical, err := xsd.Canonical(datatype, value)
+	if err != nil {
+		return Literal{}, err
+	}
+	return Literal{Value: canonical, Datatype: datatype}, nil
+}
+
+// DatatypeIRI returns the literal's datatype, including the implicit
+// rdf:langString, rdf:dirLangString and xsd:string datatypes.
+func (l Literal) DatatypeIRI() string {
+	switch {
+	case l.Datatype != "":
+		return l.Datatype
+	case l.Direction != "":
+		return RDFDirLangString
+	case l.Language != "":
+		return RDFLangString
+	}
+	return xsd.String
+}
+
+// Validate checks the lexical form against the literal's XSD datatype.
+func (l Literal) Validate() error {
+	return xsd.Validate(l.Datatype, l.Value)
+}
+
+// Canonical returns the literal with its lexical form in canonical form.
+func (l Literal) Canonical() (Literal, error) {
+	canonical, err := xsd.Canonical(l.Datatype, l.Value)
+	if err != nil {
+		return Literal{}, err
+	}
+	l.Value = canonical
+	return l, nil
+}
+
+func (l Literal) Int() (int64, error) {
+	if !xsd.IsInteger(l.Datatype) {
+		return 0, l.typeError("integer")
+	}
+	return xsd.ParseInteger(l.Datatype, l.Value)
+}
+
+// Float returns the value of any numeric literal as a float64.
+func (l Literal) Float() (float64, error) {
+	switch {
+	case xsd.IsInteger(l.Datatype):
+		n, err := xsd.ParseBigInteger(l.Datatype, l.Value)
+		if err != nil {
+			return 0, err
+		}
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f, nil
+	case l.Datatype == xsd.Decimal:
+		return xsd.ParseDecimal(l.Value)
+	case l.Datatype == xsd.Double:
+		return xsd.ParseDouble(l.Value)
+	case l.Datatype == xsd.Float:
+		f, err := xsd.ParseFloat(l.Value)
+		return float64(f), err
+	}
+	return 0, l.typeError("numeric")
+}
+
+func (l Literal) Bool() (bool, error) {
+	if l.Datatype != xsd.Boolean {
+		return false, l.typeError("boolean")
+	}
+	return xsd.ParseBoolean(l.Value)
+}
+
+// Time returns the value of an xsd:date or xsd:dateTime literal.
+func (l Literal) Time() (time.Time, error) {
+	switch l.Datatype {
+	case xsd.Date:
+		return xsd.ParseDate(l.Value)
+	case xsd.DateTime:
+		return xsd.ParseDateTime(l.Value)
+	}
+	return time.Time{}, l.typeError("date or dateTime")
+}
+
+func (l Literal) Duration() (xsd.DurationValue, error) {
+	if l.Datatype != xsd.Duration {
+		return xsd.DurationValue{}, l.typeError("duration")
+	}
+	return xsd.ParseDuration(l.Value)
+}
+
+// Bytes returns the decoded value of an xsd:hexBinary or xsd:base64Binary literal.
+func (l Literal) Bytes() ([]byte, error) {
+	switch l.Datatype {
+	case xsd.HexBinary:
+		return xsd.ParseHexBinary(l.Value)
+	case xsd.Base64Binary:
+		return xsd.ParseBase64Binary(l.Value)
+	}
+	return nil, l.typeError("binary")
+}
+
+func (l Literal) typeError(kind string) error {
+	return fmt.Errorf("literal %q has datatype %s, not %s", l.Value, l.DatatypeIRI(), kind)
+}
@@ -0,0 +1,97 @@
+package triple
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+func TestLiteralValues(t *testing.T) {
+	n, err := NewIntegerLiteral(-42).Int()
+	if err != nil || n != -42 {
+		t.Errorf("Int() = %d, %v; want -42", n, err)
+	}
+
+	f, err := Literal{Value: "2.5", Datatype: xsd.Decimal}.Float()
+	if err != nil || f != 2.5 {
+		t.Errorf("Float() = %v, %v; want 2.5", f, err)
+	}
+
+	f, err = Literal{Value: "99999999999999999999", Datatype: xsd.Integer}.Float()
+	if err != nil || f != 1e20 {
+		t.Errorf("Float() = %v, %v; want 1e20", f, err)
+	}
+
+	b, err := NewBooleanLiteral(true).Bool()
+	if err != nil || !b {
+		t.Errorf("Bool() = %v, %v; want true", b, err)
+	}
+
+	when := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	lit := NewDateTimeLiteral(when)
+	if lit.Value != "2024-01-15T10:30:00Z" {
+		t.Errorf("NewDateTimeLiteral() = %q", lit.Value)
+	}
+	parsed, err := lit.Time()
+	if err != nil || !parsed.Equal(when) {
+		t.Errorf("Time() = %v, %v; want %v", parsed, err, when)
+	}
+
+	if NewDoubleLiteral(0.001).Value != "1.0E-3" {
+		t.Errorf("NewDoubleLiteral() = %q", NewDoubleLiteral(0.001).Value)
+	}
+}
+
+func TestLiteralTypeErrors(t *testing.T) {
+	if _, err := (Literal{Value: "42"}).Int(); err == nil {
+		t.Error("Int() on plain literal expected error")
+	}
+	if _, err := (Literal{Value: "4.2", Datatype: xsd.Integer}).Int(); err == nil {
+		t.Error("Int() on invalid lexical form expected error")
+	}
+	if _, err := (Literal{Value: "1", Datatype: xsd.Integer}).Bool(); err == nil {
+		t.Error("Bool() on integer expected error")
+	}
+}
+
+func TestNewTypedLiteral(t *testing.T) {
+	lit, err := NewTypedLiteral("0042", xsd.Integer)
+	if err != nil {
+		t.Fatalf("NewTypedLiteral() error = %v", err)
+	}
+	if lit.Value != "42" {
+		t.Errorf("NewTypedLiteral() value = %q, want 42", lit.Value)
+	}
+
+	if _, err := NewTypedLiteral("maybe", xsd.Boolean); err == nil {
+		t.Error("NewTypedLiteral() expected error for invalid boolean")
+	}
+}
+
+func TestLiteralDatatypeIRI(t *testing.T) {
+	lit, err := NewDirLangLiteral("שלום", "he", "rtl")
+	if err != nil {
+		t.Fatalf("NewDirLangLiteral() error = %v", err)
+	}
+
+	tests := []struct {
+		literal  Literal
+		expected string
+	}{
+		{Literal{Value: "x"}, xsd.String},
+		{Literal{Value: "x", Language: "en"}, RDFLangString},
+		{lit, RDFDirLangString},
+		{NewIntegerLiteral(1), xsd.Integer},
+	}
+
+	for _, tt := range tests {
+		if got := tt.literal.DatatypeIRI(); got != tt.expected {
+			t.Errorf("DatatypeIRI(%+v) = %q, want %q", tt.literal, got, tt.expected)
+		}
+	}
+
+	if _, err := NewDirLangLiteral("x", "he", "sideways"); err == nil {
+		t.Error("NewDirLangLiteral() expected error for unknown direction")
+	}
+}
@@ -0,0 +1,269 @@
+package triple
+
+// Path is a SPARQL 1.1 property path. An IRI is the path of length one
+// along that predicate; the other paths combine paths.
+type Path interface {
+	isPath()
+}
+
+func (IRI) isPath() {}
+
+// SequencePath matches its paths one after another, p1/p2.
+type SequencePath struct {
+	Paths []Path
+}
+
+// AlternativePath matches any one of its paths, p1|p2.
+type AlternativePath struct {
+	Paths []Path
+}
+
+// InversePath matches Path from object to subject, ^p.
+type InversePath struct {
+	Path Path
+}
+
+// ZeroOrMorePath matches Path repeated any number of times, p*.
+type ZeroOrMorePath struct {
+	Path Path
+}
+
+// OneOrMorePath matches Path repeated at least once, p+.
+type OneOrMorePath struct {
+	Path Path
+}
+
+// ZeroOrOnePath matches Path or the empty path, p?.
+type ZeroOrOnePath struct {
+	Path Path
+}
+
+// NegatedPath matches one triple whose predicate is not in Forward, or,
+// followed from object to subject, not in Inverse: !(p1|^p2). With only
+// Inverse set it matches inverse triples alone.
+type NegatedPath struct {
+	Forward []IRI
+	Inverse []IRI
+}
+
+func (SequencePath) isPath()    {}
+func (AlternativePath) isPath() {}
+func (InversePath) isPath()     {}
+func (ZeroOrMorePath) isPath()  {}
+func (OneOrMorePath) isPath()   {}
+func (ZeroOrOnePath) isPath()   {}
+func (NegatedPath) isPath()     {}
+
+// NodePair is a subject and an object connected by a path.
+type NodePair struct {
+	Subject Node
+	Object  Node
+}
+
+// MatchPath returns the pairs of nodes connected by path in g. A nil subject
+// or object matches any node. Like SPARQL, the repetition paths *, + and ?
+// return each pair once and terminate on cyclic data, while sequences and
+// alternatives keep duplicates.
+func MatchPath(g Matcher, subject Node, path Path, object Node) []NodePair {
+	switch p := path.(type) {
+	case IRI:
+		var pairs []NodePair
+		for _, t := range g.Match(subject, p, object) {
+			pairs = append(pairs, NodePair{t.Subject, t.Object})
+		}
+		return pairs
+	case InversePath:
+		pairs := MatchPath(g, object, p.Path, subject)
+		for i, pair := range pairs {
+			pairs[i] = NodePair{pair.Object, pair.Subject}
+		}
+		return pairs
+	case SequencePath:
+		return matchSequence(g, subject, p.Paths, object)
+	case AlternativePath:
+		var pairs []NodePair
+		for _, alt := range p.Paths {
+			pairs = append(pairs, MatchPath(g, subject, alt, object)...)
+		}
+		return pairs
+	case ZeroOrOnePath:
+		pairs := zeroLength(g, subject, object)
+		pairs = append(pairs, MatchPath(g, subject, p.Path, object)...)
+		return distinctPairs(pairs)
+	case ZeroOrMorePath:
+		return matchRepeated(g, subject, p.Path, object, true)
+	case OneOrMorePath:
+		return matchRepeated(g, subject, p.Path, object, false)
+	case NegatedPath:
+		return matchNegated(g, subject, p, object)
+	}
+	return nil
+}
+
+// FollowPath returns the nodes reachable from subject along path, each once,
+// in the order they are found.
+func FollowPath(g Matcher, subject Node, path Path) []Node {
+	var nodes []Node
+	seen := make(map[Node]bool)
+	for _, pair := range MatchPath(g, subject, path, nil) {
+		if !seen[pair.Object] {
+			seen[pair.Object] = true
+			nodes = append(nodes, pair.Object)
+		}
+	}
+	return nodes
+}
+
+func matchSequence(g Matcher, subject Node, paths []Path, object Node) []NodePair {
+	switch len(paths) {
+	case 0:
+		return zeroLength(g, subject, object)
+	case 1:
+		return MatchPath(g, subject, paths[0], object)
+	}
+
+	// Walk from the bound end, if any.
+	var pairs []NodePair
+	if subject == nil && object != nil {
+		last := paths[len(paths)-1]
+		for _, right := range MatchPath(g, nil, last, object) {
+			for _, left := range matchSequence(g, nil, paths[:len(paths)-1], right.Subject) {
+				pairs = append(pairs, NodePair{left.Subject, right.Object})
+			}
+		}
+		return pairs
+	}
+
+	for _, left := range MatchPath(g, subject, paths[0], nil) {
+		for _, right := range matchSequence(g, left.Object, paths[1:], object) {
+			pairs = append(pairs, NodePair{left.Subject, right.Object})
+		}
+	}
+	return pairs
+}
+
+// matchRepeated evaluates p* (zero true) or p+ by a breadth-first walk from
+// each start node, never visiting a node twice.
+func matchRepeated(g Matcher, subject Node, path Path, object Node, zero bool) []NodePair {
+	// Walk backwards from a bound object when the subject is free.
+	if subject == nil && object != nil {
+		pairs := matchRepeated(g, object, InversePath{path}, nil, zero)
+		for i, pair := range pairs {
+			pairs[i] = NodePair{pair.Object, pair.Subject}
+		}
+		return pairs
+	}
+
+	starts := []Node{subject}
+	if subject == nil {
+		starts = graphNodes(g)
+	}
+
+	var pairs []NodePair
+	for _, start := range starts {
+		visited := make(map[Node]bool)
+		if zero {
+			visited[start] = true
+			if object == nil || object == start {
+				pairs = append(pairs, NodePair{start, start})
+			}
+		}
+
+		frontier := []Node{start}
+		for len(frontier) > 0 {
+			var next []Node
+			for _, n := range frontier {
+				for _, step := range MatchPath(g, n, path, nil) {
+					if visited[step.Object] {
+						continue
+					}
+					visited[step.Object] = true
+					if object == nil || object == step.Object {
+						pairs = append(pairs, NodePair{start, step.Object})
+					}
+					next = append(next, step.Object)
+				}
+			}
+			frontier = next
+		}
+	}
+	return pairs
+}
+
+func matchNegated(g Matcher, subject Node, p NegatedPath, object Node) []NodePair {
+	excluded := func(pred Node, set []IRI) bool {
+		for _, iri := range set {
+			if pred == iri {
+				return true
+			}
+		}
+		return false
+	}
+
+	var pairs []NodePair
+	if len(p.Forward) > 0 || len(p.Inverse) == 0 {
+		for _, t := range g.Match(subject, nil, object) {
+			if !excluded(t.Predicate, p.Forward) {
+				pairs = append(pairs, NodePair{t.Subject, t.Object})
+			}
+		}
+	}
+	if len(p.Inverse) > 0 {
+		for _, t := range g.Match(object, nil, subject) {
+			if !excluded(t.Predicate, p.Inverse) {
+				pairs = append(pairs, NodePair{t.Object, t.Subject})
+			}
+		}
+	}
+	return pairs
+}
+
+// zeroLength matches the empty path: every node with itself. A bound
+// subject or object matches itself even when it is not in the graph.
+func zeroLength(g Matcher, subject, object Node) []NodePair {
+	switch {
+	case subject != nil && object != nil:
+		if subject == object {
+			return []NodePair{{subject, object}}
+		}
+		return nil
+	case subject != nil:
+		return []NodePair{{subject, subject}}
+	case object != nil:
+		return []NodePair{{object, object}}
+	}
+
+	nodes := graphNodes(g)
+	pairs := make([]NodePair, len(nodes))
+	for i, n := range nodes {
+		pairs[i] = NodePair{n, n}
+	}
+	return pairs
+}
+
+// graphNodes returns every subject and object in g, each once.
+func graphNodes(g Matcher) []Node {
+	var nodes []Node
+	seen := make(map[Node]bool)
+	for _, t := range g.Match(nil, nil, nil) {
+		for _, n := range []Node{t.Subject, t.Object} {
+			if !seen[n] {
+				seen[n] = true
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	return nodes
+}
+
+func distinctPairs(pairs []NodePair) []NodePair {
+	seen := make(map[NodePair]bool, len(pairs))
+	result := pairs[:0]
+	for _, pair := range pairs {
+		if !seen[pair] {
+			seen[pair] = true
+			result = append(result, pair)
+		}
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package triple
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	iri := func(s string) IRI { return IRI{Value: "http://example.org/" + s} }
+	broader, related := iri("broader"), iri("related")
+
+	// a -> b -> c -> a is a cycle; d hangs off c.
+	g := NewGraph([]Triple{
+		{Subject: iri("a"), Predicate: broader, Object: iri("b")},
+		{Subject: iri("b"), Predicate: broader, Object: iri("c")},
+		{Subject: iri("c"), Predicate: broader, Object: iri("a")},
+		{Subject: iri("c"), Predicate: related, Object: iri("d")},
+	})
+
+	names := func(nodes []Node) []string {
+		var out []string
+		for _, n := range nodes {
+			out = append(out, n.(IRI).Value[len("http://example.org/"):])
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	tests := []struct {
+		name string
+		path Path
+		want []string
+	}{
+		{"predicate", broader, []string{"b"}},
+		{"one or more on a cycle", OneOrMorePath{broader}, []string{"a", "b", "c"}},
+		{"zero or more", ZeroOrMorePath{broader}, []string{"a", "b", "c"}},
+		{"zero or one", ZeroOrOnePath{broader}, []string{"a", "b"}},
+		{"sequence", SequencePath{[]Path{broader, broader, related}}, []string{"d"}},
+		{"alternative", AlternativePath{[]Path{broader, InversePath{broader}}}, []string{"b", "c"}},
+		{"inverse", InversePath{broader}, []string{"c"}},
+		{"repeated sequence", OneOrMorePath{SequencePath{[]Path{broader, broader}}}, []string{"a", "b", "c"}},
+		{"negated", SequencePath{[]Path{OneOrMorePath{broader}, NegatedPath{Forward: []IRI{broader}}}}, []string{"d"}},
+		{"negated inverse", NegatedPath{Inverse: []IRI{related}}, []string{"c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(FollowPath(g, iri("a"), tt.path)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FollowPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	pairs := MatchPath(g, nil, OneOrMorePath{broader}, iri("a"))
+	if len(pairs) != 3 {
+		t.Errorf("MatchPath(nil, broader+, a) = %v, want 3 pairs", pairs)
+	}
+	if pairs := MatchPath(g, nil, ZeroOrMorePath{related}, nil); len(pairs) != 5 {
+		t.Errorf("MatchPath(nil, related*, nil) = %d pairs, want 5", len(pairs))
+	}
+	if pairs := MatchPath(g, iri("x"), ZeroOrMorePath{broader}, nil); len(pairs) != 1 || pairs[0].Object != iri("x") {
+		t.Errorf("MatchPath(x, broader*, nil) = %v, want x itself", pairs)
+	}
+}
@@ -0,0 +1,94 @@
+package triple
+
+const (
+	rdfType      = RDFNamespace + "type"
+	rdfReifies   = RDFNamespace + "reifies"
+	rdfStatement = RDFNamespace + "Statement"
+	rdfSubject   = RDFNamespace + "subject"
+	rdfPredicate = RDFNamespace + "predicate"
+	rdfObject    = RDFNamespace + "object"
+)
+
+// StarToStatements rewrites each RDF 1.2 reification `r rdf:reifies <<( s p o )>>`
+// as classic reification: r a rdf:Statement; rdf:subject s; rdf:predicate p;
+// rdf:object o. Other triples, including annotations on r, are kept.
+func StarToStatements(triples []Triple) []Triple {
+	result := make([]Triple, 0, len(triples))
+	for _, t := range triples {
+		term, ok := t.Object.(TripleTerm)
+		if !ok || t.Predicate != (IRI{Value: rdfReifies}) {
+			result = append(result, t)
+			continue
+		}
+		result = append(result,
+			Triple{Subject: t.Subject, Predicate: IRI{Value: rdfType}, Object: IRI{Value: rdfStatement}},
+			Triple{Subject: t.Subject, Predicate: IRI{Value: rdfSubject}, Object: term.Triple.Subject},
+			Triple{Subject: t.Subject, Predicate: IRI{Value: rdfPredicate}, Object: term.Triple.Predicate},
+			Triple{Subject: t.Subject, Predicate: IRI{Value: rdfObject}, Object: term.Triple.Object},
+		)
+	}
+	return result
+}
+
+// StatementsToStar is the inverse of StarToStatements. A resource with exactly
+// one rdf:subject, rdf:predicate and rdf:object becomes `r rdf:reifies <<( s p o )>>`;
+// its rdf:Statement type is dropped. Incomplete or ambiguous descriptions are
+// left unchanged.
+func StatementsToStar(triples []Triple) []Triple {
+	type parts struct {
+		subject, predicate, object []Node
+	}
+	statements := make(map[Node]*parts)
+	for _, t := range triples {
+		p := statements[t.Subject]
+		if p == nil {
+			p = &parts{}
+			statements[t.Subject] = p
+		}
+		switch t.Predicate {
+		case IRI{Value: rdfSubject}:
+			p.subject = append(p.subject, t.Object)
+		case IRI{Value: rdfPredicate}:
+			p.predicate = append(p.predicate, t.Object)
+		case IRI{Value: rdfObject}:
+			p.object = append(p.object, t.Object)
+		}
+	}
+
+	terms := make(map[Node]TripleTerm)
+	for r, p := range statements {
+		if len(p.subject) != 1 || len(p.predicate) != 1 || len(p.object) != 1 {
+			continue
+		}
+		term := TripleTerm{Triple: Triple{Subject: p.subject[0], Predicate: p.predicate[0], Object: p.object[0]}}
+		if term.Triple.Validate() != nil {
+			continue
+		}
+		terms[r] = term
+	}
+
+	result := make([]Triple, 0, len(triples))
+	emitted := make(map[Node]bool)
+	for _, t := range triples {
+		term, ok := terms[t.Subject]
+		if !ok || !isStatementTriple(t) {
+			result = append(result, t)
+			continue
+		}
+		if !emitted[t.Subject] {
+			emitted[t.Subject] = true
+			result = append(result, Triple{Subject: t.Subject, Predicate: IRI{Value: rdfReifies}, Object: term})
+		}
+	}
+	return result
+}
+
+func isStatementTriple(t Triple) bool {
+	switch t.Predicate {
+	case IRI{Value: rdfSubject}, IRI{Value: rdfPredicate}, IRI{Value: rdfObject}:
+		return true
+	case IRI{Value: rdfType}:
+		return t.Object == IRI{Value: rdfStatement}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package triple
+
+import "testing"
+
+func TestStatementReification(t *testing.T) {
+	ex := func(local string) IRI { return IRI{Value: "http://example.org/" + local} }
+	r := BlankNode{Value: "r"}
+	quoted := Triple{Subject: ex("alice"), Predicate: ex("knows"), Object: ex("bob")}
+
+	star := []Triple{
+		{Subject: r, Predicate: IRI{Value: rdfReifies}, Object: TripleTerm{Triple: quoted}},
+		{Subject: r, Predicate: ex("source"), Object: ex("survey")},
+	}
+	statements := []Triple{
+		{Subject: r, Predicate: IRI{Value: rdfType}, Object: IRI{Value: rdfStatement}},
+		{Subject: r, Predicate: IRI{Value: rdfSubject}, Object: ex("alice")},
+		{Subject: r, Predicate: IRI{Value: rdfPredicate}, Object: ex("knows")},
+		{Subject: r, Predicate: IRI{Value: rdfObject}, Object: ex("bob")},
+		{Subject: r, Predicate: ex("source"), Object: ex("survey")},
+	}
+
+	assertTriples(t, "StarToStatements", StarToStatements(star), statements)
+	assertTriples(t, "StatementsToStar", StatementsToStar(statements), star)
+}
+
+func TestStatementsToStarIncomplete(t *testing.T) {
+	ex := func(local string) IRI { return IRI{Value: "http://example.org/" + local} }
+	r := IRI{Value: "http://example.org/r"}
+
+	tests := []struct {
+		name    string
+		triples []Triple
+	}{
+		{
+			name: "missing object",
+			triples: []Triple{
+				{Subject: r, Predicate: IRI{Value: rdfSubject}, Object: ex("alice")},
+				{Subject: r, Predicate: IRI{Value: rdfPredicate}, Object: ex("knows")},
+			},
+		},
+		{
+			name: "two subjects",
+			triples: []Triple{
+				{Subject: r, Predicate: IRI{Value: rdfSubject}, Object: ex("alice")},
+				{Subject: r, Predicate: IRI{Value: rdfSubject}, Object: ex("carol")},
+				{Subject: r, Predicate: IRI{Value: rdfPredicate}, Object: ex("knows")},
+				{Subject: r, Predicate: IRI{Value: rdfObject}, Object: ex("bob")},
+			},
+		},
+		{
+			name: "literal predicate",
+			triples: []Triple{
+				{Subject: r, Predicate: IRI{Value: rdfSubject}, Object: ex("alice")},
+				{Subject: r, Predicate: IRI{Value: rdfPredicate}, Object: Literal{Value: "knows"}},
+				{Subject: r, Predicate: IRI{Value: rdfObject}, Object: ex("bob")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertTriples(t, "StatementsToStar", StatementsToStar(tt.triples), tt.triples)
+		})
+	}
+}
+
+func assertTriples(t *testing.T, name string, got, want []Triple) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s() got %d triples, want %d: %+v", name, len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s() triple[%d] = %+v, want %+v", name, i, got[i], want[i])
+		}
+	}
+}
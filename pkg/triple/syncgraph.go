@@ -0,0 +1,298 @@
+package triple
+
+import "sync"
+
+// SyncGraph is a Graph that is safe for concurrent use. Reads share a lock
+// and writes take it alone, so readers never see half of a write. Callers
+// can subscribe to the changes each write makes.
+type SyncGraph struct {
+	mu sync.RWMutex
+	g  *Graph
+	// Each write that changes the graph takes the next ticket while it holds
+	// mu. Subscribers are told of the writes in ticket order: a write waits,
+	// without mu, until delivered reaches the ticket before its own.
+	tickets   uint64
+	turnMu    sync.Mutex
+	turn      *sync.Cond
+	delivered uint64
+
+	subsMu sync.Mutex
+	subs   map[int]func(Change)
+	nextID int
+}
+
+// Change is what one write did to a graph: the triples it added and those it
+// removed, each in the order the write made the changes.
+type Change struct {
+	Added   []Triple
+	Removed []Triple
+}
+
+func NewSyncGraph(triples []Triple) *SyncGraph {
+	g := &SyncGraph{g: NewGraph(triples), subs: make(map[int]func(Change))}
+	g.turn = sync.NewCond(&g.turnMu)
+	return g
+}
+
+// Subscribe calls fn with the Change of every later write that changes the
+// graph, one write at a time, in the order they happened. fn runs after the
+// write's lock is released, so it can read the graph, which may by then
+// hold later writes too; it must not write to the graph. The returned
+// function cancels the subscription.
+func (g *SyncGraph) Subscribe(fn func(Change)) (cancel func()) {
+	g.subsMu.Lock()
+	defer g.subsMu.Unlock()
+	id := g.nextID
+	g.nextID++
+	g.subs[id] = fn
+	return func() {
+		g.subsMu.Lock()
+		defer g.subsMu.Unlock()
+		delete(g.subs, id)
+	}
+}
+
+// Update calls fn with the write lock held and a Batch through which it
+// reads and changes the graph, then tells subscribers what changed. If fn
+// returns an error or panics, Update undoes its changes and returns the
+// error or panics again. Other readers and writers wait until fn returns.
+func (g *SyncGraph) Update(fn func(b *Batch) error) error {
+	change, ticket, err := g.write(fn)
+	if err != nil || ticket == 0 {
+		return err
+	}
+
+	g.turnMu.Lock()
+	for g.delivered != ticket-1 {
+		g.turn.Wait()
+	}
+	g.turnMu.Unlock()
+	// The next write's turn comes even if a subscriber panics.
+	defer func() {
+		g.turnMu.Lock()
+		g.delivered = ticket
+		g.turnMu.Unlock()
+		g.turn.Broadcast()
+	}()
+
+	g.subsMu.Lock()
+	subs := make([]func(Change), 0, len(g.subs))
+	for _, fn := range g.subs {
+		subs = append(subs, fn)
+	}
+	g.subsMu.Unlock()
+	for _, fn := range subs {
+		fn(change)
+	}
+	return nil
+}
+
+// write runs fn under the write lock and returns what it changed with the
+// ticket of the write, or 0 if it changed nothing.
+func (g *SyncGraph) write(fn func(b *Batch) error) (change Change, ticket uint64, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b := &Batch{g: g.g}
+	defer func() {
+		if r := recover(); r != nil {
+			b.undo()
+			panic(r)
+		}
+	}()
+	if err := fn(b); err != nil {
+		b.undo()
+		return Change{}, 0, err
+	}
+
+	change = b.change()
+	if len(change.Added) == 0 && len(change.Removed) == 0 {
+		return Change{}, 0, nil
+	}
+	g.tickets++
+	return change, g.tickets, nil
+}
+
+// Add inserts t and reports whether it was not already in the graph.
+func (g *SyncGraph) Add(t Triple) bool {
+	var added bool
+	g.Update(func(b *Batch) error {
+		added = b.Add(t)
+		return nil
+	})
+	return added
+}
+
+// Remove deletes t and reports whether it was in the graph.
+func (g *SyncGraph) Remove(t Triple) bool {
+	var removed bool
+	g.Update(func(b *Batch) error {
+		removed = b.Remove(t)
+		return nil
+	})
+	return removed
+}
+
+// AddAll inserts triples in one write and returns how many were not already
+// in the graph.
+func (g *SyncGraph) AddAll(triples []Triple) int {
+	n := 0
+	g.Update(func(b *Batch) error {
+		for _, t := range triples {
+			if b.Add(t) {
+				n++
+			}
+		}
+		return nil
+	})
+	return n
+}
+
+// RemoveAll deletes triples in one write and returns how many were in the
+// graph.
+func (g *SyncGraph) RemoveAll(triples []Triple) int {
+	n := 0
+	g.Update(func(b *Batch) error {
+		for _, t := range triples {
+			if b.Remove(t) {
+				n++
+			}
+		}
+		return nil
+	})
+	return n
+}
+
+func (g *SyncGraph) Has(t Triple) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Has(t)
+}
+
+func (g *SyncGraph) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Len()
+}
+
+// Triples returns a copy of the triples in insertion order.
+func (g *SyncGraph) Triples() []Triple {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Triples()
+}
+
+func (g *SyncGraph) Match(subject, predicate, object Node) []Triple {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Match(subject, predicate, object)
+}
+
+// MatchPage returns one page of the matches of a pattern, as
+// Graph.MatchPage does.
+func (g *SyncGraph) MatchPage(subject, predicate, object Node, offset, limit int) []Triple {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.MatchPage(subject, predicate, object, offset, limit)
+}
+
+// Count returns the number of triples matching a pattern.
+func (g *SyncGraph) Count(subject, predicate, object Node) int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Count(subject, predicate, object)
+}
+
+// Batch is the graph as Update's function sees it: reads include the
+// function's own changes, which the Batch records. It is only valid until
+// the function returns.
+type Batch struct {
+	g *Graph
+	// log lists the changes in the order they were made, so that undo can
+	// revert them in reverse.
+	log []batchChange
+}
+
+type batchChange struct {
+	t     Triple
+	added bool
+}
+
+// Add inserts t and reports whether it was not already in the graph.
+func (b *Batch) Add(t Triple) bool {
+	if !b.g.Add(t) {
+		return false
+	}
+	b.log = append(b.log, batchChange{t, true})
+	return true
+}
+
+// Remove deletes t and reports whether it was in the graph.
+func (b *Batch) Remove(t Triple) bool {
+	if !b.g.Remove(t) {
+		return false
+	}
+	b.log = append(b.log, batchChange{t, false})
+	return true
+}
+
+// change returns the net effect of the batch. Changes to a triple alternate
+// between adding and removing it, so a triple changed an even number of
+// times is as it was and is left out. The others are listed in the order
+// they were first changed.
+func (b *Batch) change() Change {
+	first := make(map[Triple]bool)
+	changes := make(map[Triple]int)
+	var order []Triple
+	for _, c := range b.log {
+		if _, ok := first[c.t]; !ok {
+			first[c.t] = c.added
+			order = append(order, c.t)
+		}
+		changes[c.t]++
+	}
+
+	var c Change
+	for _, t := range order {
+		switch {
+		case changes[t]%2 == 0:
+		case first[t]:
+			c.Added = append(c.Added, t)
+		default:
+			c.Removed = append(c.Removed, t)
+		}
+	}
+	return c
+}
+
+func (b *Batch) undo() {
+	for i := len(b.log) - 1; i >= 0; i-- {
+		if c := b.log[i]; c.added {
+			b.g.Remove(c.t)
+		} else {
+			b.g.Add(c.t)
+		}
+	}
+	b.log = nil
+}
+
+func (b *Batch) Has(t Triple) bool { return b.g.Has(t) }
+func (b *Batch) Len() int          { return b.g.Len() }
+
+// Triples returns the triples in insertion order.
+func (b *Batch) Triples() []Triple { return b.g.Triples() }
+
+func (b *Batch) Match(subject, predicate, object Node) []Triple {
+	return b.g.Match(subject, predicate, object)
+}
+
+// MatchPage returns one page of the matches of a pattern, as
+// Graph.MatchPage does.
+func (b *Batch) MatchPage(subject, predicate, object Node, offset, limit int) []Triple {
+	return b.g.MatchPage(subject, predicate, object, offset, limit)
+}
+
+// Count returns the number of triples matching a pattern.
+func (b *Batch) Count(subject, predicate, object Node) int {
+	return b.g.Count(subject, predicate, object)
+}
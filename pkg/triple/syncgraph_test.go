@@ -0,0 +1,195 @@
+package triple
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncGraphSubscribe(t *testing.T) {
+	iri := func(s string) IRI { return IRI{Value: "http://example.org/" + s} }
+	a := Triple{Subject: iri("a"), Predicate: iri("p"), Object: iri("x")}
+	b := Triple{Subject: iri("b"), Predicate: iri("p"), Object: iri("x")}
+	c := Triple{Subject: iri("c"), Predicate: iri("p"), Object: iri("x")}
+	g := NewSyncGraph([]Triple{a})
+
+	var changes []Change
+	cancel := g.Subscribe(func(c Change) { changes = append(changes, c) })
+
+	g.Add(b)
+	g.Add(b) // no change, no call
+	g.Update(func(batch *Batch) error {
+		batch.Remove(a)
+		batch.Add(c)
+		batch.Remove(b)
+		batch.Add(b) // undoes the removal
+		if batch.Len() != 2 || batch.Has(a) {
+			t.Errorf("batch does not see its own changes: %v", batch.Triples())
+		}
+		return nil
+	})
+	err := g.Update(func(batch *Batch) error {
+		batch.Add(a)
+		batch.Remove(c)
+		return errors.New("failed")
+	})
+	if err == nil || g.Has(a) || !g.Has(c) {
+		t.Errorf("failed Update: %v, %v", err, g.Triples())
+	}
+	cancel()
+	g.Remove(c)
+
+	want := []Change{{Added: []Triple{b}}, {Added: []Triple{c}, Removed: []Triple{a}}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+}
+
+// TestSyncGraphConcurrent is meant for go test -race: writers add and remove
+// pairs of triples in single writes while readers check that they never see
+// half a pair, and a subscriber mirrors the graph from its changes.
+func TestSyncGraphConcurrent(t *testing.T) {
+	g := NewSyncGraph(nil)
+	mirror := make(map[Triple]bool)
+	g.Subscribe(func(c Change) {
+		for _, t := range c.Added {
+			mirror[t] = true
+		}
+		for _, t := range c.Removed {
+			delete(mirror, t)
+		}
+	})
+
+	pair := func(w, i int) []Triple {
+		s := BlankNode{Value: "w" + string(rune('a'+w))}
+		o := NewIntegerLiteral(int64(i))
+		return []Triple{
+			{Subject: s, Predicate: IRI{Value: "http://example.org/left"}, Object: o},
+			{Subject: s, Predicate: IRI{Value: "http://example.org/right"}, Object: o},
+		}
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				g.AddAll(pair(w, i))
+				if i%3 == 0 {
+					g.RemoveAll(pair(w, i))
+				}
+			}
+		}()
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				left := g.Count(nil, IRI{Value: "http://example.org/left"}, nil)
+				if n := g.Len(); n%2 != 0 {
+					t.Errorf("Len = %d, an odd number", n)
+					return
+				}
+				if len(g.Triples())%2 != 0 || left > g.Len() {
+					t.Error("saw half a write")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if g.Len() != 4*2*133 {
+		t.Errorf("Len = %d, want %d", g.Len(), 4*2*133)
+	}
+	// Every write has been delivered once Update returns.
+	if len(mirror) != g.Len() {
+		t.Errorf("mirror has %d triples, graph %d", len(mirror), g.Len())
+	}
+	for _, tr := range g.Triples() {
+		if !mirror[tr] {
+			t.Errorf("mirror lacks %v", tr)
+		}
+	}
+}
+
+// TestSyncGraphSubscriberReads has a subscriber read the graph while other
+// goroutines write to it, which must not deadlock.
+func TestSyncGraphSubscriberReads(t *testing.T) {
+	g := NewSyncGraph(nil)
+	p := IRI{Value: "http://example.org/p"}
+	calls := 0
+	g.Subscribe(func(c Change) {
+		calls++
+		if g.Len() < len(c.Added) || len(g.Match(nil, p, nil)) > g.Len() {
+			t.Error("subscriber saw too few triples")
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s := BlankNode{Value: "w" + string(rune('a'+w))}
+				for i := 0; i < 100; i++ {
+					g.Add(Triple{Subject: s, Predicate: p, Object: NewIntegerLiteral(int64(i))})
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("writers deadlocked with a reading subscriber")
+	}
+	if calls != 400 || g.Len() != 400 {
+		t.Errorf("calls = %d, Len = %d, want 400", calls, g.Len())
+	}
+}
+
+// TestSyncGraphPanic checks that a panic in an Update or in a subscriber
+// undoes the write or passes on the turn, and leaves the graph usable.
+func TestSyncGraphPanic(t *testing.T) {
+	iri := func(s string) IRI { return IRI{Value: "http://example.org/" + s} }
+	a := Triple{Subject: iri("a"), Predicate: iri("p"), Object: iri("x")}
+	b := Triple{Subject: iri("b"), Predicate: iri("p"), Object: iri("x")}
+	g := NewSyncGraph([]Triple{a})
+
+	mustPanic := func(f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Error("did not panic")
+			}
+		}()
+		f()
+	}
+	mustPanic(func() {
+		g.Update(func(batch *Batch) error {
+			batch.Remove(a)
+			batch.Add(b)
+			batch.Add(a)
+			batch.Remove(a)
+			panic("failed")
+		})
+	})
+	if got := g.Triples(); len(got) != 1 || got[0] != a {
+		t.Errorf("after panic, Triples = %v", got)
+	}
+
+	cancel := g.Subscribe(func(Change) { panic("subscriber") })
+	mustPanic(func() { g.Add(b) })
+	cancel()
+	if !g.Remove(b) || g.Len() != 1 {
+		t.Errorf("write after subscriber panic: %v", g.Triples())
+	}
+}
@@ -14,6 +14,9 @@ type Literal struct {
 	Value    string
 	Language string
 	Datatype string
+	// Direction is the RDF 1.2 base direction of a language-tagged
+	// string: "ltr", "rtl" or empty.
+	Direction string
 }
 
 func (Literal) isNode() {}
@@ -24,6 +27,14 @@ type BlankNode struct {
 
 func (BlankNode) isNode() {}
 
+// TripleTerm is an RDF 1.2 triple term: a triple used as the object of
+// another triple, written <<( s p o )>>.
+type TripleTerm struct {
+	Triple Triple
+}
+
+func (TripleTerm) isNode() {}
+
 type Triple struct {
 	Subject   Node
 	Predicate Node
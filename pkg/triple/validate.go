@@ -0,0 +1,70 @@
+package triple
+
+import "fmt"
+
+// Validate checks that each term is allowed in its position and is well
+// formed: IRIs are absolute RFC 3987 IRIs, language tags are BCP 47, a
+// literal does not carry both a language and a datatype, and triple terms
+// appear only as objects. Lexical forms of typed literals are checked
+// separately by Literal.Validate.
+func (t Triple) Validate() error {
+	switch t.Subject.(type) {
+	case IRI, BlankNode:
+	default:
+		return fmt.Errorf("subject must be an IRI or blank node")
+	}
+
+	if _, ok := t.Predicate.(IRI); !ok {
+		return fmt.Errorf("predicate must be an IRI")
+	}
+
+	if t.Object == nil {
+		return fmt.Errorf("object is missing")
+	}
+
+	for _, n := range []Node{t.Subject, t.Predicate, t.Object} {
+		if err := validateTerm(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateTerm(n Node) error {
+	switch term := n.(type) {
+	case IRI:
+		return term.Validate()
+	case TripleTerm:
+		if err := term.Triple.Validate(); err != nil {
+			return fmt.Errorf("triple term: %w", err)
+		}
+	case BlankNode:
+		if term.Value == "" {
+			return fmt.Errorf("empty blank node label")
+		}
+	case Literal:
+		if term.Language != "" && term.Datatype != "" {
+			return fmt.Errorf("literal %q has both a language and a datatype", term.Value)
+		}
+		if term.Direction != "" {
+			if term.Direction != "ltr" && term.Direction != "rtl" {
+				return fmt.Errorf("literal %q has invalid direction %q", term.Value, term.Direction)
+			}
+			if term.Language == "" {
+				return fmt.Errorf("literal %q has a direction but no language", term.Value)
+			}
+		}
+		if term.Datatype == RDFLangString || term.Datatype == RDFDirLangString {
+			return fmt.Errorf("literal %q must use a language tag instead of datatype %s", term.Value, term.Datatype)
+		}
+		if term.Language != "" {
+			return ValidateLanguageTag(term.Language)
+		}
+		if term.Datatype != "" {
+			if err := ValidateIRI(term.Datatype); err != nil {
+				return fmt.Errorf("datatype: %w", err)
+			}
+		}
+	}
+	return nil
+}
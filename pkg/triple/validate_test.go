@@ -0,0 +1,128 @@
+package triple
+
+import "testing"
+
+func TestValidateIRI(t *testing.T) {
+	tests := []struct {
+		iri     string
+		wantErr bool
+	}{
+		{"http://example.org/note1", false},
+		{"https://user:pw@example.org:8080/a/b?q=1&r=2#frag", false},
+		{"http://[2001:db8::1]/x", false},
+		{"urn:isbn:0451450523", false},
+		{"mailto:alice@example.org", false},
+		{"http://example.org/caf%C3%A9", false},
+		{"http://例え.jp/ページ", false},
+		{"tag:example.org,2024:notes/1", false},
+		{"", true},
+		{"not an iri", true},
+		{"note1", true},
+		{"/relative/path", true},
+		{"1http://example.org/", true},
+		{"http://example.org/a b", true},
+		{"http://example.org/<x>", true},
+		{"http://example.org/%zz", true},
+		{"http://example.org:80a/", true},
+		{"http://example.org/a#b#c", true},
+		{"http://example.org/\u0007", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.iri, func(t *testing.T) {
+			err := ValidateIRI(tt.iri)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateIRI(%q) error = %v, wantErr %v", tt.iri, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLanguageTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		wantErr bool
+	}{
+		{"en", false},
+		{"en-US", false},
+		{"zh-Hant-TW", false},
+		{"sr-Latn-RS", false},
+		{"es-419", false},
+		{"de-CH-1901", false},
+		{"zh-yue-HK", false},
+		{"en-a-bbb-x-private", false},
+		{"x-whatever", false},
+		{"i-klingon", false},
+		{"", true},
+		{"123", true},
+		{"123 bad", true},
+		{"e", true},
+		{"en_US", true},
+		{"en-", true},
+		{"en-a", true},
+		{"en-x", true},
+		{"toolongtag", true},
+		{"en-US-a-", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			err := ValidateLanguageTag(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLanguageTag(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTripleValidate(t *testing.T) {
+	subject := IRI{Value: "http://example.org/note1"}
+	predicate := IRI{Value: "http://example.org/title"}
+
+	tests := []struct {
+		name    string
+		triple  Triple
+		wantErr bool
+	}{
+		{"plain literal", Triple{subject, predicate, Literal{Value: "Test"}}, false},
+		{"language literal", Triple{subject, predicate, Literal{Value: "Test", Language: "en-GB"}}, false},
+		{"blank subject", Triple{BlankNode{Value: "b0"}, predicate, IRI{Value: "http://example.org/x"}}, false},
+		{"literal subject", Triple{Literal{Value: "x"}, predicate, Literal{Value: "Test"}}, true},
+		{"blank predicate", Triple{subject, BlankNode{Value: "p"}, Literal{Value: "Test"}}, true},
+		{"invalid object IRI", Triple{subject, predicate, IRI{Value: "not an iri"}}, true},
+		{"language and datatype", Triple{subject, predicate, Literal{Value: "1", Language: "en", Datatype: "http://www.w3.org/2001/XMLSchema#integer"}}, true},
+		{"relative datatype", Triple{subject, predicate, Literal{Value: "1", Datatype: "integer"}}, true},
+		{"bad language", Triple{subject, predicate, Literal{Value: "x", Language: "en_US"}}, true},
+		{"directional literal", Triple{subject, predicate, Literal{Value: "x", Language: "ar", Direction: "rtl"}}, false},
+		{"direction without language", Triple{subject, predicate, Literal{Value: "x", Direction: "rtl"}}, true},
+		{"unknown direction", Triple{subject, predicate, Literal{Value: "x", Language: "ar", Direction: "up"}}, true},
+		{"explicit langString datatype", Triple{subject, predicate, Literal{Value: "x", Datatype: RDFLangString}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.triple.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewIRIAndLangLiteral(t *testing.T) {
+	if _, err := NewIRI("http://example.org/a b"); err == nil {
+		t.Error("NewIRI() expected error for IRI with a space")
+	}
+	iri, err := NewIRI("http://example.org/a")
+	if err != nil || iri.Value != "http://example.org/a" {
+		t.Errorf("NewIRI() = %v, %v", iri, err)
+	}
+
+	if _, err := NewLangLiteral("hi", "123 bad"); err == nil {
+		t.Error("NewLangLiteral() expected error for malformed tag")
+	}
+	lit, err := NewLangLiteral("hi", "en")
+	if err != nil || lit.Language != "en" {
+		t.Errorf("NewLangLiteral() = %v, %v", lit, err)
+	}
+}
@@ -0,0 +1,189 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package dcterms holds the IRIs of the http://purl.org/dc/terms/ vocabulary.
+package dcterms
+
+import "github.com/DeDude/tripl/pkg/triple"
+
+const Namespace = "http://purl.org/dc/terms/"
+
+var (
+	// Agent is the IRI of dcterms:Agent.
+	Agent = triple.IRI{Value: Namespace + "Agent"}
+	// AgentClass is the IRI of dcterms:AgentClass.
+	AgentClass = triple.IRI{Value: Namespace + "AgentClass"}
+	// BibliographicResource is the IRI of dcterms:BibliographicResource.
+	BibliographicResource = triple.IRI{Value: Namespace + "BibliographicResource"}
+	// Box is the IRI of dcterms:Box.
+	Box = triple.IRI{Value: Namespace + "Box"}
+	// FileFormat is the IRI of dcterms:FileFormat.
+	FileFormat = triple.IRI{Value: Namespace + "FileFormat"}
+	// Frequency is the IRI of dcterms:Frequency.
+	Frequency = triple.IRI{Value: Namespace + "Frequency"}
+	// ISO3166 is the IRI of dcterms:ISO3166.
+	ISO3166 = triple.IRI{Value: Namespace + "ISO3166"}
+	// ISO6392 is the IRI of dcterms:ISO639-2.
+	ISO6392 = triple.IRI{Value: Namespace + "ISO639-2"}
+	// ISO6393 is the IRI of dcterms:ISO639-3.
+	ISO6393 = triple.IRI{Value: Namespace + "ISO639-3"}
+	// Jurisdiction is the IRI of dcterms:Jurisdiction.
+	Jurisdiction = triple.IRI{Value: Namespace + "Jurisdiction"}
+	// LicenseDocument is the IRI of dcterms:LicenseDocument.
+	LicenseDocument = triple.IRI{Value: Namespace + "LicenseDocument"}
+	// LinguisticSystem is the IRI of dcterms:LinguisticSystem.
+	LinguisticSystem = triple.IRI{Value: Namespace + "LinguisticSystem"}
+	// Location is the IRI of dcterms:Location.
+	Location = triple.IRI{Value: Namespace + "Location"}
+	// LocationPeriodOrJurisdiction is the IRI of dcterms:LocationPeriodOrJurisdiction.
+	LocationPeriodOrJurisdiction = triple.IRI{Value: Namespace + "LocationPeriodOrJurisdiction"}
+	// MediaType is the IRI of dcterms:MediaType.
+	MediaType = triple.IRI{Value: Namespace + "MediaType"}
+	// MediaTypeOrExtent is the IRI of dcterms:MediaTypeOrExtent.
+	MediaTypeOrExtent = triple.IRI{Value: Namespace + "MediaTypeOrExtent"}
+	// MethodOfAccrual is the IRI of dcterms:MethodOfAccrual.
+	MethodOfAccrual = triple.IRI{Value: Namespace + "MethodOfAccrual"}
+	// MethodOfInstruction is the IRI of dcterms:MethodOfInstruction.
+	MethodOfInstruction = triple.IRI{Value: Namespace + "MethodOfInstruction"}
+	// Period is the IRI of dcterms:Period.
+	Period = triple.IRI{Value: Namespace + "Period"}
+	// PeriodOfTime is the IRI of dcterms:PeriodOfTime.
+	PeriodOfTime = triple.IRI{Value: Namespace + "PeriodOfTime"}
+	// PhysicalMedium is the IRI of dcterms:PhysicalMedium.
+	PhysicalMedium = triple.IRI{Value: Namespace + "PhysicalMedium"}
+	// PhysicalResource is the IRI of dcterms:PhysicalResource.
+	PhysicalResource = triple.IRI{Value: Namespace + "PhysicalResource"}
+	// Point is the IRI of dcterms:Point.
+	Point = triple.IRI{Value: Namespace + "Point"}
+	// Policy is the IRI of dcterms:Policy.
+	Policy = triple.IRI{Value: Namespace + "Policy"}
+	// ProvenanceStatement is the IRI of dcterms:ProvenanceStatement.
+	ProvenanceStatement = triple.IRI{Value: Namespace + "ProvenanceStatement"}
+	// RFC1766 is the IRI of dcterms:RFC1766.
+	RFC1766 = triple.IRI{Value: Namespace + "RFC1766"}
+	// RFC3066 is the IRI of dcterms:RFC3066.
+	RFC3066 = triple.IRI{Value: Namespace + "RFC3066"}
+	// RFC4646 is the IRI of dcterms:RFC4646.
+	RFC4646 = triple.IRI{Value: Namespace + "RFC4646"}
+	// RFC5646 is the IRI of dcterms:RFC5646.
+	RFC5646 = triple.IRI{Value: Namespace + "RFC5646"}
+	// RightsStatement is the IRI of dcterms:RightsStatement.
+	RightsStatement = triple.IRI{Value: Namespace + "RightsStatement"}
+	// SizeOrDuration is the IRI of dcterms:SizeOrDuration.
+	SizeOrDuration = triple.IRI{Value: Namespace + "SizeOrDuration"}
+	// Standard is the IRI of dcterms:Standard.
+	Standard = triple.IRI{Value: Namespace + "Standard"}
+	// URI is the IRI of dcterms:URI.
+	URI = triple.IRI{Value: Namespace + "URI"}
+	// W3CDTF is the IRI of dcterms:W3CDTF.
+	W3CDTF = triple.IRI{Value: Namespace + "W3CDTF"}
+	// Abstract is the IRI of dcterms:abstract.
+	Abstract = triple.IRI{Value: Namespace + "abstract"}
+	// AccessRights is the IRI of dcterms:accessRights.
+	AccessRights = triple.IRI{Value: Namespace + "accessRights"}
+	// AccrualMethod is the IRI of dcterms:accrualMethod.
+	AccrualMethod = triple.IRI{Value: Namespace + "accrualMethod"}
+	// AccrualPeriodicity is the IRI of dcterms:accrualPeriodicity.
+	AccrualPeriodicity = triple.IRI{Value: Namespace + "accrualPeriodicity"}
+	// AccrualPolicy is the IRI of dcterms:accrualPolicy.
+	AccrualPolicy = triple.IRI{Value: Namespace + "accrualPolicy"}
+	// Alternative is the IRI of dcterms:alternative.
+	Alternative = triple.IRI{Value: Namespace + "alternative"}
+	// Audience is the IRI of dcterms:audience.
+	Audience = triple.IRI{Value: Namespace + "audience"}
+	// Available is the IRI of dcterms:available.
+	Available = triple.IRI{Value: Namespace + "available"}
+	// BibliographicCitation is the IRI of dcterms:bibliographicCitation.
+	BibliographicCitation = triple.IRI{Value: Namespace + "bibliographicCitation"}
+	// ConformsTo is the IRI of dcterms:conformsTo.
+	ConformsTo = triple.IRI{Value: Namespace + "conformsTo"}
+	// Contributor is the IRI of dcterms:contributor.
+	Contributor = triple.IRI{Value: Namespace + "contributor"}
+	// Coverage is the IRI of dcterms:coverage.
+	Coverage = triple.IRI{Value: Namespace + "coverage"}
+	// Created is the IRI of dcterms:created.
+	Created = triple.IRI{Value: Namespace + "created"}
+	// Creator is the IRI of dcterms:creator.
+	Creator = triple.IRI{Value: Namespace + "creator"}
+	// Date is the IRI of dcterms:date.
+	Date = triple.IRI{Value: Namespace + "date"}
+	// DateAccepted is the IRI of dcterms:dateAccepted.
+	DateAccepted = triple.IRI{Value: Namespace + "dateAccepted"}
+	// DateCopyrighted is the IRI of dcterms:dateCopyrighted.
+	DateCopyrighted = triple.IRI{Value: Namespace + "dateCopyrighted"}
+	// DateSubmitted is the IRI of dcterms:dateSubmitted.
+	DateSubmitted = triple.IRI{Value: Namespace + "dateSubmitted"}
+	// Description is the IRI of dcterms:description.
+	Description = triple.IRI{Value: Namespace + "description"}
+	// EducationLevel is the IRI of dcterms:educationLevel.
+	EducationLevel = triple.IRI{Value: Namespace + "educationLevel"}
+	// Extent is the IRI of dcterms:extent.
+	Extent = triple.IRI{Value: Namespace + "extent"}
+	// Format is the IRI of dcterms:format.
+	Format = triple.IRI{Value: Namespace + "format"}
+	// HasFormat is the IRI of dcterms:hasFormat.
+	HasFormat = triple.IRI{Value: Namespace + "hasFormat"}
+	// HasPart is the IRI of dcterms:hasPart.
+	HasPart = triple.IRI{Value: Namespace + "hasPart"}
+	// HasVersion is the IRI of dcterms:hasVersion.
+	HasVersion = triple.IRI{Value: Namespace + "hasVersion"}
+	// Identifier is the IRI of dcterms:identifier.
+	Identifier = triple.IRI{Value: Namespace + "identifier"}
+	// InstructionalMethod is the IRI of dcterms:instructionalMethod.
+	InstructionalMethod = triple.IRI{Value: Namespace + "instructionalMethod"}
+	// IsFormatOf is the IRI of dcterms:isFormatOf.
+	IsFormatOf = triple.IRI{Value: Namespace + "isFormatOf"}
+	// IsPartOf is the IRI of dcterms:isPartOf.
+	IsPartOf = triple.IRI{Value: Namespace + "isPartOf"}
+	// IsReferencedBy is the IRI of dcterms:isReferencedBy.
+	IsReferencedBy = triple.IRI{Value: Namespace + "isReferencedBy"}
+	// IsReplacedBy is the IRI of dcterms:isReplacedBy.
+	IsReplacedBy = triple.IRI{Value: Namespace + "isReplacedBy"}
+	// IsRequiredBy is the IRI of dcterms:isRequiredBy.
+	IsRequiredBy = triple.IRI{Value: Namespace + "isRequiredBy"}
+	// IsVersionOf is the IRI of dcterms:isVersionOf.
+	IsVersionOf = triple.IRI{Value: Namespace + "isVersionOf"}
+	// Issued is the IRI of dcterms:issued.
+	Issued = triple.IRI{Value: Namespace + "issued"}
+	// Language is the IRI of dcterms:language.
+	Language = triple.IRI{Value: Namespace + "language"}
+	// License is the IRI of dcterms:license.
+	License = triple.IRI{Value: Namespace + "license"}
+	// Mediator is the IRI of dcterms:mediator.
+	Mediator = triple.IRI{Value: Namespace + "mediator"}
+	// Medium is the IRI of dcterms:medium.
+	Medium = triple.IRI{Value: Namespace + "medium"}
+	// Modified is the IRI of dcterms:modified.
+	Modified = triple.IRI{Value: Namespace + "modified"}
+	// Provenance is the IRI of dcterms:provenance.
+	Provenance = triple.IRI{Value: Namespace + "provenance"}
+	// Publisher is the IRI of dcterms:publisher.
+	Publisher = triple.IRI{Value: Namespace + "publisher"}
+	// References is the IRI of dcterms:references.
+	References = triple.IRI{Value: Namespace + "references"}
+	// Relation is the IRI of dcterms:relation.
+	Relation = triple.IRI{Value: Namespace + "relation"}
+	// Replaces is the IRI of dcterms:replaces.
+	Replaces = triple.IRI{Value: Namespace + "replaces"}
+	// Requires is the IRI of dcterms:requires.
+	Requires = triple.IRI{Value: Namespace + "requires"}
+	// Rights is the IRI of dcterms:rights.
+	Rights = triple.IRI{Value: Namespace + "rights"}
+	// RightsHolder is the IRI of dcterms:rightsHolder.
+	RightsHolder = triple.IRI{Value: Namespace + "rightsHolder"}
+	// Source is the IRI of dcterms:source.
+	Source = triple.IRI{Value: Namespace + "source"}
+	// Spatial is the IRI of dcterms:spatial.
+	Spatial = triple.IRI{Value: Namespace + "spatial"}
+	// Subject is the IRI of dcterms:subject.
+	Subject = triple.IRI{Value: Namespace + "subject"}
+	// TableOfContents is the IRI of dcterms:tableOfContents.
+	TableOfContents = triple.IRI{Value: Namespace + "tableOfContents"}
+	// Temporal is the IRI of dcterms:temporal.
+	Temporal = triple.IRI{Value: Namespace + "temporal"}
+	// Title is the IRI of dcterms:title.
+	Title = triple.IRI{Value: Namespace + "title"}
+	// Type is the IRI of dcterms:type.
+	Type = triple.IRI{Value: Namespace + "type"}
+	// Valid is the IRI of dcterms:valid.
+	Valid = triple.IRI{Value: Namespace + "valid"}
+)
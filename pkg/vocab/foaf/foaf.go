@@ -0,0 +1,161 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package foaf holds the IRIs of the http://xmlns.com/foaf/0.1/ vocabulary.
+package foaf
+
+import "github.com/DeDude/tripl/pkg/triple"
+
+const Namespace = "http://xmlns.com/foaf/0.1/"
+
+var (
+	// Agent is the IRI of foaf:Agent.
+	Agent = triple.IRI{Value: Namespace + "Agent"}
+	// Document is the IRI of foaf:Document.
+	Document = triple.IRI{Value: Namespace + "Document"}
+	// Group is the IRI of foaf:Group.
+	Group = triple.IRI{Value: Namespace + "Group"}
+	// Image is the IRI of foaf:Image.
+	Image = triple.IRI{Value: Namespace + "Image"}
+	// LabelProperty is the IRI of foaf:LabelProperty.
+	LabelProperty = triple.IRI{Value: Namespace + "LabelProperty"}
+	// OnlineAccount is the IRI of foaf:OnlineAccount.
+	OnlineAccount = triple.IRI{Value: Namespace + "OnlineAccount"}
+	// OnlineChatAccount is the IRI of foaf:OnlineChatAccount.
+	OnlineChatAccount = triple.IRI{Value: Namespace + "OnlineChatAccount"}
+	// OnlineEcommerceAccount is the IRI of foaf:OnlineEcommerceAccount.
+	OnlineEcommerceAccount = triple.IRI{Value: Namespace + "OnlineEcommerceAccount"}
+	// OnlineGamingAccount is the IRI of foaf:OnlineGamingAccount.
+	OnlineGamingAccount = triple.IRI{Value: Namespace + "OnlineGamingAccount"}
+	// Organization is the IRI of foaf:Organization.
+	Organization = triple.IRI{Value: Namespace + "Organization"}
+	// Person is the IRI of foaf:Person.
+	Person = triple.IRI{Value: Namespace + "Person"}
+	// PersonalProfileDocument is the IRI of foaf:PersonalProfileDocument.
+	PersonalProfileDocument = triple.IRI{Value: Namespace + "PersonalProfileDocument"}
+	// Project is the IRI of foaf:Project.
+	Project = triple.IRI{Value: Namespace + "Project"}
+	// Account is the IRI of foaf:account.
+	Account = triple.IRI{Value: Namespace + "account"}
+	// AccountName is the IRI of foaf:accountName.
+	AccountName = triple.IRI{Value: Namespace + "accountName"}
+	// AccountServiceHomepage is the IRI of foaf:accountServiceHomepage.
+	AccountServiceHomepage = triple.IRI{Value: Namespace + "accountServiceHomepage"}
+	// Age is the IRI of foaf:age.
+	Age = triple.IRI{Value: Namespace + "age"}
+	// AimChatID is the IRI of foaf:aimChatID.
+	AimChatID = triple.IRI{Value: Namespace + "aimChatID"}
+	// BasedNear is the IRI of foaf:based_near.
+	BasedNear = triple.IRI{Value: Namespace + "based_near"}
+	// Birthday is the IRI of foaf:birthday.
+	Birthday = triple.IRI{Value: Namespace + "birthday"}
+	// CurrentProject is the IRI of foaf:currentProject.
+	CurrentProject = triple.IRI{Value: Namespace + "currentProject"}
+	// Depiction is the IRI of foaf:depiction.
+	Depiction = triple.IRI{Value: Namespace + "depiction"}
+	// Depicts is the IRI of foaf:depicts.
+	Depicts = triple.IRI{Value: Namespace + "depicts"}
+	// DnaChecksum is the IRI of foaf:dnaChecksum.
+	DnaChecksum = triple.IRI{Value: Namespace + "dnaChecksum"}
+	// FamilyName is the IRI of foaf:familyName.
+	FamilyName = triple.IRI{Value: Namespace + "familyName"}
+	// FamilyName2 is the IRI of foaf:family_name.
+	FamilyName2 = triple.IRI{Value: Namespace + "family_name"}
+	// FirstName is the IRI of foaf:firstName.
+	FirstName = triple.IRI{Value: Namespace + "firstName"}
+	// Focus is the IRI of foaf:focus.
+	Focus = triple.IRI{Value: Namespace + "focus"}
+	// FundedBy is the IRI of foaf:fundedBy.
+	FundedBy = triple.IRI{Value: Namespace + "fundedBy"}
+	// Geekcode is the IRI of foaf:geekcode.
+	Geekcode = triple.IRI{Value: Namespace + "geekcode"}
+	// Gender is the IRI of foaf:gender.
+	Gender = triple.IRI{Value: Namespace + "gender"}
+	// GivenName is the IRI of foaf:givenName.
+	GivenName = triple.IRI{Value: Namespace + "givenName"}
+	// Givenname is the IRI of foaf:givenname.
+	Givenname = triple.IRI{Value: Namespace + "givenname"}
+	// HoldsAccount is the IRI of foaf:holdsAccount.
+	HoldsAccount = triple.IRI{Value: Namespace + "holdsAccount"}
+	// Homepage is the IRI of foaf:homepage.
+	Homepage = triple.IRI{Value: Namespace + "homepage"}
+	// IcqChatID is the IRI of foaf:icqChatID.
+	IcqChatID = triple.IRI{Value: Namespace + "icqChatID"}
+	// Img is the IRI of foaf:img.
+	Img = triple.IRI{Value: Namespace + "img"}
+	// Interest is the IRI of foaf:interest.
+	Interest = triple.IRI{Value: Namespace + "interest"}
+	// IsPrimaryTopicOf is the IRI of foaf:isPrimaryTopicOf.
+	IsPrimaryTopicOf = triple.IRI{Value: Namespace + "isPrimaryTopicOf"}
+	// JabberID is the IRI of foaf:jabberID.
+	JabberID = triple.IRI{Value: Namespace + "jabberID"}
+	// Knows is the IRI of foaf:knows.
+	Knows = triple.IRI{Value: Namespace + "knows"}
+	// LastName is the IRI of foaf:lastName.
+	LastName = triple.IRI{Value: Namespace + "lastName"}
+	// Logo is the IRI of foaf:logo.
+	Logo = triple.IRI{Value: Namespace + "logo"}
+	// Made is the IRI of foaf:made.
+	Made = triple.IRI{Value: Namespace + "made"}
+	// Maker is the IRI of foaf:maker.
+	Maker = triple.IRI{Value: Namespace + "maker"}
+	// Mbox is the IRI of foaf:mbox.
+	Mbox = triple.IRI{Value: Namespace + "mbox"}
+	// MboxSha1sum is the IRI of foaf:mbox_sha1sum.
+	MboxSha1sum = triple.IRI{Value: Namespace + "mbox_sha1sum"}
+	// Member is the IRI of foaf:member.
+	Member = triple.IRI{Value: Namespace + "member"}
+	// MembershipClass is the IRI of foaf:membershipClass.
+	MembershipClass = triple.IRI{Value: Namespace + "membershipClass"}
+	// MsnChatID is the IRI of foaf:msnChatID.
+	MsnChatID = triple.IRI{Value: Namespace + "msnChatID"}
+	// MyersBriggs is the IRI of foaf:myersBriggs.
+	MyersBriggs = triple.IRI{Value: Namespace + "myersBriggs"}
+	// Name is the IRI of foaf:name.
+	Name = triple.IRI{Value: Namespace + "name"}
+	// Nick is the IRI of foaf:nick.
+	Nick = triple.IRI{Value: Namespace + "nick"}
+	// Openid is the IRI of foaf:openid.
+	Openid = triple.IRI{Value: Namespace + "openid"}
+	// Page is the IRI of foaf:page.
+	Page = triple.IRI{Value: Namespace + "page"}
+	// PastProject is the IRI of foaf:pastProject.
+	PastProject = triple.IRI{Value: Namespace + "pastProject"}
+	// Phone is the IRI of foaf:phone.
+	Phone = triple.IRI{Value: Namespace + "phone"}
+	// Plan is the IRI of foaf:plan.
+	Plan = triple.IRI{Value: Namespace + "plan"}
+	// PrimaryTopic is the IRI of foaf:primaryTopic.
+	PrimaryTopic = triple.IRI{Value: Namespace + "primaryTopic"}
+	// Publications is the IRI of foaf:publications.
+	Publications = triple.IRI{Value: Namespace + "publications"}
+	// SchoolHomepage is the IRI of foaf:schoolHomepage.
+	SchoolHomepage = triple.IRI{Value: Namespace + "schoolHomepage"}
+	// Sha1 is the IRI of foaf:sha1.
+	Sha1 = triple.IRI{Value: Namespace + "sha1"}
+	// SkypeID is the IRI of foaf:skypeID.
+	SkypeID = triple.IRI{Value: Namespace + "skypeID"}
+	// Status is the IRI of foaf:status.
+	Status = triple.IRI{Value: Namespace + "status"}
+	// Surname is the IRI of foaf:surname.
+	Surname = triple.IRI{Value: Namespace + "surname"}
+	// Theme is the IRI of foaf:theme.
+	Theme = triple.IRI{Value: Namespace + "theme"}
+	// Thumbnail is the IRI of foaf:thumbnail.
+	Thumbnail = triple.IRI{Value: Namespace + "thumbnail"}
+	// Tipjar is the IRI of foaf:tipjar.
+	Tipjar = triple.IRI{Value: Namespace + "tipjar"}
+	// Title is the IRI of foaf:title.
+	Title = triple.IRI{Value: Namespace + "title"}
+	// Topic is the IRI of foaf:topic.
+	Topic = triple.IRI{Value: Namespace + "topic"}
+	// TopicInterest is the IRI of foaf:topic_interest.
+	TopicInterest = triple.IRI{Value: Namespace + "topic_interest"}
+	// Weblog is the IRI of foaf:weblog.
+	Weblog = triple.IRI{Value: Namespace + "weblog"}
+	// WorkInfoHomepage is the IRI of foaf:workInfoHomepage.
+	WorkInfoHomepage = triple.IRI{Value: Namespace + "workInfoHomepage"}
+	// WorkplaceHomepage is the IRI of foaf:workplaceHomepage.
+	WorkplaceHomepage = triple.IRI{Value: Namespace + "workplaceHomepage"}
+	// YahooChatID is the IRI of foaf:yahooChatID.
+	YahooChatID = triple.IRI{Value: Namespace + "yahooChatID"}
+)
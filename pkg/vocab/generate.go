@@ -0,0 +1,276 @@
+// Package vocab generates Go packages of IRIs from RDFS and OWL
+// vocabularies. Its subpackages hold the generated IRIs of common
+// vocabularies.
+package vocab
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/vocab/owl"
+	"github.com/DeDude/tripl/pkg/vocab/rdf"
+	"github.com/DeDude/tripl/pkg/vocab/rdfs"
+	"github.com/DeDude/tripl/pkg/vocab/skos"
+)
+
+const vannNamespace = "http://purl.org/vocab/vann/preferredNamespaceUri"
+
+// Term is a vocabulary IRI with the Go identifier it is generated as.
+type Term struct {
+	Name    string
+	IRI     string
+	Comment string
+}
+
+type GenerateOptions struct {
+	Package string
+	// Namespace restricts the generated terms to IRIs starting with it.
+	// When empty it is taken from the ontology, see InferNamespace.
+	Namespace string
+	// NamespaceFrom, if set, is a constant such as
+	// "github.com/DeDude/tripl/pkg/xsd.Namespace" that the generated
+	// Namespace is declared as instead of a string, so that the vocabulary
+	// shares its IRIs with a package that cannot import it. The package's
+	// name must be the last element of its import path.
+	NamespaceFrom string
+}
+
+const tripleImport = "github.com/DeDude/tripl/pkg/triple"
+
+// Generate returns gofmt-ed Go source declaring a Namespace constant and a
+// triple.IRI variable for every term the triples define in the namespace.
+func Generate(triples []triple.Triple, opts GenerateOptions) ([]byte, error) {
+	if !token.IsIdentifier(opts.Package) {
+		return nil, fmt.Errorf("invalid package name %q", opts.Package)
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = InferNamespace(triples)
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("cannot infer namespace: no IRI subjects found")
+	}
+
+	imports := []string{tripleImport}
+	namespaceDecl := strconv.Quote(namespace)
+	if opts.NamespaceFrom != "" {
+		importPath, name, ok := cutLast(opts.NamespaceFrom, ".")
+		if !ok || importPath == "" || !token.IsIdentifier(name) || !token.IsExported(name) {
+			return nil, fmt.Errorf("invalid namespace constant %q: want import/path.Name", opts.NamespaceFrom)
+		}
+		if importPath != tripleImport {
+			imports = append(imports, importPath)
+			sort.Strings(imports)
+		}
+		namespaceDecl = path.Base(importPath) + "." + name
+	}
+
+	terms := Terms(triples, namespace)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("no terms defined in namespace %s", namespace)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by tripl gen-vocab. DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintf(&buf, "// Package %s holds the IRIs of the %s vocabulary.\n", opts.Package, namespace)
+	fmt.Fprintf(&buf, "package %s\n\n", opts.Package)
+	if len(imports) == 1 {
+		fmt.Fprintf(&buf, "import %q\n\n", imports[0])
+	} else {
+		fmt.Fprintln(&buf, "import (")
+		for _, p := range imports {
+			fmt.Fprintf(&buf, "%q\n", p)
+		}
+		fmt.Fprintln(&buf, ")")
+		fmt.Fprintln(&buf)
+	}
+	fmt.Fprintf(&buf, "const Namespace = %s\n\n", namespaceDecl)
+	fmt.Fprintln(&buf, "var (")
+	for _, term := range terms {
+		local := strings.TrimPrefix(term.IRI, namespace)
+		fmt.Fprintf(&buf, "// %s is the IRI of %s:%s.", term.Name, opts.Package, local)
+		if term.Comment != "" {
+			fmt.Fprintf(&buf, " %s", sentence(term.Comment))
+		}
+		fmt.Fprintln(&buf)
+		fmt.Fprintf(&buf, "%s = triple.IRI{Value: Namespace + %q}\n", term.Name, local)
+	}
+	fmt.Fprintln(&buf, ")")
+
+	return format.Source(buf.Bytes())
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+// InferNamespace returns the vann:preferredNamespaceUri of the ontology if
+// present, otherwise the namespace shared by most IRI subjects.
+func InferNamespace(triples []triple.Triple) string {
+	counts := make(map[string]int)
+	for _, t := range triples {
+		if t.Predicate == (triple.IRI{Value: vannNamespace}) {
+			if lit, ok := t.Object.(triple.Literal); ok {
+				return lit.Value
+			}
+			if iri, ok := t.Object.(triple.IRI); ok {
+				return iri.Value
+			}
+		}
+		iri, ok := t.Subject.(triple.IRI)
+		if !ok || (t.Predicate == rdf.Type && t.Object == owl.Ontology) {
+			continue
+		}
+		if ns, local := splitIRI(iri.Value); local != "" {
+			counts[ns]++
+		}
+	}
+
+	best := ""
+	for ns, n := range counts {
+		if n > counts[best] || (n == counts[best] && ns < best) {
+			best = ns
+		}
+	}
+	return best
+}
+
+// Terms returns the IRI subjects in namespace, sorted by IRI, with unique Go
+// identifiers. A lowercase name that collides with a capitalized one
+// (schema:event and schema:Event) gets a "Prop" suffix; other collisions are
+// numbered.
+func Terms(triples []triple.Triple, namespace string) []Term {
+	comments := make(map[string]string)
+	labels := make(map[string]string)
+	seen := make(map[string]bool)
+	var iris []string
+
+	for _, t := range triples {
+		iri, ok := t.Subject.(triple.IRI)
+		if !ok || !strings.HasPrefix(iri.Value, namespace) || len(iri.Value) == len(namespace) {
+			continue
+		}
+		if !seen[iri.Value] {
+			seen[iri.Value] = true
+			iris = append(iris, iri.Value)
+		}
+		lit, ok := t.Object.(triple.Literal)
+		if !ok || (lit.Language != "" && !strings.HasPrefix(strings.ToLower(lit.Language), "en")) {
+			continue
+		}
+		switch t.Predicate {
+		case rdfs.Comment, skos.Definition:
+			if comments[iri.Value] == "" {
+				comments[iri.Value] = firstSentence(lit.Value)
+			}
+		case rdfs.Label:
+			if labels[iri.Value] == "" {
+				labels[iri.Value] = firstSentence(lit.Value)
+			}
+		}
+	}
+	sort.Strings(iris)
+
+	// Capitalized local names keep their identifier; Namespace is taken by
+	// the generated constant.
+	reserved := map[string]bool{"Namespace": true}
+	for _, iri := range iris {
+		local := strings.TrimPrefix(iri, namespace)
+		if unicode.IsUpper([]rune(local)[0]) {
+			reserved[identifier(local)] = true
+		}
+	}
+
+	terms := make([]Term, 0, len(iris))
+	used := map[string]bool{"Namespace": true}
+	for _, iri := range iris {
+		local := strings.TrimPrefix(iri, namespace)
+		name := identifier(local)
+		if reserved[name] && !unicode.IsUpper([]rune(local)[0]) {
+			name += "Prop"
+		}
+		for base, i := name, 2; used[name]; i++ {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+		used[name] = true
+
+		comment := comments[iri]
+		if comment == "" {
+			comment = labels[iri]
+		}
+		terms = append(terms, Term{Name: name, IRI: iri, Comment: comment})
+	}
+	return terms
+}
+
+// identifier turns a local name such as "has-part" or "date_time" into an
+// exported Go identifier ("HasPart", "DateTime").
+func identifier(local string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range local {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "T" + name
+	}
+	return name
+}
+
+func splitIRI(iri string) (string, string) {
+	i := strings.LastIndexAny(iri, "#/")
+	if i < 0 {
+		return iri, ""
+	}
+	return iri[:i+1], iri[i+1:]
+}
+
+// sentence capitalizes s and ends it with a full stop.
+func sentence(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	if !strings.ContainsRune(".!?", r[len(r)-1]) {
+		r = append(r, '.')
+	}
+	return string(r)
+}
+
+// firstSentence collapses whitespace and cuts s after the first full stop
+// that is followed by a capitalized word.
+func firstSentence(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	for i := strings.Index(s, ". "); i >= 0; {
+		if r := []rune(s[i+2:]); len(r) > 0 && unicode.IsUpper(r[0]) {
+			return s[:i+1]
+		}
+		next := strings.Index(s[i+2:], ". ")
+		if next < 0 {
+			break
+		}
+		i += 2 + next
+	}
+	return s
+}
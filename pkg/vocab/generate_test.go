@@ -0,0 +1,94 @@
+package vocab
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DeDude/tripl/pkg/encoder"
+)
+
+const sampleOntology = `@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix ex: <http://example.org/ns#> .
+
+<http://example.org/ns> a owl:Ontology .
+
+ex:Event a owl:Class ;
+    rdfs:label "Event"@en ;
+    rdfs:comment "Something that happens, e.g. a concert.  Events have a date."@en, "Ein Ereignis."@de .
+ex:event a owl:ObjectProperty .
+ex:start-date a owl:DatatypeProperty ; rdfs:label "start date" .
+ex:namespace a owl:DatatypeProperty .
+ex:2024 a owl:NamedIndividual .
+rdfs:label rdfs:comment "Not in this vocabulary." .
+`
+
+func TestGenerate(t *testing.T) {
+	triples, _, err := encoder.Decode(sampleOntology, encoder.Turtle, encoder.DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if ns := InferNamespace(triples); ns != "http://example.org/ns#" {
+		t.Fatalf("InferNamespace() = %q", ns)
+	}
+
+	source, err := Generate(triples, GenerateOptions{Package: "ex"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	// Collapse gofmt alignment so the checks do not depend on it.
+	got := strings.Join(strings.Fields(string(source)), " ")
+
+	for _, want := range []string{
+		"// Code generated by tripl gen-vocab. DO NOT EDIT.",
+		`package ex import "github.com/DeDude/tripl/pkg/triple"`,
+		`const Namespace = "http://example.org/ns#"`,
+		"// Event is the IRI of ex:Event. Something that happens, e.g. a concert. Event =",
+		`Event = triple.IRI{Value: Namespace + "Event"}`,
+		`// EventProp is the IRI of ex:event. EventProp = triple.IRI{Value: Namespace + "event"}`,
+		`NamespaceProp = triple.IRI{Value: Namespace + "namespace"}`,
+		"// StartDate is the IRI of ex:start-date. Start date. StartDate =",
+		`StartDate = triple.IRI{Value: Namespace + "start-date"}`,
+		`T2024 = triple.IRI{Value: Namespace + "2024"}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Generate() output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Ereignis") || strings.Contains(got, "Label") {
+		t.Errorf("Generate() included terms or comments it should skip:\n%s", got)
+	}
+
+	source, err = Generate(triples, GenerateOptions{Package: "ex", NamespaceFrom: "example.org/ns.Namespace"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	got = strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		`import ( "example.org/ns" "github.com/DeDude/tripl/pkg/triple" )`,
+		"const Namespace = ns.Namespace",
+		`Event = triple.IRI{Value: Namespace + "Event"}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Generate() with NamespaceFrom output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	triples, _, _ := encoder.Decode(sampleOntology, encoder.Turtle, encoder.DecodeOptions{})
+
+	if _, err := Generate(triples, GenerateOptions{Package: "not-a-package"}); err == nil {
+		t.Error("Generate() expected error for invalid package name")
+	}
+	if _, err := Generate(triples, GenerateOptions{Package: "ex", Namespace: "http://other.org/"}); err == nil {
+		t.Error("Generate() expected error for namespace without terms")
+	}
+	for _, from := range []string{"Namespace", "example.org/ns", "example.org/ns.namespace"} {
+		if _, err := Generate(triples, GenerateOptions{Package: "ex", NamespaceFrom: from}); err == nil {
+			t.Errorf("Generate() expected error for namespace constant %q", from)
+		}
+	}
+}
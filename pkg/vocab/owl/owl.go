@@ -0,0 +1,147 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package owl holds the IRIs of the http://www.w3.org/2002/07/owl# vocabulary.
+package owl
+
+import "github.com/DeDude/tripl/pkg/triple"
+
+const Namespace = "http://www.w3.org/2002/07/owl#"
+
+var (
+	// AllDifferent is the IRI of owl:AllDifferent. The class of collections of pairwise different individuals.
+	AllDifferent = triple.IRI{Value: Namespace + "AllDifferent"}
+	// AllDisjointClasses is the IRI of owl:AllDisjointClasses. The class of collections of pairwise disjoint classes.
+	AllDisjointClasses = triple.IRI{Value: Namespace + "AllDisjointClasses"}
+	// AllDisjointProperties is the IRI of owl:AllDisjointProperties. The class of collections of pairwise disjoint properties.
+	AllDisjointProperties = triple.IRI{Value: Namespace + "AllDisjointProperties"}
+	// Annotation is the IRI of owl:Annotation. The class of annotated annotations for which the RDF serialization consists of an annotated subject, predicate and object.
+	Annotation = triple.IRI{Value: Namespace + "Annotation"}
+	// AnnotationProperty is the IRI of owl:AnnotationProperty. The class of annotation properties.
+	AnnotationProperty = triple.IRI{Value: Namespace + "AnnotationProperty"}
+	// AsymmetricProperty is the IRI of owl:AsymmetricProperty. The class of asymmetric properties.
+	AsymmetricProperty = triple.IRI{Value: Namespace + "AsymmetricProperty"}
+	// Axiom is the IRI of owl:Axiom. The class of annotated axioms for which the RDF serialization consists of an annotated subject, predicate and object.
+	Axiom = triple.IRI{Value: Namespace + "Axiom"}
+	// Class is the IRI of owl:Class. The class of OWL classes.
+	Class = triple.IRI{Value: Namespace + "Class"}
+	// DataRange is the IRI of owl:DataRange. The class of OWL data ranges, which are special kinds of datatypes.
+	DataRange = triple.IRI{Value: Namespace + "DataRange"}
+	// DatatypeProperty is the IRI of owl:DatatypeProperty. The class of data properties.
+	DatatypeProperty = triple.IRI{Value: Namespace + "DatatypeProperty"}
+	// DeprecatedClass is the IRI of owl:DeprecatedClass. The class of deprecated classes.
+	DeprecatedClass = triple.IRI{Value: Namespace + "DeprecatedClass"}
+	// DeprecatedProperty is the IRI of owl:DeprecatedProperty. The class of deprecated properties.
+	DeprecatedProperty = triple.IRI{Value: Namespace + "DeprecatedProperty"}
+	// FunctionalProperty is the IRI of owl:FunctionalProperty. The class of functional properties.
+	FunctionalProperty = triple.IRI{Value: Namespace + "FunctionalProperty"}
+	// InverseFunctionalProperty is the IRI of owl:InverseFunctionalProperty. The class of inverse-functional properties.
+	InverseFunctionalProperty = triple.IRI{Value: Namespace + "InverseFunctionalProperty"}
+	// IrreflexiveProperty is the IRI of owl:IrreflexiveProperty. The class of irreflexive properties.
+	IrreflexiveProperty = triple.IRI{Value: Namespace + "IrreflexiveProperty"}
+	// NamedIndividual is the IRI of owl:NamedIndividual. The class of named individuals.
+	NamedIndividual = triple.IRI{Value: Namespace + "NamedIndividual"}
+	// NegativePropertyAssertion is the IRI of owl:NegativePropertyAssertion. The class of negative property assertions.
+	NegativePropertyAssertion = triple.IRI{Value: Namespace + "NegativePropertyAssertion"}
+	// Nothing is the IRI of owl:Nothing. This is the empty class.
+	Nothing = triple.IRI{Value: Namespace + "Nothing"}
+	// ObjectProperty is the IRI of owl:ObjectProperty. The class of object properties.
+	ObjectProperty = triple.IRI{Value: Namespace + "ObjectProperty"}
+	// Ontology is the IRI of owl:Ontology. The class of ontologies.
+	Ontology = triple.IRI{Value: Namespace + "Ontology"}
+	// OntologyProperty is the IRI of owl:OntologyProperty. The class of ontology properties.
+	OntologyProperty = triple.IRI{Value: Namespace + "OntologyProperty"}
+	// ReflexiveProperty is the IRI of owl:ReflexiveProperty. The class of reflexive properties.
+	ReflexiveProperty = triple.IRI{Value: Namespace + "ReflexiveProperty"}
+	// Restriction is the IRI of owl:Restriction. The class of property restrictions.
+	Restriction = triple.IRI{Value: Namespace + "Restriction"}
+	// SymmetricProperty is the IRI of owl:SymmetricProperty. The class of symmetric properties.
+	SymmetricProperty = triple.IRI{Value: Namespace + "SymmetricProperty"}
+	// Thing is the IRI of owl:Thing. The class of OWL individuals.
+	Thing = triple.IRI{Value: Namespace + "Thing"}
+	// TransitiveProperty is the IRI of owl:TransitiveProperty. The class of transitive properties.
+	TransitiveProperty = triple.IRI{Value: Namespace + "TransitiveProperty"}
+	// AllValuesFrom is the IRI of owl:allValuesFrom. The property that determines the class that a universal property restriction refers to.
+	AllValuesFrom = triple.IRI{Value: Namespace + "allValuesFrom"}
+	// AnnotatedProperty is the IRI of owl:annotatedProperty. The property that determines the predicate of an annotated axiom or annotated annotation.
+	AnnotatedProperty = triple.IRI{Value: Namespace + "annotatedProperty"}
+	// AnnotatedSource is the IRI of owl:annotatedSource. The property that determines the subject of an annotated axiom or annotated annotation.
+	AnnotatedSource = triple.IRI{Value: Namespace + "annotatedSource"}
+	// AnnotatedTarget is the IRI of owl:annotatedTarget. The property that determines the object of an annotated axiom or annotated annotation.
+	AnnotatedTarget = triple.IRI{Value: Namespace + "annotatedTarget"}
+	// AssertionProperty is the IRI of owl:assertionProperty. The property that determines the predicate of a negative property assertion.
+	AssertionProperty = triple.IRI{Value: Namespace + "assertionProperty"}
+	// Cardinality is the IRI of owl:cardinality. The property that determines the cardinality of an exact cardinality restriction.
+	Cardinality = triple.IRI{Value: Namespace + "cardinality"}
+	// ComplementOf is the IRI of owl:complementOf. The property that determines that a given class is the complement of another class.
+	ComplementOf = triple.IRI{Value: Namespace + "complementOf"}
+	// Deprecated is the IRI of owl:deprecated. The annotation property that indicates that a given entity has been deprecated.
+	Deprecated = triple.IRI{Value: Namespace + "deprecated"}
+	// DifferentFrom is the IRI of owl:differentFrom. The property that determines that two given individuals are different.
+	DifferentFrom = triple.IRI{Value: Namespace + "differentFrom"}
+	// DisjointUnionOf is the IRI of owl:disjointUnionOf. The property that determines that a given class is equivalent to the disjoint union of a collection of other classes.
+	DisjointUnionOf = triple.IRI{Value: Namespace + "disjointUnionOf"}
+	// DisjointWith is the IRI of owl:disjointWith. The property that determines that two given classes are disjoint.
+	DisjointWith = triple.IRI{Value: Namespace + "disjointWith"}
+	// DistinctMembers is the IRI of owl:distinctMembers. The property that determines the collection of pairwise different individuals in a owl:AllDifferent axiom.
+	DistinctMembers = triple.IRI{Value: Namespace + "distinctMembers"}
+	// EquivalentClass is the IRI of owl:equivalentClass. The property that determines that two given classes are equivalent.
+	EquivalentClass = triple.IRI{Value: Namespace + "equivalentClass"}
+	// EquivalentProperty is the IRI of owl:equivalentProperty. The property that determines that two given properties are equivalent.
+	EquivalentProperty = triple.IRI{Value: Namespace + "equivalentProperty"}
+	// HasKey is the IRI of owl:hasKey. The property that determines the collection of properties that jointly build a key.
+	HasKey = triple.IRI{Value: Namespace + "hasKey"}
+	// HasSelf is the IRI of owl:hasSelf. The property that determines the property that a self restriction refers to.
+	HasSelf = triple.IRI{Value: Namespace + "hasSelf"}
+	// HasValue is the IRI of owl:hasValue. The property that determines the individual that a has-value restriction refers to.
+	HasValue = triple.IRI{Value: Namespace + "hasValue"}
+	// Imports is the IRI of owl:imports. The property that is used for importing other ontologies into a given ontology.
+	Imports = triple.IRI{Value: Namespace + "imports"}
+	// IntersectionOf is the IRI of owl:intersectionOf. The property that determines the collection of classes or data ranges that build an intersection.
+	IntersectionOf = triple.IRI{Value: Namespace + "intersectionOf"}
+	// InverseOf is the IRI of owl:inverseOf. The property that determines that two given properties are inverse.
+	InverseOf = triple.IRI{Value: Namespace + "inverseOf"}
+	// MaxCardinality is the IRI of owl:maxCardinality. The property that determines the cardinality of a maximum cardinality restriction.
+	MaxCardinality = triple.IRI{Value: Namespace + "maxCardinality"}
+	// MaxQualifiedCardinality is the IRI of owl:maxQualifiedCardinality. The property that determines the cardinality of a maximum qualified cardinality restriction.
+	MaxQualifiedCardinality = triple.IRI{Value: Namespace + "maxQualifiedCardinality"}
+	// Members is the IRI of owl:members. The property that determines the collection of members in either a owl:AllDifferent, owl:AllDisjointClasses or owl:AllDisjointProperties axiom.
+	Members = triple.IRI{Value: Namespace + "members"}
+	// MinCardinality is the IRI of owl:minCardinality. The property that determines the cardinality of a minimum cardinality restriction.
+	MinCardinality = triple.IRI{Value: Namespace + "minCardinality"}
+	// MinQualifiedCardinality is the IRI of owl:minQualifiedCardinality. The property that determines the cardinality of a minimum qualified cardinality restriction.
+	MinQualifiedCardinality = triple.IRI{Value: Namespace + "minQualifiedCardinality"}
+	// OnClass is the IRI of owl:onClass. The property that determines the class that a qualified object cardinality restriction refers to.
+	OnClass = triple.IRI{Value: Namespace + "onClass"}
+	// OnDataRange is the IRI of owl:onDataRange. The property that determines the data range that a qualified data cardinality restriction refers to.
+	OnDataRange = triple.IRI{Value: Namespace + "onDataRange"}
+	// OnDatatype is the IRI of owl:onDatatype. The property that determines the datatype that a datatype restriction refers to.
+	OnDatatype = triple.IRI{Value: Namespace + "onDatatype"}
+	// OnProperty is the IRI of owl:onProperty. The property that determines the property that a property restriction refers to.
+	OnProperty = triple.IRI{Value: Namespace + "onProperty"}
+	// OneOf is the IRI of owl:oneOf. The property that determines the collection of individuals or data values that build an enumeration.
+	OneOf = triple.IRI{Value: Namespace + "oneOf"}
+	// PropertyChainAxiom is the IRI of owl:propertyChainAxiom. The property that determines the n-tuple of properties that build a sub property chain of a given property.
+	PropertyChainAxiom = triple.IRI{Value: Namespace + "propertyChainAxiom"}
+	// PropertyDisjointWith is the IRI of owl:propertyDisjointWith. The property that determines that two given properties are disjoint.
+	PropertyDisjointWith = triple.IRI{Value: Namespace + "propertyDisjointWith"}
+	// QualifiedCardinality is the IRI of owl:qualifiedCardinality. The property that determines the cardinality of an exact qualified cardinality restriction.
+	QualifiedCardinality = triple.IRI{Value: Namespace + "qualifiedCardinality"}
+	// SameAs is the IRI of owl:sameAs. The property that determines that two given individuals are equal.
+	SameAs = triple.IRI{Value: Namespace + "sameAs"}
+	// SomeValuesFrom is the IRI of owl:someValuesFrom. The property that determines the class that an existential property restriction refers to.
+	SomeValuesFrom = triple.IRI{Value: Namespace + "someValuesFrom"}
+	// SourceIndividual is the IRI of owl:sourceIndividual. The property that determines the subject of a negative property assertion.
+	SourceIndividual = triple.IRI{Value: Namespace + "sourceIndividual"}
+	// TargetIndividual is the IRI of owl:targetIndividual. The property that determines the object of a negative object property assertion.
+	TargetIndividual = triple.IRI{Value: Namespace + "targetIndividual"}
+	// TargetValue is the IRI of owl:targetValue. The property that determines the value of a negative data property assertion.
+	TargetValue = triple.IRI{Value: Namespace + "targetValue"}
+	// UnionOf is the IRI of owl:unionOf. The property that determines the collection of classes or data ranges that build a union.
+	UnionOf = triple.IRI{Value: Namespace + "unionOf"}
+	// VersionIRI is the IRI of owl:versionIRI. The property that identifies the version IRI of an ontology.
+	VersionIRI = triple.IRI{Value: Namespace + "versionIRI"}
+	// VersionInfo is the IRI of owl:versionInfo. The annotation property that provides version information for an ontology or another OWL construct.
+	VersionInfo = triple.IRI{Value: Namespace + "versionInfo"}
+	// WithRestrictions is the IRI of owl:withRestrictions. The property that determines the collection of facet-value pairs that define a datatype restriction.
+	WithRestrictions = triple.IRI{Value: Namespace + "withRestrictions"}
+)
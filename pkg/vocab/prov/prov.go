@@ -0,0 +1,171 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package prov holds the IRIs of the http://www.w3.org/ns/prov# vocabulary.
+package prov
+
+import "github.com/DeDude/tripl/pkg/triple"
+
+const Namespace = "http://www.w3.org/ns/prov#"
+
+var (
+	// Activity is the IRI of prov:Activity.
+	Activity = triple.IRI{Value: Namespace + "Activity"}
+	// ActivityInfluence is the IRI of prov:ActivityInfluence.
+	ActivityInfluence = triple.IRI{Value: Namespace + "ActivityInfluence"}
+	// Agent is the IRI of prov:Agent.
+	Agent = triple.IRI{Value: Namespace + "Agent"}
+	// AgentInfluence is the IRI of prov:AgentInfluence.
+	AgentInfluence = triple.IRI{Value: Namespace + "AgentInfluence"}
+	// Association is the IRI of prov:Association.
+	Association = triple.IRI{Value: Namespace + "Association"}
+	// Attribution is the IRI of prov:Attribution.
+	Attribution = triple.IRI{Value: Namespace + "Attribution"}
+	// Bundle is the IRI of prov:Bundle.
+	Bundle = triple.IRI{Value: Namespace + "Bundle"}
+	// Collection is the IRI of prov:Collection.
+	Collection = triple.IRI{Value: Namespace + "Collection"}
+	// Communication is the IRI of prov:Communication.
+	Communication = triple.IRI{Value: Namespace + "Communication"}
+	// Delegation is the IRI of prov:Delegation.
+	Delegation = triple.IRI{Value: Namespace + "Delegation"}
+	// Derivation is the IRI of prov:Derivation.
+	Derivation = triple.IRI{Value: Namespace + "Derivation"}
+	// EmptyCollection is the IRI of prov:EmptyCollection.
+	EmptyCollection = triple.IRI{Value: Namespace + "EmptyCollection"}
+	// End is the IRI of prov:End.
+	End = triple.IRI{Value: Namespace + "End"}
+	// Entity is the IRI of prov:Entity.
+	Entity = triple.IRI{Value: Namespace + "Entity"}
+	// EntityInfluence is the IRI of prov:EntityInfluence.
+	EntityInfluence = triple.IRI{Value: Namespace + "EntityInfluence"}
+	// Generation is the IRI of prov:Generation.
+	Generation = triple.IRI{Value: Namespace + "Generation"}
+	// Influence is the IRI of prov:Influence.
+	Influence = triple.IRI{Value: Namespace + "Influence"}
+	// InstantaneousEvent is the IRI of prov:InstantaneousEvent.
+	InstantaneousEvent = triple.IRI{Value: Namespace + "InstantaneousEvent"}
+	// Invalidation is the IRI of prov:Invalidation.
+	Invalidation = triple.IRI{Value: Namespace + "Invalidation"}
+	// Location is the IRI of prov:Location.
+	Location = triple.IRI{Value: Namespace + "Location"}
+	// Organization is the IRI of prov:Organization.
+	Organization = triple.IRI{Value: Namespace + "Organization"}
+	// Person is the IRI of prov:Person.
+	Person = triple.IRI{Value: Namespace + "Person"}
+	// Plan is the IRI of prov:Plan.
+	Plan = triple.IRI{Value: Namespace + "Plan"}
+	// PrimarySource is the IRI of prov:PrimarySource.
+	PrimarySource = triple.IRI{Value: Namespace + "PrimarySource"}
+	// Quotation is the IRI of prov:Quotation.
+	Quotation = triple.IRI{Value: Namespace + "Quotation"}
+	// Revision is the IRI of prov:Revision.
+	Revision = triple.IRI{Value: Namespace + "Revision"}
+	// Role is the IRI of prov:Role.
+	Role = triple.IRI{Value: Namespace + "Role"}
+	// SoftwareAgent is the IRI of prov:SoftwareAgent.
+	SoftwareAgent = triple.IRI{Value: Namespace + "SoftwareAgent"}
+	// Start is the IRI of prov:Start.
+	Start = triple.IRI{Value: Namespace + "Start"}
+	// Usage is the IRI of prov:Usage.
+	Usage = triple.IRI{Value: Namespace + "Usage"}
+	// ActedOnBehalfOf is the IRI of prov:actedOnBehalfOf.
+	ActedOnBehalfOf = triple.IRI{Value: Namespace + "actedOnBehalfOf"}
+	// ActivityProp is the IRI of prov:activity.
+	ActivityProp = triple.IRI{Value: Namespace + "activity"}
+	// AgentProp is the IRI of prov:agent.
+	AgentProp = triple.IRI{Value: Namespace + "agent"}
+	// AlternateOf is the IRI of prov:alternateOf.
+	AlternateOf = triple.IRI{Value: Namespace + "alternateOf"}
+	// AtLocation is the IRI of prov:atLocation.
+	AtLocation = triple.IRI{Value: Namespace + "atLocation"}
+	// AtTime is the IRI of prov:atTime.
+	AtTime = triple.IRI{Value: Namespace + "atTime"}
+	// EndedAtTime is the IRI of prov:endedAtTime.
+	EndedAtTime = triple.IRI{Value: Namespace + "endedAtTime"}
+	// EntityProp is the IRI of prov:entity.
+	EntityProp = triple.IRI{Value: Namespace + "entity"}
+	// Generated is the IRI of prov:generated.
+	Generated = triple.IRI{Value: Namespace + "generated"}
+	// GeneratedAtTime is the IRI of prov:generatedAtTime.
+	GeneratedAtTime = triple.IRI{Value: Namespace + "generatedAtTime"}
+	// HadActivity is the IRI of prov:hadActivity.
+	HadActivity = triple.IRI{Value: Namespace + "hadActivity"}
+	// HadGeneration is the IRI of prov:hadGeneration.
+	HadGeneration = triple.IRI{Value: Namespace + "hadGeneration"}
+	// HadMember is the IRI of prov:hadMember.
+	HadMember = triple.IRI{Value: Namespace + "hadMember"}
+	// HadPlan is the IRI of prov:hadPlan.
+	HadPlan = triple.IRI{Value: Namespace + "hadPlan"}
+	// HadPrimarySource is the IRI of prov:hadPrimarySource.
+	HadPrimarySource = triple.IRI{Value: Namespace + "hadPrimarySource"}
+	// HadRole is the IRI of prov:hadRole.
+	HadRole = triple.IRI{Value: Namespace + "hadRole"}
+	// HadUsage is the IRI of prov:hadUsage.
+	HadUsage = triple.IRI{Value: Namespace + "hadUsage"}
+	// Influenced is the IRI of prov:influenced.
+	Influenced = triple.IRI{Value: Namespace + "influenced"}
+	// Influencer is the IRI of prov:influencer.
+	Influencer = triple.IRI{Value: Namespace + "influencer"}
+	// Invalidated is the IRI of prov:invalidated.
+	Invalidated = triple.IRI{Value: Namespace + "invalidated"}
+	// InvalidatedAtTime is the IRI of prov:invalidatedAtTime.
+	InvalidatedAtTime = triple.IRI{Value: Namespace + "invalidatedAtTime"}
+	// QualifiedAssociation is the IRI of prov:qualifiedAssociation.
+	QualifiedAssociation = triple.IRI{Value: Namespace + "qualifiedAssociation"}
+	// QualifiedAttribution is the IRI of prov:qualifiedAttribution.
+	QualifiedAttribution = triple.IRI{Value: Namespace + "qualifiedAttribution"}
+	// QualifiedCommunication is the IRI of prov:qualifiedCommunication.
+	QualifiedCommunication = triple.IRI{Value: Namespace + "qualifiedCommunication"}
+	// QualifiedDelegation is the IRI of prov:qualifiedDelegation.
+	QualifiedDelegation = triple.IRI{Value: Namespace + "qualifiedDelegation"}
+	// QualifiedDerivation is the IRI of prov:qualifiedDerivation.
+	QualifiedDerivation = triple.IRI{Value: Namespace + "qualifiedDerivation"}
+	// QualifiedEnd is the IRI of prov:qualifiedEnd.
+	QualifiedEnd = triple.IRI{Value: Namespace + "qualifiedEnd"}
+	// QualifiedGeneration is the IRI of prov:qualifiedGeneration.
+	QualifiedGeneration = triple.IRI{Value: Namespace + "qualifiedGeneration"}
+	// QualifiedInfluence is the IRI of prov:qualifiedInfluence.
+	QualifiedInfluence = triple.IRI{Value: Namespace + "qualifiedInfluence"}
+	// QualifiedInvalidation is the IRI of prov:qualifiedInvalidation.
+	QualifiedInvalidation = triple.IRI{Value: Namespace + "qualifiedInvalidation"}
+	// QualifiedPrimarySource is the IRI of prov:qualifiedPrimarySource.
+	QualifiedPrimarySource = triple.IRI{Value: Namespace + "qualifiedPrimarySource"}
+	// QualifiedQuotation is the IRI of prov:qualifiedQuotation.
+	QualifiedQuotation = triple.IRI{Value: Namespace + "qualifiedQuotation"}
+	// QualifiedRevision is the IRI of prov:qualifiedRevision.
+	QualifiedRevision = triple.IRI{Value: Namespace + "qualifiedRevision"}
+	// QualifiedStart is the IRI of prov:qualifiedStart.
+	QualifiedStart = triple.IRI{Value: Namespace + "qualifiedStart"}
+	// QualifiedUsage is the IRI of prov:qualifiedUsage.
+	QualifiedUsage = triple.IRI{Value: Namespace + "qualifiedUsage"}
+	// SpecializationOf is the IRI of prov:specializationOf.
+	SpecializationOf = triple.IRI{Value: Namespace + "specializationOf"}
+	// StartedAtTime is the IRI of prov:startedAtTime.
+	StartedAtTime = triple.IRI{Value: Namespace + "startedAtTime"}
+	// Used is the IRI of prov:used.
+	Used = triple.IRI{Value: Namespace + "used"}
+	// Value is the IRI of prov:value.
+	Value = triple.IRI{Value: Namespace + "value"}
+	// WasAssociatedWith is the IRI of prov:wasAssociatedWith.
+	WasAssociatedWith = triple.IRI{Value: Namespace + "wasAssociatedWith"}
+	// WasAttributedTo is the IRI of prov:wasAttributedTo.
+	WasAttributedTo = triple.IRI{Value: Namespace + "wasAttributedTo"}
+	// WasDerivedFrom is the IRI of prov:wasDerivedFrom.
+	WasDerivedFrom = triple.IRI{Value: Namespace + "wasDerivedFrom"}
+	// WasEndedBy is the IRI of prov:wasEndedBy.
+	WasEndedBy = triple.IRI{Value: Namespace + "wasEndedBy"}
+	// WasGeneratedBy is the IRI of prov:wasGeneratedBy.
+	WasGeneratedBy = triple.IRI{Value: Namespace + "wasGeneratedBy"}
+	// WasInfluencedBy is the IRI of prov:wasInfluencedBy.
+	WasInfluencedBy = triple.IRI{Value: Namespace + "wasInfluencedBy"}
+	// WasInformedBy is the IRI of prov:wasInformedBy.
+	WasInformedBy = triple.IRI{Value: Namespace + "wasInformedBy"}
+	// WasInvalidatedBy is the IRI of prov:wasInvalidatedBy.
+	WasInvalidatedBy = triple.IRI{Value: Namespace + "wasInvalidatedBy"}
+	// WasQuotedFrom is the IRI of prov:wasQuotedFrom.
+	WasQuotedFrom = triple.IRI{Value: Namespace + "wasQuotedFrom"}
+	// WasRevisionOf is the IRI of prov:wasRevisionOf.
+	WasRevisionOf = triple.IRI{Value: Namespace + "wasRevisionOf"}
+	// WasStartedBy is the IRI of prov:wasStartedBy.
+	WasStartedBy = triple.IRI{Value: Namespace + "wasStartedBy"}
+)
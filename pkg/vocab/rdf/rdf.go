@@ -0,0 +1,59 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package rdf holds the IRIs of the http://www.w3.org/1999/02/22-rdf-syntax-ns# vocabulary.
+package rdf
+
+import "github.com/DeDude/tripl/pkg/triple"
+
+const Namespace = triple.RDFNamespace
+
+var (
+	// Alt is the IRI of rdf:Alt. The class of containers of alternatives.
+	Alt = triple.IRI{Value: Namespace + "Alt"}
+	// Bag is the IRI of rdf:Bag. The class of unordered containers.
+	Bag = triple.IRI{Value: Namespace + "Bag"}
+	// CompoundLiteral is the IRI of rdf:CompoundLiteral. A class representing a compound literal.
+	CompoundLiteral = triple.IRI{Value: Namespace + "CompoundLiteral"}
+	// HTML is the IRI of rdf:HTML. The datatype of RDF literals storing fragments of HTML content.
+	HTML = triple.IRI{Value: Namespace + "HTML"}
+	// JSON is the IRI of rdf:JSON. The datatype of RDF literals storing JSON content.
+	JSON = triple.IRI{Value: Namespace + "JSON"}
+	// List is the IRI of rdf:List. The class of RDF Lists.
+	List = triple.IRI{Value: Namespace + "List"}
+	// PlainLiteral is the IRI of rdf:PlainLiteral. The class of plain (i.e. untyped) literal values.
+	PlainLiteral = triple.IRI{Value: Namespace + "PlainLiteral"}
+	// Property is the IRI of rdf:Property. The class of RDF properties.
+	Property = triple.IRI{Value: Namespace + "Property"}
+	// Seq is the IRI of rdf:Seq. The class of ordered containers.
+	Seq = triple.IRI{Value: Namespace + "Seq"}
+	// Statement is the IRI of rdf:Statement. The class of RDF statements.
+	Statement = triple.IRI{Value: Namespace + "Statement"}
+	// XMLLiteral is the IRI of rdf:XMLLiteral. The datatype of XML literal values.
+	XMLLiteral = triple.IRI{Value: Namespace + "XMLLiteral"}
+	// DirLangString is the IRI of rdf:dirLangString. The datatype of language-tagged string values with a base direction.
+	DirLangString = triple.IRI{Value: Namespace + "dirLangString"}
+	// Direction is the IRI of rdf:direction. The base direction component of a CompoundLiteral.
+	Direction = triple.IRI{Value: Namespace + "direction"}
+	// First is the IRI of rdf:first. The first item in the subject RDF list.
+	First = triple.IRI{Value: Namespace + "first"}
+	// LangString is the IRI of rdf:langString. The datatype of language-tagged string values.
+	LangString = triple.IRI{Value: Namespace + "langString"}
+	// Language is the IRI of rdf:language. The language component of a CompoundLiteral.
+	Language = triple.IRI{Value: Namespace + "language"}
+	// Nil is the IRI of rdf:nil. The empty list, with no items in it.
+	Nil = triple.IRI{Value: Namespace + "nil"}
+	// Object is the IRI of rdf:object. The object of the subject RDF statement.
+	Object = triple.IRI{Value: Namespace + "object"}
+	// Predicate is the IRI of rdf:predicate. The predicate of the subject RDF statement.
+	Predicate = triple.IRI{Value: Namespace + "predicate"}
+	// Reifies is the IRI of rdf:reifies. The subject is a reifier of the triple term object.
+	Reifies = triple.IRI{Value: Namespace + "reifies"}
+	// Rest is the IRI of rdf:rest. The rest of the subject RDF list after the first item.
+	Rest = triple.IRI{Value: Namespace + "rest"}
+	// Subject is the IRI of rdf:subject. The subject of the subject RDF statement.
+	Subject = triple.IRI{Value: Namespace + "subject"}
+	// Type is the IRI of rdf:type. The subject is an instance of a class.
+	Type = triple.IRI{Value: Namespace + "type"}
+	// Value is the IRI of rdf:value. Idiomatic property used for structured values.
+	Value = triple.IRI{Value: Namespace + "value"}
+)
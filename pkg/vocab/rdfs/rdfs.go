@@ -0,0 +1,41 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package rdfs holds the IRIs of the http://www.w3.org/2000/01/rdf-schema# vocabulary.
+package rdfs
+
+import "github.com/DeDude/tripl/pkg/triple"
+
+const Namespace = "http://www.w3.org/2000/01/rdf-schema#"
+
+var (
+	// Class is the IRI of rdfs:Class. The class of classes.
+	Class = triple.IRI{Value: Namespace + "Class"}
+	// Container is the IRI of rdfs:Container. The class of RDF containers.
+	Container = triple.IRI{Value: Namespace + "Container"}
+	// ContainerMembershipProperty is the IRI of rdfs:ContainerMembershipProperty. The class of container membership properties, rdf:_1, rdf:_2, ..., all of which are sub-properties of 'member'.
+	ContainerMembershipProperty = triple.IRI{Value: Namespace + "ContainerMembershipProperty"}
+	// Datatype is the IRI of rdfs:Datatype. The class of RDF datatypes.
+	Datatype = triple.IRI{Value: Namespace + "Datatype"}
+	// Literal is the IRI of rdfs:Literal. The class of literal values, eg. textual strings and integers.
+	Literal = triple.IRI{Value: Namespace + "Literal"}
+	// Resource is the IRI of rdfs:Resource. The class resource, everything.
+	Resource = triple.IRI{Value: Namespace + "Resource"}
+	// Comment is the IRI of rdfs:comment. A description of the subject resource.
+	Comment = triple.IRI{Value: Namespace + "comment"}
+	// Domain is the IRI of rdfs:domain. A domain of the subject property.
+	Domain = triple.IRI{Value: Namespace + "domain"}
+	// IsDefinedBy is the IRI of rdfs:isDefinedBy. The definition of the subject resource.
+	IsDefinedBy = triple.IRI{Value: Namespace + "isDefinedBy"}
+	// Label is the IRI of rdfs:label. A human-readable name for the subject.
+	Label = triple.IRI{Value: Namespace + "label"}
+	// Member is the IRI of rdfs:member. A member of the subject resource.
+	Member = triple.IRI{Value: Namespace + "member"}
+	// Range is the IRI of rdfs:range. A range of the subject property.
+	Range = triple.IRI{Value: Namespace + "range"}
+	// SeeAlso is the IRI of rdfs:seeAlso. Further information about the subject resource.
+	SeeAlso = triple.IRI{Value: Namespace + "seeAlso"}
+	// SubClassOf is the IRI of rdfs:subClassOf. The subject is a subclass of a class.
+	SubClassOf = triple.IRI{Value: Namespace + "subClassOf"}
+	// SubPropertyOf is the IRI of rdfs:subPropertyOf. The subject is a subproperty of a property.
+	SubPropertyOf = triple.IRI{Value: Namespace + "subPropertyOf"}
+)
@@ -0,0 +1,223 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package schema holds the IRIs of the https://schema.org/ vocabulary.
+package schema
+
+import "github.com/DeDude/tripl/pkg/triple"
+
+const Namespace = "https://schema.org/"
+
+var (
+	// Action is the IRI of schema:Action.
+	Action = triple.IRI{Value: Namespace + "Action"}
+	// AggregateRating is the IRI of schema:AggregateRating.
+	AggregateRating = triple.IRI{Value: Namespace + "AggregateRating"}
+	// Article is the IRI of schema:Article.
+	Article = triple.IRI{Value: Namespace + "Article"}
+	// Audience is the IRI of schema:Audience.
+	Audience = triple.IRI{Value: Namespace + "Audience"}
+	// Book is the IRI of schema:Book.
+	Book = triple.IRI{Value: Namespace + "Book"}
+	// Brand is the IRI of schema:Brand.
+	Brand = triple.IRI{Value: Namespace + "Brand"}
+	// BreadcrumbList is the IRI of schema:BreadcrumbList.
+	BreadcrumbList = triple.IRI{Value: Namespace + "BreadcrumbList"}
+	// ContactPoint is the IRI of schema:ContactPoint.
+	ContactPoint = triple.IRI{Value: Namespace + "ContactPoint"}
+	// CreativeWork is the IRI of schema:CreativeWork.
+	CreativeWork = triple.IRI{Value: Namespace + "CreativeWork"}
+	// DataCatalog is the IRI of schema:DataCatalog.
+	DataCatalog = triple.IRI{Value: Namespace + "DataCatalog"}
+	// DataDownload is the IRI of schema:DataDownload.
+	DataDownload = triple.IRI{Value: Namespace + "DataDownload"}
+	// Dataset is the IRI of schema:Dataset.
+	Dataset = triple.IRI{Value: Namespace + "Dataset"}
+	// Event is the IRI of schema:Event.
+	Event = triple.IRI{Value: Namespace + "Event"}
+	// FAQPage is the IRI of schema:FAQPage.
+	FAQPage = triple.IRI{Value: Namespace + "FAQPage"}
+	// GeoCoordinates is the IRI of schema:GeoCoordinates.
+	GeoCoordinates = triple.IRI{Value: Namespace + "GeoCoordinates"}
+	// HowTo is the IRI of schema:HowTo.
+	HowTo = triple.IRI{Value: Namespace + "HowTo"}
+	// ImageObject is the IRI of schema:ImageObject.
+	ImageObject = triple.IRI{Value: Namespace + "ImageObject"}
+	// Intangible is the IRI of schema:Intangible.
+	Intangible = triple.IRI{Value: Namespace + "Intangible"}
+	// ItemList is the IRI of schema:ItemList.
+	ItemList = triple.IRI{Value: Namespace + "ItemList"}
+	// JobPosting is the IRI of schema:JobPosting.
+	JobPosting = triple.IRI{Value: Namespace + "JobPosting"}
+	// ListItem is the IRI of schema:ListItem.
+	ListItem = triple.IRI{Value: Namespace + "ListItem"}
+	// LocalBusiness is the IRI of schema:LocalBusiness.
+	LocalBusiness = triple.IRI{Value: Namespace + "LocalBusiness"}
+	// MediaObject is the IRI of schema:MediaObject.
+	MediaObject = triple.IRI{Value: Namespace + "MediaObject"}
+	// Movie is the IRI of schema:Movie.
+	Movie = triple.IRI{Value: Namespace + "Movie"}
+	// MusicRecording is the IRI of schema:MusicRecording.
+	MusicRecording = triple.IRI{Value: Namespace + "MusicRecording"}
+	// NewsArticle is the IRI of schema:NewsArticle.
+	NewsArticle = triple.IRI{Value: Namespace + "NewsArticle"}
+	// Offer is the IRI of schema:Offer.
+	Offer = triple.IRI{Value: Namespace + "Offer"}
+	// Organization is the IRI of schema:Organization.
+	Organization = triple.IRI{Value: Namespace + "Organization"}
+	// Person is the IRI of schema:Person.
+	Person = triple.IRI{Value: Namespace + "Person"}
+	// Place is the IRI of schema:Place.
+	Place = triple.IRI{Value: Namespace + "Place"}
+	// PostalAddress is the IRI of schema:PostalAddress.
+	PostalAddress = triple.IRI{Value: Namespace + "PostalAddress"}
+	// Product is the IRI of schema:Product.
+	Product = triple.IRI{Value: Namespace + "Product"}
+	// PropertyValue is the IRI of schema:PropertyValue.
+	PropertyValue = triple.IRI{Value: Namespace + "PropertyValue"}
+	// Question is the IRI of schema:Question.
+	Question = triple.IRI{Value: Namespace + "Question"}
+	// Rating is the IRI of schema:Rating.
+	Rating = triple.IRI{Value: Namespace + "Rating"}
+	// Recipe is the IRI of schema:Recipe.
+	Recipe = triple.IRI{Value: Namespace + "Recipe"}
+	// Review is the IRI of schema:Review.
+	Review = triple.IRI{Value: Namespace + "Review"}
+	// Service is the IRI of schema:Service.
+	Service = triple.IRI{Value: Namespace + "Service"}
+	// SoftwareApplication is the IRI of schema:SoftwareApplication.
+	SoftwareApplication = triple.IRI{Value: Namespace + "SoftwareApplication"}
+	// Thing is the IRI of schema:Thing.
+	Thing = triple.IRI{Value: Namespace + "Thing"}
+	// VideoObject is the IRI of schema:VideoObject.
+	VideoObject = triple.IRI{Value: Namespace + "VideoObject"}
+	// WebPage is the IRI of schema:WebPage.
+	WebPage = triple.IRI{Value: Namespace + "WebPage"}
+	// WebSite is the IRI of schema:WebSite.
+	WebSite = triple.IRI{Value: Namespace + "WebSite"}
+	// About is the IRI of schema:about.
+	About = triple.IRI{Value: Namespace + "about"}
+	// AcceptedAnswer is the IRI of schema:acceptedAnswer.
+	AcceptedAnswer = triple.IRI{Value: Namespace + "acceptedAnswer"}
+	// Actor is the IRI of schema:actor.
+	Actor = triple.IRI{Value: Namespace + "actor"}
+	// Address is the IRI of schema:address.
+	Address = triple.IRI{Value: Namespace + "address"}
+	// AddressCountry is the IRI of schema:addressCountry.
+	AddressCountry = triple.IRI{Value: Namespace + "addressCountry"}
+	// AddressLocality is the IRI of schema:addressLocality.
+	AddressLocality = triple.IRI{Value: Namespace + "addressLocality"}
+	// AddressRegion is the IRI of schema:addressRegion.
+	AddressRegion = triple.IRI{Value: Namespace + "addressRegion"}
+	// AggregateRatingProp is the IRI of schema:aggregateRating.
+	AggregateRatingProp = triple.IRI{Value: Namespace + "aggregateRating"}
+	// AlternateName is the IRI of schema:alternateName.
+	AlternateName = triple.IRI{Value: Namespace + "alternateName"}
+	// Author is the IRI of schema:author.
+	Author = triple.IRI{Value: Namespace + "author"}
+	// Availability is the IRI of schema:availability.
+	Availability = triple.IRI{Value: Namespace + "availability"}
+	// BirthDate is the IRI of schema:birthDate.
+	BirthDate = triple.IRI{Value: Namespace + "birthDate"}
+	// BrandProp is the IRI of schema:brand.
+	BrandProp = triple.IRI{Value: Namespace + "brand"}
+	// ContactPointProp is the IRI of schema:contactPoint.
+	ContactPointProp = triple.IRI{Value: Namespace + "contactPoint"}
+	// ContentUrl is the IRI of schema:contentUrl.
+	ContentUrl = triple.IRI{Value: Namespace + "contentUrl"}
+	// Creator is the IRI of schema:creator.
+	Creator = triple.IRI{Value: Namespace + "creator"}
+	// DateCreated is the IRI of schema:dateCreated.
+	DateCreated = triple.IRI{Value: Namespace + "dateCreated"}
+	// DateModified is the IRI of schema:dateModified.
+	DateModified = triple.IRI{Value: Namespace + "dateModified"}
+	// DatePublished is the IRI of schema:datePublished.
+	DatePublished = triple.IRI{Value: Namespace + "datePublished"}
+	// Description is the IRI of schema:description.
+	Description = triple.IRI{Value: Namespace + "description"}
+	// Duration is the IRI of schema:duration.
+	Duration = triple.IRI{Value: Namespace + "duration"}
+	// Email is the IRI of schema:email.
+	Email = triple.IRI{Value: Namespace + "email"}
+	// EndDate is the IRI of schema:endDate.
+	EndDate = triple.IRI{Value: Namespace + "endDate"}
+	// EventProp is the IRI of schema:event.
+	EventProp = triple.IRI{Value: Namespace + "event"}
+	// FamilyName is the IRI of schema:familyName.
+	FamilyName = triple.IRI{Value: Namespace + "familyName"}
+	// Founder is the IRI of schema:founder.
+	Founder = triple.IRI{Value: Namespace + "founder"}
+	// Funder is the IRI of schema:funder.
+	Funder = triple.IRI{Value: Namespace + "funder"}
+	// Geo is the IRI of schema:geo.
+	Geo = triple.IRI{Value: Namespace + "geo"}
+	// GivenName is the IRI of schema:givenName.
+	GivenName = triple.IRI{Value: Namespace + "givenName"}
+	// Headline is the IRI of schema:headline.
+	Headline = triple.IRI{Value: Namespace + "headline"}
+	// Identifier is the IRI of schema:identifier.
+	Identifier = triple.IRI{Value: Namespace + "identifier"}
+	// Image is the IRI of schema:image.
+	Image = triple.IRI{Value: Namespace + "image"}
+	// IsPartOf is the IRI of schema:isPartOf.
+	IsPartOf = triple.IRI{Value: Namespace + "isPartOf"}
+	// ItemListElement is the IRI of schema:itemListElement.
+	ItemListElement = triple.IRI{Value: Namespace + "itemListElement"}
+	// JobTitle is the IRI of schema:jobTitle.
+	JobTitle = triple.IRI{Value: Namespace + "jobTitle"}
+	// Keywords is the IRI of schema:keywords.
+	Keywords = triple.IRI{Value: Namespace + "keywords"}
+	// Latitude is the IRI of schema:latitude.
+	Latitude = triple.IRI{Value: Namespace + "latitude"}
+	// License is the IRI of schema:license.
+	License = triple.IRI{Value: Namespace + "license"}
+	// Location is the IRI of schema:location.
+	Location = triple.IRI{Value: Namespace + "location"}
+	// Logo is the IRI of schema:logo.
+	Logo = triple.IRI{Value: Namespace + "logo"}
+	// Longitude is the IRI of schema:longitude.
+	Longitude = triple.IRI{Value: Namespace + "longitude"}
+	// MemberOf is the IRI of schema:memberOf.
+	MemberOf = triple.IRI{Value: Namespace + "memberOf"}
+	// Name is the IRI of schema:name.
+	Name = triple.IRI{Value: Namespace + "name"}
+	// Offers is the IRI of schema:offers.
+	Offers = triple.IRI{Value: Namespace + "offers"}
+	// Organizer is the IRI of schema:organizer.
+	Organizer = triple.IRI{Value: Namespace + "organizer"}
+	// Performer is the IRI of schema:performer.
+	Performer = triple.IRI{Value: Namespace + "performer"}
+	// Position is the IRI of schema:position.
+	Position = triple.IRI{Value: Namespace + "position"}
+	// PostalCode is the IRI of schema:postalCode.
+	PostalCode = triple.IRI{Value: Namespace + "postalCode"}
+	// Price is the IRI of schema:price.
+	Price = triple.IRI{Value: Namespace + "price"}
+	// PriceCurrency is the IRI of schema:priceCurrency.
+	PriceCurrency = triple.IRI{Value: Namespace + "priceCurrency"}
+	// Publisher is the IRI of schema:publisher.
+	Publisher = triple.IRI{Value: Namespace + "publisher"}
+	// RatingValue is the IRI of schema:ratingValue.
+	RatingValue = triple.IRI{Value: Namespace + "ratingValue"}
+	// ReviewProp is the IRI of schema:review.
+	ReviewProp = triple.IRI{Value: Namespace + "review"}
+	// ReviewRating is the IRI of schema:reviewRating.
+	ReviewRating = triple.IRI{Value: Namespace + "reviewRating"}
+	// SameAs is the IRI of schema:sameAs.
+	SameAs = triple.IRI{Value: Namespace + "sameAs"}
+	// StartDate is the IRI of schema:startDate.
+	StartDate = triple.IRI{Value: Namespace + "startDate"}
+	// StreetAddress is the IRI of schema:streetAddress.
+	StreetAddress = triple.IRI{Value: Namespace + "streetAddress"}
+	// Telephone is the IRI of schema:telephone.
+	Telephone = triple.IRI{Value: Namespace + "telephone"}
+	// Text is the IRI of schema:text.
+	Text = triple.IRI{Value: Namespace + "text"}
+	// ThumbnailUrl is the IRI of schema:thumbnailUrl.
+	ThumbnailUrl = triple.IRI{Value: Namespace + "thumbnailUrl"}
+	// Url is the IRI of schema:url.
+	Url = triple.IRI{Value: Namespace + "url"}
+	// Version is the IRI of schema:version.
+	Version = triple.IRI{Value: Namespace + "version"}
+	// WorksFor is the IRI of schema:worksFor.
+	WorksFor = triple.IRI{Value: Namespace + "worksFor"}
+)
@@ -0,0 +1,223 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package sh holds the IRIs of the http://www.w3.org/ns/shacl# vocabulary.
+package sh
+
+import "github.com/DeDude/tripl/pkg/triple"
+
+const Namespace = "http://www.w3.org/ns/shacl#"
+
+var (
+	// AbstractResult is the IRI of sh:AbstractResult.
+	AbstractResult = triple.IRI{Value: Namespace + "AbstractResult"}
+	// BlankNode is the IRI of sh:BlankNode.
+	BlankNode = triple.IRI{Value: Namespace + "BlankNode"}
+	// BlankNodeOrIRI is the IRI of sh:BlankNodeOrIRI.
+	BlankNodeOrIRI = triple.IRI{Value: Namespace + "BlankNodeOrIRI"}
+	// BlankNodeOrLiteral is the IRI of sh:BlankNodeOrLiteral.
+	BlankNodeOrLiteral = triple.IRI{Value: Namespace + "BlankNodeOrLiteral"}
+	// ConstraintComponent is the IRI of sh:ConstraintComponent.
+	ConstraintComponent = triple.IRI{Value: Namespace + "ConstraintComponent"}
+	// Function is the IRI of sh:Function.
+	Function = triple.IRI{Value: Namespace + "Function"}
+	// IRI is the IRI of sh:IRI.
+	IRI = triple.IRI{Value: Namespace + "IRI"}
+	// IRIOrLiteral is the IRI of sh:IRIOrLiteral.
+	IRIOrLiteral = triple.IRI{Value: Namespace + "IRIOrLiteral"}
+	// Info is the IRI of sh:Info.
+	Info = triple.IRI{Value: Namespace + "Info"}
+	// JSConstraint is the IRI of sh:JSConstraint.
+	JSConstraint = triple.IRI{Value: Namespace + "JSConstraint"}
+	// Literal is the IRI of sh:Literal.
+	Literal = triple.IRI{Value: Namespace + "Literal"}
+	// NodeKindSelector is the IRI of sh:NodeKindSelector.
+	NodeKindSelector = triple.IRI{Value: Namespace + "NodeKindSelector"}
+	// NodeShape is the IRI of sh:NodeShape.
+	NodeShape = triple.IRI{Value: Namespace + "NodeShape"}
+	// Parameter is the IRI of sh:Parameter.
+	Parameter = triple.IRI{Value: Namespace + "Parameter"}
+	// Parameterizable is the IRI of sh:Parameterizable.
+	Parameterizable = triple.IRI{Value: Namespace + "Parameterizable"}
+	// PrefixDeclaration is the IRI of sh:PrefixDeclaration.
+	PrefixDeclaration = triple.IRI{Value: Namespace + "PrefixDeclaration"}
+	// PropertyGroup is the IRI of sh:PropertyGroup.
+	PropertyGroup = triple.IRI{Value: Namespace + "PropertyGroup"}
+	// PropertyShape is the IRI of sh:PropertyShape.
+	PropertyShape = triple.IRI{Value: Namespace + "PropertyShape"}
+	// ResultAnnotation is the IRI of sh:ResultAnnotation.
+	ResultAnnotation = triple.IRI{Value: Namespace + "ResultAnnotation"}
+	// Rule is the IRI of sh:Rule.
+	Rule = triple.IRI{Value: Namespace + "Rule"}
+	// SPARQLAskValidator is the IRI of sh:SPARQLAskValidator.
+	SPARQLAskValidator = triple.IRI{Value: Namespace + "SPARQLAskValidator"}
+	// SPARQLConstraint is the IRI of sh:SPARQLConstraint.
+	SPARQLConstraint = triple.IRI{Value: Namespace + "SPARQLConstraint"}
+	// SPARQLSelectValidator is the IRI of sh:SPARQLSelectValidator.
+	SPARQLSelectValidator = triple.IRI{Value: Namespace + "SPARQLSelectValidator"}
+	// SPARQLTarget is the IRI of sh:SPARQLTarget.
+	SPARQLTarget = triple.IRI{Value: Namespace + "SPARQLTarget"}
+	// SPARQLUpdateExecutable is the IRI of sh:SPARQLUpdateExecutable.
+	SPARQLUpdateExecutable = triple.IRI{Value: Namespace + "SPARQLUpdateExecutable"}
+	// Severity is the IRI of sh:Severity.
+	Severity = triple.IRI{Value: Namespace + "Severity"}
+	// Shape is the IRI of sh:Shape.
+	Shape = triple.IRI{Value: Namespace + "Shape"}
+	// Target is the IRI of sh:Target.
+	Target = triple.IRI{Value: Namespace + "Target"}
+	// TargetType is the IRI of sh:TargetType.
+	TargetType = triple.IRI{Value: Namespace + "TargetType"}
+	// TripleRule is the IRI of sh:TripleRule.
+	TripleRule = triple.IRI{Value: Namespace + "TripleRule"}
+	// ValidationReport is the IRI of sh:ValidationReport.
+	ValidationReport = triple.IRI{Value: Namespace + "ValidationReport"}
+	// ValidationResult is the IRI of sh:ValidationResult.
+	ValidationResult = triple.IRI{Value: Namespace + "ValidationResult"}
+	// Validator is the IRI of sh:Validator.
+	Validator = triple.IRI{Value: Namespace + "Validator"}
+	// Violation is the IRI of sh:Violation.
+	Violation = triple.IRI{Value: Namespace + "Violation"}
+	// Warning is the IRI of sh:Warning.
+	Warning = triple.IRI{Value: Namespace + "Warning"}
+	// AlternativePath is the IRI of sh:alternativePath.
+	AlternativePath = triple.IRI{Value: Namespace + "alternativePath"}
+	// And is the IRI of sh:and.
+	And = triple.IRI{Value: Namespace + "and"}
+	// Class is the IRI of sh:class.
+	Class = triple.IRI{Value: Namespace + "class"}
+	// Closed is the IRI of sh:closed.
+	Closed = triple.IRI{Value: Namespace + "closed"}
+	// Conforms is the IRI of sh:conforms.
+	Conforms = triple.IRI{Value: Namespace + "conforms"}
+	// Datatype is the IRI of sh:datatype.
+	Datatype = triple.IRI{Value: Namespace + "datatype"}
+	// Deactivated is the IRI of sh:deactivated.
+	Deactivated = triple.IRI{Value: Namespace + "deactivated"}
+	// Declare is the IRI of sh:declare.
+	Declare = triple.IRI{Value: Namespace + "declare"}
+	// DefaultValue is the IRI of sh:defaultValue.
+	DefaultValue = triple.IRI{Value: Namespace + "defaultValue"}
+	// Description is the IRI of sh:description.
+	Description = triple.IRI{Value: Namespace + "description"}
+	// Detail is the IRI of sh:detail.
+	Detail = triple.IRI{Value: Namespace + "detail"}
+	// Disjoint is the IRI of sh:disjoint.
+	Disjoint = triple.IRI{Value: Namespace + "disjoint"}
+	// Entailment is the IRI of sh:entailment.
+	Entailment = triple.IRI{Value: Namespace + "entailment"}
+	// Equals is the IRI of sh:equals.
+	Equals = triple.IRI{Value: Namespace + "equals"}
+	// Flags is the IRI of sh:flags.
+	Flags = triple.IRI{Value: Namespace + "flags"}
+	// FocusNode is the IRI of sh:focusNode.
+	FocusNode = triple.IRI{Value: Namespace + "focusNode"}
+	// Group is the IRI of sh:group.
+	Group = triple.IRI{Value: Namespace + "group"}
+	// HasValue is the IRI of sh:hasValue.
+	HasValue = triple.IRI{Value: Namespace + "hasValue"}
+	// IgnoredProperties is the IRI of sh:ignoredProperties.
+	IgnoredProperties = triple.IRI{Value: Namespace + "ignoredProperties"}
+	// In is the IRI of sh:in.
+	In = triple.IRI{Value: Namespace + "in"}
+	// InversePath is the IRI of sh:inversePath.
+	InversePath = triple.IRI{Value: Namespace + "inversePath"}
+	// LanguageIn is the IRI of sh:languageIn.
+	LanguageIn = triple.IRI{Value: Namespace + "languageIn"}
+	// LessThan is the IRI of sh:lessThan.
+	LessThan = triple.IRI{Value: Namespace + "lessThan"}
+	// LessThanOrEquals is the IRI of sh:lessThanOrEquals.
+	LessThanOrEquals = triple.IRI{Value: Namespace + "lessThanOrEquals"}
+	// MaxCount is the IRI of sh:maxCount.
+	MaxCount = triple.IRI{Value: Namespace + "maxCount"}
+	// MaxExclusive is the IRI of sh:maxExclusive.
+	MaxExclusive = triple.IRI{Value: Namespace + "maxExclusive"}
+	// MaxInclusive is the IRI of sh:maxInclusive.
+	MaxInclusive = triple.IRI{Value: Namespace + "maxInclusive"}
+	// MaxLength is the IRI of sh:maxLength.
+	MaxLength = triple.IRI{Value: Namespace + "maxLength"}
+	// Message is the IRI of sh:message.
+	Message = triple.IRI{Value: Namespace + "message"}
+	// MinCount is the IRI of sh:minCount.
+	MinCount = triple.IRI{Value: Namespace + "minCount"}
+	// MinExclusive is the IRI of sh:minExclusive.
+	MinExclusive = triple.IRI{Value: Namespace + "minExclusive"}
+	// MinInclusive is the IRI of sh:minInclusive.
+	MinInclusive = triple.IRI{Value: Namespace + "minInclusive"}
+	// MinLength is the IRI of sh:minLength.
+	MinLength = triple.IRI{Value: Namespace + "minLength"}
+	// Name is the IRI of sh:name.
+	Name = triple.IRI{Value: Namespace + "name"}
+	// NamespaceProp is the IRI of sh:namespace.
+	NamespaceProp = triple.IRI{Value: Namespace + "namespace"}
+	// Node is the IRI of sh:node.
+	Node = triple.IRI{Value: Namespace + "node"}
+	// NodeKind is the IRI of sh:nodeKind.
+	NodeKind = triple.IRI{Value: Namespace + "nodeKind"}
+	// Not is the IRI of sh:not.
+	Not = triple.IRI{Value: Namespace + "not"}
+	// OneOrMorePath is the IRI of sh:oneOrMorePath.
+	OneOrMorePath = triple.IRI{Value: Namespace + "oneOrMorePath"}
+	// Or is the IRI of sh:or.
+	Or = triple.IRI{Value: Namespace + "or"}
+	// Order is the IRI of sh:order.
+	Order = triple.IRI{Value: Namespace + "order"}
+	// Path is the IRI of sh:path.
+	Path = triple.IRI{Value: Namespace + "path"}
+	// Pattern is the IRI of sh:pattern.
+	Pattern = triple.IRI{Value: Namespace + "pattern"}
+	// Prefix is the IRI of sh:prefix.
+	Prefix = triple.IRI{Value: Namespace + "prefix"}
+	// Prefixes is the IRI of sh:prefixes.
+	Prefixes = triple.IRI{Value: Namespace + "prefixes"}
+	// Property is the IRI of sh:property.
+	Property = triple.IRI{Value: Namespace + "property"}
+	// QualifiedMaxCount is the IRI of sh:qualifiedMaxCount.
+	QualifiedMaxCount = triple.IRI{Value: Namespace + "qualifiedMaxCount"}
+	// QualifiedMinCount is the IRI of sh:qualifiedMinCount.
+	QualifiedMinCount = triple.IRI{Value: Namespace + "qualifiedMinCount"}
+	// QualifiedValueShape is the IRI of sh:qualifiedValueShape.
+	QualifiedValueShape = triple.IRI{Value: Namespace + "qualifiedValueShape"}
+	// QualifiedValueShapesDisjoint is the IRI of sh:qualifiedValueShapesDisjoint.
+	QualifiedValueShapesDisjoint = triple.IRI{Value: Namespace + "qualifiedValueShapesDisjoint"}
+	// Result is the IRI of sh:result.
+	Result = triple.IRI{Value: Namespace + "result"}
+	// ResultMessage is the IRI of sh:resultMessage.
+	ResultMessage = triple.IRI{Value: Namespace + "resultMessage"}
+	// ResultPath is the IRI of sh:resultPath.
+	ResultPath = triple.IRI{Value: Namespace + "resultPath"}
+	// ResultSeverity is the IRI of sh:resultSeverity.
+	ResultSeverity = triple.IRI{Value: Namespace + "resultSeverity"}
+	// Select is the IRI of sh:select.
+	Select = triple.IRI{Value: Namespace + "select"}
+	// SeverityProp is the IRI of sh:severity.
+	SeverityProp = triple.IRI{Value: Namespace + "severity"}
+	// ShapesGraph is the IRI of sh:shapesGraph.
+	ShapesGraph = triple.IRI{Value: Namespace + "shapesGraph"}
+	// SourceConstraint is the IRI of sh:sourceConstraint.
+	SourceConstraint = triple.IRI{Value: Namespace + "sourceConstraint"}
+	// SourceConstraintComponent is the IRI of sh:sourceConstraintComponent.
+	SourceConstraintComponent = triple.IRI{Value: Namespace + "sourceConstraintComponent"}
+	// SourceShape is the IRI of sh:sourceShape.
+	SourceShape = triple.IRI{Value: Namespace + "sourceShape"}
+	// Sparql is the IRI of sh:sparql.
+	Sparql = triple.IRI{Value: Namespace + "sparql"}
+	// TargetProp is the IRI of sh:target.
+	TargetProp = triple.IRI{Value: Namespace + "target"}
+	// TargetClass is the IRI of sh:targetClass.
+	TargetClass = triple.IRI{Value: Namespace + "targetClass"}
+	// TargetNode is the IRI of sh:targetNode.
+	TargetNode = triple.IRI{Value: Namespace + "targetNode"}
+	// TargetObjectsOf is the IRI of sh:targetObjectsOf.
+	TargetObjectsOf = triple.IRI{Value: Namespace + "targetObjectsOf"}
+	// TargetSubjectsOf is the IRI of sh:targetSubjectsOf.
+	TargetSubjectsOf = triple.IRI{Value: Namespace + "targetSubjectsOf"}
+	// UniqueLang is the IRI of sh:uniqueLang.
+	UniqueLang = triple.IRI{Value: Namespace + "uniqueLang"}
+	// Value is the IRI of sh:value.
+	Value = triple.IRI{Value: Namespace + "value"}
+	// Xone is the IRI of sh:xone.
+	Xone = triple.IRI{Value: Namespace + "xone"}
+	// ZeroOrMorePath is the IRI of sh:zeroOrMorePath.
+	ZeroOrMorePath = triple.IRI{Value: Namespace + "zeroOrMorePath"}
+	// ZeroOrOnePath is the IRI of sh:zeroOrOnePath.
+	ZeroOrOnePath = triple.IRI{Value: Namespace + "zeroOrOnePath"}
+)
@@ -0,0 +1,75 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package skos holds the IRIs of the http://www.w3.org/2004/02/skos/core# vocabulary.
+package skos
+
+import "github.com/DeDude/tripl/pkg/triple"
+
+const Namespace = "http://www.w3.org/2004/02/skos/core#"
+
+var (
+	// Collection is the IRI of skos:Collection. A meaningful collection of concepts.
+	Collection = triple.IRI{Value: Namespace + "Collection"}
+	// Concept is the IRI of skos:Concept. An idea or notion; a unit of thought.
+	Concept = triple.IRI{Value: Namespace + "Concept"}
+	// ConceptScheme is the IRI of skos:ConceptScheme. A set of concepts, optionally including statements about semantic relationships between those concepts.
+	ConceptScheme = triple.IRI{Value: Namespace + "ConceptScheme"}
+	// OrderedCollection is the IRI of skos:OrderedCollection. An ordered collection of concepts, where both the grouping and the ordering are meaningful.
+	OrderedCollection = triple.IRI{Value: Namespace + "OrderedCollection"}
+	// AltLabel is the IRI of skos:altLabel. An alternative lexical label for a resource.
+	AltLabel = triple.IRI{Value: Namespace + "altLabel"}
+	// BroadMatch is the IRI of skos:broadMatch. Skos:broadMatch is used to state a hierarchical mapping link between two conceptual resources in different concept schemes.
+	BroadMatch = triple.IRI{Value: Namespace + "broadMatch"}
+	// Broader is the IRI of skos:broader. Relates a concept to a concept that is more general in meaning.
+	Broader = triple.IRI{Value: Namespace + "broader"}
+	// BroaderTransitive is the IRI of skos:broaderTransitive. Skos:broaderTransitive is a transitive superproperty of skos:broader.
+	BroaderTransitive = triple.IRI{Value: Namespace + "broaderTransitive"}
+	// ChangeNote is the IRI of skos:changeNote. A note about a modification to a concept.
+	ChangeNote = triple.IRI{Value: Namespace + "changeNote"}
+	// CloseMatch is the IRI of skos:closeMatch. Skos:closeMatch is used to link two concepts that are sufficiently similar that they can be used interchangeably in some information retrieval applications.
+	CloseMatch = triple.IRI{Value: Namespace + "closeMatch"}
+	// Definition is the IRI of skos:definition. A statement or formal explanation of the meaning of a concept.
+	Definition = triple.IRI{Value: Namespace + "definition"}
+	// EditorialNote is the IRI of skos:editorialNote. A note for an editor, translator or maintainer of the vocabulary.
+	EditorialNote = triple.IRI{Value: Namespace + "editorialNote"}
+	// ExactMatch is the IRI of skos:exactMatch. Skos:exactMatch is used to link two concepts, indicating a high degree of confidence that the concepts can be used interchangeably across a wide range of information retrieval applications.
+	ExactMatch = triple.IRI{Value: Namespace + "exactMatch"}
+	// Example is the IRI of skos:example. An example of the use of a concept.
+	Example = triple.IRI{Value: Namespace + "example"}
+	// HasTopConcept is the IRI of skos:hasTopConcept. Relates, by convention, a concept scheme to a concept which is topmost in the broader/narrower concept hierarchies for that scheme.
+	HasTopConcept = triple.IRI{Value: Namespace + "hasTopConcept"}
+	// HiddenLabel is the IRI of skos:hiddenLabel. A lexical label for a resource that should be hidden when generating visual displays of the resource, but should still be accessible to free text search operations.
+	HiddenLabel = triple.IRI{Value: Namespace + "hiddenLabel"}
+	// HistoryNote is the IRI of skos:historyNote. A note about the past state/use/meaning of a concept.
+	HistoryNote = triple.IRI{Value: Namespace + "historyNote"}
+	// InScheme is the IRI of skos:inScheme. Relates a resource (for example a concept) to a concept scheme in which it is included.
+	InScheme = triple.IRI{Value: Namespace + "inScheme"}
+	// MappingRelation is the IRI of skos:mappingRelation. Relates two concepts coming, by convention, from different schemes, and that have comparable meanings.
+	MappingRelation = triple.IRI{Value: Namespace + "mappingRelation"}
+	// Member is the IRI of skos:member. Relates a collection to one of its members.
+	Member = triple.IRI{Value: Namespace + "member"}
+	// MemberList is the IRI of skos:memberList. Relates an ordered collection to the RDF list containing its members.
+	MemberList = triple.IRI{Value: Namespace + "memberList"}
+	// NarrowMatch is the IRI of skos:narrowMatch. Skos:narrowMatch is used to state a hierarchical mapping link between two conceptual resources in different concept schemes.
+	NarrowMatch = triple.IRI{Value: Namespace + "narrowMatch"}
+	// Narrower is the IRI of skos:narrower. Relates a concept to a concept that is more specific in meaning.
+	Narrower = triple.IRI{Value: Namespace + "narrower"}
+	// NarrowerTransitive is the IRI of skos:narrowerTransitive. Skos:narrowerTransitive is a transitive superproperty of skos:narrower.
+	NarrowerTransitive = triple.IRI{Value: Namespace + "narrowerTransitive"}
+	// Notation is the IRI of skos:notation. A notation, also known as classification code, is a string of characters such as "T58.5" or "303.4833" used to uniquely identify a concept within the scope of a given concept scheme.
+	Notation = triple.IRI{Value: Namespace + "notation"}
+	// Note is the IRI of skos:note. A general note, for any purpose.
+	Note = triple.IRI{Value: Namespace + "note"}
+	// PrefLabel is the IRI of skos:prefLabel. The preferred and lexically-labeled label for a resource, in a given language.
+	PrefLabel = triple.IRI{Value: Namespace + "prefLabel"}
+	// Related is the IRI of skos:related. Relates a concept to a concept with which there is an associative semantic relationship.
+	Related = triple.IRI{Value: Namespace + "related"}
+	// RelatedMatch is the IRI of skos:relatedMatch. Skos:relatedMatch is used to state an associative mapping link between two conceptual resources in different concept schemes.
+	RelatedMatch = triple.IRI{Value: Namespace + "relatedMatch"}
+	// ScopeNote is the IRI of skos:scopeNote. A note that helps to clarify the meaning and/or the use of a concept.
+	ScopeNote = triple.IRI{Value: Namespace + "scopeNote"}
+	// SemanticRelation is the IRI of skos:semanticRelation. Links a concept to a concept related by meaning.
+	SemanticRelation = triple.IRI{Value: Namespace + "semanticRelation"}
+	// TopConceptOf is the IRI of skos:topConceptOf. Relates a concept to the concept scheme that it is a top level concept of.
+	TopConceptOf = triple.IRI{Value: Namespace + "topConceptOf"}
+)
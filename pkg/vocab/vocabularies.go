@@ -0,0 +1,15 @@
+package vocab
+
+// The subpackages are generated from the trimmed ontologies in ontologies/.
+// The rdf and xsd namespaces are declared by packages triple and xsd, which
+// the generated packages import, so each IRI has one source.
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/rdf.ttl --package rdf --namespace-from github.com/DeDude/tripl/pkg/triple.RDFNamespace --output rdf/rdf.go --force
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/rdfs.ttl --package rdfs --output rdfs/rdfs.go --force
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/xsd.ttl --package xsd --namespace-from github.com/DeDude/tripl/pkg/xsd.Namespace --output xsd/xsd.go --force
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/owl.ttl --package owl --output owl/owl.go --force
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/skos.ttl --package skos --output skos/skos.go --force
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/dcterms.ttl --package dcterms --output dcterms/dcterms.go --force
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/foaf.ttl --package foaf --output foaf/foaf.go --force
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/schema.ttl --package schema --output schema/schema.go --force
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/prov.ttl --package prov --output prov/prov.go --force
+//go:generate go run ../../cmd/tripl gen-vocab ontologies/sh.ttl --package sh --output sh/sh.go --force
@@ -0,0 +1,92 @@
+// Code generated by tripl gen-vocab. DO NOT EDIT.
+
+// Package xsd holds the IRIs of the http://www.w3.org/2001/XMLSchema# vocabulary.
+package xsd
+
+import (
+	"github.com/DeDude/tripl/pkg/triple"
+	"github.com/DeDude/tripl/pkg/xsd"
+)
+
+const Namespace = xsd.Namespace
+
+var (
+	// NCName is the IRI of xsd:NCName.
+	NCName = triple.IRI{Value: Namespace + "NCName"}
+	// NMTOKEN is the IRI of xsd:NMTOKEN.
+	NMTOKEN = triple.IRI{Value: Namespace + "NMTOKEN"}
+	// Name is the IRI of xsd:Name.
+	Name = triple.IRI{Value: Namespace + "Name"}
+	// AnyURI is the IRI of xsd:anyURI.
+	AnyURI = triple.IRI{Value: Namespace + "anyURI"}
+	// Base64Binary is the IRI of xsd:base64Binary.
+	Base64Binary = triple.IRI{Value: Namespace + "base64Binary"}
+	// Boolean is the IRI of xsd:boolean.
+	Boolean = triple.IRI{Value: Namespace + "boolean"}
+	// Byte is the IRI of xsd:byte.
+	Byte = triple.IRI{Value: Namespace + "byte"}
+	// Date is the IRI of xsd:date.
+	Date = triple.IRI{Value: Namespace + "date"}
+	// DateTime is the IRI of xsd:dateTime.
+	DateTime = triple.IRI{Value: Namespace + "dateTime"}
+	// DateTimeStamp is the IRI of xsd:dateTimeStamp.
+	DateTimeStamp = triple.IRI{Value: Namespace + "dateTimeStamp"}
+	// DayTimeDuration is the IRI of xsd:dayTimeDuration.
+	DayTimeDuration = triple.IRI{Value: Namespace + "dayTimeDuration"}
+	// Decimal is the IRI of xsd:decimal.
+	Decimal = triple.IRI{Value: Namespace + "decimal"}
+	// Double is the IRI of xsd:double.
+	Double = triple.IRI{Value: Namespace + "double"}
+	// Duration is the IRI of xsd:duration.
+	Duration = triple.IRI{Value: Namespace + "duration"}
+	// Float is the IRI of xsd:float.
+	Float = triple.IRI{Value: Namespace + "float"}
+	// GDay is the IRI of xsd:gDay.
+	GDay = triple.IRI{Value: Namespace + "gDay"}
+	// GMonth is the IRI of xsd:gMonth.
+	GMonth = triple.IRI{Value: Namespace + "gMonth"}
+	// GMonthDay is the IRI of xsd:gMonthDay.
+	GMonthDay = triple.IRI{Value: Namespace + "gMonthDay"}
+	// GYear is the IRI of xsd:gYear.
+	GYear = triple.IRI{Value: Namespace + "gYear"}
+	// GYearMonth is the IRI of xsd:gYearMonth.
+	GYearMonth = triple.IRI{Value: Namespace + "gYearMonth"}
+	// HexBinary is the IRI of xsd:hexBinary.
+	HexBinary = triple.IRI{Value: Namespace + "hexBinary"}
+	// Int is the IRI of xsd:int.
+	Int = triple.IRI{Value: Namespace + "int"}
+	// Integer is the IRI of xsd:integer.
+	Integer = triple.IRI{Value: Namespace + "integer"}
+	// Language is the IRI of xsd:language.
+	Language = triple.IRI{Value: Namespace + "language"}
+	// Long is the IRI of xsd:long.
+	Long = triple.IRI{Value: Namespace + "long"}
+	// NegativeInteger is the IRI of xsd:negativeInteger.
+	NegativeInteger = triple.IRI{Value: Namespace + "negativeInteger"}
+	// NonNegativeInteger is the IRI of xsd:nonNegativeInteger.
+	NonNegativeInteger = triple.IRI{Value: Namespace + "nonNegativeInteger"}
+	// NonPositiveInteger is the IRI of xsd:nonPositiveInteger.
+	NonPositiveInteger = triple.IRI{Value: Namespace + "nonPositiveInteger"}
+	// NormalizedString is the IRI of xsd:normalizedString.
+	NormalizedString = triple.IRI{Value: Namespace + "normalizedString"}
+	// PositiveInteger is the IRI of xsd:positiveInteger.
+	PositiveInteger = triple.IRI{Value: Namespace + "positiveInteger"}
+	// Short is the IRI of xsd:short.
+	Short = triple.IRI{Value: Namespace + "short"}
+	// String is the IRI of xsd:string.
+	String = triple.IRI{Value: Namespace + "string"}
+	// Time is the IRI of xsd:time.
+	Time = triple.IRI{Value: Namespace + "time"}
+	// Token is the IRI of xsd:token.
+	Token = triple.IRI{Value: Namespace + "token"}
+	// UnsignedByte is the IRI of xsd:unsignedByte.
+	UnsignedByte = triple.IRI{Value: Namespace + "unsignedByte"}
+	// UnsignedInt is the IRI of xsd:unsignedInt.
+	UnsignedInt = triple.IRI{Value: Namespace + "unsignedInt"}
+	// UnsignedLong is the IRI of xsd:unsignedLong.
+	UnsignedLong = triple.IRI{Value: Namespace + "unsignedLong"}
+	// UnsignedShort is the IRI of xsd:unsignedShort.
+	UnsignedShort = triple.IRI{Value: Namespace + "unsignedShort"}
+	// YearMonthDuration is the IRI of xsd:yearMonthDuration.
+	YearMonthDuration = triple.IRI{Value: Namespace + "yearMonthDuration"}
+)
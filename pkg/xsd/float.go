@@ -0,0 +1,94 @@
+package xsd
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+func ParseDouble(lexical string) (float64, error) {
+	return parseFloat(Double, lexical, 64)
+}
+
+func ParseFloat(lexical string) (float32, error) {
+	f, err := parseFloat(Float, lexical, 32)
+	return float32(f), err
+}
+
+func parseFloat(datatype, lexical string, bitSize int) (float64, error) {
+	if !floatPattern.MatchString(lexical) {
+		return 0, invalid(datatype, lexical)
+	}
+
+	switch strings.TrimPrefix(lexical, "+") {
+	case "INF":
+		return math.Inf(1), nil
+	case "-INF":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+
+	f, err := strconv.ParseFloat(lexical, bitSize)
+	if err != nil {
+		// Out-of-range values round to infinity, as XSD allows.
+		if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+			return f, nil
+		}
+		return 0, invalid(datatype, lexical)
+	}
+	return f, nil
+}
+
+// FormatDouble returns the canonical xsd:double form, e.g. "1.5E2".
+func FormatDouble(f float64) string {
+	return formatFloat(f, 64)
+}
+
+// FormatFloat returns the canonical xsd:float form.
+func FormatFloat(f float32) string {
+	return formatFloat(float64(f), 32)
+}
+
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	case math.IsNaN(f):
+		return "NaN"
+	case f == 0:
+		if math.Signbit(f) {
+			return "-0.0E0"
+		}
+		return "0.0E0"
+	}
+
+	s := strconv.FormatFloat(f, 'E', -1, bitSize)
+	mantissa, exponent, _ := strings.Cut(s, "E")
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+
+	exp, _ := strconv.Atoi(exponent)
+	return mantissa + "E" + strconv.Itoa(exp)
+}
+
+// FormatDecimal returns the canonical xsd:decimal form of f.
+func FormatDecimal(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	canonical, err := canonicalDecimal(s)
+	if err != nil {
+		return s
+	}
+	return canonical
+}
+
+// ParseDecimal parses an xsd:decimal lexical form into a float64.
+func ParseDecimal(lexical string) (float64, error) {
+	if !decimalPattern.MatchString(lexical) {
+		return 0, invalid(Decimal, lexical)
+	}
+	return strconv.ParseFloat(lexical, 64)
+}
@@ -0,0 +1,340 @@
+package xsd
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	datePattern     = regexp.MustCompile(`^(-?[0-9]{4,})-([0-9]{2})-([0-9]{2})(Z|[+-][0-9]{2}:[0-9]{2})?$`)
+	dateTimePattern = regexp.MustCompile(`^(-?[0-9]{4,})-([0-9]{2})-([0-9]{2})T([0-9]{2}):([0-9]{2}):([0-9]{2})(\.[0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2})?$`)
+	gYearPattern    = regexp.MustCompile(`^(-?[0-9]{4,})(Z|[+-][0-9]{2}:[0-9]{2})?$`)
+)
+
+// dateTimeValue holds the components of a date or dateTime lexical form.
+// The fraction keeps its digits so canonical forms are lossless.
+type dateTimeValue struct {
+	year                 int
+	month, day           int
+	hour, minute, second int
+	fraction             string
+	hasZone              bool
+	offset               int // minutes east of UTC
+}
+
+func parseDateTimeValue(lexical string, withTime bool) (dateTimeValue, error) {
+	datatype, pattern := Date, datePattern
+	if withTime {
+		datatype, pattern = DateTime, dateTimePattern
+	}
+
+	m := pattern.FindStringSubmatch(lexical)
+	if m == nil {
+		return dateTimeValue{}, invalid(datatype, lexical)
+	}
+
+	var v dateTimeValue
+	var ok bool
+	if v.year, ok = parseYear(m[1]); !ok {
+		return dateTimeValue{}, invalid(datatype, lexical)
+	}
+	v.month, _ = strconv.Atoi(m[2])
+	v.day, _ = strconv.Atoi(m[3])
+	if v.month < 1 || v.month > 12 || v.day < 1 || v.day > daysIn(v.year, v.month) {
+		return dateTimeValue{}, invalid(datatype, lexical)
+	}
+
+	zone := m[4]
+	if withTime {
+		v.hour, _ = strconv.Atoi(m[4])
+		v.minute, _ = strconv.Atoi(m[5])
+		v.second, _ = strconv.Atoi(m[6])
+		v.fraction = strings.TrimRight(strings.TrimPrefix(m[7], "."), "0")
+		zone = m[8]
+
+		endOfDay := v.hour == 24 && v.minute == 0 && v.second == 0 && v.fraction == ""
+		if (v.hour > 23 && !endOfDay) || v.minute > 59 || v.second > 59 {
+			return dateTimeValue{}, invalid(datatype, lexical)
+		}
+		if endOfDay {
+			v.hour = 0
+			v.day++
+			if v.day > daysIn(v.year, v.month) {
+				v.day = 1
+				v.month++
+				if v.month > 12 {
+					v.month = 1
+					v.year++
+				}
+			}
+		}
+	}
+
+	if v.offset, v.hasZone, ok = parseZone(zone); !ok {
+		return dateTimeValue{}, invalid(datatype, lexical)
+	}
+	return v, nil
+}
+
+func parseYear(s string) (int, bool) {
+	digits := strings.TrimPrefix(s, "-")
+	if len(digits) > 4 && digits[0] == '0' {
+		return 0, false
+	}
+	year, err := strconv.Atoi(s)
+	return year, err == nil
+}
+
+func parseZone(zone string) (offset int, hasZone, ok bool) {
+	if zone == "" {
+		return 0, false, true
+	}
+	if zone == "Z" {
+		return 0, true, true
+	}
+	hours, _ := strconv.Atoi(zone[1:3])
+	minutes, _ := strconv.Atoi(zone[4:6])
+	if minutes > 59 || hours*60+minutes > 14*60 {
+		return 0, false, false
+	}
+	offset = hours*60 + minutes
+	if zone[0] == '-' {
+		offset = -offset
+	}
+	return offset, true, true
+}
+
+func daysIn(year, month int) int {
+	switch month {
+	case 2:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	}
+	return 31
+}
+
+func formatYear(year int) string {
+	if year < 0 {
+		return fmt.Sprintf("-%04d", -year)
+	}
+	return fmt.Sprintf("%04d", year)
+}
+
+func formatZone(hasZone bool, offset int) string {
+	switch {
+	case !hasZone:
+		return ""
+	case offset == 0:
+		return "Z"
+	case offset < 0:
+		return fmt.Sprintf("-%02d:%02d", -offset/60, -offset%60)
+	}
+	return fmt.Sprintf("+%02d:%02d", offset/60, offset%60)
+}
+
+func (v dateTimeValue) dateString() string {
+	return fmt.Sprintf("%s-%02d-%02d%s", formatYear(v.year), v.month, v.day, formatZone(v.hasZone, v.offset))
+}
+
+func (v dateTimeValue) dateTimeString() string {
+	s := fmt.Sprintf("%s-%02d-%02dT%02d:%02d:%02d", formatYear(v.year), v.month, v.day, v.hour, v.minute, v.second)
+	if v.fraction != "" {
+		s += "." + v.fraction
+	}
+	return s + formatZone(v.hasZone, v.offset)
+}
+
+// utc returns v moved to UTC, as the canonical form of a dateTime with a
+// timezone is written. A value without a timezone is returned as is.
+func (v dateTimeValue) utc() dateTimeValue {
+	if !v.hasZone || v.offset == 0 {
+		return v
+	}
+
+	minutes := v.hour*60 + v.minute - v.offset
+	v.offset = 0
+	switch {
+	case minutes < 0:
+		minutes += 24 * 60
+		v.day--
+		if v.day < 1 {
+			v.month--
+			if v.month < 1 {
+				v.month = 12
+				v.year--
+			}
+			v.day = daysIn(v.year, v.month)
+		}
+	case minutes >= 24*60:
+		minutes -= 24 * 60
+		v.day++
+		if v.day > daysIn(v.year, v.month) {
+			v.day = 1
+			v.month++
+			if v.month > 12 {
+				v.month = 1
+				v.year++
+			}
+		}
+	}
+	v.hour, v.minute = minutes/60, minutes%60
+	return v
+}
+
+func (v dateTimeValue) time() time.Time {
+	loc := time.UTC
+	if v.hasZone && v.offset != 0 {
+		loc = time.FixedZone("", v.offset*60)
+	}
+
+	nanos := 0
+	if v.fraction != "" {
+		digits := (v.fraction + "000000000")[:9]
+		nanos, _ = strconv.Atoi(digits)
+	}
+	return time.Date(v.year, time.Month(v.month), v.day, v.hour, v.minute, v.second, nanos, loc)
+}
+
+// ParseDate parses an xsd:date. Dates without a timezone are returned in UTC.
+func ParseDate(lexical string) (time.Time, error) {
+	v, err := parseDateTimeValue(lexical, false)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return v.time(), nil
+}
+
+// ParseDateTime parses an xsd:dateTime. Values without a timezone are
+// returned in UTC.
+func ParseDateTime(lexical string) (time.Time, error) {
+	v, err := parseDateTimeValue(lexical, true)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return v.time(), nil
+}
+
+func FormatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+func FormatDateTime(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05.999999999Z07:00")
+}
+
+func canonicalGYear(lexical string) (string, error) {
+	m := gYearPattern.FindStringSubmatch(lexical)
+	if m == nil {
+		return "", invalid(GYear, lexical)
+	}
+	year, ok := parseYear(m[1])
+	if !ok {
+		return "", invalid(GYear, lexical)
+	}
+	offset, hasZone, ok := parseZone(m[2])
+	if !ok {
+		return "", invalid(GYear, lexical)
+	}
+	return formatYear(year) + formatZone(hasZone, offset), nil
+}
+
+// DurationValue is an xsd:duration split into its month and day-time parts,
+// which XSD keeps separate because months vary in length.
+type DurationValue struct {
+	Months      int64
+	Nanoseconds int64
+}
+
+func ParseDuration(lexical string) (DurationValue, error) {
+	m := durationPattern.FindStringSubmatch(lexical)
+	if m == nil || strings.HasSuffix(lexical, "P") || strings.HasSuffix(lexical, "T") {
+		return DurationValue{}, invalid(Duration, lexical)
+	}
+
+	component := func(s string, scale int64) *big.Int {
+		n, _ := new(big.Int).SetString(s, 10)
+		if n == nil {
+			n = new(big.Int)
+		}
+		return n.Mul(n, big.NewInt(scale))
+	}
+
+	months := component(m[2], 12)
+	months.Add(months, component(m[3], 1))
+
+	nanos := component(m[4], 24*int64(time.Hour))
+	nanos.Add(nanos, component(m[5], int64(time.Hour)))
+	nanos.Add(nanos, component(m[6], int64(time.Minute)))
+	if m[7] != "" {
+		whole, fraction, _ := strings.Cut(m[7], ".")
+		nanos.Add(nanos, component(whole, int64(time.Second)))
+		if fraction != "" {
+			nanos.Add(nanos, component((fraction + "000000000")[:9], 1))
+		}
+	}
+
+	if !months.IsInt64() || !nanos.IsInt64() {
+		return DurationValue{}, fmt.Errorf("duration %q out of range", lexical)
+	}
+
+	d := DurationValue{Months: months.Int64(), Nanoseconds: nanos.Int64()}
+	if m[1] == "-" {
+		d.Months, d.Nanoseconds = -d.Months, -d.Nanoseconds
+	}
+	return d, nil
+}
+
+// String returns the canonical lexical form of the duration.
+func (d DurationValue) String() string {
+	if d.Months == 0 && d.Nanoseconds == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	months, nanos := d.Months, d.Nanoseconds
+	if months < 0 || nanos < 0 {
+		b.WriteString("-")
+		months, nanos = -months, -nanos
+	}
+	b.WriteString("P")
+
+	if y := months / 12; y > 0 {
+		fmt.Fprintf(&b, "%dY", y)
+	}
+	if mo := months % 12; mo > 0 {
+		fmt.Fprintf(&b, "%dM", mo)
+	}
+
+	day := 24 * int64(time.Hour)
+	if days := nanos / day; days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	nanos %= day
+	if nanos == 0 {
+		return b.String()
+	}
+
+	b.WriteString("T")
+	if h := nanos / int64(time.Hour); h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+	}
+	if mi := nanos % int64(time.Hour) / int64(time.Minute); mi > 0 {
+		fmt.Fprintf(&b, "%dM", mi)
+	}
+	if s := nanos % int64(time.Minute); s > 0 {
+		seconds := strconv.FormatInt(s/int64(time.Second), 10)
+		if frac := s % int64(time.Second); frac > 0 {
+			seconds += "." + strings.TrimRight(fmt.Sprintf("%09d", frac), "0")
+		}
+		b.WriteString(seconds + "S")
+	}
+	return b.String()
+}
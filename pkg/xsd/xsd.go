@@ -0,0 +1,296 @@
+package xsd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const Namespace = "http://www.w3.org/2001/XMLSchema#"
+
+const (
+	String       = Namespace + "string"
+	Boolean      = Namespace + "boolean"
+	Decimal      = Namespace + "decimal"
+	Integer      = Namespace + "integer"
+	Double       = Namespace + "double"
+	Float        = Namespace + "float"
+	Date         = Namespace + "date"
+	DateTime     = Namespace + "dateTime"
+	Duration     = Namespace + "duration"
+	GYear        = Namespace + "gYear"
+	AnyURI       = Namespace + "anyURI"
+	HexBinary    = Namespace + "hexBinary"
+	Base64Binary = Namespace + "base64Binary"
+
+	Long               = Namespace + "long"
+	Int                = Namespace + "int"
+	Short              = Namespace + "short"
+	Byte               = Namespace + "byte"
+	NonNegativeInteger = Namespace + "nonNegativeInteger"
+	PositiveInteger    = Namespace + "positiveInteger"
+	NonPositiveInteger = Namespace + "nonPositiveInteger"
+	NegativeInteger    = Namespace + "negativeInteger"
+	UnsignedLong       = Namespace + "unsignedLong"
+	UnsignedInt        = Namespace + "unsignedInt"
+	UnsignedShort      = Namespace + "unsignedShort"
+	UnsignedByte       = Namespace + "unsignedByte"
+)
+
+// integerRange bounds the value space of xsd:integer and its derived types.
+// A nil bound is unbounded.
+type integerRange struct {
+	min, max *big.Int
+}
+
+func bound(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return n
+}
+
+var integerTypes = map[string]integerRange{
+	Integer:            {},
+	Long:               {bound("-9223372036854775808"), bound("9223372036854775807")},
+	Int:                {bound("-2147483648"), bound("2147483647")},
+	Short:              {bound("-32768"), bound("32767")},
+	Byte:               {bound("-128"), bound("127")},
+	NonNegativeInteger: {bound("0"), nil},
+	PositiveInteger:    {bound("1"), nil},
+	NonPositiveInteger: {nil, bound("0")},
+	NegativeInteger:    {nil, bound("-1")},
+	UnsignedLong:       {bound("0"), bound("18446744073709551615")},
+	UnsignedInt:        {bound("0"), bound("4294967295")},
+	UnsignedShort:      {bound("0"), bound("65535")},
+	UnsignedByte:       {bound("0"), bound("255")},
+}
+
+var (
+	integerPattern  = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	decimalPattern  = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+	floatPattern    = regexp.MustCompile(`^([+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?|[+-]?INF|NaN)$`)
+	hexPattern      = regexp.MustCompile(`^([0-9a-fA-F]{2})*$`)
+	base64Pattern   = regexp.MustCompile(`^[A-Za-z0-9+/= ]*$`)
+	durationPattern = regexp.MustCompile(`^(-)?P(?:([0-9]+)Y)?(?:([0-9]+)M)?(?:([0-9]+)D)?(?:T(?:([0-9]+)H)?(?:([0-9]+)M)?(?:([0-9]+(?:\.[0-9]+)?)S)?)?$`)
+)
+
+// IsInteger reports whether datatype is xsd:integer or one of its derived types.
+func IsInteger(datatype string) bool {
+	_, ok := integerTypes[datatype]
+	return ok
+}
+
+// IsNumeric reports whether datatype is one of the XSD numeric types.
+func IsNumeric(datatype string) bool {
+	return IsInteger(datatype) || datatype == Decimal || datatype == Double || datatype == Float
+}
+
+// Supported reports whether datatype is validated by this package.
+func Supported(datatype string) bool {
+	if IsInteger(datatype) {
+		return true
+	}
+	switch datatype {
+	case String, Boolean, Decimal, Double, Float, Date, DateTime, Duration,
+		GYear, AnyURI, HexBinary, Base64Binary:
+		return true
+	}
+	return false
+}
+
+// Validate checks that lexical is in the lexical space of datatype.
+// Datatypes that are not supported are accepted as-is.
+func Validate(datatype, lexical string) error {
+	_, err := Canonical(datatype, lexical)
+	return err
+}
+
+// Canonical returns the canonical lexical form of lexical for datatype.
+// Unsupported datatypes are returned unchanged.
+func Canonical(datatype, lexical string) (string, error) {
+	if IsInteger(datatype) {
+		n, err := ParseBigInteger(datatype, lexical)
+		if err != nil {
+			return "", err
+		}
+		return n.String(), nil
+	}
+
+	switch datatype {
+	case Boolean:
+		b, err := ParseBoolean(lexical)
+		if err != nil {
+			return "", err
+		}
+		return FormatBoolean(b), nil
+	case Decimal:
+		return canonicalDecimal(lexical)
+	case Double:
+		f, err := ParseDouble(lexical)
+		if err != nil {
+			return "", err
+		}
+		return FormatDouble(f), nil
+	case Float:
+		f, err := ParseFloat(lexical)
+		if err != nil {
+			return "", err
+		}
+		return FormatFloat(f), nil
+	case Date:
+		v, err := parseDateTimeValue(lexical, false)
+		if err != nil {
+			return "", err
+		}
+		return v.dateString(), nil
+	case DateTime:
+		v, err := parseDateTimeValue(lexical, true)
+		if err != nil {
+			return "", err
+		}
+		return v.utc().dateTimeString(), nil
+	case GYear:
+		return canonicalGYear(lexical)
+	case Duration:
+		d, err := ParseDuration(lexical)
+		if err != nil {
+			return "", err
+		}
+		return d.String(), nil
+	case AnyURI:
+		return canonicalAnyURI(lexical)
+	case HexBinary:
+		b, err := ParseHexBinary(lexical)
+		if err != nil {
+			return "", err
+		}
+		return FormatHexBinary(b), nil
+	case Base64Binary:
+		b, err := ParseBase64Binary(lexical)
+		if err != nil {
+			return "", err
+		}
+		return FormatBase64Binary(b), nil
+	}
+
+	return lexical, nil
+}
+
+func invalid(datatype, lexical string) error {
+	return fmt.Errorf("invalid lexical form %q for %s", lexical, shortName(datatype))
+}
+
+func shortName(datatype string) string {
+	if strings.HasPrefix(datatype, Namespace) {
+		return "xsd:" + strings.TrimPrefix(datatype, Namespace)
+	}
+	return datatype
+}
+
+// ParseBigInteger parses an xsd:integer (or derived type) lexical form of any size.
+func ParseBigInteger(datatype, lexical string) (*big.Int, error) {
+	if !integerPattern.MatchString(lexical) {
+		return nil, invalid(datatype, lexical)
+	}
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(lexical, "+"), 10)
+	if !ok {
+		return nil, invalid(datatype, lexical)
+	}
+
+	r := integerTypes[datatype]
+	if (r.min != nil && n.Cmp(r.min) < 0) || (r.max != nil && n.Cmp(r.max) > 0) {
+		return nil, fmt.Errorf("value %s out of range for %s", lexical, shortName(datatype))
+	}
+	return n, nil
+}
+
+// ParseInteger parses an xsd:integer (or derived type) lexical form into an int64.
+func ParseInteger(datatype, lexical string) (int64, error) {
+	n, err := ParseBigInteger(datatype, lexical)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() {
+		return 0, fmt.Errorf("value %s overflows int64", lexical)
+	}
+	return n.Int64(), nil
+}
+
+func ParseBoolean(lexical string) (bool, error) {
+	switch lexical {
+	case "true", "1":
+		return true, nil
+	case "false", "0":
+		return false, nil
+	}
+	return false, invalid(Boolean, lexical)
+}
+
+func FormatBoolean(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func canonicalDecimal(lexical string) (string, error) {
+	if !decimalPattern.MatchString(lexical) {
+		return "", invalid(Decimal, lexical)
+	}
+
+	negative := strings.HasPrefix(lexical, "-")
+	digits := strings.TrimLeft(lexical, "+-")
+
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	intPart = strings.TrimLeft(intPart, "0")
+	fracPart = strings.TrimRight(fracPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if fracPart == "" {
+		fracPart = "0"
+	}
+
+	result := intPart + "." + fracPart
+	if negative && result != "0.0" {
+		result = "-" + result
+	}
+	return result, nil
+}
+
+func ParseHexBinary(lexical string) ([]byte, error) {
+	if !hexPattern.MatchString(lexical) {
+		return nil, invalid(HexBinary, lexical)
+	}
+	return hex.DecodeString(lexical)
+}
+
+func FormatHexBinary(b []byte) string {
+	return strings.ToUpper(hex.EncodeToString(b))
+}
+
+func ParseBase64Binary(lexical string) ([]byte, error) {
+	if !base64Pattern.MatchString(lexical) {
+		return nil, invalid(Base64Binary, lexical)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(lexical, " ", ""))
+	if err != nil {
+		return nil, invalid(Base64Binary, lexical)
+	}
+	return b, nil
+}
+
+func FormatBase64Binary(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func canonicalAnyURI(lexical string) (string, error) {
+	collapsed := strings.Join(strings.Fields(lexical), " ")
+	if _, err := url.Parse(collapsed); err != nil {
+		return "", invalid(AnyURI, lexical)
+	}
+	return collapsed, nil
+}
@@ -0,0 +1,126 @@
+package xsd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		datatype string
+		input    string
+		expected string
+	}{
+		{Integer, "+007", "7"},
+		{Integer, "-0", "0"},
+		{Integer, "123456789012345678901234567890", "123456789012345678901234567890"},
+		{Byte, "-128", "-128"},
+		{Decimal, "+01.50", "1.5"},
+		{Decimal, "-.0", "0.0"},
+		{Decimal, "3", "3.0"},
+		{Double, "1500", "1.5E3"},
+		{Double, "-0.01e-2", "-1.0E-4"},
+		{Double, "+INF", "INF"},
+		{Float, "0", "0.0E0"},
+		{Boolean, "1", "true"},
+		{Boolean, "false", "false"},
+		{Date, "2024-02-29+00:00", "2024-02-29Z"},
+		{Date, "2024-01-15-05:00", "2024-01-15-05:00"},
+		{DateTime, "2024-01-15T10:30:00.500Z", "2024-01-15T10:30:00.5Z"},
+		{DateTime, "2023-12-31T24:00:00", "2024-01-01T00:00:00"},
+		{DateTime, "2020-01-02T03:04:05+01:00", "2020-01-02T02:04:05Z"},
+		{DateTime, "2020-01-02T03:04:05", "2020-01-02T03:04:05"},
+		{DateTime, "2020-01-01T00:30:00+01:00", "2019-12-31T23:30:00Z"},
+		{DateTime, "2024-02-28T22:00:00.25-03:30", "2024-02-29T01:30:00.25Z"},
+		{GYear, "-0044", "-0044"},
+		{GYear, "2024-00:00", "2024Z"},
+		{Duration, "P0Y18M", "P1Y6M"},
+		{Duration, "PT36H", "P1DT12H"},
+		{Duration, "-PT1.50S", "-PT1.5S"},
+		{Duration, "P0D", "PT0S"},
+		{AnyURI, " http://example.org/a ", "http://example.org/a"},
+		{HexBinary, "0fb7", "0FB7"},
+		{Base64Binary, "aGVs bG8=", "aGVsbG8="},
+		{"http://example.org/custom", " anything ", " anything "},
+	}
+
+	for _, tt := range tests {
+		t.Run(shortName(tt.datatype)+" "+tt.input, func(t *testing.T) {
+			result, err := Canonical(tt.datatype, tt.input)
+			if err != nil {
+				t.Fatalf("Canonical() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Canonical() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateInvalid(t *testing.T) {
+	tests := []struct {
+		datatype string
+		input    string
+	}{
+		{Integer, "1.0"},
+		{Integer, ""},
+		{Byte, "128"},
+		{NonNegativeInteger, "-1"},
+		{Decimal, "1e3"},
+		{Decimal, "."},
+		{Double, "one"},
+		{Double, "inf"},
+		{Boolean, "yes"},
+		{Date, "2023-02-29"},
+		{Date, "2024-13-01"},
+		{Date, "2024-01-15T00:00:00"},
+		{DateTime, "2024-01-15"},
+		{DateTime, "2024-01-15T24:00:01"},
+		{DateTime, "2024-01-15T10:00:00+15:00"},
+		{GYear, "24"},
+		{GYear, "02024"},
+		{Duration, "P"},
+		{Duration, "P1DT"},
+		{Duration, "PT1.5M"},
+		{HexBinary, "ABC"},
+		{Base64Binary, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(shortName(tt.datatype)+" "+tt.input, func(t *testing.T) {
+			if err := Validate(tt.datatype, tt.input); err == nil {
+				t.Errorf("Validate(%q) expected error", tt.input)
+			}
+		})
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	result, err := ParseDateTime("2024-01-15T10:30:00.25-05:00")
+	if err != nil {
+		t.Fatalf("ParseDateTime() error = %v", err)
+	}
+
+	expected := time.Date(2024, 1, 15, 15, 30, 0, 250000000, time.UTC)
+	if !result.Equal(expected) {
+		t.Errorf("ParseDateTime() = %v, want %v", result, expected)
+	}
+	if formatted := FormatDateTime(result); formatted != "2024-01-15T10:30:00.25-05:00" {
+		t.Errorf("FormatDateTime() = %q", formatted)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	d, err := ParseDuration("P1Y2M3DT4H5M6.5S")
+	if err != nil {
+		t.Fatalf("ParseDuration() error = %v", err)
+	}
+
+	if d.Months != 14 {
+		t.Errorf("Months = %d, want 14", d.Months)
+	}
+	expected := 3*24*time.Hour + 4*time.Hour + 5*time.Minute + 6500*time.Millisecond
+	if time.Duration(d.Nanoseconds) != expected {
+		t.Errorf("Nanoseconds = %v, want %v", time.Duration(d.Nanoseconds), expected)
+	}
+}